@@ -12,9 +12,13 @@ import (
 	"github.com/eafajri/hr-service.git/config"
 	employeeRest "github.com/eafajri/hr-service.git/module/employee/transport/rest"
 	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
 )
 
 func main() {
+	// Keep money as JSON numbers in API responses, decimals are encoded as strings by default.
+	decimal.MarshalJSONWithoutQuotes = true
+
 	conf := config.GetConfig()
 	e := echo.New()
 
@@ -27,7 +27,7 @@ func main() {
 
 		status := "open"
 		if !periodEnd.After(cutoffDate) {
-			status = "closed"
+			status = "locked"
 		}
 
 		periods = append(periods, PayrollPeriod{
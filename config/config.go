@@ -3,9 +3,13 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 	"sync"
+	"time"
 
+	"github.com/eafajri/hr-service.git/internal/money"
 	"github.com/joho/godotenv"
+	"github.com/shopspring/decimal"
 )
 
 type Config struct {
@@ -16,6 +20,25 @@ type Config struct {
 	DBUser     string
 	DBPassword string
 	DBName     string
+
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// RevocationSyncInterval is how often revoked sessions are reloaded, the longest a revocation made on another instance takes to apply.
+	RevocationSyncInterval time.Duration
+
+	LoginMaxFailedAttempts int
+	LoginLockoutDuration   time.Duration
+
+	CacheMaxEntries      int
+	CacheDefaultTTL      time.Duration
+	CacheCleanupInterval time.Duration
+
+	PayrollCurrency    string
+	PayrollRounding    money.RoundingPolicy
+	PayrollNetPayFloor decimal.Decimal
+
+	PayrollPreviewOvertimeWarningHours int
 }
 
 var (
@@ -44,4 +67,68 @@ func (c *Config) loadEnvVariables() {
 	c.DBUser = os.Getenv("DB_USER")
 	c.DBPassword = os.Getenv("DB_PASSWORD")
 	c.DBName = os.Getenv("DB_NAME")
+
+	c.JWTSecret = os.Getenv("JWT_SECRET")
+	if c.JWTSecret == "" {
+		log.Fatal("JWT_SECRET must be set")
+	}
+	c.AccessTokenTTL = getDurationEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+	c.RefreshTokenTTL = getDurationEnv("REFRESH_TOKEN_TTL", 7*24*time.Hour)
+	c.RevocationSyncInterval = getDurationEnv("REVOCATION_SYNC_INTERVAL", 5*time.Second)
+
+	c.LoginMaxFailedAttempts = getIntEnv("LOGIN_MAX_FAILED_ATTEMPTS", 5)
+	c.LoginLockoutDuration = getDurationEnv("LOGIN_LOCKOUT_DURATION", 15*time.Minute)
+
+	c.CacheMaxEntries = getIntEnv("CACHE_MAX_ENTRIES", 10000)
+	c.CacheDefaultTTL = getDurationEnv("CACHE_DEFAULT_TTL", 10*time.Minute)
+	c.CacheCleanupInterval = getDurationEnv("CACHE_CLEANUP_INTERVAL", time.Minute)
+
+	c.PayrollCurrency = os.Getenv("PAYROLL_CURRENCY")
+	if c.PayrollCurrency == "" {
+		c.PayrollCurrency = "IDR"
+	}
+	roundingPolicies, err := money.ParsePolicies(os.Getenv("MONEY_ROUNDING_POLICIES"))
+	if err != nil {
+		log.Fatalf("Invalid MONEY_ROUNDING_POLICIES: %v", err)
+	}
+	c.PayrollRounding, err = roundingPolicies.For(c.PayrollCurrency)
+	if err != nil {
+		log.Fatalf("Invalid PAYROLL_CURRENCY: %v", err)
+	}
+
+	c.PayrollNetPayFloor = decimal.Zero
+	if value := os.Getenv("PAYROLL_NET_PAY_FLOOR"); value != "" {
+		c.PayrollNetPayFloor, err = decimal.NewFromString(value)
+		if err != nil || c.PayrollNetPayFloor.IsNegative() {
+			log.Fatalf("Invalid PAYROLL_NET_PAY_FLOOR: %s", value)
+		}
+	}
+
+	c.PayrollPreviewOvertimeWarningHours = getIntEnv("PAYROLL_PREVIEW_OVERTIME_WARNING_HOURS", 40)
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid number for %s: %v", key, err)
+	}
+	return number
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid duration for %s: %v", key, err)
+	}
+	return duration
 }
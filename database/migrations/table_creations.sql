@@ -1,5 +1,5 @@
-CREATE TYPE user_role AS ENUM ('employee', 'admin');
-CREATE TYPE payroll_periods_status AS ENUM ('open', 'closed');
+CREATE TYPE user_role AS ENUM ('employee', 'admin', 'hr', 'finance', 'auditor', 'manager');
+CREATE TYPE payroll_periods_status AS ENUM ('open', 'locked', 'calculated', 'approved', 'paid', 'archived');
 
 -- public.audit_logs definition
 
@@ -15,6 +15,7 @@ CREATE TABLE public.audit_logs (
 	"action" varchar(255) NOT NULL,
 	"target" varchar(255) NOT NULL,
 	payload jsonb NULL,
+	subject_user_id int8 NULL,
 	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
 	created_by varchar(255) NOT NULL,
 	CONSTRAINT audit_logs_pkey PRIMARY KEY (id)
@@ -38,7 +39,10 @@ CREATE TABLE public.payroll_periods (
 	updated_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
 	updated_by varchar(255) NOT NULL,
 	CONSTRAINT payroll_periods_period_start_period_end_key UNIQUE (period_start, period_end),
-	CONSTRAINT payroll_periods_pkey PRIMARY KEY (id)
+	CONSTRAINT payroll_periods_pkey PRIMARY KEY (id),
+	CONSTRAINT payroll_periods_period_end_check CHECK (period_end >= period_start),
+	-- No two periods may share a day.
+	CONSTRAINT payroll_periods_no_overlap EXCLUDE USING gist (daterange(period_start, period_end, '[]') WITH &&)
 );
 
 
@@ -53,7 +57,13 @@ CREATE TABLE public.users (
 	username varchar(255) NOT NULL,
 	"password" text NOT NULL,
 	"role" public."user_role" NOT NULL,
-	CONSTRAINT users_pkey PRIMARY KEY (id)
+	is_active bool DEFAULT true NOT NULL,
+	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	created_by varchar(255) NULL,
+	updated_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	updated_by varchar(255) NULL,
+	CONSTRAINT users_pkey PRIMARY KEY (id),
+	CONSTRAINT users_username_key UNIQUE (username)
 );
 
 
@@ -90,6 +100,8 @@ CREATE TABLE public.employee_overtimes (
 	user_id int4 NOT NULL,
 	"date" date NOT NULL,
 	durations int4 NOT NULL,
+	-- Kept in step with public.holidays by the holiday endpoints.
+	on_holiday bool DEFAULT false NOT NULL,
 	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
 	created_by varchar(255) NOT NULL,
 	updated_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
@@ -122,6 +134,67 @@ CREATE TABLE public.employee_reimbursements (
 );
 
 
+-- public.payroll_policies definition
+
+-- Drop table
+
+-- DROP TABLE public.payroll_policies;
+
+CREATE TABLE public.payroll_policies (
+	id serial4 NOT NULL,
+	"version" int4 NOT NULL,
+	effective_from date NOT NULL,
+	hours_per_day int4 NOT NULL,
+	attendance_hours_rounding varchar(16) DEFAULT 'down' NOT NULL,
+	max_attendance_hours_per_day int4 DEFAULT 0 NOT NULL,
+	overtime_multiplier numeric(4, 2) NOT NULL,
+	-- 0 pays holiday overtime at overtime_multiplier.
+	holiday_overtime_multiplier numeric(4, 2) DEFAULT 0 NOT NULL,
+	overtime_min_hours int4 NOT NULL,
+	overtime_max_hours int4 NOT NULL,
+	description text NULL,
+	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	created_by varchar(255) NOT NULL,
+	CONSTRAINT payroll_policies_pkey PRIMARY KEY (id),
+	CONSTRAINT payroll_policies_version_key UNIQUE (version),
+	CONSTRAINT payroll_policies_attendance_hours_rounding_check CHECK (attendance_hours_rounding IN ('down', 'nearest', 'up'))
+);
+
+-- The rules payslips were calculated with before policies became configurable.
+INSERT INTO public.payroll_policies ("version", effective_from, hours_per_day, attendance_hours_rounding, max_attendance_hours_per_day, overtime_multiplier, overtime_min_hours, overtime_max_hours, description, created_by) VALUES
+	(1, '2000-01-01', 8, 'down', 0, 2, 1, 3, 'Initial policy', 'system');
+
+
+-- public.payroll_runs definition
+
+-- Drop table
+
+-- DROP TABLE public.payroll_runs;
+
+CREATE TABLE public.payroll_runs (
+	id serial4 NOT NULL,
+	payroll_period_id int4 NOT NULL,
+	"version" int4 NOT NULL,
+	payslip_count int4 NOT NULL,
+	total_gross_pay numeric(14, 2) NOT NULL,
+	total_take_home numeric(14, 2) NOT NULL,
+	reason text NULL,
+	request_id varchar(255) NULL,
+	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	created_by varchar(255) NOT NULL,
+	approved_at timestamp NULL,
+	approved_by varchar(255) NULL,
+	superseded_at timestamp NULL,
+	superseded_by varchar(255) NULL,
+	CONSTRAINT payroll_runs_pkey PRIMARY KEY (id),
+	CONSTRAINT payroll_runs_payroll_period_id_version_key UNIQUE (payroll_period_id, version),
+	CONSTRAINT payroll_runs_payroll_period_id_fkey FOREIGN KEY (payroll_period_id) REFERENCES public.payroll_periods(id) ON DELETE CASCADE
+);
+
+-- A period has one current run, runs superseded by a rerun or by reopening the period are kept as older versions.
+CREATE UNIQUE INDEX payroll_runs_payroll_period_id_key ON public.payroll_runs USING btree (payroll_period_id) WHERE superseded_at IS NULL;
+
+
 -- public.payroll_payslips definition
 
 -- Drop table
@@ -132,22 +205,72 @@ CREATE TABLE public.payroll_payslips (
 	id serial4 NOT NULL,
 	user_id int4 NOT NULL,
 	payroll_period_id int4 NOT NULL,
+	payroll_run_id int4 NULL,
 	base_salary numeric(10, 2) NOT NULL,
 	attendance_days int4 NOT NULL,
 	attendance_hours int4 NOT NULL,
 	attendance_pay numeric(10, 2) NOT NULL,
 	overtime_hours int4 NOT NULL,
 	overtime_pay numeric(10, 2) NOT NULL,
+	holiday_overtime_hours int4 DEFAULT 0 NOT NULL,
+	holiday_overtime_pay numeric(10, 2) DEFAULT 0 NOT NULL,
 	reimbursement_total numeric(10, 2) NOT NULL,
+	allowance_total numeric(10, 2) DEFAULT 0 NOT NULL,
+	gross_pay numeric(10, 2) DEFAULT 0 NOT NULL,
+	deduction_total numeric(10, 2) DEFAULT 0 NOT NULL,
+	taxable_income numeric(10, 2) DEFAULT 0 NOT NULL,
+	employee_contribution_total numeric(10, 2) DEFAULT 0 NOT NULL,
+	employer_contribution_total numeric(10, 2) DEFAULT 0 NOT NULL,
+	tax_status varchar(8) NULL,
+	income_tax_method varchar(16) NULL,
+	income_tax_rate numeric(7, 4) DEFAULT 0 NOT NULL,
+	income_tax numeric(10, 2) DEFAULT 0 NOT NULL,
 	total_take_home numeric(10, 2) NOT NULL,
+	proration_factor numeric(5, 4) DEFAULT 1 NOT NULL,
+	salary_segments jsonb NULL,
+	earnings jsonb NULL,
+	deductions jsonb NULL,
+	contributions jsonb NULL,
+	payroll_policy_id int4 NULL,
 	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
 	created_by varchar(255) NOT NULL,
+	superseded_at timestamp NULL,
+	superseded_by varchar(255) NULL,
 	CONSTRAINT payroll_payslips_pkey PRIMARY KEY (id),
-	CONSTRAINT payroll_payslips_user_id_payroll_period_id_key UNIQUE (user_id, payroll_period_id),
 	CONSTRAINT payroll_payslips_payroll_period_id_fkey FOREIGN KEY (payroll_period_id) REFERENCES public.payroll_periods(id) ON DELETE CASCADE,
+	CONSTRAINT payroll_payslips_payroll_policy_id_fkey FOREIGN KEY (payroll_policy_id) REFERENCES public.payroll_policies(id),
+	CONSTRAINT payroll_payslips_payroll_run_id_fkey FOREIGN KEY (payroll_run_id) REFERENCES public.payroll_runs(id) ON DELETE CASCADE,
 	CONSTRAINT payroll_payslips_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
 );
 
+-- One current payslip per user and period, payslips superseded by reopening the period are kept.
+CREATE UNIQUE INDEX payroll_payslips_user_id_payroll_period_id_key ON public.payroll_payslips USING btree (user_id, payroll_period_id) WHERE superseded_at IS NULL;
+
+
+-- public.payslip_lines definition
+
+-- Drop table
+
+-- DROP TABLE public.payslip_lines;
+
+CREATE TABLE public.payslip_lines (
+	id serial4 NOT NULL,
+	payslip_id int4 NOT NULL,
+	"sequence" int4 NOT NULL,
+	code varchar(32) NOT NULL,
+	category varchar(16) NOT NULL,
+	description text NULL,
+	quantity numeric(15, 2) DEFAULT 1 NOT NULL,
+	rate numeric(15, 4) DEFAULT 0 NOT NULL,
+	amount numeric(15, 2) NOT NULL,
+	source_type varchar(64) NULL,
+	source_id int4 NULL,
+	CONSTRAINT payslip_lines_pkey PRIMARY KEY (id),
+	CONSTRAINT payslip_lines_payslip_id_sequence_key UNIQUE (payslip_id, sequence),
+	CONSTRAINT payslip_lines_category_check CHECK (category IN ('earning', 'deduction', 'employer_cost', 'info')),
+	CONSTRAINT payslip_lines_payslip_id_fkey FOREIGN KEY (payslip_id) REFERENCES public.payroll_payslips(id) ON DELETE CASCADE
+);
+CREATE INDEX payslip_lines_source_type_source_id_idx ON public.payslip_lines USING btree (source_type, source_id);
 
 -- public.user_salaries definition
 
@@ -160,8 +283,429 @@ CREATE TABLE public.user_salaries (
 	user_id int4 NOT NULL,
 	amount numeric(10, 2) NOT NULL,
 	effective_from date NOT NULL,
+	reason text NULL,
 	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
 	created_by varchar(255) NULL,
 	CONSTRAINT user_salaries_pkey PRIMARY KEY (id),
 	CONSTRAINT user_salaries_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
-);
\ No newline at end of file
+);
+CREATE INDEX user_salaries_user_id_effective_from_idx ON public.user_salaries USING btree (user_id, effective_from);
+
+-- public.pay_components definition
+
+-- Drop table
+
+-- DROP TABLE public.pay_components;
+
+CREATE TABLE public.pay_components (
+	id serial4 NOT NULL,
+	code varchar(64) NOT NULL,
+	"name" varchar(255) NOT NULL,
+	kind varchar(16) DEFAULT 'earning' NOT NULL,
+	is_active bool DEFAULT true NOT NULL,
+	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	created_by varchar(255) NOT NULL,
+	updated_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	updated_by varchar(255) NOT NULL,
+	CONSTRAINT pay_components_pkey PRIMARY KEY (id),
+	CONSTRAINT pay_components_code_key UNIQUE (code),
+	CONSTRAINT pay_components_kind_check CHECK (kind IN ('earning'))
+);
+
+
+-- public.employee_pay_components definition
+
+-- Drop table
+
+-- DROP TABLE public.employee_pay_components;
+
+CREATE TABLE public.employee_pay_components (
+	id serial4 NOT NULL,
+	user_id int4 NOT NULL,
+	pay_component_id int4 NOT NULL,
+	"method" varchar(32) NOT NULL,
+	amount numeric(10, 2) NOT NULL,
+	effective_from date NOT NULL,
+	effective_to date NULL,
+	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	created_by varchar(255) NOT NULL,
+	updated_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	updated_by varchar(255) NOT NULL,
+	CONSTRAINT employee_pay_components_pkey PRIMARY KEY (id),
+	CONSTRAINT employee_pay_components_method_check CHECK ("method" IN ('fixed', 'per_attendance_day')),
+	CONSTRAINT employee_pay_components_pay_component_id_fkey FOREIGN KEY (pay_component_id) REFERENCES public.pay_components(id),
+	CONSTRAINT employee_pay_components_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+CREATE INDEX employee_pay_components_user_id_effective_from_idx ON public.employee_pay_components USING btree (user_id, effective_from);
+
+-- public.employee_deductions definition
+
+-- Drop table
+
+-- DROP TABLE public.employee_deductions;
+
+CREATE TABLE public.employee_deductions (
+	id serial4 NOT NULL,
+	user_id int4 NOT NULL,
+	"type" varchar(16) NOT NULL,
+	description text NOT NULL,
+	priority int4 DEFAULT 0 NOT NULL,
+	amount numeric(10, 2) NOT NULL,
+	principal numeric(10, 2) DEFAULT 0 NOT NULL,
+	balance numeric(10, 2) DEFAULT 0 NOT NULL,
+	carried_over numeric(10, 2) DEFAULT 0 NOT NULL,
+	effective_from date NOT NULL,
+	effective_to date NULL,
+	status varchar(16) DEFAULT 'active' NOT NULL,
+	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	created_by varchar(255) NOT NULL,
+	updated_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	updated_by varchar(255) NOT NULL,
+	CONSTRAINT employee_deductions_pkey PRIMARY KEY (id),
+	CONSTRAINT employee_deductions_type_check CHECK ("type" IN ('recurring', 'one_off', 'loan')),
+	CONSTRAINT employee_deductions_status_check CHECK (status IN ('active', 'settled', 'cancelled')),
+	CONSTRAINT employee_deductions_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+CREATE INDEX employee_deductions_user_id_status_idx ON public.employee_deductions USING btree (user_id, status);
+
+-- public.statutory_contribution_rates definition
+
+-- Drop table
+
+-- DROP TABLE public.statutory_contribution_rates;
+
+CREATE TABLE public.statutory_contribution_rates (
+	id serial4 NOT NULL,
+	code varchar(32) NOT NULL,
+	"name" varchar(255) NOT NULL,
+	employee_rate numeric(7, 4) NOT NULL,
+	employer_rate numeric(7, 4) NOT NULL,
+	wage_cap numeric(15, 2) DEFAULT 0 NOT NULL,
+	taxable_benefit bool DEFAULT false NOT NULL,
+	tax_deductible bool DEFAULT false NOT NULL,
+	effective_from date NOT NULL,
+	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	created_by varchar(255) NOT NULL,
+	CONSTRAINT statutory_contribution_rates_pkey PRIMARY KEY (id),
+	CONSTRAINT statutory_contribution_rates_code_effective_from_key UNIQUE (code, effective_from)
+);
+
+-- BPJS Kesehatan and Ketenagakerjaan, a wage_cap of 0 means uncapped. JKK is the lowest risk class.
+INSERT INTO public.statutory_contribution_rates (code, "name", employee_rate, employer_rate, wage_cap, taxable_benefit, tax_deductible, effective_from, created_by) VALUES
+	('BPJS_KES', 'BPJS Kesehatan', 0.01, 0.04, 12000000, true, false, '2024-01-01', 'system'),
+	('JHT', 'Jaminan Hari Tua', 0.02, 0.037, 0, false, true, '2024-01-01', 'system'),
+	('JP', 'Jaminan Pensiun', 0.01, 0.02, 10042300, false, true, '2024-01-01', 'system'),
+	('JKK', 'Jaminan Kecelakaan Kerja', 0, 0.0024, 0, true, false, '2024-01-01', 'system'),
+	('JKM', 'Jaminan Kematian', 0, 0.003, 0, true, false, '2024-01-01', 'system'),
+	('JP', 'Jaminan Pensiun', 0.01, 0.02, 10547400, false, true, '2025-03-01', 'system');
+
+-- public.pph21_ptkp definition
+
+-- Drop table
+
+-- DROP TABLE public.pph21_ptkp;
+
+CREATE TABLE public.pph21_ptkp (
+	id serial4 NOT NULL,
+	tax_status varchar(8) NOT NULL,
+	annual_amount numeric(15, 2) NOT NULL,
+	ter_category varchar(1) NOT NULL,
+	effective_from date NOT NULL,
+	CONSTRAINT pph21_ptkp_pkey PRIMARY KEY (id),
+	CONSTRAINT pph21_ptkp_tax_status_effective_from_key UNIQUE (tax_status, effective_from)
+);
+
+INSERT INTO public.pph21_ptkp (tax_status, annual_amount, ter_category, effective_from) VALUES
+	('TK/0', 54000000, 'A', '2024-01-01'),
+	('TK/1', 58500000, 'A', '2024-01-01'),
+	('TK/2', 63000000, 'B', '2024-01-01'),
+	('TK/3', 67500000, 'B', '2024-01-01'),
+	('K/0', 58500000, 'A', '2024-01-01'),
+	('K/1', 63000000, 'B', '2024-01-01'),
+	('K/2', 67500000, 'B', '2024-01-01'),
+	('K/3', 72000000, 'C', '2024-01-01');
+
+-- public.pph21_ter_rates definition
+
+-- Drop table
+
+-- DROP TABLE public.pph21_ter_rates;
+
+CREATE TABLE public.pph21_ter_rates (
+	id serial4 NOT NULL,
+	ter_category varchar(1) NOT NULL,
+	min_income numeric(15, 2) NOT NULL,
+	rate numeric(7, 4) NOT NULL,
+	effective_from date NOT NULL,
+	CONSTRAINT pph21_ter_rates_pkey PRIMARY KEY (id),
+	CONSTRAINT pph21_ter_rates_ter_category_min_income_effective_from_key UNIQUE (ter_category, min_income, effective_from)
+);
+
+-- Monthly TER brackets of PP 58/2023, min_income is inclusive.
+INSERT INTO public.pph21_ter_rates (ter_category, min_income, rate, effective_from) VALUES
+	('A', 0, 0, '2024-01-01'),
+	('A', 5400001, 0.0025, '2024-01-01'),
+	('A', 5650001, 0.005, '2024-01-01'),
+	('A', 5950001, 0.0075, '2024-01-01'),
+	('A', 6300001, 0.01, '2024-01-01'),
+	('A', 6750001, 0.0125, '2024-01-01'),
+	('A', 7500001, 0.015, '2024-01-01'),
+	('A', 8550001, 0.0175, '2024-01-01'),
+	('A', 9650001, 0.02, '2024-01-01'),
+	('A', 10050001, 0.0225, '2024-01-01'),
+	('A', 10350001, 0.025, '2024-01-01'),
+	('A', 10700001, 0.03, '2024-01-01'),
+	('A', 11050001, 0.035, '2024-01-01'),
+	('A', 11600001, 0.04, '2024-01-01'),
+	('A', 12500001, 0.05, '2024-01-01'),
+	('A', 13750001, 0.06, '2024-01-01'),
+	('A', 15100001, 0.07, '2024-01-01'),
+	('A', 16950001, 0.08, '2024-01-01'),
+	('A', 19750001, 0.09, '2024-01-01'),
+	('A', 24150001, 0.1, '2024-01-01'),
+	('A', 26450001, 0.11, '2024-01-01'),
+	('A', 28000001, 0.12, '2024-01-01'),
+	('A', 30050001, 0.13, '2024-01-01'),
+	('A', 32400001, 0.14, '2024-01-01'),
+	('A', 35400001, 0.15, '2024-01-01'),
+	('A', 39100001, 0.16, '2024-01-01'),
+	('A', 43850001, 0.17, '2024-01-01'),
+	('A', 47800001, 0.18, '2024-01-01'),
+	('A', 51400001, 0.19, '2024-01-01'),
+	('A', 56300001, 0.2, '2024-01-01'),
+	('A', 62200001, 0.21, '2024-01-01'),
+	('A', 68600001, 0.22, '2024-01-01'),
+	('A', 77500001, 0.23, '2024-01-01'),
+	('A', 89000001, 0.24, '2024-01-01'),
+	('A', 103000001, 0.25, '2024-01-01'),
+	('A', 125000001, 0.26, '2024-01-01'),
+	('A', 157000001, 0.27, '2024-01-01'),
+	('A', 206000001, 0.28, '2024-01-01'),
+	('A', 337000001, 0.29, '2024-01-01'),
+	('A', 454000001, 0.3, '2024-01-01'),
+	('A', 550000001, 0.31, '2024-01-01'),
+	('A', 695000001, 0.32, '2024-01-01'),
+	('A', 910000001, 0.33, '2024-01-01'),
+	('A', 1400000001, 0.34, '2024-01-01'),
+	('B', 0, 0, '2024-01-01'),
+	('B', 6200001, 0.0025, '2024-01-01'),
+	('B', 6500001, 0.005, '2024-01-01'),
+	('B', 6850001, 0.0075, '2024-01-01'),
+	('B', 7300001, 0.01, '2024-01-01'),
+	('B', 9200001, 0.015, '2024-01-01'),
+	('B', 10750001, 0.02, '2024-01-01'),
+	('B', 11250001, 0.025, '2024-01-01'),
+	('B', 11600001, 0.03, '2024-01-01'),
+	('B', 12600001, 0.04, '2024-01-01'),
+	('B', 13600001, 0.05, '2024-01-01'),
+	('B', 14950001, 0.06, '2024-01-01'),
+	('B', 16400001, 0.07, '2024-01-01'),
+	('B', 18450001, 0.08, '2024-01-01'),
+	('B', 21850001, 0.09, '2024-01-01'),
+	('B', 26000001, 0.1, '2024-01-01'),
+	('B', 27700001, 0.11, '2024-01-01'),
+	('B', 29350001, 0.12, '2024-01-01'),
+	('B', 31450001, 0.13, '2024-01-01'),
+	('B', 33950001, 0.14, '2024-01-01'),
+	('B', 37100001, 0.15, '2024-01-01'),
+	('B', 41100001, 0.16, '2024-01-01'),
+	('B', 45800001, 0.17, '2024-01-01'),
+	('B', 49500001, 0.18, '2024-01-01'),
+	('B', 53800001, 0.19, '2024-01-01'),
+	('B', 58500001, 0.2, '2024-01-01'),
+	('B', 64000001, 0.21, '2024-01-01'),
+	('B', 71000001, 0.22, '2024-01-01'),
+	('B', 80000001, 0.23, '2024-01-01'),
+	('B', 93000001, 0.24, '2024-01-01'),
+	('B', 109000001, 0.25, '2024-01-01'),
+	('B', 129000001, 0.26, '2024-01-01'),
+	('B', 163000001, 0.27, '2024-01-01'),
+	('B', 211000001, 0.28, '2024-01-01'),
+	('B', 374000001, 0.29, '2024-01-01'),
+	('B', 459000001, 0.3, '2024-01-01'),
+	('B', 555000001, 0.31, '2024-01-01'),
+	('B', 704000001, 0.32, '2024-01-01'),
+	('B', 957000001, 0.33, '2024-01-01'),
+	('B', 1405000001, 0.34, '2024-01-01'),
+	('C', 0, 0, '2024-01-01'),
+	('C', 6600001, 0.0025, '2024-01-01'),
+	('C', 6950001, 0.005, '2024-01-01'),
+	('C', 7350001, 0.0075, '2024-01-01'),
+	('C', 7800001, 0.01, '2024-01-01'),
+	('C', 8850001, 0.0125, '2024-01-01'),
+	('C', 9800001, 0.015, '2024-01-01'),
+	('C', 10950001, 0.0175, '2024-01-01'),
+	('C', 11200001, 0.02, '2024-01-01'),
+	('C', 12050001, 0.03, '2024-01-01'),
+	('C', 12950001, 0.04, '2024-01-01'),
+	('C', 14150001, 0.05, '2024-01-01'),
+	('C', 15550001, 0.06, '2024-01-01'),
+	('C', 17050001, 0.07, '2024-01-01'),
+	('C', 19500001, 0.08, '2024-01-01'),
+	('C', 22700001, 0.09, '2024-01-01'),
+	('C', 26600001, 0.1, '2024-01-01'),
+	('C', 28100001, 0.11, '2024-01-01'),
+	('C', 30100001, 0.12, '2024-01-01'),
+	('C', 32600001, 0.13, '2024-01-01'),
+	('C', 35400001, 0.14, '2024-01-01'),
+	('C', 38900001, 0.15, '2024-01-01'),
+	('C', 43000001, 0.16, '2024-01-01'),
+	('C', 47400001, 0.17, '2024-01-01'),
+	('C', 51200001, 0.18, '2024-01-01'),
+	('C', 55800001, 0.19, '2024-01-01'),
+	('C', 60400001, 0.2, '2024-01-01'),
+	('C', 66700001, 0.21, '2024-01-01'),
+	('C', 74500001, 0.22, '2024-01-01'),
+	('C', 83200001, 0.23, '2024-01-01'),
+	('C', 95600001, 0.24, '2024-01-01'),
+	('C', 110000001, 0.25, '2024-01-01'),
+	('C', 134000001, 0.26, '2024-01-01'),
+	('C', 169000001, 0.27, '2024-01-01'),
+	('C', 221000001, 0.28, '2024-01-01'),
+	('C', 390000001, 0.29, '2024-01-01'),
+	('C', 463000001, 0.3, '2024-01-01'),
+	('C', 561000001, 0.31, '2024-01-01'),
+	('C', 709000001, 0.32, '2024-01-01'),
+	('C', 965000001, 0.33, '2024-01-01'),
+	('C', 1419000001, 0.34, '2024-01-01');
+
+-- public.pph21_progressive_rates definition
+
+-- Drop table
+
+-- DROP TABLE public.pph21_progressive_rates;
+
+CREATE TABLE public.pph21_progressive_rates (
+	id serial4 NOT NULL,
+	min_income numeric(15, 2) NOT NULL,
+	rate numeric(7, 4) NOT NULL,
+	effective_from date NOT NULL,
+	CONSTRAINT pph21_progressive_rates_pkey PRIMARY KEY (id),
+	CONSTRAINT pph21_progressive_rates_min_income_effective_from_key UNIQUE (min_income, effective_from)
+);
+
+-- Yearly brackets of Article 17 of the income tax law, as amended by the HPP law.
+INSERT INTO public.pph21_progressive_rates (min_income, rate, effective_from) VALUES
+	(0, 0.05, '2024-01-01'),
+	(60000000, 0.15, '2024-01-01'),
+	(250000000, 0.25, '2024-01-01'),
+	(500000000, 0.30, '2024-01-01'),
+	(5000000000, 0.35, '2024-01-01');
+
+-- public.pph21_parameters definition
+
+-- Drop table
+
+-- DROP TABLE public.pph21_parameters;
+
+CREATE TABLE public.pph21_parameters (
+	id serial4 NOT NULL,
+	occupational_cost_rate numeric(7, 4) NOT NULL,
+	occupational_cost_monthly_cap numeric(15, 2) NOT NULL,
+	effective_from date NOT NULL,
+	CONSTRAINT pph21_parameters_pkey PRIMARY KEY (id),
+	CONSTRAINT pph21_parameters_effective_from_key UNIQUE (effective_from)
+);
+
+-- Without a row PPh 21 is not withheld.
+INSERT INTO public.pph21_parameters (occupational_cost_rate, occupational_cost_monthly_cap, effective_from) VALUES
+	(0.05, 500000, '2024-01-01');
+
+-- public.holidays definition
+
+-- Drop table
+
+-- DROP TABLE public.holidays;
+
+CREATE TABLE public.holidays (
+	id serial4 NOT NULL,
+	"date" date NOT NULL,
+	"name" varchar(255) NOT NULL,
+	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	created_by varchar(255) NOT NULL,
+	updated_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	updated_by varchar(255) NOT NULL,
+	CONSTRAINT holidays_pkey PRIMARY KEY (id),
+	CONSTRAINT holidays_date_key UNIQUE (date)
+);
+
+-- public.user_sessions definition
+
+-- Drop table
+
+-- DROP TABLE public.user_sessions;
+
+CREATE TABLE public.user_sessions (
+	id varchar(64) NOT NULL,
+	user_id int4 NOT NULL,
+	ip_address varchar(64) NULL,
+	expires_at timestamp NOT NULL,
+	revoked_at timestamp NULL,
+	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	CONSTRAINT user_sessions_pkey PRIMARY KEY (id),
+	CONSTRAINT user_sessions_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+CREATE INDEX user_sessions_user_id_idx ON public.user_sessions USING btree (user_id);
+
+
+
+-- public.employee_profiles definition
+
+-- Drop table
+
+-- DROP TABLE public.employee_profiles;
+
+CREATE TABLE public.employee_profiles (
+	user_id int4 NOT NULL,
+	employee_number varchar(50) NOT NULL,
+	full_name varchar(255) NOT NULL,
+	department varchar(255) NULL,
+	job_title varchar(255) NULL,
+	hire_date date NOT NULL,
+	termination_date date NULL,
+	bank_name varchar(100) NULL,
+	bank_account_number varchar(50) NULL,
+	bank_account_name varchar(255) NULL,
+	tax_id varchar(16) NULL,
+	tax_status varchar(8) DEFAULT 'TK/0' NOT NULL,
+	updated_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	updated_by varchar(255) NULL,
+	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	created_by varchar(255) NULL,
+	CONSTRAINT employee_profiles_pkey PRIMARY KEY (user_id),
+	CONSTRAINT employee_profiles_employee_number_key UNIQUE (employee_number),
+	CONSTRAINT employee_profiles_termination_date_check CHECK (termination_date IS NULL OR termination_date >= hire_date),
+	CONSTRAINT employee_profiles_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+CREATE INDEX employee_profiles_department_idx ON public.employee_profiles USING btree (department);
+
+
+-- public.role_permissions definition
+
+-- Drop table
+
+-- DROP TABLE public.role_permissions;
+
+CREATE TABLE public.role_permissions (
+	"role" public."user_role" NOT NULL,
+	permission varchar(100) NOT NULL,
+	created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+	created_by varchar(255) NOT NULL,
+	CONSTRAINT role_permissions_pkey PRIMARY KEY (role, permission)
+);
+
+-- Admins are granted every permission in code, so they are not listed here.
+INSERT INTO public.role_permissions ("role", permission, created_by) VALUES
+	('hr', 'user.manage', 'system'),
+	('hr', 'payslip.read_all', 'system'),
+	('hr', 'submission.on_behalf', 'system'),
+	('hr', 'employee.manage', 'system'),
+	('hr', 'salary.manage', 'system'),
+	('hr', 'holiday.manage', 'system'),
+	('hr', 'payroll.approve', 'system'),
+	('finance', 'payroll.generate', 'system'),
+	('finance', 'payroll.pay', 'system'),
+	('finance', 'period.close', 'system'),
+	('finance', 'period.manage', 'system'),
+	('finance', 'payslip.read_all', 'system'),
+	('auditor', 'payslip.read_all', 'system');
\ No newline at end of file
@@ -1,42 +1,276 @@
 package cache
 
-import "sync"
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrLoadPanicked = errors.New("cache: load function panicked")
 
 type MemoryCache interface {
 	Get(key string) (interface{}, bool)
 	Set(key string, value interface{})
+	SetWithTTL(key string, value interface{}, ttl time.Duration)
 	Delete(key string)
+
+	// GetOrLoad returns the cached value or calls load to fill it. Concurrent misses for the
+	// same key share a single call to load; errors are returned to every waiter and never cached.
+	GetOrLoad(key string, load func() (interface{}, error)) (interface{}, error)
+	// GetOrLoadWithTTL is GetOrLoad storing the loaded value for the given ttl instead of the default one.
+	GetOrLoadWithTTL(key string, ttl time.Duration, load func() (interface{}, error)) (interface{}, error)
+
+	Stats() Stats
+	Close()
+}
+
+type Config struct {
+	// MaxEntries bounds the cache size, the least recently used entry is evicted first. Zero means unbounded.
+	MaxEntries int
+	// DefaultTTL is used by Set and GetOrLoad. Zero means entries never expire.
+	DefaultTTL time.Duration
+	// CleanupInterval is how often the janitor removes expired entries. Zero disables the janitor,
+	// expired entries are then only dropped when they are read or pushed out by the LRU.
+	CleanupInterval time.Duration
+}
+
+type Stats struct {
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`
+	Expirations uint64 `json:"expirations"`
+	Entries     int    `json:"entries"`
+}
+
+type entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+func (e *entry) isExpired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+type loadCall struct {
+	wg    sync.WaitGroup
+	value interface{}
+	err   error
+	// stale is set when the key is written or deleted while the load is running,
+	// so the loaded value is handed to the waiters but not stored.
+	stale bool
 }
 
 type MemoryCacheImpl struct {
-	cache map[string]interface{}
-	mu    sync.Mutex
+	config Config
+
+	mu      sync.Mutex
+	items   map[string]*list.Element
+	lru     *list.List
+	loading map[string]*loadCall
+	stats   Stats
+
+	stop      chan struct{}
+	closeOnce sync.Once
+	now       func() time.Time
 }
 
-func NewMemoryCache() *MemoryCacheImpl {
-	return &MemoryCacheImpl{
-		cache: make(map[string]interface{}),
+func NewMemoryCache(config Config) *MemoryCacheImpl {
+	m := &MemoryCacheImpl{
+		config:  config,
+		items:   make(map[string]*list.Element),
+		lru:     list.New(),
+		loading: make(map[string]*loadCall),
+		stop:    make(chan struct{}),
+		now:     time.Now,
 	}
+
+	if config.CleanupInterval > 0 {
+		go m.janitor(config.CleanupInterval)
+	}
+
+	return m
 }
 
 func (m *MemoryCacheImpl) Get(key string) (interface{}, bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	value, exists := m.cache[key]
-	return value, exists
+	return m.get(key)
 }
 
 func (m *MemoryCacheImpl) Set(key string, value interface{}) {
+	m.SetWithTTL(key, value, m.config.DefaultTTL)
+}
+
+// SetWithTTL stores the value for the given ttl; a ttl of zero keeps it until it is deleted or evicted.
+func (m *MemoryCacheImpl) SetWithTTL(key string, value interface{}, ttl time.Duration) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.cache[key] = value
+	m.markStale(key)
+	m.set(key, value, ttl)
 }
 
 func (m *MemoryCacheImpl) Delete(key string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	delete(m.cache, key)
+	m.markStale(key)
+	if element, ok := m.items[key]; ok {
+		m.remove(element)
+	}
+}
+
+func (m *MemoryCacheImpl) GetOrLoad(key string, load func() (interface{}, error)) (interface{}, error) {
+	return m.GetOrLoadWithTTL(key, m.config.DefaultTTL, load)
+}
+
+func (m *MemoryCacheImpl) GetOrLoadWithTTL(key string, ttl time.Duration, load func() (interface{}, error)) (interface{}, error) {
+	m.mu.Lock()
+	if value, ok := m.get(key); ok {
+		m.mu.Unlock()
+		return value, nil
+	}
+
+	if call, ok := m.loading[key]; ok {
+		m.mu.Unlock()
+		call.wg.Wait()
+		return call.value, call.err
+	}
+
+	call := &loadCall{}
+	call.wg.Add(1)
+	m.loading[key] = call
+	m.mu.Unlock()
+
+	m.runLoad(key, ttl, call, load)
+
+	return call.value, call.err
+}
+
+func (m *MemoryCacheImpl) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.stats
+	stats.Entries = m.lru.Len()
+	return stats
+}
+
+// Close stops the janitor. The cache stays usable afterwards.
+func (m *MemoryCacheImpl) Close() {
+	m.closeOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+func (m *MemoryCacheImpl) runLoad(key string, ttl time.Duration, call *loadCall, load func() (interface{}, error)) {
+	completed := false
+	defer func() {
+		if !completed {
+			call.err = ErrLoadPanicked
+		}
+
+		m.mu.Lock()
+		if m.loading[key] == call {
+			delete(m.loading, key)
+		}
+		if completed && call.err == nil && !call.stale {
+			m.set(key, call.value, ttl)
+		}
+		m.mu.Unlock()
+
+		call.wg.Done()
+	}()
+
+	call.value, call.err = load()
+	completed = true
+}
+
+// get must be called with the lock held.
+func (m *MemoryCacheImpl) get(key string) (interface{}, bool) {
+	element, ok := m.items[key]
+	if !ok {
+		m.stats.Misses++
+		return nil, false
+	}
+
+	item := element.Value.(*entry)
+	if item.isExpired(m.now()) {
+		m.remove(element)
+		m.stats.Expirations++
+		m.stats.Misses++
+		return nil, false
+	}
+
+	m.lru.MoveToFront(element)
+	m.stats.Hits++
+	return item.value, true
+}
+
+// set must be called with the lock held.
+func (m *MemoryCacheImpl) set(key string, value interface{}, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = m.now().Add(ttl)
+	}
+
+	if element, ok := m.items[key]; ok {
+		item := element.Value.(*entry)
+		item.value = value
+		item.expiresAt = expiresAt
+		m.lru.MoveToFront(element)
+		return
+	}
+
+	m.items[key] = m.lru.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+
+	if m.config.MaxEntries > 0 && m.lru.Len() > m.config.MaxEntries {
+		m.remove(m.lru.Back())
+		m.stats.Evictions++
+	}
+}
+
+func (m *MemoryCacheImpl) remove(element *list.Element) {
+	m.lru.Remove(element)
+	delete(m.items, element.Value.(*entry).key)
+}
+
+// markStale detaches a running load from the key so its result does not overwrite a newer write or delete.
+func (m *MemoryCacheImpl) markStale(key string) {
+	if call, ok := m.loading[key]; ok {
+		call.stale = true
+		delete(m.loading, key)
+	}
+}
+
+func (m *MemoryCacheImpl) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.deleteExpired()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+func (m *MemoryCacheImpl) deleteExpired() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for element := m.lru.Back(); element != nil; {
+		previous := element.Prev()
+		if element.Value.(*entry).isExpired(now) {
+			m.remove(element)
+			m.stats.Expirations++
+		}
+		element = previous
+	}
 }
@@ -0,0 +1,167 @@
+package cache_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eafajri/hr-service.git/internal/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MemoryCache_LRUEviction(t *testing.T) {
+	memoryCache := cache.NewMemoryCache(cache.Config{MaxEntries: 2})
+	defer memoryCache.Close()
+
+	memoryCache.Set("a", 1)
+	memoryCache.Set("b", 2)
+
+	// reading "a" makes "b" the least recently used entry
+	_, ok := memoryCache.Get("a")
+	assert.True(t, ok)
+
+	memoryCache.Set("c", 3)
+
+	_, ok = memoryCache.Get("b")
+	assert.False(t, ok)
+
+	value, ok := memoryCache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	assert.Equal(t, cache.Stats{Hits: 2, Misses: 1, Evictions: 1, Entries: 2}, memoryCache.Stats())
+}
+
+func Test_MemoryCache_TTL(t *testing.T) {
+	memoryCache := cache.NewMemoryCache(cache.Config{DefaultTTL: 20 * time.Millisecond})
+	defer memoryCache.Close()
+
+	memoryCache.Set("default", 1)
+	memoryCache.SetWithTTL("long", 2, time.Hour)
+	memoryCache.GetOrLoad("loaded", func() (interface{}, error) {
+		return 3, nil
+	})
+	memoryCache.GetOrLoadWithTTL("loaded long", time.Hour, func() (interface{}, error) {
+		return 4, nil
+	})
+
+	time.Sleep(40 * time.Millisecond)
+
+	_, ok := memoryCache.Get("default")
+	assert.False(t, ok)
+
+	value, ok := memoryCache.Get("long")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+
+	_, ok = memoryCache.Get("loaded")
+	assert.False(t, ok)
+
+	value, ok = memoryCache.Get("loaded long")
+	assert.True(t, ok)
+	assert.Equal(t, 4, value)
+
+	stats := memoryCache.Stats()
+	assert.Equal(t, uint64(2), stats.Expirations)
+	assert.Equal(t, 2, stats.Entries)
+}
+
+func Test_MemoryCache_Janitor(t *testing.T) {
+	memoryCache := cache.NewMemoryCache(cache.Config{CleanupInterval: 10 * time.Millisecond})
+	defer memoryCache.Close()
+
+	memoryCache.SetWithTTL("a", 1, 5*time.Millisecond)
+	memoryCache.SetWithTTL("b", 2, 0)
+
+	assert.Eventually(t, func() bool {
+		return memoryCache.Stats().Entries == 1
+	}, time.Second, 10*time.Millisecond)
+
+	value, ok := memoryCache.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+}
+
+func Test_MemoryCache_GetOrLoad(t *testing.T) {
+	t.Run("concurrent misses load once", func(t *testing.T) {
+		memoryCache := cache.NewMemoryCache(cache.Config{})
+
+		var loads int32
+		release := make(chan struct{})
+		load := func() (interface{}, error) {
+			atomic.AddInt32(&loads, 1)
+			<-release
+			return "value", nil
+		}
+
+		var wg sync.WaitGroup
+		results := make([]interface{}, 10)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], _ = memoryCache.GetOrLoad("key", load)
+			}(i)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&loads))
+		for _, result := range results {
+			assert.Equal(t, "value", result)
+		}
+
+		value, ok := memoryCache.Get("key")
+		assert.True(t, ok)
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		memoryCache := cache.NewMemoryCache(cache.Config{})
+
+		_, err := memoryCache.GetOrLoad("key", func() (interface{}, error) {
+			return nil, errors.New("error")
+		})
+		assert.EqualError(t, err, "error")
+
+		value, err := memoryCache.GetOrLoad("key", func() (interface{}, error) {
+			return "value", nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("delete during load discards the loaded value", func(t *testing.T) {
+		memoryCache := cache.NewMemoryCache(cache.Config{})
+
+		value, err := memoryCache.GetOrLoad("key", func() (interface{}, error) {
+			memoryCache.Delete("key")
+			return "outdated", nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "outdated", value)
+
+		_, ok := memoryCache.Get("key")
+		assert.False(t, ok)
+	})
+
+	t.Run("panicking load releases waiters", func(t *testing.T) {
+		memoryCache := cache.NewMemoryCache(cache.Config{})
+
+		assert.Panics(t, func() {
+			memoryCache.GetOrLoad("key", func() (interface{}, error) {
+				panic("boom")
+			})
+		})
+
+		value, err := memoryCache.GetOrLoad("key", func() (interface{}, error) {
+			return "value", nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+}
@@ -0,0 +1,105 @@
+package money
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxScale is the number of decimal places the money columns (numeric(10,2)) can store.
+const MaxScale = 2
+
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "half_up"
+	RoundHalfEven RoundingMode = "half_even"
+	RoundDown     RoundingMode = "down"
+)
+
+func (m RoundingMode) IsValid() bool {
+	return m == RoundHalfUp || m == RoundHalfEven || m == RoundDown
+}
+
+// RoundingPolicy is how amounts of a currency are rounded once a calculation is done.
+type RoundingPolicy struct {
+	Scale int32        `json:"scale"`
+	Mode  RoundingMode `json:"mode"`
+}
+
+func (p RoundingPolicy) Round(amount decimal.Decimal) decimal.Decimal {
+	switch p.Mode {
+	case RoundHalfEven:
+		return amount.RoundBank(p.Scale)
+	case RoundDown:
+		return amount.RoundDown(p.Scale)
+	default:
+		return amount.Round(p.Scale)
+	}
+}
+
+// Policies maps ISO 4217 currency codes to their rounding policy.
+type Policies map[string]RoundingPolicy
+
+var DefaultPolicies = Policies{
+	"IDR": {Scale: 0, Mode: RoundHalfUp},
+	"USD": {Scale: 2, Mode: RoundHalfEven},
+	"SGD": {Scale: 2, Mode: RoundHalfUp},
+}
+
+func (p Policies) For(currency string) (RoundingPolicy, error) {
+	policy, ok := p[strings.ToUpper(currency)]
+	if !ok {
+		return RoundingPolicy{}, fmt.Errorf("no rounding policy for currency %q", currency)
+	}
+	return policy, nil
+}
+
+/*
+ParsePolicies reads a comma separated list of CURRENCY=SCALE:MODE entries, for example "IDR=0:half_up,USD=2:half_even",
+on top of the default policies.
+*/
+func ParsePolicies(value string) (Policies, error) {
+	policies := make(Policies, len(DefaultPolicies))
+	for currency, policy := range DefaultPolicies {
+		policies[currency] = policy
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		currency, rule, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rounding policy %q, must be CURRENCY=SCALE:MODE", item)
+		}
+
+		scaleValue, mode, ok := strings.Cut(rule, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid rounding policy %q, must be CURRENCY=SCALE:MODE", item)
+		}
+
+		scale, err := strconv.Atoi(scaleValue)
+		if err != nil || scale < 0 || scale > MaxScale {
+			return nil, fmt.Errorf("invalid rounding scale in %q, must be between 0 and %d", item, MaxScale)
+		}
+
+		policy := RoundingPolicy{Scale: int32(scale), Mode: RoundingMode(mode)}
+		if !policy.Mode.IsValid() {
+			return nil, fmt.Errorf("invalid rounding mode in %q", item)
+		}
+
+		policies[strings.ToUpper(strings.TrimSpace(currency))] = policy
+	}
+
+	return policies, nil
+}
+
+// FitsScale reports whether the amount can be stored without losing decimal places.
+func FitsScale(amount decimal.Decimal) bool {
+	return amount.Equal(amount.Truncate(MaxScale))
+}
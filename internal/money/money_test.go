@@ -0,0 +1,66 @@
+package money_test
+
+import (
+	"testing"
+
+	"github.com/eafajri/hr-service.git/internal/money"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundingPolicy_Round(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy money.RoundingPolicy
+		amount string
+		want   string
+	}{
+		{name: "half up", policy: money.RoundingPolicy{Scale: 0, Mode: money.RoundHalfUp}, amount: "2.5", want: "3"},
+		{name: "half even", policy: money.RoundingPolicy{Scale: 0, Mode: money.RoundHalfEven}, amount: "2.5", want: "2"},
+		{name: "down", policy: money.RoundingPolicy{Scale: 2, Mode: money.RoundDown}, amount: "10.129", want: "10.12"},
+		{name: "half up to cents", policy: money.RoundingPolicy{Scale: 2, Mode: money.RoundHalfUp}, amount: "3.335", want: "3.34"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.Round(decimal.RequireFromString(tt.amount))
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestRoundingPolicy_RoundHasNoDrift(t *testing.T) {
+	// 5,000,000 over 21 working days of 8 hours, rounded once after multiplying back
+	ratePerHour := decimal.NewFromInt(5000000).Div(decimal.NewFromInt(21)).Div(decimal.NewFromInt(8))
+	pay := ratePerHour.Mul(decimal.NewFromInt(21 * 8))
+
+	policy := money.RoundingPolicy{Scale: 0, Mode: money.RoundHalfUp}
+	assert.Equal(t, "5000000", policy.Round(pay).String())
+}
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := money.ParsePolicies("usd=0:down, JPY=0:half_even")
+	assert.NoError(t, err)
+
+	usd, err := policies.For("USD")
+	assert.NoError(t, err)
+	assert.Equal(t, money.RoundingPolicy{Scale: 0, Mode: money.RoundDown}, usd)
+
+	idr, err := policies.For("idr")
+	assert.NoError(t, err)
+	assert.Equal(t, money.DefaultPolicies["IDR"], idr)
+
+	_, err = policies.For("EUR")
+	assert.EqualError(t, err, `no rounding policy for currency "EUR"`)
+
+	_, err = money.ParsePolicies("IDR=3:half_up")
+	assert.EqualError(t, err, `invalid rounding scale in "IDR=3:half_up", must be between 0 and 2`)
+
+	_, err = money.ParsePolicies("IDR=0:ceiling")
+	assert.EqualError(t, err, `invalid rounding mode in "IDR=0:ceiling"`)
+}
+
+func TestFitsScale(t *testing.T) {
+	assert.True(t, money.FitsScale(decimal.RequireFromString("150000.50")))
+	assert.False(t, money.FitsScale(decimal.RequireFromString("150000.505")))
+}
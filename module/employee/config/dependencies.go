@@ -3,6 +3,7 @@ package transport
 import (
 	"log"
 
+	"github.com/eafajri/hr-service.git/config"
 	"github.com/eafajri/hr-service.git/database"
 	"github.com/eafajri/hr-service.git/internal/cache"
 
@@ -15,6 +16,7 @@ type ModuleDependencies struct {
 }
 
 func NewModuleDependencies() *ModuleDependencies {
+	conf := config.GetConfig()
 	db := database.GetDB()
 
 	sqlDB, err := db.DB()
@@ -28,7 +30,11 @@ func NewModuleDependencies() *ModuleDependencies {
 	}
 
 	return &ModuleDependencies{
-		MemoryCache: cache.NewMemoryCache(),
-		Database:    *db,
+		MemoryCache: cache.NewMemoryCache(cache.Config{
+			MaxEntries:      conf.CacheMaxEntries,
+			DefaultTTL:      conf.CacheDefaultTTL,
+			CleanupInterval: conf.CacheCleanupInterval,
+		}),
+		Database: *db,
 	}
 }
@@ -14,6 +14,8 @@ type AuditLog struct {
 	Action    string         `gorm:"action" json:"action"`
 	Target    string         `gorm:"target" json:"target"`
 	Payload   datatypes.JSON `gorm:"type:jsonb" json:"payload"`
-	CreatedBy string         `gorm:"created_by" json:"created_by"`
-	CreatedAt time.Time      `gorm:"created_at" json:"created_at"`
+	// SubjectUserID is the user the action was performed for, when it is not the actor in CreatedBy.
+	SubjectUserID *int64    `gorm:"subject_user_id" json:"subject_user_id,omitempty"`
+	CreatedBy     string    `gorm:"created_by" json:"created_by"`
+	CreatedAt     time.Time `gorm:"created_at" json:"created_at"`
 }
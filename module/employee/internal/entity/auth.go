@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
+type UserSession struct {
+	ID        string     `gorm:"primaryKey" json:"id"`
+	UserID    int64      `gorm:"user_id" json:"user_id"`
+	IPAddress string     `gorm:"ip_address" json:"ip_address"`
+	ExpiresAt time.Time  `gorm:"expires_at" json:"expires_at"`
+	RevokedAt *time.Time `gorm:"revoked_at" json:"revoked_at"`
+	CreatedAt time.Time  `gorm:"created_at" json:"created_at"`
+}
+
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+func (s UserSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// TokenClaims is the payload signed into both access and refresh tokens.
+type TokenClaims struct {
+	UserID    int64     `json:"uid"`
+	Username  string    `json:"username"`
+	Role      UserRole  `json:"role"`
+	SessionID string    `json:"sid"`
+	TokenType TokenType `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	TokenType        string    `json:"token_type"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
@@ -0,0 +1,209 @@
+package entity
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eafajri/hr-service.git/internal/money"
+	"github.com/shopspring/decimal"
+)
+
+type DeductionType string
+
+const (
+	// DeductionRecurring withholds Amount every period between the effective dates.
+	DeductionRecurring DeductionType = "recurring"
+	// DeductionOneOff withholds Amount once, from the period containing the effective date.
+	DeductionOneOff DeductionType = "one_off"
+	// DeductionLoan repays Principal in installments of Amount until the balance is zero.
+	DeductionLoan DeductionType = "loan"
+)
+
+func (t DeductionType) IsValid() bool {
+	return t == DeductionRecurring || t == DeductionOneOff || t == DeductionLoan
+}
+
+// ErrDeductionChanged is returned when a deduction is updated between calculating the payroll and storing it.
+var ErrDeductionChanged = errors.New("a deduction was changed by another request while the payroll was calculated, try again")
+
+type DeductionStatus string
+
+const (
+	DeductionStatusActive    DeductionStatus = "active"
+	DeductionStatusSettled   DeductionStatus = "settled"
+	DeductionStatusCancelled DeductionStatus = "cancelled"
+)
+
+/*
+EmployeeDeduction is withheld from the take home pay of an employee. Balance is what is left to recover of one off
+deductions and loans, CarriedOver is the part of earlier periods that could not be recovered and is due on top of
+the next recurring amount or installment. Deductions with a lower Priority are recovered first.
+*/
+type EmployeeDeduction struct {
+	ID            int64           `gorm:"primaryKey" json:"id"`
+	UserID        int64           `gorm:"user_id" json:"user_id"`
+	Type          DeductionType   `gorm:"type" json:"type"`
+	Description   string          `gorm:"description" json:"description"`
+	Priority      int             `gorm:"priority" json:"priority"`
+	Amount        decimal.Decimal `gorm:"type:numeric(10,2)" json:"amount"`
+	Principal     decimal.Decimal `gorm:"type:numeric(10,2)" json:"principal"`
+	Balance       decimal.Decimal `gorm:"type:numeric(10,2)" json:"balance"`
+	CarriedOver   decimal.Decimal `gorm:"type:numeric(10,2)" json:"carried_over"`
+	EffectiveFrom time.Time       `gorm:"type:date" json:"effective_from"`
+	EffectiveTo   *time.Time      `gorm:"type:date" json:"effective_to"`
+	Status        DeductionStatus `gorm:"status;default:'active'" json:"status"`
+	UpdatedAt     time.Time       `gorm:"updated_at" json:"updated_at"`
+	UpdatedBy     string          `gorm:"updated_by" json:"updated_by"`
+	CreatedAt     time.Time       `gorm:"created_at" json:"created_at"`
+	CreatedBy     string          `gorm:"created_by" json:"created_by"`
+}
+
+func (EmployeeDeduction) TableName() string {
+	return "employee_deductions"
+}
+
+// DueFor returns the amount to withhold in the period, before the net pay floor is applied.
+func (d EmployeeDeduction) DueFor(periodDetail PayrollPeriod) decimal.Decimal {
+	if d.Status != DeductionStatusActive || truncateToDay(d.EffectiveFrom).After(truncateToDay(periodDetail.PeriodEnd)) {
+		return decimal.Zero
+	}
+
+	switch d.Type {
+	case DeductionOneOff:
+		return d.Balance
+	case DeductionLoan:
+		return decimal.Min(d.Amount.Add(d.CarriedOver), d.Balance)
+	default:
+		if d.EffectiveTo != nil && truncateToDay(*d.EffectiveTo).Before(truncateToDay(periodDetail.PeriodStart)) {
+			return d.CarriedOver
+		}
+		return d.Amount.Add(d.CarriedOver)
+	}
+}
+
+// recover books the amount withheld in the period against the due amount and settles the deduction once nothing is left.
+func (d *EmployeeDeduction) recover(periodDetail PayrollPeriod, due decimal.Decimal, recovered decimal.Decimal) {
+	switch d.Type {
+	case DeductionOneOff:
+		d.Balance = d.Balance.Sub(recovered)
+		if !d.Balance.IsPositive() {
+			d.Status = DeductionStatusSettled
+		}
+	case DeductionLoan:
+		d.Balance = d.Balance.Sub(recovered)
+		d.CarriedOver = due.Sub(recovered)
+		if !d.Balance.IsPositive() {
+			d.CarriedOver = decimal.Zero
+			d.Status = DeductionStatusSettled
+		}
+	default:
+		d.CarriedOver = due.Sub(recovered)
+		ended := d.EffectiveTo != nil && !truncateToDay(*d.EffectiveTo).After(truncateToDay(periodDetail.PeriodEnd))
+		if ended && !d.CarriedOver.IsPositive() {
+			d.Status = DeductionStatusSettled
+		}
+	}
+}
+
+/*
+Restore undoes the recovery booked by a payslip deduction, for a payslip that is superseded. Cancelled deductions
+stay cancelled.
+*/
+func (d *EmployeeDeduction) Restore(line PayslipDeduction) {
+	if d.Type == DeductionOneOff || d.Type == DeductionLoan {
+		d.Balance = d.Balance.Add(line.Amount)
+	}
+	if d.Type == DeductionRecurring || d.Type == DeductionLoan {
+		d.CarriedOver = line.CarriedOverBefore
+	}
+	if d.Status == DeductionStatusSettled {
+		d.Status = DeductionStatusActive
+	}
+}
+
+// PayslipDeduction is a deduction line of a payslip, Unrecovered is carried into the next period.
+type PayslipDeduction struct {
+	DeductionID int64           `json:"deduction_id"`
+	Type        DeductionType   `json:"type"`
+	Description string          `json:"description"`
+	Due         decimal.Decimal `json:"due"`
+	Amount      decimal.Decimal `json:"amount"`
+	Unrecovered decimal.Decimal `json:"unrecovered"`
+	// CarriedOverBefore is the carried over amount of the deduction before the payslip, kept to restore it.
+	CarriedOverBefore decimal.Decimal `json:"carried_over_before"`
+}
+
+/*
+ApplyDeductions withholds the deductions from the take home pay in priority order (lowest first, then oldest) and
+never takes it below netPayFloor, whatever does not fit is carried into the next period. What is recovered is
+rounded down to the scale of the rounding policy, so it never exceeds what is due or available and its line needs no
+further rounding. It returns the deductions with their balances updated, to be stored together with the payslip.
+*/
+func (p *PayrollPayslip) ApplyDeductions(periodDetail PayrollPeriod, deductions []EmployeeDeduction, netPayFloor decimal.Decimal, rounding money.RoundingPolicy) []EmployeeDeduction {
+	netPay := p.TotalTakeHome
+	p.DeductionTotal = decimal.Zero
+	roundDown := money.RoundingPolicy{Scale: rounding.Scale, Mode: money.RoundDown}
+
+	ordered := make([]EmployeeDeduction, len(deductions))
+	copy(ordered, deductions)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Priority != ordered[j].Priority {
+			return ordered[i].Priority < ordered[j].Priority
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	available := decimal.Max(netPay.Sub(netPayFloor), decimal.Zero)
+
+	updated := make([]EmployeeDeduction, 0, len(ordered))
+	for _, deduction := range ordered {
+		due := deduction.DueFor(periodDetail)
+		if !due.IsPositive() {
+			continue
+		}
+
+		recovered := roundDown.Round(decimal.Min(due, available))
+		available = available.Sub(recovered)
+		carriedOverBefore := deduction.CarriedOver
+		deduction.recover(periodDetail, due, recovered)
+
+		p.Deductions = append(p.Deductions, PayslipDeduction{
+			DeductionID:       deduction.ID,
+			Type:              deduction.Type,
+			Description:       deduction.Description,
+			Due:               due,
+			Amount:            recovered,
+			Unrecovered:       due.Sub(recovered),
+			CarriedOverBefore: carriedOverBefore,
+		})
+		p.DeductionTotal = p.DeductionTotal.Add(recovered)
+		if recovered.IsPositive() {
+			p.addLine(rounding, PayslipLine{
+				Code:        strings.ToUpper(string(deduction.Type)),
+				Category:    PayslipLineDeduction,
+				Description: deduction.Description,
+				Quantity:    decimal.NewFromInt(1),
+				Rate:        recovered,
+				Amount:      recovered,
+				SourceType:  EmployeeDeduction{}.TableName(),
+				SourceID:    &deduction.ID,
+			})
+		}
+		updated = append(updated, deduction)
+	}
+
+	p.TotalTakeHome = p.netPay()
+	return updated
+}
+
+type DeductionRequest struct {
+	Type          DeductionType   `json:"type"`
+	Description   string          `json:"description"`
+	Priority      int             `json:"priority"`
+	Amount        decimal.Decimal `json:"amount"`
+	Principal     decimal.Decimal `json:"principal"`
+	EffectiveFrom string          `json:"effective_from"`
+	EffectiveTo   string          `json:"effective_to"`
+}
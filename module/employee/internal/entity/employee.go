@@ -1,6 +1,10 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 type EmployeeAttendance struct {
 	ID           int64     `gorm:"id" json:"id"`
@@ -23,6 +27,9 @@ type EmployeeOvertime struct {
 	UserID    int64     `gorm:"user_id" json:"user_id"`
 	Date      time.Time `gorm:"date" json:"date"`
 	Durations int       `gorm:"durations" json:"durations"`
+	// OnHoliday marks overtime worked on a public holiday, it is kept in step with the holiday calendar for display,
+	// payroll looks the holidays up itself.
+	OnHoliday bool      `gorm:"on_holiday" json:"on_holiday"`
 	UpdatedAt time.Time `gorm:"updated_at" json:"updated_at"`
 	UpdatedBy string    `gorm:"updated_by" json:"updated_by"`
 	CreatedAt time.Time `gorm:"created_at" json:"created_at"`
@@ -34,15 +41,15 @@ func (EmployeeOvertime) TableName() string {
 }
 
 type EmployeeReimbursement struct {
-	ID          int64     `gorm:"id" json:"id"`
-	UserID      int64     `gorm:"user_id" json:"user_id"`
-	Date        time.Time `gorm:"date" json:"date"`
-	Amount      float64   `gorm:"amount" json:"amount"`
-	Description string    `gorm:"description" json:"description"`
-	UpdatedAt   time.Time `gorm:"updated_at" json:"updated_at"`
-	UpdatedBy   string    `gorm:"updated_by" json:"updated_by"`
-	CreatedAt   time.Time `gorm:"created_at" json:"created_at"`
-	CreatedBy   string    `gorm:"created_by" json:"created_by"`
+	ID          int64           `gorm:"id" json:"id"`
+	UserID      int64           `gorm:"user_id" json:"user_id"`
+	Date        time.Time       `gorm:"date" json:"date"`
+	Amount      decimal.Decimal `gorm:"amount" json:"amount"`
+	Description string          `gorm:"description" json:"description"`
+	UpdatedAt   time.Time       `gorm:"updated_at" json:"updated_at"`
+	UpdatedBy   string          `gorm:"updated_by" json:"updated_by"`
+	CreatedAt   time.Time       `gorm:"created_at" json:"created_at"`
+	CreatedBy   string          `gorm:"created_by" json:"created_by"`
 }
 
 func (EmployeeReimbursement) TableName() string {
@@ -0,0 +1,58 @@
+package entity
+
+import "time"
+
+type EmployeeProfile struct {
+	UserID            int64      `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	EmployeeNumber    string     `gorm:"employee_number" json:"employee_number"`
+	FullName          string     `gorm:"full_name" json:"full_name"`
+	Department        string     `gorm:"department" json:"department"`
+	JobTitle          string     `gorm:"job_title" json:"job_title"`
+	HireDate          time.Time  `gorm:"type:date" json:"hire_date"`
+	TerminationDate   *time.Time `gorm:"type:date" json:"termination_date"`
+	BankName          string     `gorm:"bank_name" json:"bank_name"`
+	BankAccountNumber string     `gorm:"bank_account_number" json:"bank_account_number"`
+	BankAccountName   string     `gorm:"bank_account_name" json:"bank_account_name"`
+	TaxID             string     `gorm:"tax_id" json:"tax_id"`
+	TaxStatus         string     `gorm:"tax_status" json:"tax_status"`
+	UpdatedAt         time.Time  `gorm:"updated_at" json:"updated_at"`
+	UpdatedBy         string     `gorm:"updated_by" json:"updated_by"`
+	CreatedAt         time.Time  `gorm:"created_at" json:"created_at"`
+	CreatedBy         string     `gorm:"created_by" json:"created_by"`
+}
+
+func (EmployeeProfile) TableName() string {
+	return "employee_profiles"
+}
+
+// IsEmployedOn reports whether the date is between the hire date and the termination date (the last employed day).
+func (e EmployeeProfile) IsEmployedOn(date time.Time) bool {
+	return isWithinEmployment(date, &e.HireDate, e.TerminationDate)
+}
+
+type EmployeeProfileRequest struct {
+	UserID            int64  `json:"user_id"`
+	EmployeeNumber    string `json:"employee_number"`
+	FullName          string `json:"full_name"`
+	Department        string `json:"department"`
+	JobTitle          string `json:"job_title"`
+	HireDate          string `json:"hire_date"`
+	TerminationDate   string `json:"termination_date"`
+	BankName          string `json:"bank_name"`
+	BankAccountNumber string `json:"bank_account_number"`
+	BankAccountName   string `json:"bank_account_name"`
+	TaxID             string `json:"tax_id"`
+	TaxStatus         string `json:"tax_status"`
+}
+
+type EmployeeProfileFilter struct {
+	Search     string `query:"search"`
+	Department string `query:"department"`
+	Page       int    `query:"page"`
+	Limit      int    `query:"limit"`
+}
+
+type EmployeeProfileList struct {
+	Profiles   []EmployeeProfile `json:"profiles"`
+	Pagination Pagination        `json:"pagination"`
+}
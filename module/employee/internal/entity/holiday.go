@@ -0,0 +1,56 @@
+package entity
+
+import "time"
+
+// Holiday is a public holiday, it is not a working day and attendance cannot be submitted on it.
+type Holiday struct {
+	ID        int64     `gorm:"primaryKey" json:"id"`
+	Date      time.Time `gorm:"type:date" json:"date"`
+	Name      string    `gorm:"name" json:"name"`
+	UpdatedAt time.Time `gorm:"updated_at" json:"updated_at"`
+	UpdatedBy string    `gorm:"updated_by" json:"updated_by"`
+	CreatedAt time.Time `gorm:"created_at" json:"created_at"`
+	CreatedBy string    `gorm:"created_by" json:"created_by"`
+}
+
+func (Holiday) TableName() string {
+	return "holidays"
+}
+
+// CountWorkingDays counts Monday to Friday between from and to, both inclusive, that are not holidays.
+func CountWorkingDays(from time.Time, to time.Time, holidays []Holiday) int {
+	count := countWeekdays(from, to)
+	seen := make(map[time.Time]bool, len(holidays))
+	for _, holiday := range holidays {
+		day := truncateToDay(holiday.Date)
+		if seen[day] || day.Before(truncateToDay(from)) || day.After(truncateToDay(to)) {
+			continue
+		}
+		seen[day] = true
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			count--
+		}
+	}
+	return count
+}
+
+// holidayDates is the set of days that are holidays.
+func holidayDates(holidays []Holiday) map[time.Time]bool {
+	dates := make(map[time.Time]bool, len(holidays))
+	for _, holiday := range holidays {
+		dates[truncateToDay(holiday.Date)] = true
+	}
+	return dates
+}
+
+type HolidayRequest struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// HolidayImportResult lists the holidays of an import, Created and Updated count the new dates and the renamed ones.
+type HolidayImportResult struct {
+	Created  int       `json:"created"`
+	Updated  int       `json:"updated"`
+	Holidays []Holiday `json:"holidays"`
+}
@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type PayComponentKind string
+
+const (
+	PayComponentKindEarning PayComponentKind = "earning"
+)
+
+func (k PayComponentKind) IsValid() bool {
+	return k == PayComponentKindEarning
+}
+
+type CalculationMethod string
+
+const (
+	// CalculationFixed pays the amount every period, prorated by the weekdays the assignment covers.
+	CalculationFixed CalculationMethod = "fixed"
+	// CalculationPerAttendanceDay pays the amount for every attendance day the assignment covers.
+	CalculationPerAttendanceDay CalculationMethod = "per_attendance_day"
+)
+
+func (m CalculationMethod) IsValid() bool {
+	return m == CalculationFixed || m == CalculationPerAttendanceDay
+}
+
+// PayComponent is an entry of the catalog of pay components (transport allowance, meal allowance, ...).
+type PayComponent struct {
+	ID        int64            `gorm:"primaryKey" json:"id"`
+	Code      string           `gorm:"code" json:"code"`
+	Name      string           `gorm:"name" json:"name"`
+	Kind      PayComponentKind `gorm:"kind" json:"kind"`
+	IsActive  bool             `gorm:"is_active;default:true" json:"is_active"`
+	UpdatedAt time.Time        `gorm:"updated_at" json:"updated_at"`
+	UpdatedBy string           `gorm:"updated_by" json:"updated_by"`
+	CreatedAt time.Time        `gorm:"created_at" json:"created_at"`
+	CreatedBy string           `gorm:"created_by" json:"created_by"`
+}
+
+func (PayComponent) TableName() string {
+	return "pay_components"
+}
+
+// EmployeePayComponent is a recurring pay component of an employee, EffectiveTo is the last day it applies to.
+type EmployeePayComponent struct {
+	ID             int64             `gorm:"primaryKey" json:"id"`
+	UserID         int64             `gorm:"user_id" json:"user_id"`
+	PayComponentID int64             `gorm:"pay_component_id" json:"pay_component_id"`
+	Method         CalculationMethod `gorm:"method" json:"method"`
+	Amount         decimal.Decimal   `gorm:"type:numeric(10,2)" json:"amount"`
+	EffectiveFrom  time.Time         `gorm:"type:date" json:"effective_from"`
+	EffectiveTo    *time.Time        `gorm:"type:date" json:"effective_to"`
+	UpdatedAt      time.Time         `gorm:"updated_at" json:"updated_at"`
+	UpdatedBy      string            `gorm:"updated_by" json:"updated_by"`
+	CreatedAt      time.Time         `gorm:"created_at" json:"created_at"`
+	CreatedBy      string            `gorm:"created_by" json:"created_by"`
+
+	PayComponent PayComponent `gorm:"foreignKey:PayComponentID" json:"pay_component"`
+}
+
+func (EmployeePayComponent) TableName() string {
+	return "employee_pay_components"
+}
+
+// Within returns the part of [start, end] covered by the assignment, ok is false when they do not overlap.
+func (e EmployeePayComponent) Within(start time.Time, end time.Time) (time.Time, time.Time, bool) {
+	start, end = truncateToDay(start), truncateToDay(end)
+	if effectiveFrom := truncateToDay(e.EffectiveFrom); effectiveFrom.After(start) {
+		start = effectiveFrom
+	}
+	if e.EffectiveTo != nil {
+		if effectiveTo := truncateToDay(*e.EffectiveTo); effectiveTo.Before(end) {
+			end = effectiveTo
+		}
+	}
+	return start, end, !start.After(end)
+}
+
+// PayslipEarning is an earning line of a payslip coming from a pay component. Days are the attendance days
+// paid for per attendance day components and the weekdays covered for fixed ones.
+type PayslipEarning struct {
+	Code   string            `json:"code"`
+	Name   string            `json:"name"`
+	Method CalculationMethod `json:"method"`
+	Rate   decimal.Decimal   `json:"rate"`
+	Days   int               `json:"days"`
+	Amount decimal.Decimal   `json:"amount"`
+}
+
+type PayComponentRequest struct {
+	Code     string           `json:"code"`
+	Name     string           `json:"name"`
+	Kind     PayComponentKind `json:"kind"`
+	IsActive *bool            `json:"is_active"`
+}
+
+type PayComponentAssignmentRequest struct {
+	PayComponentID int64             `json:"pay_component_id"`
+	Method         CalculationMethod `json:"method"`
+	Amount         decimal.Decimal   `json:"amount"`
+	EffectiveFrom  string            `json:"effective_from"`
+	EffectiveTo    string            `json:"effective_to"`
+}
+
+type EndPayComponentAssignmentRequest struct {
+	EffectiveTo string `json:"effective_to"`
+}
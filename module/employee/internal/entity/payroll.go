@@ -1,17 +1,95 @@
 package entity
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/eafajri/hr-service.git/internal/money"
+	"github.com/shopspring/decimal"
+)
 
 type PayrollPeriodStatus string
 
+// A period goes through the statuses in this order, see PayrollPeriodTransition.
 const (
-	PayrollStatusOpen   PayrollPeriodStatus = "open"
-	PayrollStatusClosed PayrollPeriodStatus = "closed"
+	PayrollStatusOpen       PayrollPeriodStatus = "open"
+	PayrollStatusLocked     PayrollPeriodStatus = "locked"
+	PayrollStatusCalculated PayrollPeriodStatus = "calculated"
+	PayrollStatusApproved   PayrollPeriodStatus = "approved"
+	PayrollStatusPaid       PayrollPeriodStatus = "paid"
+	PayrollStatusArchived   PayrollPeriodStatus = "archived"
 )
 
 type EmployeeBaseSalary struct {
-	UserID     int64   `json:"user_id"`
-	BaseSalary float64 `json:"base_salary"`
+	UserID     int64           `json:"user_id"`
+	BaseSalary decimal.Decimal `json:"base_salary"`
+	// EffectiveFrom of BaseSalary, when it is after the first employed day of the period the days before are unpaid.
+	EffectiveFrom time.Time `json:"-"`
+
+	// Employment window from the employee profile, nil when the user has no profile or is still employed.
+	HireDate        *time.Time `json:"hire_date,omitempty"`
+	TerminationDate *time.Time `json:"termination_date,omitempty"`
+	TaxStatus       string     `json:"tax_status,omitempty"`
+
+	// SalaryChanges taking effect after the first employed day of the period, ordered by effective date.
+	SalaryChanges []UserSalary `gorm:"-" json:"salary_changes,omitempty"`
+}
+
+// IsEmployedOn reports whether the date falls inside the employment window.
+func (e EmployeeBaseSalary) IsEmployedOn(date time.Time) bool {
+	return isWithinEmployment(date, e.HireDate, e.TerminationDate)
+}
+
+// EmploymentWithin returns the part of [start, end] during which the employee was employed.
+func (e EmployeeBaseSalary) EmploymentWithin(start time.Time, end time.Time) (time.Time, time.Time) {
+	if e.HireDate != nil && e.HireDate.After(start) {
+		start = *e.HireDate
+	}
+	if e.TerminationDate != nil && e.TerminationDate.Before(end) {
+		end = *e.TerminationDate
+	}
+	return start, end
+}
+
+/*
+SalarySegmentsWithin splits the employed part of [start, end] at every salary change, each segment is paid
+with the salary in effect during it. When several changes share an effective date the last one recorded wins.
+Days before the first salary of the employee took effect form a segment without salary.
+Segment working days leave out the holidays, like the working days of the period.
+*/
+func (e EmployeeBaseSalary) SalarySegmentsWithin(start time.Time, end time.Time, holidays []Holiday) []PayslipSalarySegment {
+	from, to := e.EmploymentWithin(start, end)
+	from, to = truncateToDay(from), truncateToDay(to)
+
+	segments := []PayslipSalarySegment{{From: from, To: to, BaseSalary: e.BaseSalary}}
+	if effectiveFrom := truncateToDay(e.EffectiveFrom); effectiveFrom.After(from) {
+		segments[0].BaseSalary = decimal.Zero
+		if !effectiveFrom.After(to) {
+			segments[0].To = effectiveFrom.AddDate(0, 0, -1)
+			segments = append(segments, PayslipSalarySegment{From: effectiveFrom, To: to, BaseSalary: e.BaseSalary})
+		}
+	}
+
+	for _, change := range e.SalaryChanges {
+		effectiveFrom := truncateToDay(change.EffectiveFrom)
+		if !effectiveFrom.After(from) || effectiveFrom.After(to) {
+			continue
+		}
+
+		last := &segments[len(segments)-1]
+		if effectiveFrom.Equal(last.From) {
+			last.BaseSalary = change.Amount
+			continue
+		}
+
+		last.To = effectiveFrom.AddDate(0, 0, -1)
+		segments = append(segments, PayslipSalarySegment{From: effectiveFrom, To: to, BaseSalary: change.Amount})
+	}
+
+	for i := range segments {
+		segments[i].WorkingDays = CountWorkingDays(segments[i].From, segments[i].To, holidays)
+	}
+	return segments
 }
 
 type PayrollPeriod struct {
@@ -31,49 +109,312 @@ func (PayrollPeriod) TableName() string {
 }
 
 type PayrollPayslip struct {
-	ID                 int64     `gorm:"id" json:"id"`
-	UserID             int64     `gorm:"user_id" json:"user_id"`
-	PayrollPeriodID    int64     `gorm:"payroll_period_id" json:"payroll_period_id"`
-	BaseSalary         float64   `gorm:"base_salary" json:"base_salary"`
-	AttendanceDays     int       `gorm:"attendance_days" json:"attendance_days"`
-	AttendanceHours    int       `gorm:"attendance_hours" json:"attendance_hours"`
-	AttendancePay      float64   `gorm:"attendance_pay" json:"attendance_pay"`
-	OvertimeHours      int       `gorm:"overtime_hours" json:"overtime_hours"`
-	OvertimePay        float64   `gorm:"overtime_pay" json:"overtime_pay"`
-	ReimbursementTotal float64   `gorm:"reimbursement_total" json:"reimbursement_total"`
-	TotalTakeHome      float64   `gorm:"total_take_home" json:"total_take_home"`
-	CreatedAt          time.Time `gorm:"created_at" json:"created_at"`
-	CreatedBy          string    `gorm:"created_by" json:"created_by"`
+	ID                        int64           `gorm:"id" json:"id"`
+	UserID                    int64           `gorm:"user_id" json:"user_id"`
+	PayrollPeriodID           int64           `gorm:"payroll_period_id" json:"payroll_period_id"`
+	PayrollRunID              *int64          `gorm:"payroll_run_id" json:"payroll_run_id"`
+	BaseSalary                decimal.Decimal `gorm:"base_salary" json:"base_salary"`
+	AttendanceDays            int             `gorm:"attendance_days" json:"attendance_days"`
+	AttendanceHours           int             `gorm:"attendance_hours" json:"attendance_hours"`
+	AttendancePay             decimal.Decimal `gorm:"attendance_pay" json:"attendance_pay"`
+	OvertimeHours             int             `gorm:"overtime_hours" json:"overtime_hours"`
+	OvertimePay               decimal.Decimal `gorm:"overtime_pay" json:"overtime_pay"`
+	HolidayOvertimeHours      int             `gorm:"holiday_overtime_hours" json:"holiday_overtime_hours"`
+	HolidayOvertimePay        decimal.Decimal `gorm:"holiday_overtime_pay" json:"holiday_overtime_pay"`
+	ReimbursementTotal        decimal.Decimal `gorm:"reimbursement_total" json:"reimbursement_total"`
+	AllowanceTotal            decimal.Decimal `gorm:"allowance_total" json:"allowance_total"`
+	GrossPay                  decimal.Decimal `gorm:"gross_pay" json:"gross_pay"`
+	TaxableIncome             decimal.Decimal `gorm:"taxable_income" json:"taxable_income"`
+	EmployeeContributionTotal decimal.Decimal `gorm:"employee_contribution_total" json:"employee_contribution_total"`
+	EmployerContributionTotal decimal.Decimal `gorm:"employer_contribution_total" json:"employer_contribution_total"`
+	TaxStatus                 string          `gorm:"tax_status" json:"tax_status"`
+	IncomeTaxMethod           string          `gorm:"income_tax_method" json:"income_tax_method"`
+	IncomeTaxRate             decimal.Decimal `gorm:"type:numeric(7,4)" json:"income_tax_rate"`
+	IncomeTax                 decimal.Decimal `gorm:"income_tax" json:"income_tax"`
+	DeductionTotal            decimal.Decimal `gorm:"deduction_total" json:"deduction_total"`
+	TotalTakeHome             decimal.Decimal `gorm:"total_take_home" json:"total_take_home"`
+	ProrationFactor           decimal.Decimal `gorm:"type:numeric(5,4)" json:"proration_factor"`
+	PayrollPolicyID           int64           `gorm:"payroll_policy_id" json:"payroll_policy_id"`
+	CreatedAt                 time.Time       `gorm:"created_at" json:"created_at"`
+	CreatedBy                 string          `gorm:"created_by" json:"created_by"`
+	// SupersededAt is set when the period is reopened, superseded payslips are kept but no longer read.
+	SupersededAt *time.Time `gorm:"superseded_at" json:"superseded_at,omitempty"`
+	SupersededBy string     `gorm:"superseded_by" json:"superseded_by,omitempty"`
+
+	SalarySegments []PayslipSalarySegment `gorm:"serializer:json" json:"salary_segments"`
+	Earnings       []PayslipEarning       `gorm:"serializer:json" json:"earnings"`
+	Contributions  []PayslipContribution  `gorm:"serializer:json" json:"contributions"`
+	Deductions     []PayslipDeduction     `gorm:"serializer:json" json:"deductions"`
+
+	// Lines itemise the payslip, the totals above are sums of them kept for reporting.
+	Lines []PayslipLine `gorm:"foreignKey:PayslipID" json:"lines"`
+
+	// Employee is attached when the payslip is read back, it is not stored with the payslip.
+	Employee *EmployeeProfile `gorm:"-" json:"employee,omitempty"`
 }
 
 func (PayrollPayslip) TableName() string {
 	return "payroll_payslips"
 }
 
-func (p *PayrollPayslip) GeneratePayslip(periodDetail PayrollPeriod, baseSalaryDetail EmployeeBaseSalary, attendanceRecords []EmployeeAttendance, overtimeRecords []EmployeeOvertime, reimbursementRecords []EmployeeReimbursement, createdBy string) {
+// PayslipSalarySegment is the part of a payslip paid with one salary, a period has several when the salary changed in it.
+type PayslipSalarySegment struct {
+	From            time.Time       `json:"from"`
+	To              time.Time       `json:"to"`
+	BaseSalary      decimal.Decimal `json:"base_salary"`
+	WorkingDays     int             `json:"working_days"`
+	AttendanceDays  int             `json:"attendance_days"`
+	AttendanceHours int             `json:"attendance_hours"`
+	AttendancePay   decimal.Decimal `json:"attendance_pay"`
+	OvertimeHours   int             `json:"overtime_hours"`
+	OvertimePay     decimal.Decimal `json:"overtime_pay"`
+	// HolidayOvertimeHours are not part of OvertimeHours, they are paid at the holiday overtime multiplier.
+	HolidayOvertimeHours int             `json:"holiday_overtime_hours"`
+	HolidayOvertimePay   decimal.Decimal `json:"holiday_overtime_pay"`
+}
+
+func (s PayslipSalarySegment) contains(date time.Time) bool {
+	day := truncateToDay(date)
+	return !day.Before(s.From) && !day.After(s.To)
+}
+
+/*
+Records dated outside the employment window are ignored, so attendance pay of an employee hired or
+terminated during the period only covers the days they were employed. ProrationFactor is the share of
+the period's weekdays within the employment window.
+When the salary changed during the period, attendance and overtime are paid at the rates of the salary segment
+they fall in, rates are always the segment's salary spread over the period's working days. BaseSalary is the
+salary in effect at the end of the employed part of the period.
+Every amount is recorded as a line, rounded with the rounding policy when it is added, and the totals are sums of
+the rounded lines: GrossPay is the sum of the earning lines, segments and earnings keep the rounded amount of their line.
+Overtime on holidays is kept apart from the other overtime and paid at the holiday overtime multiplier, as are the
+hours of attendance recorded before its date was declared a holiday. Holidays are the ones given, not the OnHoliday
+flag stored with the overtime, so holidays added after the submission are taken into account.
+Reimbursements are paid as submitted. Working hours, attendance hour rounding and caps and the overtime multipliers
+come from the payroll policy, whose ID is kept on the payslip. Recurring pay components become earning lines,
+and are added to the take home pay as AllowanceTotal. Statutory contributions and income
+tax are withheld afterwards with ApplyStatutory, then deductions with ApplyDeductions.
+*/
+func (p *PayrollPayslip) GeneratePayslip(periodDetail PayrollPeriod, holidays []Holiday, policy PayrollPolicy, rounding money.RoundingPolicy, baseSalaryDetail EmployeeBaseSalary, attendanceRecords []EmployeeAttendance, overtimeRecords []EmployeeOvertime, reimbursementRecords []EmployeeReimbursement, payComponents []EmployeePayComponent, createdBy string) {
 	p.UserID = baseSalaryDetail.UserID
 	p.PayrollPeriodID = periodDetail.ID
-	p.BaseSalary = baseSalaryDetail.BaseSalary
+	p.PayrollPolicyID = policy.ID
+	p.ProrationFactor = prorationFactor(periodDetail, baseSalaryDetail)
+
+	attendanceRecords = filterEmployed(attendanceRecords, baseSalaryDetail, func(record EmployeeAttendance) time.Time { return record.Date })
+	overtimeRecords = filterEmployed(overtimeRecords, baseSalaryDetail, func(record EmployeeOvertime) time.Time { return record.Date })
+	reimbursementRecords = filterEmployed(reimbursementRecords, baseSalaryDetail, func(record EmployeeReimbursement) time.Time { return record.Date })
 
-	ratePerDay := baseSalaryDetail.BaseSalary / float64(periodDetail.WorkingDays)
-	ratePerHour := ratePerDay / 8
+	segments := baseSalaryDetail.SalarySegmentsWithin(periodDetail.PeriodStart, periodDetail.PeriodEnd, holidays)
+	segmentOf := func(date time.Time) *PayslipSalarySegment {
+		for i := range segments {
+			if segments[i].contains(date) {
+				return &segments[i]
+			}
+		}
+		return &segments[len(segments)-1]
+	}
 
-	p.AttendanceDays = len(attendanceRecords)
+	isHoliday := holidayDates(holidays)
 	for _, record := range attendanceRecords {
-		duration := record.CheckOutTime.Sub(record.CheckInTime)
-		p.AttendanceHours += int(duration.Hours())
+		segment := segmentOf(record.Date)
+		// attendance recorded before its date was declared a holiday is work on a holiday
+		if isHoliday[truncateToDay(record.Date)] {
+			segment.HolidayOvertimeHours += policy.AttendanceHours(record.CheckInTime, record.CheckOutTime)
+			continue
+		}
+		segment.AttendanceDays++
+		segment.AttendanceHours += policy.AttendanceHours(record.CheckInTime, record.CheckOutTime)
 	}
-	p.AttendancePay = float64(p.AttendanceHours) * ratePerHour
 
 	for _, record := range overtimeRecords {
-		p.OvertimeHours += record.Durations
+		if isHoliday[truncateToDay(record.Date)] {
+			segmentOf(record.Date).HolidayOvertimeHours += record.Durations
+			continue
+		}
+		segmentOf(record.Date).OvertimeHours += record.Durations
+	}
+
+	p.BaseSalary = segments[len(segments)-1].BaseSalary
+	p.addLine(rounding, PayslipLine{Code: PayslipLineCodeBaseSalary, Category: PayslipLineInfo, Description: "Base salary", Quantity: decimal.NewFromInt(1), Rate: p.BaseSalary, Amount: p.BaseSalary})
+	for i := range segments {
+		segment := &segments[i]
+		hourlyRate := payForHours(segment.BaseSalary, periodDetail.WorkingDays, policy.HoursPerDay, 1, decimal.NewFromInt(1))
+		if segment.AttendanceHours > 0 {
+			segment.AttendancePay = p.addLine(rounding, PayslipLine{
+				Code:        PayslipLineCodeAttendance,
+				Category:    PayslipLineEarning,
+				Description: fmt.Sprintf("Attendance hours from %s to %s", segment.From.Format("2006-01-02"), segment.To.Format("2006-01-02")),
+				Quantity:    decimal.NewFromInt(int64(segment.AttendanceHours)),
+				Rate:        hourlyRate,
+				Amount:      payForHours(segment.BaseSalary, periodDetail.WorkingDays, policy.HoursPerDay, segment.AttendanceHours, decimal.NewFromInt(1)),
+			})
+		}
+		if segment.OvertimeHours > 0 {
+			segment.OvertimePay = p.addLine(rounding, PayslipLine{
+				Code:        PayslipLineCodeOvertime,
+				Category:    PayslipLineEarning,
+				Description: fmt.Sprintf("Overtime hours from %s to %s", segment.From.Format("2006-01-02"), segment.To.Format("2006-01-02")),
+				Quantity:    decimal.NewFromInt(int64(segment.OvertimeHours)),
+				Rate:        hourlyRate.Mul(policy.OvertimeMultiplier),
+				Amount:      payForHours(segment.BaseSalary, periodDetail.WorkingDays, policy.HoursPerDay, segment.OvertimeHours, policy.OvertimeMultiplier),
+			})
+		}
+		if segment.HolidayOvertimeHours > 0 {
+			segment.HolidayOvertimePay = p.addLine(rounding, PayslipLine{
+				Code:        PayslipLineCodeHolidayOvertime,
+				Category:    PayslipLineEarning,
+				Description: fmt.Sprintf("Holiday overtime hours from %s to %s", segment.From.Format("2006-01-02"), segment.To.Format("2006-01-02")),
+				Quantity:    decimal.NewFromInt(int64(segment.HolidayOvertimeHours)),
+				Rate:        hourlyRate.Mul(policy.HolidayOvertimeRate()),
+				Amount:      payForHours(segment.BaseSalary, periodDetail.WorkingDays, policy.HoursPerDay, segment.HolidayOvertimeHours, policy.HolidayOvertimeRate()),
+			})
+		}
+
+		p.AttendanceDays += segment.AttendanceDays
+		p.AttendanceHours += segment.AttendanceHours
+		p.AttendancePay = p.AttendancePay.Add(segment.AttendancePay)
+		p.OvertimeHours += segment.OvertimeHours
+		p.OvertimePay = p.OvertimePay.Add(segment.OvertimePay)
+		p.HolidayOvertimeHours += segment.HolidayOvertimeHours
+		p.HolidayOvertimePay = p.HolidayOvertimePay.Add(segment.HolidayOvertimePay)
 	}
-	p.OvertimePay = float64(p.OvertimeHours) * ratePerHour * 2
+	p.SalarySegments = segments
 
 	for _, record := range reimbursementRecords {
-		p.ReimbursementTotal += record.Amount
+		reimbursed := p.addLine(rounding, PayslipLine{
+			Code:        PayslipLineCodeReimbursement,
+			Category:    PayslipLineEarning,
+			Description: record.Description,
+			Quantity:    decimal.NewFromInt(1),
+			Rate:        record.Amount,
+			Amount:      record.Amount,
+			SourceType:  EmployeeReimbursement{}.TableName(),
+			SourceID:    &record.ID,
+		})
+		p.ReimbursementTotal = p.ReimbursementTotal.Add(reimbursed)
 	}
 
-	p.TotalTakeHome = p.AttendancePay + p.OvertimePay + p.ReimbursementTotal
+	p.addEarnings(periodDetail, rounding, baseSalaryDetail, attendanceRecords, payComponents)
+
+	p.GrossPay = p.LineTotal(PayslipLineEarning)
+	p.TotalTakeHome = p.netPay()
 	p.CreatedBy = createdBy
 }
+
+func (p *PayrollPayslip) addEarnings(periodDetail PayrollPeriod, rounding money.RoundingPolicy, baseSalaryDetail EmployeeBaseSalary, attendanceRecords []EmployeeAttendance, payComponents []EmployeePayComponent) {
+	periodWeekdays := countWeekdays(periodDetail.PeriodStart, periodDetail.PeriodEnd)
+	employedFrom, employedTo := baseSalaryDetail.EmploymentWithin(periodDetail.PeriodStart, periodDetail.PeriodEnd)
+
+	for _, component := range payComponents {
+		from, to, ok := component.Within(employedFrom, employedTo)
+		if !ok {
+			continue
+		}
+
+		earning := PayslipEarning{
+			Code:   component.PayComponent.Code,
+			Name:   component.PayComponent.Name,
+			Method: component.Method,
+			Rate:   component.Amount,
+			Amount: decimal.Zero,
+		}
+
+		switch component.Method {
+		case CalculationPerAttendanceDay:
+			for _, record := range attendanceRecords {
+				day := truncateToDay(record.Date)
+				if !day.Before(from) && !day.After(to) {
+					earning.Days++
+				}
+			}
+			earning.Amount = component.Amount.Mul(decimal.NewFromInt(int64(earning.Days)))
+		default:
+			earning.Days = countWeekdays(from, to)
+			if periodWeekdays > 0 {
+				earning.Amount = component.Amount.Mul(decimal.NewFromInt(int64(earning.Days))).Div(decimal.NewFromInt(int64(periodWeekdays)))
+			}
+		}
+
+		if earning.Days == 0 {
+			continue
+		}
+
+		earning.Amount = p.addLine(rounding, PayslipLine{
+			Code:        earning.Code,
+			Category:    PayslipLineEarning,
+			Description: earning.Name,
+			Quantity:    decimal.NewFromInt(int64(earning.Days)),
+			Rate:        earning.Rate,
+			Amount:      earning.Amount,
+			SourceType:  EmployeePayComponent{}.TableName(),
+			SourceID:    &component.ID,
+		})
+		p.Earnings = append(p.Earnings, earning)
+		p.AllowanceTotal = p.AllowanceTotal.Add(earning.Amount)
+	}
+}
+
+// payForHours is baseSalary / workingDays / hoursPerDay * hours * multiplier, multiplied first so the only division is the last step.
+func payForHours(baseSalary decimal.Decimal, workingDays int, hoursPerDay int, hours int, multiplier decimal.Decimal) decimal.Decimal {
+	if workingDays <= 0 || hoursPerDay <= 0 || hours == 0 {
+		return decimal.Zero
+	}
+
+	return baseSalary.
+		Mul(decimal.NewFromInt(int64(hours))).
+		Mul(multiplier).
+		Div(decimal.NewFromInt(int64(workingDays) * int64(hoursPerDay)))
+}
+
+// prorationFactorScale is the scale of the proration_factor column.
+const prorationFactorScale = 4
+
+func prorationFactor(periodDetail PayrollPeriod, baseSalaryDetail EmployeeBaseSalary) decimal.Decimal {
+	periodWeekdays := countWeekdays(periodDetail.PeriodStart, periodDetail.PeriodEnd)
+	if periodWeekdays == 0 {
+		return decimal.NewFromInt(1)
+	}
+
+	employedFrom, employedTo := baseSalaryDetail.EmploymentWithin(periodDetail.PeriodStart, periodDetail.PeriodEnd)
+	employedWeekdays := decimal.NewFromInt(int64(countWeekdays(employedFrom, employedTo)))
+	return employedWeekdays.DivRound(decimal.NewFromInt(int64(periodWeekdays)), prorationFactorScale)
+}
+
+func filterEmployed[T any](records []T, baseSalaryDetail EmployeeBaseSalary, dateOf func(T) time.Time) []T {
+	filtered := make([]T, 0, len(records))
+	for _, record := range records {
+		if baseSalaryDetail.IsEmployedOn(dateOf(record)) {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+func isWithinEmployment(date time.Time, hireDate *time.Time, terminationDate *time.Time) bool {
+	day := truncateToDay(date)
+	if hireDate != nil && day.Before(truncateToDay(*hireDate)) {
+		return false
+	}
+	if terminationDate != nil && day.After(truncateToDay(*terminationDate)) {
+		return false
+	}
+	return true
+}
+
+// countWeekdays counts Monday to Friday between from and to, both inclusive.
+func countWeekdays(from time.Time, to time.Time) int {
+	from, to = truncateToDay(from), truncateToDay(to)
+
+	count := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			count++
+		}
+	}
+	return count
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
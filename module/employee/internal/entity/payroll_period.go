@@ -0,0 +1,130 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// PayrollPeriodStatuses lists the statuses in the order a period goes through them.
+var PayrollPeriodStatuses = []PayrollPeriodStatus{
+	PayrollStatusOpen,
+	PayrollStatusLocked,
+	PayrollStatusCalculated,
+	PayrollStatusApproved,
+	PayrollStatusPaid,
+	PayrollStatusArchived,
+}
+
+func (s PayrollPeriodStatus) IsValid() bool {
+	return s.stage() >= 0
+}
+
+// IsClosed reports whether the period stopped taking submissions, every status after open.
+func (s PayrollPeriodStatus) IsClosed() bool {
+	return s.stage() > PayrollStatusOpen.stage()
+}
+
+// HasPayslips reports whether the payslips of the period have been calculated.
+func (s PayrollPeriodStatus) HasPayslips() bool {
+	return s.stage() >= PayrollStatusCalculated.stage()
+}
+
+// IsReleased reports whether the payslips of the period are approved, from then on they are final.
+func (s PayrollPeriodStatus) IsReleased() bool {
+	return s.stage() >= PayrollStatusApproved.stage()
+}
+
+/*
+ReviewsPayslips reports whether the user generates or approves payslips. They see the payslips of the current run as
+soon as it is calculated, to review them, everybody else only sees approved runs.
+*/
+func (u UserContext) ReviewsPayslips() bool {
+	return u.HasPermission(PayrollPeriodCalculate.Permission) || u.HasPermission(PayrollPeriodApprove.Permission)
+}
+
+func (s PayrollPeriodStatus) stage() int {
+	for i, status := range PayrollPeriodStatuses {
+		if s == status {
+			return i
+		}
+	}
+	return -1
+}
+
+// PayrollPeriodTransition moves a period from one status to the next, only holders of Permission may make it.
+type PayrollPeriodTransition struct {
+	Action     string
+	From       PayrollPeriodStatus
+	To         PayrollPeriodStatus
+	Permission Permission
+}
+
+var (
+	// PayrollPeriodLock stops the submissions of the period.
+	PayrollPeriodLock = PayrollPeriodTransition{Action: "lock", From: PayrollStatusOpen, To: PayrollStatusLocked, Permission: PermissionPeriodClose}
+	// PayrollPeriodCalculate stores the payslips, in the same transaction as the payslips themselves.
+	PayrollPeriodCalculate = PayrollPeriodTransition{Action: "calculate", From: PayrollStatusLocked, To: PayrollStatusCalculated, Permission: PermissionPayrollGenerate}
+	// PayrollPeriodApprove releases the payslips to the employees.
+	PayrollPeriodApprove = PayrollPeriodTransition{Action: "approve", From: PayrollStatusCalculated, To: PayrollStatusApproved, Permission: PermissionPayrollApprove}
+	PayrollPeriodPay     = PayrollPeriodTransition{Action: "pay", From: PayrollStatusApproved, To: PayrollStatusPaid, Permission: PermissionPayrollPay}
+	PayrollPeriodArchive = PayrollPeriodTransition{Action: "archive", From: PayrollStatusPaid, To: PayrollStatusArchived, Permission: PermissionPeriodManage}
+)
+
+// Guard rejects the transition when the period is not in its From status.
+func (t PayrollPeriodTransition) Guard(period PayrollPeriod) error {
+	if period.Status != t.From {
+		return fmt.Errorf("only %s payroll periods can be %s, payroll period %d is %s", t.From, t.To, period.ID, period.Status)
+	}
+	return nil
+}
+
+// NewPayrollPeriod creates an open period, WorkingDays defaults to the weekdays between start and end that are not holidays.
+func NewPayrollPeriod(start time.Time, end time.Time, workingDays int, holidays []Holiday, createdBy string) PayrollPeriod {
+	if workingDays == 0 {
+		workingDays = CountWorkingDays(start, end, holidays)
+	}
+
+	return PayrollPeriod{
+		PeriodStart: truncateToDay(start),
+		PeriodEnd:   truncateToDay(end),
+		WorkingDays: workingDays,
+		Status:      PayrollStatusOpen,
+		UpdatedBy:   createdBy,
+		CreatedBy:   createdBy,
+	}
+}
+
+/*
+PayrollPeriodsForYear returns the twelve periods ending on the cut-off day of each month of the year, each one
+starting the day after the previous cut-off. A cut-off day the month does not have is its last day, so 31 gives
+calendar months.
+*/
+func PayrollPeriodsForYear(year int, cutOffDay int, holidays []Holiday, createdBy string) []PayrollPeriod {
+	cutOff := func(month time.Month) time.Time {
+		lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		return time.Date(year, month, min(cutOffDay, lastDay), 0, 0, 0, 0, time.UTC)
+	}
+
+	periods := make([]PayrollPeriod, 0, 12)
+	for month := time.January; month <= time.December; month++ {
+		periods = append(periods, NewPayrollPeriod(cutOff(month-1).AddDate(0, 0, 1), cutOff(month), 0, holidays, createdBy))
+	}
+	return periods
+}
+
+type PayrollPeriodRequest struct {
+	PeriodStart string `json:"period_start"`
+	PeriodEnd   string `json:"period_end"`
+	// WorkingDays is optional, the weekdays of the period that are not holidays are used when it is 0.
+	WorkingDays int `json:"working_days"`
+}
+
+type GeneratePayrollPeriodsRequest struct {
+	Year      int `json:"year"`
+	CutOffDay int `json:"cut_off_day"`
+}
+
+// ReopenPayrollPeriodRequest carries the justification recorded in the audit log.
+type ReopenPayrollPeriodRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type HoursRounding string
+
+const (
+	HoursRoundingDown    HoursRounding = "down"
+	HoursRoundingNearest HoursRounding = "nearest"
+	HoursRoundingUp      HoursRounding = "up"
+)
+
+func (r HoursRounding) IsValid() bool {
+	return r == HoursRoundingDown || r == HoursRoundingNearest || r == HoursRoundingUp
+}
+
+/*
+PayrollPolicy holds the rules payslips are calculated with. Policies are never edited, a change is a new version
+with its own effective date, so a payslip can always be recalculated with the version it references.
+*/
+type PayrollPolicy struct {
+	ID                       int64           `gorm:"primaryKey" json:"id"`
+	Version                  int             `gorm:"version" json:"version"`
+	EffectiveFrom            time.Time       `gorm:"type:date" json:"effective_from"`
+	HoursPerDay              int             `gorm:"hours_per_day" json:"hours_per_day"`
+	AttendanceHoursRounding  HoursRounding   `gorm:"attendance_hours_rounding" json:"attendance_hours_rounding"`
+	MaxAttendanceHoursPerDay int             `gorm:"max_attendance_hours_per_day" json:"max_attendance_hours_per_day"`
+	OvertimeMultiplier       decimal.Decimal `gorm:"type:numeric(4,2)" json:"overtime_multiplier"`
+	// HolidayOvertimeMultiplier applies to overtime on public holidays, zero means OvertimeMultiplier.
+	HolidayOvertimeMultiplier decimal.Decimal `gorm:"type:numeric(4,2)" json:"holiday_overtime_multiplier"`
+	OvertimeMinHours          int             `gorm:"overtime_min_hours" json:"overtime_min_hours"`
+	OvertimeMaxHours          int             `gorm:"overtime_max_hours" json:"overtime_max_hours"`
+	Description               string          `gorm:"description" json:"description"`
+	CreatedAt                 time.Time       `gorm:"created_at" json:"created_at"`
+	CreatedBy                 string          `gorm:"created_by" json:"created_by"`
+}
+
+func (PayrollPolicy) TableName() string {
+	return "payroll_policies"
+}
+
+// HolidayOvertimeRate is the multiplier of overtime worked on a public holiday.
+func (p PayrollPolicy) HolidayOvertimeRate() decimal.Decimal {
+	if p.HolidayOvertimeMultiplier.IsZero() {
+		return p.OvertimeMultiplier
+	}
+	return p.HolidayOvertimeMultiplier
+}
+
+// AttendanceHours is the number of hours of an attendance that are paid, zero MaxAttendanceHoursPerDay means no cap.
+func (p PayrollPolicy) AttendanceHours(checkIn time.Time, checkOut time.Time) int {
+	hours := checkOut.Sub(checkIn).Hours()
+
+	var counted int
+	switch p.AttendanceHoursRounding {
+	case HoursRoundingNearest:
+		counted = int(math.Round(hours))
+	case HoursRoundingUp:
+		counted = int(math.Ceil(hours))
+	default:
+		counted = int(hours)
+	}
+
+	if p.MaxAttendanceHoursPerDay > 0 && counted > p.MaxAttendanceHoursPerDay {
+		return p.MaxAttendanceHoursPerDay
+	}
+	return counted
+}
+
+type PayrollPolicyRequest struct {
+	EffectiveFrom             string          `json:"effective_from"`
+	HoursPerDay               int             `json:"hours_per_day"`
+	AttendanceHoursRounding   HoursRounding   `json:"attendance_hours_rounding"`
+	MaxAttendanceHoursPerDay  int             `json:"max_attendance_hours_per_day"`
+	OvertimeMultiplier        decimal.Decimal `json:"overtime_multiplier"`
+	HolidayOvertimeMultiplier decimal.Decimal `json:"holiday_overtime_multiplier"`
+	OvertimeMinHours          int             `json:"overtime_min_hours"`
+	OvertimeMaxHours          int             `json:"overtime_max_hours"`
+	Description               string          `json:"description"`
+}
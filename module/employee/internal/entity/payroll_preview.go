@@ -0,0 +1,66 @@
+package entity
+
+import "github.com/shopspring/decimal"
+
+const (
+	PayrollWarningMissingSalary   = "missing_salary"
+	PayrollWarningNoAttendance    = "no_attendance"
+	PayrollWarningUnusualOvertime = "unusual_overtime"
+)
+
+// PayrollPreview is the payroll of a period calculated on its current data, nothing of it is stored.
+type PayrollPreview struct {
+	Period   PayrollPeriod           `json:"period"`
+	Payslips []PayrollPayslip        `json:"payslips"`
+	Totals   PayrollPreviewTotals    `json:"totals"`
+	Warnings []PayrollPreviewWarning `json:"warnings"`
+}
+
+type PayrollPreviewTotals struct {
+	Headcount                 int             `json:"headcount"`
+	GrossPay                  decimal.Decimal `json:"gross_pay"`
+	EmployeeContributionTotal decimal.Decimal `json:"employee_contribution_total"`
+	EmployerContributionTotal decimal.Decimal `json:"employer_contribution_total"`
+	IncomeTax                 decimal.Decimal `json:"income_tax"`
+	DeductionTotal            decimal.Decimal `json:"deduction_total"`
+	TotalTakeHome             decimal.Decimal `json:"total_take_home"`
+}
+
+// PayrollPreviewWarning points at an employee whose payslip is likely to need a correction before the period is closed.
+type PayrollPreviewWarning struct {
+	UserID  int64  `json:"user_id"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewPayrollPreview adds up the previewed payslips of the period, warnings are added by the caller.
+func NewPayrollPreview(period PayrollPeriod, payslips []PayrollPayslip) PayrollPreview {
+	preview := PayrollPreview{
+		Period:   period,
+		Payslips: payslips,
+		Totals: PayrollPreviewTotals{
+			Headcount:                 len(payslips),
+			GrossPay:                  decimal.Zero,
+			EmployeeContributionTotal: decimal.Zero,
+			EmployerContributionTotal: decimal.Zero,
+			IncomeTax:                 decimal.Zero,
+			DeductionTotal:            decimal.Zero,
+			TotalTakeHome:             decimal.Zero,
+		},
+		Warnings: []PayrollPreviewWarning{},
+	}
+
+	for _, payslip := range payslips {
+		preview.Totals.GrossPay = preview.Totals.GrossPay.Add(payslip.GrossPay)
+		preview.Totals.EmployeeContributionTotal = preview.Totals.EmployeeContributionTotal.Add(payslip.EmployeeContributionTotal)
+		preview.Totals.EmployerContributionTotal = preview.Totals.EmployerContributionTotal.Add(payslip.EmployerContributionTotal)
+		preview.Totals.IncomeTax = preview.Totals.IncomeTax.Add(payslip.IncomeTax)
+		preview.Totals.DeductionTotal = preview.Totals.DeductionTotal.Add(payslip.DeductionTotal)
+		preview.Totals.TotalTakeHome = preview.Totals.TotalTakeHome.Add(payslip.TotalTakeHome)
+	}
+	return preview
+}
+
+func (p *PayrollPreview) Warn(userID int64, code string, message string) {
+	p.Warnings = append(p.Warnings, PayrollPreviewWarning{UserID: userID, Code: code, Message: message})
+}
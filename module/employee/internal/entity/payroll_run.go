@@ -0,0 +1,173 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+/*
+PayrollRun records one generation of the payslips of a period. A period has at most one current run, reopening the
+period or rerunning its payroll supersedes it together with its payslips. Runs of a period are numbered by Version,
+employees only see the payslips of the latest approved one.
+*/
+type PayrollRun struct {
+	ID              int64           `gorm:"primaryKey" json:"id"`
+	PayrollPeriodID int64           `gorm:"payroll_period_id" json:"payroll_period_id"`
+	Version         int             `gorm:"version" json:"version"`
+	PayslipCount    int             `gorm:"payslip_count" json:"payslip_count"`
+	TotalGrossPay   decimal.Decimal `gorm:"total_gross_pay" json:"total_gross_pay"`
+	TotalTakeHome   decimal.Decimal `gorm:"total_take_home" json:"total_take_home"`
+	// Reason is the correction a rerun was made for, empty for the first run of the period.
+	Reason       string     `gorm:"reason" json:"reason,omitempty"`
+	RequestID    string     `gorm:"request_id" json:"request_id"`
+	CreatedAt    time.Time  `gorm:"created_at" json:"created_at"`
+	CreatedBy    string     `gorm:"created_by" json:"created_by"`
+	ApprovedAt   *time.Time `gorm:"approved_at" json:"approved_at,omitempty"`
+	ApprovedBy   string     `gorm:"approved_by" json:"approved_by,omitempty"`
+	SupersededAt *time.Time `gorm:"superseded_at" json:"superseded_at,omitempty"`
+	SupersededBy string     `gorm:"superseded_by" json:"superseded_by,omitempty"`
+}
+
+func (PayrollRun) TableName() string {
+	return "payroll_runs"
+}
+
+func (r PayrollRun) IsApproved() bool {
+	return r.ApprovedAt != nil
+}
+
+// NewPayrollRun summarises the payslips generated for the period.
+func NewPayrollRun(period PayrollPeriod, payslips []PayrollPayslip, userContext UserContext) PayrollRun {
+	run := PayrollRun{
+		PayrollPeriodID: period.ID,
+		PayslipCount:    len(payslips),
+		TotalGrossPay:   decimal.Zero,
+		TotalTakeHome:   decimal.Zero,
+		RequestID:       userContext.RequestID,
+		CreatedBy:       userContext.Username,
+	}
+
+	for _, payslip := range payslips {
+		run.TotalGrossPay = run.TotalGrossPay.Add(payslip.GrossPay)
+		run.TotalTakeHome = run.TotalTakeHome.Add(payslip.TotalTakeHome)
+	}
+	return run
+}
+
+// RerunPayrollRequest carries the correction the payroll is recalculated for, recorded on the new run.
+type RerunPayrollRequest struct {
+	Reason string `json:"reason"`
+}
+
+const (
+	PayslipDiffAdded   = "added"
+	PayslipDiffRemoved = "removed"
+	PayslipDiffChanged = "changed"
+)
+
+// PayrollRunDiff lists the employees whose payslip differs between two runs of the same period.
+type PayrollRunDiff struct {
+	PayrollPeriodID int64         `json:"payroll_period_id"`
+	From            PayrollRun    `json:"from"`
+	To              PayrollRun    `json:"to"`
+	Payslips        []PayslipDiff `json:"payslips"`
+}
+
+// PayslipDiff lists the amounts of an employee's payslip that changed, every amount when it was added or removed.
+type PayslipDiff struct {
+	UserID  int64                 `json:"user_id"`
+	Change  string                `json:"change"`
+	Amounts []PayslipAmountChange `json:"amounts"`
+}
+
+type PayslipAmountChange struct {
+	Amount     string          `json:"amount"`
+	From       decimal.Decimal `json:"from"`
+	To         decimal.Decimal `json:"to"`
+	Difference decimal.Decimal `json:"difference"`
+}
+
+// payslipAmounts are the amounts compared by DiffPayrollRuns, in the order they appear on the payslip.
+var payslipAmounts = []struct {
+	name  string
+	value func(PayrollPayslip) decimal.Decimal
+}{
+	{"base_salary", func(p PayrollPayslip) decimal.Decimal { return p.BaseSalary }},
+	{"attendance_pay", func(p PayrollPayslip) decimal.Decimal { return p.AttendancePay }},
+	{"overtime_pay", func(p PayrollPayslip) decimal.Decimal { return p.OvertimePay }},
+	{"holiday_overtime_pay", func(p PayrollPayslip) decimal.Decimal { return p.HolidayOvertimePay }},
+	{"reimbursement_total", func(p PayrollPayslip) decimal.Decimal { return p.ReimbursementTotal }},
+	{"allowance_total", func(p PayrollPayslip) decimal.Decimal { return p.AllowanceTotal }},
+	{"gross_pay", func(p PayrollPayslip) decimal.Decimal { return p.GrossPay }},
+	{"taxable_income", func(p PayrollPayslip) decimal.Decimal { return p.TaxableIncome }},
+	{"employee_contribution_total", func(p PayrollPayslip) decimal.Decimal { return p.EmployeeContributionTotal }},
+	{"employer_contribution_total", func(p PayrollPayslip) decimal.Decimal { return p.EmployerContributionTotal }},
+	{"income_tax", func(p PayrollPayslip) decimal.Decimal { return p.IncomeTax }},
+	{"deduction_total", func(p PayrollPayslip) decimal.Decimal { return p.DeductionTotal }},
+	{"total_take_home", func(p PayrollPayslip) decimal.Decimal { return p.TotalTakeHome }},
+}
+
+/*
+DiffPayrollRuns compares the payslips of two runs employee by employee. Employees whose amounts are all equal are
+left out, a payslip only in one of the runs is compared against zero amounts. Employees are listed in the order of
+the payslips of the from run, followed by the ones added in the to run.
+*/
+func DiffPayrollRuns(from PayrollRun, fromPayslips []PayrollPayslip, to PayrollRun, toPayslips []PayrollPayslip) PayrollRunDiff {
+	diff := PayrollRunDiff{
+		PayrollPeriodID: to.PayrollPeriodID,
+		From:            from,
+		To:              to,
+		Payslips:        []PayslipDiff{},
+	}
+
+	toByUserID := make(map[int64]PayrollPayslip, len(toPayslips))
+	for _, payslip := range toPayslips {
+		toByUserID[payslip.UserID] = payslip
+	}
+
+	compared := make(map[int64]bool, len(fromPayslips))
+	for _, fromPayslip := range fromPayslips {
+		compared[fromPayslip.UserID] = true
+		toPayslip, ok := toByUserID[fromPayslip.UserID]
+		if !ok {
+			diff.Payslips = append(diff.Payslips, diffPayslips(fromPayslip.UserID, PayslipDiffRemoved, fromPayslip, PayrollPayslip{}))
+			continue
+		}
+
+		payslipDiff := diffPayslips(fromPayslip.UserID, PayslipDiffChanged, fromPayslip, toPayslip)
+		if len(payslipDiff.Amounts) > 0 {
+			diff.Payslips = append(diff.Payslips, payslipDiff)
+		}
+	}
+
+	for _, toPayslip := range toPayslips {
+		if !compared[toPayslip.UserID] {
+			diff.Payslips = append(diff.Payslips, diffPayslips(toPayslip.UserID, PayslipDiffAdded, PayrollPayslip{}, toPayslip))
+		}
+	}
+
+	return diff
+}
+
+func diffPayslips(userID int64, change string, from PayrollPayslip, to PayrollPayslip) PayslipDiff {
+	payslipDiff := PayslipDiff{
+		UserID:  userID,
+		Change:  change,
+		Amounts: []PayslipAmountChange{},
+	}
+
+	for _, amount := range payslipAmounts {
+		fromAmount, toAmount := amount.value(from), amount.value(to)
+		if change == PayslipDiffChanged && fromAmount.Equal(toAmount) {
+			continue
+		}
+		payslipDiff.Amounts = append(payslipDiff.Amounts, PayslipAmountChange{
+			Amount:     amount.name,
+			From:       fromAmount,
+			To:         toAmount,
+			Difference: toAmount.Sub(fromAmount),
+		})
+	}
+	return payslipDiff
+}
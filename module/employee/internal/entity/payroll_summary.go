@@ -0,0 +1,182 @@
+package entity
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eafajri/hr-service.git/internal/money"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	PayrollSummaryByDepartment = "department"
+	PayrollSummaryByRole       = "role"
+
+	// PayrollSummaryUnassigned is the group of the employees without a department or role.
+	PayrollSummaryUnassigned = "unassigned"
+	// PayrollSummaryTotal names the row of all employees in the CSV export.
+	PayrollSummaryTotal = "total"
+)
+
+// PayrollSummary adds up the payslips of a payroll run, for all employees and per group when GroupBy is set.
+type PayrollSummary struct {
+	PayrollPeriodID int64                 `json:"payroll_period_id"`
+	RunVersion      int                   `json:"run_version"`
+	GroupBy         string                `json:"group_by,omitempty"`
+	Total           PayrollSummaryGroup   `json:"total"`
+	Groups          []PayrollSummaryGroup `json:"groups"`
+}
+
+type PayrollSummaryGroup struct {
+	Group              string              `json:"group,omitempty"`
+	Headcount          int                 `json:"headcount"`
+	BaseSalary         PayrollSummaryStats `json:"base_salary"`
+	AttendancePay      PayrollSummaryStats `json:"attendance_pay"`
+	OvertimePay        PayrollSummaryStats `json:"overtime_pay"`
+	ReimbursementTotal PayrollSummaryStats `json:"reimbursement_total"`
+	TotalTakeHome      PayrollSummaryStats `json:"total_take_home"`
+}
+
+type PayrollSummaryStats struct {
+	Total   decimal.Decimal `json:"total"`
+	Average decimal.Decimal `json:"average"`
+	Min     decimal.Decimal `json:"min"`
+	Max     decimal.Decimal `json:"max"`
+}
+
+// payrollSummaryAmounts are the amounts a summary adds up, in the order of the CSV columns.
+var payrollSummaryAmounts = []struct {
+	name  string
+	value func(PayrollPayslip) decimal.Decimal
+	stats func(*PayrollSummaryGroup) *PayrollSummaryStats
+}{
+	{"base_salary", func(p PayrollPayslip) decimal.Decimal { return p.BaseSalary }, func(g *PayrollSummaryGroup) *PayrollSummaryStats { return &g.BaseSalary }},
+	{"attendance_pay", func(p PayrollPayslip) decimal.Decimal { return p.AttendancePay }, func(g *PayrollSummaryGroup) *PayrollSummaryStats { return &g.AttendancePay }},
+	{"overtime_pay", func(p PayrollPayslip) decimal.Decimal { return p.OvertimePay }, func(g *PayrollSummaryGroup) *PayrollSummaryStats { return &g.OvertimePay }},
+	{"reimbursement_total", func(p PayrollPayslip) decimal.Decimal { return p.ReimbursementTotal }, func(g *PayrollSummaryGroup) *PayrollSummaryStats { return &g.ReimbursementTotal }},
+	{"total_take_home", func(p PayrollPayslip) decimal.Decimal { return p.TotalTakeHome }, func(g *PayrollSummaryGroup) *PayrollSummaryStats { return &g.TotalTakeHome }},
+}
+
+/*
+NewPayrollSummary adds up the payslips of the run. When groupBy is set every payslip is also added to the group
+groupOf returns for it, groups are sorted by name. The payslip amounts are already rounded, averages are only
+cut to the scale of the money columns.
+*/
+func NewPayrollSummary(
+	run PayrollRun,
+	payslips []PayrollPayslip,
+	groupBy string,
+	groupOf func(PayrollPayslip) string,
+) PayrollSummary {
+	summary := PayrollSummary{
+		PayrollPeriodID: run.PayrollPeriodID,
+		RunVersion:      run.Version,
+		GroupBy:         groupBy,
+		Total:           newPayrollSummaryGroup(""),
+		Groups:          []PayrollSummaryGroup{},
+	}
+
+	groups := map[string]*PayrollSummaryGroup{}
+	for _, payslip := range payslips {
+		summary.Total.add(payslip)
+		if groupBy == "" {
+			continue
+		}
+
+		name := groupOf(payslip)
+		if groups[name] == nil {
+			group := newPayrollSummaryGroup(name)
+			groups[name] = &group
+		}
+		groups[name].add(payslip)
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	summary.Total.average()
+	for _, name := range names {
+		groups[name].average()
+		summary.Groups = append(summary.Groups, *groups[name])
+	}
+	return summary
+}
+
+func newPayrollSummaryGroup(name string) PayrollSummaryGroup {
+	group := PayrollSummaryGroup{Group: name}
+	for _, amount := range payrollSummaryAmounts {
+		*amount.stats(&group) = PayrollSummaryStats{
+			Total:   decimal.Zero,
+			Average: decimal.Zero,
+			Min:     decimal.Zero,
+			Max:     decimal.Zero,
+		}
+	}
+	return group
+}
+
+func (g *PayrollSummaryGroup) add(payslip PayrollPayslip) {
+	g.Headcount++
+	for _, amount := range payrollSummaryAmounts {
+		stats, value := amount.stats(g), amount.value(payslip)
+		stats.Total = stats.Total.Add(value)
+		if g.Headcount == 1 || value.LessThan(stats.Min) {
+			stats.Min = value
+		}
+		if g.Headcount == 1 || value.GreaterThan(stats.Max) {
+			stats.Max = value
+		}
+	}
+}
+
+func (g *PayrollSummaryGroup) average() {
+	if g.Headcount == 0 {
+		return
+	}
+
+	for _, amount := range payrollSummaryAmounts {
+		stats := amount.stats(g)
+		stats.Average = stats.Total.DivRound(decimal.NewFromInt(int64(g.Headcount)), money.MaxScale)
+	}
+}
+
+// CSVRecords returns the summary as a header and one row per group, followed by the row of all employees.
+func (s PayrollSummary) CSVRecords() [][]string {
+	header := []string{"group", "headcount"}
+	for _, amount := range payrollSummaryAmounts {
+		header = append(header, amount.name+"_total", amount.name+"_average", amount.name+"_min", amount.name+"_max")
+	}
+
+	records := [][]string{header}
+	for _, group := range s.Groups {
+		records = append(records, group.csvRecord(group.Group))
+	}
+	return append(records, s.Total.csvRecord(PayrollSummaryTotal))
+}
+
+func (g PayrollSummaryGroup) csvRecord(name string) []string {
+	record := []string{csvCell(name), strconv.Itoa(g.Headcount)}
+	for _, amount := range payrollSummaryAmounts {
+		stats := amount.stats(&g)
+		record = append(record, csvCell(stats.Total.String()), csvCell(stats.Average.String()), csvCell(stats.Min.String()), csvCell(stats.Max.String()))
+	}
+	return record
+}
+
+/*
+csvCell quotes a cell that a spreadsheet would read as a formula, department and role names are free text. Numbers
+are kept as they are so negative amounts stay numeric.
+*/
+func csvCell(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@", rune(value[0])) {
+		return value
+	}
+	if _, err := decimal.NewFromString(value); err == nil {
+		return value
+	}
+	return "'" + value
+}
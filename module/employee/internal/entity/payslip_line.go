@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"github.com/eafajri/hr-service.git/internal/money"
+	"github.com/shopspring/decimal"
+)
+
+type PayslipLineCategory string
+
+const (
+	// PayslipLineEarning is paid to the employee and counts toward the gross pay.
+	PayslipLineEarning PayslipLineCategory = "earning"
+	// PayslipLineDeduction is withheld from the gross pay.
+	PayslipLineDeduction PayslipLineCategory = "deduction"
+	// PayslipLineEmployerCost is paid by the company on top of the gross pay, such as the employer part of BPJS.
+	PayslipLineEmployerCost PayslipLineCategory = "employer_cost"
+	// PayslipLineInfo is shown on the payslip but never added to a total.
+	PayslipLineInfo PayslipLineCategory = "info"
+)
+
+const (
+	PayslipLineCodeBaseSalary      = "BASE_SALARY"
+	PayslipLineCodeAttendance      = "ATTENDANCE"
+	PayslipLineCodeOvertime        = "OVERTIME"
+	PayslipLineCodeHolidayOvertime = "HOLIDAY_OVERTIME"
+	PayslipLineCodeReimbursement   = "REIMBURSEMENT"
+	PayslipLineCodeTaxableIncome   = "TAXABLE_INCOME"
+	PayslipLineCodeIncomeTax       = "PPH21"
+)
+
+/*
+PayslipLine is one item of a payslip, Amount is Quantity times Rate rounded with the rounding policy of the payroll. SourceType and SourceID point
+at the record the line was calculated from (a reimbursement, a pay component assignment, a deduction) when there is one.
+*/
+type PayslipLine struct {
+	ID          int64               `gorm:"primaryKey" json:"id"`
+	PayslipID   int64               `gorm:"payslip_id" json:"payslip_id"`
+	Sequence    int                 `gorm:"sequence" json:"sequence"`
+	Code        string              `gorm:"code" json:"code"`
+	Category    PayslipLineCategory `gorm:"category" json:"category"`
+	Description string              `gorm:"description" json:"description"`
+	Quantity    decimal.Decimal     `gorm:"type:numeric(15,2)" json:"quantity"`
+	Rate        decimal.Decimal     `gorm:"type:numeric(15,4)" json:"rate"`
+	Amount      decimal.Decimal     `gorm:"type:numeric(15,2)" json:"amount"`
+	SourceType  string              `gorm:"source_type" json:"source_type,omitempty"`
+	SourceID    *int64              `gorm:"source_id" json:"source_id,omitempty"`
+}
+
+func (PayslipLine) TableName() string {
+	return "payslip_lines"
+}
+
+/*
+addLine rounds the amount of the line and adds it to the payslip, the one place payslip amounts are rounded. It
+returns the rounded amount, the totals of the payslip are sums of it so they always add up to the lines.
+*/
+func (p *PayrollPayslip) addLine(rounding money.RoundingPolicy, line PayslipLine) decimal.Decimal {
+	line.Amount = rounding.Round(line.Amount)
+	line.Sequence = len(p.Lines) + 1
+	p.Lines = append(p.Lines, line)
+	return line.Amount
+}
+
+// LineTotal sums the amounts of the lines in the category.
+func (p PayrollPayslip) LineTotal(category PayslipLineCategory) decimal.Decimal {
+	total := decimal.Zero
+	for _, line := range p.Lines {
+		if line.Category == category {
+			total = total.Add(line.Amount)
+		}
+	}
+	return total
+}
+
+// netPay is what is left of the earnings once every deduction line is withheld.
+func (p PayrollPayslip) netPay() decimal.Decimal {
+	return p.LineTotal(PayslipLineEarning).Sub(p.LineTotal(PayslipLineDeduction))
+}
@@ -0,0 +1,66 @@
+package entity
+
+import "time"
+
+type Permission string
+
+const (
+	PermissionPayrollGenerate Permission = "payroll.generate"
+	PermissionPayrollApprove  Permission = "payroll.approve"
+	PermissionPayrollPay      Permission = "payroll.pay"
+	PermissionPeriodClose     Permission = "period.close"
+	PermissionPeriodManage    Permission = "period.manage"
+	PermissionPayslipReadAll  Permission = "payslip.read_all"
+	PermissionUserManage      Permission = "user.manage"
+	PermissionRoleManage      Permission = "role.manage"
+	PermissionEmployeeManage  Permission = "employee.manage"
+	PermissionSalaryManage    Permission = "salary.manage"
+	PermissionPolicyManage    Permission = "policy.manage"
+	PermissionHolidayManage   Permission = "holiday.manage"
+	// PermissionPeriodReopen is not granted to any role by default, reopening supersedes the payslips of the period.
+	PermissionPeriodReopen Permission = "period.reopen"
+	// PermissionSubmitOnBehalf allows submitting attendance, overtime and reimbursements for another user.
+	PermissionSubmitOnBehalf Permission = "submission.on_behalf"
+)
+
+// AllPermissions is the catalog of permissions that can be assigned to a role.
+var AllPermissions = []Permission{
+	PermissionPayrollGenerate,
+	PermissionPayrollApprove,
+	PermissionPayrollPay,
+	PermissionPeriodClose,
+	PermissionPeriodManage,
+	PermissionPeriodReopen,
+	PermissionPayslipReadAll,
+	PermissionUserManage,
+	PermissionRoleManage,
+	PermissionEmployeeManage,
+	PermissionSalaryManage,
+	PermissionPolicyManage,
+	PermissionHolidayManage,
+	PermissionSubmitOnBehalf,
+}
+
+func (p Permission) IsValid() bool {
+	for _, permission := range AllPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+type RolePermission struct {
+	Role       UserRole   `gorm:"role" json:"role"`
+	Permission Permission `gorm:"permission" json:"permission"`
+	CreatedAt  time.Time  `gorm:"created_at" json:"created_at"`
+	CreatedBy  string     `gorm:"created_by" json:"created_by"`
+}
+
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
+
+type RolePermissionRequest struct {
+	Permission Permission `json:"permission"`
+}
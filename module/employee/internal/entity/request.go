@@ -1,5 +1,7 @@
 package entity
 
+import "github.com/shopspring/decimal"
+
 type SubmitAttendanceRequest struct {
 	UserID       int64  `json:"user_id"`
 	Date         string `json:"date"`
@@ -14,8 +16,22 @@ type SubmitOvertimeRequest struct {
 }
 
 type SubmitReimbursementRequest struct {
-	UserID      int64   `json:"user_id"`
-	Date        string  `json:"date"`
-	Amount      float64 `json:"amount"`
-	Description string  `json:"description"`
+	UserID      int64           `json:"user_id"`
+	Date        string          `json:"date"`
+	Amount      decimal.Decimal `json:"amount"`
+	Description string          `json:"description"`
+}
+
+type CreateUserRequest struct {
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	Role     UserRole `json:"role"`
+}
+
+type ResetPasswordRequest struct {
+	NewPassword string `json:"new_password"`
+}
+
+type ChangeRoleRequest struct {
+	Role UserRole `json:"role"`
 }
@@ -9,3 +9,28 @@ type Response struct {
 	Meta Meta        `json:"meta"`
 	Data interface{} `json:"data"`
 }
+
+type Pagination struct {
+	Page  int   `json:"page"`
+	Limit int   `json:"limit"`
+	Total int64 `json:"total"`
+}
+
+// NewPagination applies the default page size and caps the limit so a single
+// request cannot pull an entire table.
+func NewPagination(page int, limit int) Pagination {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return Pagination{Page: page, Limit: limit}
+}
+
+func (p Pagination) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
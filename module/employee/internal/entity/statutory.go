@@ -0,0 +1,292 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/eafajri/hr-service.git/internal/money"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultTaxStatus is the PTKP status of employees whose profile does not have one (single, no dependents).
+const DefaultTaxStatus = "TK/0"
+
+// IsValidTaxStatus reports whether the status is a PTKP status: TK (single) or K (married) with 0 to 3 dependents.
+func IsValidTaxStatus(status string) bool {
+	switch status {
+	case "TK/0", "TK/1", "TK/2", "TK/3", "K/0", "K/1", "K/2", "K/3":
+		return true
+	}
+	return false
+}
+
+const (
+	IncomeTaxMethodTER    = "ter"
+	IncomeTaxMethodAnnual = "annual"
+)
+
+/*
+StatutoryContributionRate is a BPJS program (Kesehatan, JHT, JP, JKK, JKM). Rates are fractions of the wage,
+capped at WageCap when it is set. TaxableBenefit adds the employer part to the income PPh 21 is withheld on,
+TaxDeductible subtracts the employee part from the yearly income in the annual reconciliation.
+*/
+type StatutoryContributionRate struct {
+	ID             int64           `gorm:"primaryKey" json:"id"`
+	Code           string          `gorm:"code" json:"code"`
+	Name           string          `gorm:"name" json:"name"`
+	EmployeeRate   decimal.Decimal `gorm:"type:numeric(7,4)" json:"employee_rate"`
+	EmployerRate   decimal.Decimal `gorm:"type:numeric(7,4)" json:"employer_rate"`
+	WageCap        decimal.Decimal `gorm:"type:numeric(15,2)" json:"wage_cap"`
+	TaxableBenefit bool            `gorm:"taxable_benefit" json:"taxable_benefit"`
+	TaxDeductible  bool            `gorm:"tax_deductible" json:"tax_deductible"`
+	EffectiveFrom  time.Time       `gorm:"type:date" json:"effective_from"`
+	CreatedAt      time.Time       `gorm:"created_at" json:"created_at"`
+	CreatedBy      string          `gorm:"created_by" json:"created_by"`
+}
+
+func (StatutoryContributionRate) TableName() string {
+	return "statutory_contribution_rates"
+}
+
+// PTKPAllowance is the yearly non taxable income of a tax status and the TER category its monthly rates come from.
+type PTKPAllowance struct {
+	ID            int64           `gorm:"primaryKey" json:"id"`
+	TaxStatus     string          `gorm:"tax_status" json:"tax_status"`
+	AnnualAmount  decimal.Decimal `gorm:"type:numeric(15,2)" json:"annual_amount"`
+	TERCategory   string          `gorm:"column:ter_category" json:"ter_category"`
+	EffectiveFrom time.Time       `gorm:"type:date" json:"effective_from"`
+}
+
+func (PTKPAllowance) TableName() string {
+	return "pph21_ptkp"
+}
+
+// TERRate applies to monthly gross income of at least MinIncome in its category.
+type TERRate struct {
+	ID            int64           `gorm:"primaryKey" json:"id"`
+	TERCategory   string          `gorm:"column:ter_category" json:"ter_category"`
+	MinIncome     decimal.Decimal `gorm:"type:numeric(15,2)" json:"min_income"`
+	Rate          decimal.Decimal `gorm:"type:numeric(7,4)" json:"rate"`
+	EffectiveFrom time.Time       `gorm:"type:date" json:"effective_from"`
+}
+
+func (TERRate) TableName() string {
+	return "pph21_ter_rates"
+}
+
+// ProgressiveTaxRate is a yearly income tax bracket, it applies to the part of the taxable income above MinIncome.
+type ProgressiveTaxRate struct {
+	ID            int64           `gorm:"primaryKey" json:"id"`
+	MinIncome     decimal.Decimal `gorm:"type:numeric(15,2)" json:"min_income"`
+	Rate          decimal.Decimal `gorm:"type:numeric(7,4)" json:"rate"`
+	EffectiveFrom time.Time       `gorm:"type:date" json:"effective_from"`
+}
+
+func (ProgressiveTaxRate) TableName() string {
+	return "pph21_progressive_rates"
+}
+
+// IncomeTaxParameters holds the occupational cost (biaya jabatan) deducted in the annual reconciliation.
+type IncomeTaxParameters struct {
+	ID                         int64           `gorm:"primaryKey" json:"id"`
+	OccupationalCostRate       decimal.Decimal `gorm:"type:numeric(7,4)" json:"occupational_cost_rate"`
+	OccupationalCostMonthlyCap decimal.Decimal `gorm:"type:numeric(15,2)" json:"occupational_cost_monthly_cap"`
+	EffectiveFrom              time.Time       `gorm:"type:date" json:"effective_from"`
+}
+
+func (IncomeTaxParameters) TableName() string {
+	return "pph21_parameters"
+}
+
+// StatutoryRates are the contribution and income tax tables in effect on a date, IncomeTax is nil when PPh 21 is not configured.
+type StatutoryRates struct {
+	Contributions    []StatutoryContributionRate `json:"contributions"`
+	PTKP             []PTKPAllowance             `json:"ptkp"`
+	TERRates         []TERRate                   `json:"ter_rates"`
+	ProgressiveRates []ProgressiveTaxRate        `json:"progressive_rates"`
+	IncomeTax        *IncomeTaxParameters        `json:"income_tax"`
+}
+
+func (s StatutoryRates) ptkpOf(taxStatus string) (PTKPAllowance, error) {
+	for _, allowance := range s.PTKP {
+		if allowance.TaxStatus == taxStatus {
+			return allowance, nil
+		}
+	}
+	return PTKPAllowance{}, fmt.Errorf("no PTKP is configured for tax status %s", taxStatus)
+}
+
+func (s StatutoryRates) terRate(category string, income decimal.Decimal) decimal.Decimal {
+	rate, floor := decimal.Zero, decimal.NewFromInt(-1)
+	for _, bracket := range s.TERRates {
+		if bracket.TERCategory == category && !bracket.MinIncome.GreaterThan(income) && bracket.MinIncome.GreaterThan(floor) {
+			rate, floor = bracket.Rate, bracket.MinIncome
+		}
+	}
+	return rate
+}
+
+func (s StatutoryRates) progressiveTax(taxableIncome decimal.Decimal) decimal.Decimal {
+	brackets := make([]ProgressiveTaxRate, len(s.ProgressiveRates))
+	copy(brackets, s.ProgressiveRates)
+	sort.Slice(brackets, func(i, j int) bool { return brackets[i].MinIncome.LessThan(brackets[j].MinIncome) })
+
+	tax := decimal.Zero
+	for i, bracket := range brackets {
+		if !taxableIncome.GreaterThan(bracket.MinIncome) {
+			break
+		}
+		upper := taxableIncome
+		if i+1 < len(brackets) && brackets[i+1].MinIncome.LessThan(upper) {
+			upper = brackets[i+1].MinIncome
+		}
+		tax = tax.Add(upper.Sub(bracket.MinIncome).Mul(bracket.Rate))
+	}
+	return tax
+}
+
+// PayslipContribution is a contribution line of a payslip, Wage is the capped wage the rates were applied to.
+type PayslipContribution struct {
+	Code           string          `json:"code"`
+	Name           string          `json:"name"`
+	Wage           decimal.Decimal `json:"wage"`
+	EmployeeRate   decimal.Decimal `json:"employee_rate"`
+	EmployerRate   decimal.Decimal `json:"employer_rate"`
+	EmployeeAmount decimal.Decimal `json:"employee_amount"`
+	EmployerAmount decimal.Decimal `json:"employer_amount"`
+	TaxDeductible  bool            `json:"tax_deductible"`
+}
+
+// IncomeTaxYearToDate sums the payslips of the tax year before the period being calculated.
+type IncomeTaxYearToDate struct {
+	Periods                 int
+	TaxableIncome           decimal.Decimal
+	DeductibleContributions decimal.Decimal
+	IncomeTax               decimal.Decimal
+}
+
+func NewIncomeTaxYearToDate(payslips []PayrollPayslip) IncomeTaxYearToDate {
+	yearToDate := IncomeTaxYearToDate{}
+	for _, payslip := range payslips {
+		yearToDate.Periods++
+		yearToDate.TaxableIncome = yearToDate.TaxableIncome.Add(payslip.TaxableIncome)
+		yearToDate.IncomeTax = yearToDate.IncomeTax.Add(payslip.IncomeTax)
+		for _, contribution := range payslip.Contributions {
+			if contribution.TaxDeductible {
+				yearToDate.DeductibleContributions = yearToDate.DeductibleContributions.Add(contribution.EmployeeAmount)
+			}
+		}
+	}
+	return yearToDate
+}
+
+/*
+TaxYear is the tax year the payslips of the period count towards: the calendar year of its pay month, the month the
+period ends in. A period from December 10 to January 9 is the first of the next tax year.
+*/
+func (p PayrollPeriod) TaxYear() int {
+	return p.PeriodEnd.Year()
+}
+
+// IsFinalTaxPeriod reports whether the period closes the employee's tax year: the one paid in December, or the month they leave.
+func IsFinalTaxPeriod(periodDetail PayrollPeriod, baseSalaryDetail EmployeeBaseSalary) bool {
+	if periodDetail.PeriodEnd.Month() == time.December {
+		return true
+	}
+	return baseSalaryDetail.TerminationDate != nil && !baseSalaryDetail.TerminationDate.After(periodDetail.PeriodEnd)
+}
+
+/*
+ApplyStatutory withholds the BPJS contributions and PPh 21 from the gross pay. Contributions are taken on the salary
+plus the fixed allowances, up to the cap of each program.
+PPh 21 is withheld on the taxable income, the gross pay without reimbursements plus the employer contributions that
+count as a benefit. Every month but the final one uses the TER rate of the employee's category. The final period
+reconciles the year: the yearly income, less the occupational cost, the deductible contributions and the PTKP and
+rounded down to a thousand, is taxed at the progressive rates and what was withheld earlier in the year is
+subtracted, so the final withholding can be negative when too much was withheld. Contributions and the income tax
+are rounded when their lines are added.
+*/
+func (p *PayrollPayslip) ApplyStatutory(rates StatutoryRates, rounding money.RoundingPolicy, taxStatus string, finalPeriod bool, yearToDate IncomeTaxYearToDate) error {
+	if taxStatus == "" {
+		taxStatus = DefaultTaxStatus
+	}
+	p.TaxStatus = taxStatus
+
+	wage := p.BaseSalary
+	for _, earning := range p.Earnings {
+		if earning.Method == CalculationFixed {
+			wage = wage.Add(earning.Rate)
+		}
+	}
+
+	benefits, deductible := decimal.Zero, decimal.Zero
+	for _, rate := range rates.Contributions {
+		contribution := PayslipContribution{
+			Code:          rate.Code,
+			Name:          rate.Name,
+			Wage:          wage,
+			EmployeeRate:  rate.EmployeeRate,
+			EmployerRate:  rate.EmployerRate,
+			TaxDeductible: rate.TaxDeductible,
+		}
+		if rate.WageCap.IsPositive() && contribution.Wage.GreaterThan(rate.WageCap) {
+			contribution.Wage = rate.WageCap
+		}
+		contribution.EmployeeAmount = contribution.Wage.Mul(rate.EmployeeRate)
+		contribution.EmployerAmount = contribution.Wage.Mul(rate.EmployerRate)
+		if contribution.EmployeeAmount.IsPositive() {
+			contribution.EmployeeAmount = p.addLine(rounding, PayslipLine{Code: rate.Code, Category: PayslipLineDeduction, Description: rate.Name, Quantity: contribution.Wage, Rate: rate.EmployeeRate, Amount: contribution.EmployeeAmount})
+		}
+		if contribution.EmployerAmount.IsPositive() {
+			contribution.EmployerAmount = p.addLine(rounding, PayslipLine{Code: rate.Code, Category: PayslipLineEmployerCost, Description: rate.Name, Quantity: contribution.Wage, Rate: rate.EmployerRate, Amount: contribution.EmployerAmount})
+		}
+		p.Contributions = append(p.Contributions, contribution)
+		p.EmployeeContributionTotal = p.EmployeeContributionTotal.Add(contribution.EmployeeAmount)
+		p.EmployerContributionTotal = p.EmployerContributionTotal.Add(contribution.EmployerAmount)
+		if rate.TaxableBenefit {
+			benefits = benefits.Add(contribution.EmployerAmount)
+		}
+		if rate.TaxDeductible {
+			deductible = deductible.Add(contribution.EmployeeAmount)
+		}
+	}
+
+	p.TaxableIncome = p.AttendancePay.Add(p.OvertimePay).Add(p.HolidayOvertimePay).Add(p.AllowanceTotal).Add(benefits)
+	p.addLine(rounding, PayslipLine{Code: PayslipLineCodeTaxableIncome, Category: PayslipLineInfo, Description: "Taxable income", Quantity: decimal.NewFromInt(1), Rate: p.TaxableIncome, Amount: p.TaxableIncome})
+
+	if rates.IncomeTax != nil {
+		ptkp, err := rates.ptkpOf(taxStatus)
+		if err != nil {
+			return err
+		}
+
+		if !finalPeriod {
+			p.IncomeTaxMethod = IncomeTaxMethodTER
+			p.IncomeTaxRate = rates.terRate(ptkp.TERCategory, p.TaxableIncome)
+			p.IncomeTax = p.TaxableIncome.Mul(p.IncomeTaxRate)
+		} else {
+			yearlyIncome := yearToDate.TaxableIncome.Add(p.TaxableIncome)
+			occupationalCost := decimal.Min(
+				yearlyIncome.Mul(rates.IncomeTax.OccupationalCostRate),
+				rates.IncomeTax.OccupationalCostMonthlyCap.Mul(decimal.NewFromInt(int64(yearToDate.Periods+1))),
+			)
+			netIncome := yearlyIncome.Sub(occupationalCost).Sub(yearToDate.DeductibleContributions).Sub(deductible)
+			thousand := decimal.NewFromInt(1000)
+			taxableIncome := decimal.Max(netIncome.Sub(ptkp.AnnualAmount), decimal.Zero).Div(thousand).Floor().Mul(thousand)
+
+			p.IncomeTaxMethod = IncomeTaxMethodAnnual
+			p.IncomeTax = rates.progressiveTax(taxableIncome).Sub(yearToDate.IncomeTax)
+		}
+
+		incomeTaxLine := PayslipLine{Code: PayslipLineCodeIncomeTax, Category: PayslipLineDeduction, Description: "PPh 21 at the TER rate", Quantity: p.TaxableIncome, Rate: p.IncomeTaxRate, Amount: p.IncomeTax}
+		if finalPeriod {
+			incomeTaxLine.Description = "PPh 21 annual reconciliation"
+			incomeTaxLine.Quantity, incomeTaxLine.Rate = decimal.NewFromInt(1), p.IncomeTax
+		}
+		p.IncomeTax = p.addLine(rounding, incomeTaxLine)
+	}
+
+	p.TotalTakeHome = p.netPay()
+	return nil
+}
@@ -1,41 +1,111 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 type UserRole string
 
 const (
 	RoleEmployee UserRole = "employee"
 	RoleAdmin    UserRole = "admin"
+	RoleHR       UserRole = "hr"
+	RoleFinance  UserRole = "finance"
+	RoleAuditor  UserRole = "auditor"
+	RoleManager  UserRole = "manager"
 )
 
+var AllRoles = []UserRole{
+	RoleEmployee,
+	RoleAdmin,
+	RoleHR,
+	RoleFinance,
+	RoleAuditor,
+	RoleManager,
+}
+
+func (r UserRole) IsValid() bool {
+	for _, role := range AllRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
 type User struct {
-	ID       int64    `gorm:"primaryKey" json:"id"`
-	Username string   `gorm:"username" json:"username"`
-	Password string   `gorm:"password" json:"password"`
-	Role     UserRole `gorm:"role" json:"role"`
+	ID        int64     `gorm:"primaryKey" json:"id"`
+	Username  string    `gorm:"username" json:"username"`
+	Password  string    `gorm:"password" json:"-"`
+	Role      UserRole  `gorm:"role" json:"role"`
+	IsActive  bool      `gorm:"is_active;default:true" json:"is_active"`
+	UpdatedAt time.Time `gorm:"updated_at" json:"updated_at"`
+	UpdatedBy string    `gorm:"updated_by" json:"updated_by"`
+	CreatedAt time.Time `gorm:"created_at" json:"created_at"`
+	CreatedBy string    `gorm:"created_by" json:"created_by"`
 }
 
-func (EmployeeAttendance) User() string {
+func (User) TableName() string {
 	return "users"
 }
 
+type UserFilter struct {
+	Search   string   `query:"search"`
+	Role     UserRole `query:"role"`
+	IsActive *bool    `query:"is_active"`
+	Page     int      `query:"page"`
+	Limit    int      `query:"limit"`
+}
+
+type UserList struct {
+	Users      []User     `json:"users"`
+	Pagination Pagination `json:"pagination"`
+}
+
 type UserSalary struct {
-	ID            int64     `gorm:"primaryKey" json:"id"`
-	UserID        int64     `gorm:"not null;index" json:"user_id"`
-	Amount        float64   `gorm:"type:numeric(10,2);default:0" json:"amount"`
-	EffectiveFrom time.Time `gorm:"autoCreateTime" json:"effective_from"`
-	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+	ID            int64           `gorm:"primaryKey" json:"id"`
+	UserID        int64           `gorm:"not null;index" json:"user_id"`
+	Amount        decimal.Decimal `gorm:"type:numeric(10,2);default:0" json:"amount"`
+	EffectiveFrom time.Time       `gorm:"type:date" json:"effective_from"`
+	Reason        string          `gorm:"reason" json:"reason"`
+	CreatedAt     time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	CreatedBy     string          `gorm:"created_by" json:"created_by"`
 }
 
-func (EmployeeAttendance) UserSalary() string {
+func (UserSalary) TableName() string {
 	return "user_salaries"
 }
 
+type SalaryChangeRequest struct {
+	Amount        decimal.Decimal `json:"amount"`
+	EffectiveFrom string          `json:"effective_from"`
+	Reason        string          `json:"reason"`
+}
+
 type UserContext struct {
 	RequestID string   `json:"request_id"`
 	IPAddress string   `json:"ip_address"`
 	UserID    int64    `json:"user_id"`
 	Username  string   `json:"username"`
 	Role      UserRole `json:"role"`
+	SessionID string   `json:"session_id,omitempty"`
+
+	Permissions []Permission `json:"permissions,omitempty"`
+}
+
+// HasPermission reports whether the user may perform an action guarded by the given permission.
+// Admins are allowed everything so that they can never lock themselves out of role management.
+func (u UserContext) HasPermission(permission Permission) bool {
+	if u.Role == RoleAdmin {
+		return true
+	}
+
+	for _, granted := range u.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
 }
@@ -19,6 +19,11 @@ func NewAuditLogRepository(db *gorm.DB) *AuditLogRepositoryImpl {
 }
 
 func (r *AuditLogRepositoryImpl) Create(log entity.AuditLog, payload any) error {
+	return createAuditLog(r.DB, log, payload)
+}
+
+// createAuditLog lets other repositories write the audit log in their own transaction.
+func createAuditLog(db *gorm.DB, log entity.AuditLog, payload any) error {
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		log.Payload = datatypes.JSON([]byte("{}"))
@@ -26,5 +31,5 @@ func (r *AuditLogRepositoryImpl) Create(log entity.AuditLog, payload any) error
 		log.Payload = datatypes.JSON(payloadBytes)
 	}
 
-	return r.DB.Create(&log).Error
+	return db.Create(&log).Error
 }
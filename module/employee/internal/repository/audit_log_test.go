@@ -61,10 +61,10 @@ func Test_AuditLogRepositoryImpl_Create(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			mock.ExpectBegin()
-			query := "INSERT INTO `audit_logs` (`request_id`,`ip_address`,`table_name`,`action`,`target`,`payload`,`created_by`,`created_at`) VALUES (?,?,?,?,?,CAST(? AS JSON),?,?)"
+			query := "INSERT INTO `audit_logs` (`request_id`,`ip_address`,`table_name`,`action`,`target`,`payload`,`subject_user_id`,`created_by`,`created_at`) VALUES (?,?,?,?,?,CAST(? AS JSON),?,?,?)"
 			mock.ExpectExec(query).
 				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-					sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 				WillReturnResult(tc.mocked.mockDBQueryResult).
 				WillReturnError(tc.mocked.mockDBQueryErr)
 
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"gorm.io/gorm"
+)
+
+type DeductionRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewDeductionRepository(db *gorm.DB) *DeductionRepositoryImpl {
+	return &DeductionRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *DeductionRepositoryImpl) GetDeductionByID(deductionID int64) (entity.EmployeeDeduction, error) {
+	var deduction entity.EmployeeDeduction
+	err := r.DB.First(&deduction, deductionID).Error
+
+	return deduction, err
+}
+
+func (r *DeductionRepositoryImpl) GetDeductionsByUserID(userID int64) ([]entity.EmployeeDeduction, error) {
+	var deductions []entity.EmployeeDeduction
+	err := r.DB.Where("user_id = ?", userID).
+		Order("effective_from DESC, id DESC").
+		Find(&deductions).Error
+
+	return deductions, err
+}
+
+func (r *DeductionRepositoryImpl) CreateDeduction(deduction entity.EmployeeDeduction) (entity.EmployeeDeduction, error) {
+	err := r.DB.Create(&deduction).Error
+
+	return deduction, err
+}
+
+func (r *DeductionRepositoryImpl) UpdateDeduction(deductionID int64, updates map[string]interface{}) error {
+	return r.DB.Model(&entity.EmployeeDeduction{}).Where("id = ?", deductionID).Updates(updates).Error
+}
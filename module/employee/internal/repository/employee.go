@@ -31,11 +31,24 @@ func (r *EmployeeRepositoryImpl) UpsertOvertime(overtime entity.EmployeeOvertime
 	return r.DB.Clauses(clause.OnConflict{
 		Columns: []clause.Column{{Name: "user_id"}, {Name: "date"}},
 		DoUpdates: clause.AssignmentColumns([]string{
-			"durations", "updated_at", "updated_by",
+			"durations", "on_holiday", "updated_at", "updated_by",
 		}),
 	}).Create(&overtime).Error
 }
 
+// GetHolidayByDate returns gorm.ErrRecordNotFound when the date is not a holiday.
+func (r *EmployeeRepositoryImpl) GetHolidayByDate(date time.Time) (entity.Holiday, error) {
+	var holiday entity.Holiday
+	err := r.DB.Where("date = ?", date).First(&holiday).Error
+	return holiday, err
+}
+
+func (r *EmployeeRepositoryImpl) GetAllHolidaysByTimeRange(startTime time.Time, endTime time.Time) ([]entity.Holiday, error) {
+	var holidays []entity.Holiday
+	err := r.DB.Where("date BETWEEN ? AND ?", startTime, endTime).Order("date").Find(&holidays).Error
+	return holidays, err
+}
+
 func (r *EmployeeRepositoryImpl) UpsertReimbursement(reimbursement entity.EmployeeReimbursement) error {
 	return r.DB.Clauses(clause.OnConflict{
 		Columns: []clause.Column{{Name: "user_id"}, {Name: "date"}},
@@ -92,27 +105,110 @@ func (r *EmployeeRepositoryImpl) GetAllReimbursementByTimeRange(startTime time.T
 	return reimbursements, nil
 }
 
-func (r *EmployeeRepositoryImpl) GetEmployeeBaseSalaryByPeriodStart(periodStartTime time.Time, userID *int64) ([]entity.EmployeeBaseSalary, error) {
+func (r *EmployeeRepositoryImpl) GetAllPayComponentsByTimeRange(startTime time.Time, endTime time.Time, userID *int64) ([]entity.EmployeePayComponent, error) {
+	var payComponents []entity.EmployeePayComponent
+	query := r.DB.Preload("PayComponent").
+		Where("effective_from <= ? AND (effective_to IS NULL OR effective_to >= ?)", endTime, startTime)
+
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	err := query.Order("user_id, pay_component_id, effective_from").Find(&payComponents).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return payComponents, nil
+}
+
+func (r *EmployeeRepositoryImpl) GetDeductionsByIDs(deductionIDs []int64) ([]entity.EmployeeDeduction, error) {
+	var deductions []entity.EmployeeDeduction
+	err := r.DB.Where("id IN ?", deductionIDs).Order("id").Find(&deductions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deductions, nil
+}
+
+// GetAllActiveDeductions returns the active deductions that took effect on or before periodEnd.
+func (r *EmployeeRepositoryImpl) GetAllActiveDeductions(periodEnd time.Time, userID *int64) ([]entity.EmployeeDeduction, error) {
+	var deductions []entity.EmployeeDeduction
+	query := r.DB.Where("status = ? AND effective_from <= ?", entity.DeductionStatusActive, periodEnd)
+
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	err := query.Order("user_id, priority, id").Find(&deductions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deductions, nil
+}
+
+/*
+GetEmployeeBaseSalaryByPeriod returns the salary of everyone employed at some point between periodStart and
+periodEnd, taken at their first employed day of the period, together with the salary changes that take effect
+later in the period. Employees whose first salary only takes effect later in the period get that salary, its
+EffectiveFrom tells the days before it apart. Users without an employee profile are treated as employed throughout.
+*/
+func (r *EmployeeRepositoryImpl) GetEmployeeBaseSalaryByPeriod(periodStart time.Time, periodEnd time.Time, userID *int64) ([]entity.EmployeeBaseSalary, error) {
 	var salaries []entity.EmployeeBaseSalary
 
+	// Per user the latest salary in effect on the first employed day, or the earliest one after it.
 	baseQuery := `
-		SELECT DISTINCT ON (us.user_id) 
-			us.user_id AS user_id, us.amount AS base_salary
+		SELECT DISTINCT ON (us.user_id)
+			us.user_id AS user_id, us.amount AS base_salary, us.effective_from AS effective_from,
+			ep.hire_date AS hire_date, ep.termination_date AS termination_date, ep.tax_status AS tax_status
 		FROM user_salaries us
-		WHERE us.effective_from <= ?
+		LEFT JOIN employee_profiles ep ON ep.user_id = us.user_id
+		WHERE (ep.hire_date IS NULL OR ep.hire_date <= ?)
+			AND (ep.termination_date IS NULL OR ep.termination_date >= ?)
+			AND us.effective_from <= ?
 	`
 
-	args := []interface{}{periodStartTime}
+	args := []interface{}{periodEnd, periodStart, periodEnd}
 
 	if userID != nil {
 		baseQuery += " AND us.user_id = ?"
 		args = append(args, *userID)
 	}
 
-	baseQuery += " ORDER BY us.user_id, us.effective_from DESC;"
+	baseQuery += `
+		ORDER BY us.user_id,
+			us.effective_from > GREATEST(?, ep.hire_date),
+			CASE WHEN us.effective_from <= GREATEST(?, ep.hire_date) THEN us.effective_from END DESC NULLS LAST,
+			us.effective_from, us.id DESC;`
+	args = append(args, periodStart, periodStart)
 
 	err := r.DB.Raw(baseQuery, args...).Scan(&salaries).Error
-	return salaries, err
+	if err != nil || len(salaries) == 0 {
+		return salaries, err
+	}
+
+	changesQuery := r.DB.Where("effective_from > ? AND effective_from <= ?", periodStart, periodEnd)
+	if userID != nil {
+		changesQuery = changesQuery.Where("user_id = ?", *userID)
+	}
+
+	var changes []entity.UserSalary
+	err = changesQuery.Order("user_id, effective_from, id").Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	changesByUserID := make(map[int64][]entity.UserSalary)
+	for _, change := range changes {
+		changesByUserID[change.UserID] = append(changesByUserID[change.UserID], change)
+	}
+	for i := range salaries {
+		salaries[i].SalaryChanges = changesByUserID[salaries[i].UserID]
+	}
+
+	return salaries, nil
 }
 
 // GetAttendanceByUserAndDate implements usecase.EmployeeRepository.
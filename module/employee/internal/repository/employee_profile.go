@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"gorm.io/gorm"
+)
+
+type EmployeeProfileRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewEmployeeProfileRepository(db *gorm.DB) *EmployeeProfileRepositoryImpl {
+	return &EmployeeProfileRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *EmployeeProfileRepositoryImpl) GetProfileByUserID(userID int64) (entity.EmployeeProfile, error) {
+	var profile entity.EmployeeProfile
+	err := r.DB.Where("user_id = ?", userID).First(&profile).Error
+
+	return profile, err
+}
+
+func (r *EmployeeProfileRepositoryImpl) GetProfileByEmployeeNumber(employeeNumber string) (entity.EmployeeProfile, error) {
+	var profile entity.EmployeeProfile
+	err := r.DB.Where("employee_number = ?", employeeNumber).First(&profile).Error
+
+	return profile, err
+}
+
+func (r *EmployeeProfileRepositoryImpl) GetProfilesByUserIDs(userIDs []int64) ([]entity.EmployeeProfile, error) {
+	var profiles []entity.EmployeeProfile
+	if len(userIDs) == 0 {
+		return profiles, nil
+	}
+
+	err := r.DB.Where("user_id IN ?", userIDs).Find(&profiles).Error
+
+	return profiles, err
+}
+
+// GetProfilesEmployedBetween returns the profiles of the employees employed on at least one day of [startTime, endTime].
+func (r *EmployeeProfileRepositoryImpl) GetProfilesEmployedBetween(startTime time.Time, endTime time.Time) ([]entity.EmployeeProfile, error) {
+	var profiles []entity.EmployeeProfile
+	err := r.DB.Where("hire_date <= ? AND (termination_date IS NULL OR termination_date >= ?)", endTime, startTime).
+		Order("user_id").
+		Find(&profiles).Error
+
+	return profiles, err
+}
+
+func (r *EmployeeProfileRepositoryImpl) ListProfiles(filter entity.EmployeeProfileFilter, pagination entity.Pagination) ([]entity.EmployeeProfile, int64, error) {
+	var (
+		profiles []entity.EmployeeProfile
+		total    int64
+	)
+
+	query := r.DB.Model(&entity.EmployeeProfile{})
+	if filter.Search != "" {
+		query = query.Where("full_name ILIKE ? OR employee_number ILIKE ?", "%"+filter.Search+"%", "%"+filter.Search+"%")
+	}
+	if filter.Department != "" {
+		query = query.Where("department = ?", filter.Department)
+	}
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Order("employee_number").Limit(pagination.Limit).Offset(pagination.Offset()).Find(&profiles).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return profiles, total, nil
+}
+
+func (r *EmployeeProfileRepositoryImpl) CreateProfile(profile entity.EmployeeProfile) (entity.EmployeeProfile, error) {
+	err := r.DB.Create(&profile).Error
+
+	return profile, err
+}
+
+func (r *EmployeeProfileRepositoryImpl) UpdateProfile(userID int64, updates map[string]interface{}) error {
+	return r.DB.Model(&entity.EmployeeProfile{}).Where("user_id = ?", userID).Updates(updates).Error
+}
+
+func (r *EmployeeProfileRepositoryImpl) DeleteProfile(userID int64) error {
+	return r.DB.Where("user_id = ?", userID).Delete(&entity.EmployeeProfile{}).Error
+}
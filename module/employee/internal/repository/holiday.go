@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/*
+HolidayRepositoryImpl keeps the on_holiday flag of overtime in step with the calendar: every change re-flags the
+overtime of the dates it touched in the same transaction.
+*/
+type HolidayRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewHolidayRepository(db *gorm.DB) *HolidayRepositoryImpl {
+	return &HolidayRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *HolidayRepositoryImpl) GetHolidayByID(holidayID int64) (entity.Holiday, error) {
+	var holiday entity.Holiday
+	err := r.DB.First(&holiday, holidayID).Error
+
+	return holiday, err
+}
+
+func (r *HolidayRepositoryImpl) GetHolidaysByTimeRange(startTime time.Time, endTime time.Time) ([]entity.Holiday, error) {
+	var holidays []entity.Holiday
+	err := r.DB.Where("date BETWEEN ? AND ?", startTime, endTime).
+		Order("date").
+		Find(&holidays).Error
+
+	return holidays, err
+}
+
+func (r *HolidayRepositoryImpl) CreateHoliday(holiday entity.Holiday) (entity.Holiday, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&holiday).Error; err != nil {
+			return err
+		}
+		return flagHolidayOvertime(tx, holiday.Date)
+	})
+
+	return holiday, err
+}
+
+func (r *HolidayRepositoryImpl) UpdateHoliday(holidayID int64, updates map[string]interface{}) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var previous entity.Holiday
+		if err := tx.First(&previous, holidayID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.Holiday{}).Where("id = ?", holidayID).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		dates := []time.Time{previous.Date}
+		if date, ok := updates["date"].(time.Time); ok {
+			dates = append(dates, date)
+		}
+		return flagHolidayOvertime(tx, dates...)
+	})
+}
+
+func (r *HolidayRepositoryImpl) DeleteHoliday(holidayID int64) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var holiday entity.Holiday
+		if err := tx.First(&holiday, holidayID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&entity.Holiday{}, holidayID).Error; err != nil {
+			return err
+		}
+		return flagHolidayOvertime(tx, holiday.Date)
+	})
+}
+
+// UpsertHolidays creates the holidays, a date that is already a holiday gets the new name.
+func (r *HolidayRepositoryImpl) UpsertHolidays(holidays []entity.Holiday) ([]entity.Holiday, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at", "updated_by"}),
+		}).Create(&holidays).Error
+		if err != nil {
+			return err
+		}
+
+		dates := make([]time.Time, 0, len(holidays))
+		for _, holiday := range holidays {
+			dates = append(dates, holiday.Date)
+		}
+		return flagHolidayOvertime(tx, dates...)
+	})
+
+	return holidays, err
+}
+
+func flagHolidayOvertime(tx *gorm.DB, dates ...time.Time) error {
+	return tx.Exec(
+		"UPDATE employee_overtimes SET on_holiday = EXISTS (SELECT 1 FROM holidays WHERE holidays.date = employee_overtimes.date) WHERE date IN ?",
+		dates,
+	).Error
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"gorm.io/gorm"
+)
+
+type PayComponentRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewPayComponentRepository(db *gorm.DB) *PayComponentRepositoryImpl {
+	return &PayComponentRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *PayComponentRepositoryImpl) GetComponentByID(componentID int64) (entity.PayComponent, error) {
+	var component entity.PayComponent
+	err := r.DB.First(&component, componentID).Error
+
+	return component, err
+}
+
+func (r *PayComponentRepositoryImpl) GetComponentByCode(code string) (entity.PayComponent, error) {
+	var component entity.PayComponent
+	err := r.DB.Where("code = ?", code).First(&component).Error
+
+	return component, err
+}
+
+func (r *PayComponentRepositoryImpl) ListComponents() ([]entity.PayComponent, error) {
+	var components []entity.PayComponent
+	err := r.DB.Order("code").Find(&components).Error
+
+	return components, err
+}
+
+func (r *PayComponentRepositoryImpl) CreateComponent(component entity.PayComponent) (entity.PayComponent, error) {
+	err := r.DB.Create(&component).Error
+
+	return component, err
+}
+
+func (r *PayComponentRepositoryImpl) UpdateComponent(componentID int64, updates map[string]interface{}) error {
+	return r.DB.Model(&entity.PayComponent{}).Where("id = ?", componentID).Updates(updates).Error
+}
+
+func (r *PayComponentRepositoryImpl) GetAssignmentByID(assignmentID int64) (entity.EmployeePayComponent, error) {
+	var assignment entity.EmployeePayComponent
+	err := r.DB.Preload("PayComponent").First(&assignment, assignmentID).Error
+
+	return assignment, err
+}
+
+func (r *PayComponentRepositoryImpl) GetAssignmentsByUserID(userID int64) ([]entity.EmployeePayComponent, error) {
+	var assignments []entity.EmployeePayComponent
+	err := r.DB.Preload("PayComponent").
+		Where("user_id = ?", userID).
+		Order("effective_from DESC, id DESC").
+		Find(&assignments).Error
+
+	return assignments, err
+}
+
+func (r *PayComponentRepositoryImpl) CreateAssignment(assignment entity.EmployeePayComponent) (entity.EmployeePayComponent, error) {
+	err := r.DB.Omit("PayComponent").Create(&assignment).Error
+
+	return assignment, err
+}
+
+func (r *PayComponentRepositoryImpl) UpdateAssignment(assignmentID int64, updates map[string]interface{}) error {
+	return r.DB.Model(&entity.EmployeePayComponent{}).Where("id = ?", assignmentID).Updates(updates).Error
+}
@@ -1,10 +1,12 @@
 package repository
 
 import (
+	"errors"
 	"time"
 
 	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PayrollRepositoryImpl struct {
@@ -32,22 +34,526 @@ func (r *PayrollRepositoryImpl) GetPeriodByEntityDate(date time.Time) (entity.Pa
 	return period, err
 }
 
-func (r *PayrollRepositoryImpl) GetPayslip(userID int64, periodID int64) (entity.PayrollPayslip, error) {
+// GetLatestClosedPeriod returns the period ending last among the ones that stopped taking submissions.
+func (r *PayrollRepositoryImpl) GetLatestClosedPeriod() (entity.PayrollPeriod, error) {
+	var period entity.PayrollPeriod
+	err := r.DB.Where("status <> ?", entity.PayrollStatusOpen).Order("period_end DESC").First(&period).Error
+	return period, err
+}
+
+// ListPeriods returns the periods with the status, every period when it is empty, oldest first.
+func (r *PayrollRepositoryImpl) ListPeriods(status string) ([]entity.PayrollPeriod, error) {
+	var periods []entity.PayrollPeriod
+	query := r.DB.Order("period_start")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err := query.Find(&periods).Error
+	return periods, err
+}
+
+// GetOverlappingPeriods returns the periods sharing at least a day with [startTime, endTime], except excludeID.
+func (r *PayrollRepositoryImpl) GetOverlappingPeriods(startTime time.Time, endTime time.Time, excludeID int64) ([]entity.PayrollPeriod, error) {
+	var periods []entity.PayrollPeriod
+	err := r.DB.Where("period_start <= ? AND period_end >= ? AND id <> ?", endTime, startTime, excludeID).
+		Order("period_start").
+		Find(&periods).Error
+	return periods, err
+}
+
+func (r *PayrollRepositoryImpl) CreatePeriods(periods []entity.PayrollPeriod) ([]entity.PayrollPeriod, error) {
+	err := r.DB.Create(&periods).Error
+	return periods, err
+}
+
+/*
+UpdatePeriod updates the period while it is in the given status. It returns gorm.ErrRecordNotFound when the period is not
+in that status any more, so a change checked against the status read before cannot land on a period moved on meanwhile.
+*/
+func (r *PayrollRepositoryImpl) UpdatePeriod(periodID int64, status entity.PayrollPeriodStatus, updates map[string]interface{}) error {
+	result := r.DB.Model(&entity.PayrollPeriod{}).
+		Where("id = ? AND status = ?", periodID, status).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *PayrollRepositoryImpl) GetPayslipByRunID(runID int64, userID int64) (entity.PayrollPayslip, error) {
 	var payslip entity.PayrollPayslip
-	err := r.DB.Where("user_id = ? AND payroll_period_id = ?", userID, periodID).First(&payslip).Error
+	err := r.DB.Preload("Lines", orderBySequence).
+		Where("payroll_run_id = ? AND user_id = ?", runID, userID).
+		First(&payslip).Error
 	return payslip, err
 }
 
+// GetPayslipsByRunID returns the payslips of the run, also when it is superseded.
+func (r *PayrollRepositoryImpl) GetPayslipsByRunID(runID int64) ([]entity.PayrollPayslip, error) {
+	var payslips []entity.PayrollPayslip
+	err := r.DB.Preload("Lines", orderBySequence).
+		Where("payroll_run_id = ?", runID).
+		Order("user_id").
+		Find(&payslips).Error
+	return payslips, err
+}
+
 func (r *PayrollRepositoryImpl) GetPayslips(periodID int64) ([]entity.PayrollPayslip, error) {
 	var payslips []entity.PayrollPayslip
-	err := r.DB.Where("payroll_period_id = ?", periodID).Find(&payslips).Error
+	err := r.DB.Preload("Lines", orderBySequence).
+		Where("payroll_period_id = ? AND superseded_at IS NULL", periodID).
+		Find(&payslips).Error
 	return payslips, err
 }
 
-func (r *PayrollRepositoryImpl) ClosePayrollPeriod(periodID int64) error {
-	return r.DB.Exec("UPDATE payroll_periods SET status = 'closed' WHERE id = ?", periodID).Error
+func orderBySequence(db *gorm.DB) *gorm.DB {
+	return db.Order("sequence")
+}
+
+// GetPayslipsByTimeRange returns the payslips of the periods that end within startTime and endTime.
+func (r *PayrollRepositoryImpl) GetPayslipsByTimeRange(startTime time.Time, endTime time.Time, userID *int64) ([]entity.PayrollPayslip, error) {
+	var payslips []entity.PayrollPayslip
+	query := r.DB.Joins("JOIN payroll_periods pp ON pp.id = payroll_payslips.payroll_period_id").
+		Where("pp.period_end >= ? AND pp.period_end <= ? AND payroll_payslips.superseded_at IS NULL", startTime, endTime)
+
+	if userID != nil {
+		query = query.Where("payroll_payslips.user_id = ?", *userID)
+	}
+
+	err := query.Order("payroll_payslips.user_id, pp.period_start").Find(&payslips).Error
+	return payslips, err
+}
+
+/*
+UpdatePeriodStatus moves the period from one status to another, approving it also approves its current run. It returns gorm.ErrRecordNotFound when the period
+is not in the from status any more, so two requests racing for the same transition cannot both make it.
+*/
+func (r *PayrollRepositoryImpl) UpdatePeriodStatus(periodID int64, from entity.PayrollPeriodStatus, to entity.PayrollPeriodStatus, updatedBy string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := updatePeriodStatus(tx, periodID, from, to, updatedBy); err != nil {
+			return err
+		}
+		if to != entity.PayrollStatusApproved {
+			return nil
+		}
+
+		// approving the period approves its current run, the version employees see from now on.
+		return tx.Model(&entity.PayrollRun{}).
+			Where("payroll_period_id = ? AND superseded_at IS NULL", periodID).
+			Updates(map[string]interface{}{
+				"approved_at": time.Now(),
+				"approved_by": updatedBy,
+			}).Error
+	})
+}
+
+func updatePeriodStatus(tx *gorm.DB, periodID int64, from entity.PayrollPeriodStatus, to entity.PayrollPeriodStatus, updatedBy string) error {
+	result := tx.Model(&entity.PayrollPeriod{}).
+		Where("id = ? AND status = ?", periodID, from).
+		Updates(map[string]interface{}{
+			"status":     to,
+			"updated_by": updatedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+/*
+ReopenPayrollPeriod opens the period again in one transaction: its run and payslips are marked superseded and the
+deductions they recovered are locked and get back the balances they had before. It returns the superseded payslip
+IDs and the restored deductions, or gorm.ErrRecordNotFound when the period is not locked or calculated any more.
+*/
+func (r *PayrollRepositoryImpl) ReopenPayrollPeriod(periodID int64, reopenedBy string) ([]int64, []entity.EmployeeDeduction, error) {
+	payslipIDs := []int64{}
+	deductions := []entity.EmployeeDeduction{}
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockPeriod(tx, periodID); err != nil {
+			return err
+		}
+
+		var payslips []entity.PayrollPayslip
+		err := tx.Where("payroll_period_id = ? AND superseded_at IS NULL", periodID).Find(&payslips).Error
+		if err != nil {
+			return err
+		}
+
+		lines := make(map[int64]entity.PayslipDeduction)
+		deductionIDs := []int64{}
+		for _, payslip := range payslips {
+			payslipIDs = append(payslipIDs, payslip.ID)
+			for _, line := range payslip.Deductions {
+				lines[line.DeductionID] = line
+				deductionIDs = append(deductionIDs, line.DeductionID)
+			}
+		}
+
+		if len(deductionIDs) > 0 {
+			// Locked so a deduction cancelled or changed meanwhile is restored from what it is now.
+			err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", deductionIDs).Order("id").Find(&deductions).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for i := range deductions {
+			deductions[i].Restore(lines[deductions[i].ID])
+			deductions[i].UpdatedBy = reopenedBy
+			err := tx.Model(&entity.EmployeeDeduction{}).Where("id = ?", deductions[i].ID).Updates(map[string]interface{}{
+				"balance":      deductions[i].Balance,
+				"carried_over": deductions[i].CarriedOver,
+				"status":       deductions[i].Status,
+				"updated_by":   deductions[i].UpdatedBy,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		superseded := map[string]interface{}{
+			"superseded_at": time.Now(),
+			"superseded_by": reopenedBy,
+		}
+		err = tx.Model(&entity.PayrollRun{}).
+			Where("payroll_period_id = ? AND superseded_at IS NULL", periodID).
+			Updates(superseded).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&entity.PayrollPayslip{}).
+			Where("payroll_period_id = ? AND superseded_at IS NULL", periodID).
+			Updates(superseded).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&entity.PayrollPeriod{}).
+			Where("id = ? AND status IN ?", periodID, []entity.PayrollPeriodStatus{entity.PayrollStatusLocked, entity.PayrollStatusCalculated}).
+			Updates(map[string]interface{}{
+				"status":     entity.PayrollStatusOpen,
+				"updated_by": reopenedBy,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return payslipIDs, deductions, nil
+}
+
+// GetCurrentPayrollRun returns the run of the period that has not been superseded.
+func (r *PayrollRepositoryImpl) GetCurrentPayrollRun(periodID int64) (entity.PayrollRun, error) {
+	var run entity.PayrollRun
+	err := r.DB.Where("payroll_period_id = ? AND superseded_at IS NULL", periodID).First(&run).Error
+	return run, err
+}
+
+// GetLatestApprovedPayrollRun returns the approved run of the period with the highest version, superseded or not.
+func (r *PayrollRepositoryImpl) GetLatestApprovedPayrollRun(periodID int64) (entity.PayrollRun, error) {
+	var run entity.PayrollRun
+	err := r.DB.Where("payroll_period_id = ? AND approved_at IS NOT NULL", periodID).Order("version DESC").First(&run).Error
+	return run, err
+}
+
+func (r *PayrollRepositoryImpl) GetPayrollRunByVersion(periodID int64, version int) (entity.PayrollRun, error) {
+	var run entity.PayrollRun
+	err := r.DB.Where("payroll_period_id = ? AND version = ?", periodID, version).First(&run).Error
+	return run, err
+}
+
+// ListPayrollRuns returns every run of the period, oldest version first.
+func (r *PayrollRepositoryImpl) ListPayrollRuns(periodID int64) ([]entity.PayrollRun, error) {
+	var runs []entity.PayrollRun
+	err := r.DB.Where("payroll_period_id = ?", periodID).Order("version").Find(&runs).Error
+	return runs, err
+}
+
+/*
+CreatePayrollRun stores the run, its payslips with their lines, the deduction balances they left and the audit
+log, and marks the period calculated, in one transaction. The transaction holds the advisory lock of the period,
+so a concurrent call waits and then gets the run stored here instead of storing a second one. It returns
+gorm.ErrRecordNotFound when the period has no current run and is not locked any more, and
+entity.ErrDeductionChanged when a deduction was updated after the payslips were calculated.
+*/
+func (r *PayrollRepositoryImpl) CreatePayrollRun(
+	run entity.PayrollRun,
+	payslips []entity.PayrollPayslip,
+	deductions []entity.EmployeeDeduction,
+	auditLog entity.AuditLog,
+) (entity.PayrollRun, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockPeriod(tx, run.PayrollPeriodID); err != nil {
+			return err
+		}
+
+		var current entity.PayrollRun
+		err := tx.Where("payroll_period_id = ? AND superseded_at IS NULL", run.PayrollPeriodID).First(&current).Error
+		if err == nil {
+			run = current
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		err = updatePeriodStatus(tx, run.PayrollPeriodID, entity.PayrollPeriodCalculate.From, entity.PayrollPeriodCalculate.To, run.CreatedBy)
+		if err != nil {
+			return err
+		}
+
+		run, err = storePayrollRun(tx, run, payslips, deductions)
+		if err != nil {
+			return err
+		}
+
+		return createAuditLog(tx, auditLog, map[string]interface{}{
+			"run":      run,
+			"from":     entity.PayrollPeriodCalculate.From,
+			"to":       entity.PayrollPeriodCalculate.To,
+			"payslips": payslips,
+		})
+	})
+
+	return run, err
 }
 
-func (r *PayrollRepositoryImpl) CreatePayslipsByPeriod(payslips []entity.PayrollPayslip) error {
-	return r.DB.CreateInBatches(payslips, 100).Error
+/*
+RerunPayroll replaces the current run of the period with a new version in one transaction: the previous run and
+its payslips are superseded, the new run is stored with its payslips, deduction balances and audit log, and the
+period goes from its status back to calculated. It returns gorm.ErrRecordNotFound when the previous run is not the
+current one any more or the period left the from status, so two racing reruns cannot both be stored, and
+entity.ErrDeductionChanged when a deduction was updated after the payslips were calculated.
+*/
+func (r *PayrollRepositoryImpl) RerunPayroll(
+	previousRunID int64,
+	from entity.PayrollPeriodStatus,
+	run entity.PayrollRun,
+	payslips []entity.PayrollPayslip,
+	deductions []entity.EmployeeDeduction,
+	auditLog entity.AuditLog,
+) (entity.PayrollRun, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockPeriod(tx, run.PayrollPeriodID); err != nil {
+			return err
+		}
+
+		superseded := map[string]interface{}{
+			"superseded_at": time.Now(),
+			"superseded_by": run.CreatedBy,
+		}
+		result := tx.Model(&entity.PayrollRun{}).
+			Where("id = ? AND payroll_period_id = ? AND superseded_at IS NULL", previousRunID, run.PayrollPeriodID).
+			Updates(superseded)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		err := tx.Model(&entity.PayrollPayslip{}).
+			Where("payroll_run_id = ?", previousRunID).
+			Updates(superseded).Error
+		if err != nil {
+			return err
+		}
+
+		err = updatePeriodStatus(tx, run.PayrollPeriodID, from, entity.PayrollStatusCalculated, run.CreatedBy)
+		if err != nil {
+			return err
+		}
+
+		run, err = storePayrollRun(tx, run, payslips, deductions)
+		if err != nil {
+			return err
+		}
+
+		return createAuditLog(tx, auditLog, map[string]interface{}{
+			"run":             run,
+			"previous_run_id": previousRunID,
+			"from":            from,
+			"to":              entity.PayrollStatusCalculated,
+			"payslips":        payslips,
+			"deductions":      deductions,
+		})
+	})
+
+	return run, err
+}
+
+// storePayrollRun stores the run as the next version of its period, with its payslips and the deduction balances.
+func storePayrollRun(tx *gorm.DB, run entity.PayrollRun, payslips []entity.PayrollPayslip, deductions []entity.EmployeeDeduction) (entity.PayrollRun, error) {
+	err := tx.Model(&entity.PayrollRun{}).
+		Select("COALESCE(MAX(version), 0) + 1").
+		Where("payroll_period_id = ?", run.PayrollPeriodID).
+		Scan(&run.Version).Error
+	if err != nil {
+		return entity.PayrollRun{}, err
+	}
+
+	if err := tx.Create(&run).Error; err != nil {
+		return entity.PayrollRun{}, err
+	}
+
+	if len(payslips) > 0 {
+		for i := range payslips {
+			payslips[i].PayrollRunID = &run.ID
+		}
+		if err := tx.CreateInBatches(payslips, 100).Error; err != nil {
+			return entity.PayrollRun{}, err
+		}
+	}
+
+	if err := lockDeductions(tx, deductions); err != nil {
+		return entity.PayrollRun{}, err
+	}
+
+	for _, deduction := range deductions {
+		err := tx.Model(&entity.EmployeeDeduction{}).Where("id = ?", deduction.ID).Updates(map[string]interface{}{
+			"balance":      deduction.Balance,
+			"carried_over": deduction.CarriedOver,
+			"status":       deduction.Status,
+			"updated_by":   deduction.UpdatedBy,
+		}).Error
+		if err != nil {
+			return entity.PayrollRun{}, err
+		}
+	}
+
+	return run, nil
+}
+
+/*
+lockDeductions locks the deductions the payslips were calculated from and returns entity.ErrDeductionChanged when
+one of them was updated since it was read, the balances calculated from it would overwrite that change.
+*/
+func lockDeductions(tx *gorm.DB, deductions []entity.EmployeeDeduction) error {
+	if len(deductions) == 0 {
+		return nil
+	}
+
+	deductionIDs := make([]int64, 0, len(deductions))
+	for _, deduction := range deductions {
+		deductionIDs = append(deductionIDs, deduction.ID)
+	}
+
+	var current []entity.EmployeeDeduction
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "updated_at").
+		Where("id IN ?", deductionIDs).
+		Find(&current).Error
+	if err != nil {
+		return err
+	}
+
+	updatedAt := make(map[int64]time.Time, len(current))
+	for _, deduction := range current {
+		updatedAt[deduction.ID] = deduction.UpdatedAt
+	}
+	for _, deduction := range deductions {
+		if at, ok := updatedAt[deduction.ID]; !ok || !at.Equal(deduction.UpdatedAt) {
+			return entity.ErrDeductionChanged
+		}
+	}
+	return nil
+}
+
+// lockPeriod takes the transaction level advisory lock that serialises generating, rerunning and reopening a period.
+func lockPeriod(tx *gorm.DB, periodID int64) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext('payroll_periods'), ?)", periodID).Error
+}
+
+func (r *PayrollRepositoryImpl) GetPolicyByID(policyID int64) (entity.PayrollPolicy, error) {
+	var policy entity.PayrollPolicy
+	err := r.DB.First(&policy, policyID).Error
+	return policy, err
+}
+
+func (r *PayrollRepositoryImpl) GetPolicyEffectiveOn(date time.Time) (entity.PayrollPolicy, error) {
+	var policy entity.PayrollPolicy
+	err := r.DB.Where("effective_from <= ?", date).Order("effective_from DESC, version DESC").First(&policy).Error
+	return policy, err
+}
+
+func (r *PayrollRepositoryImpl) GetLatestPolicy() (entity.PayrollPolicy, error) {
+	var policy entity.PayrollPolicy
+	err := r.DB.Order("version DESC").First(&policy).Error
+	return policy, err
+}
+
+func (r *PayrollRepositoryImpl) ListPolicies() ([]entity.PayrollPolicy, error) {
+	var policies []entity.PayrollPolicy
+	err := r.DB.Order("version DESC").Find(&policies).Error
+	return policies, err
+}
+
+func (r *PayrollRepositoryImpl) CreatePolicy(policy entity.PayrollPolicy) (entity.PayrollPolicy, error) {
+	err := r.DB.Create(&policy).Error
+	return policy, err
+}
+
+/*
+GetStatutoryRatesEffectiveOn loads the statutory tables in effect on the date. Contribution rates and PTKP amounts
+are effective dated one by one, TER and progressive brackets as a whole table, so a new year's brackets replace
+every bracket of the previous one.
+*/
+func (r *PayrollRepositoryImpl) GetStatutoryRatesEffectiveOn(date time.Time) (entity.StatutoryRates, error) {
+	rates := entity.StatutoryRates{}
+
+	err := r.DB.Raw(`
+		SELECT DISTINCT ON (code) * FROM statutory_contribution_rates
+		WHERE effective_from <= ?
+		ORDER BY code, effective_from DESC, id DESC
+	`, date).Scan(&rates.Contributions).Error
+	if err != nil {
+		return entity.StatutoryRates{}, err
+	}
+
+	err = r.DB.Raw(`
+		SELECT DISTINCT ON (tax_status) * FROM pph21_ptkp
+		WHERE effective_from <= ?
+		ORDER BY tax_status, effective_from DESC, id DESC
+	`, date).Scan(&rates.PTKP).Error
+	if err != nil {
+		return entity.StatutoryRates{}, err
+	}
+
+	err = r.DB.Where("effective_from = (SELECT MAX(effective_from) FROM pph21_ter_rates WHERE effective_from <= ?)", date).
+		Order("ter_category, min_income").Find(&rates.TERRates).Error
+	if err != nil {
+		return entity.StatutoryRates{}, err
+	}
+
+	err = r.DB.Where("effective_from = (SELECT MAX(effective_from) FROM pph21_progressive_rates WHERE effective_from <= ?)", date).
+		Order("min_income").Find(&rates.ProgressiveRates).Error
+	if err != nil {
+		return entity.StatutoryRates{}, err
+	}
+
+	var parameters entity.IncomeTaxParameters
+	err = r.DB.Where("effective_from <= ?", date).Order("effective_from DESC, id DESC").First(&parameters).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.StatutoryRates{}, err
+	}
+	if err == nil {
+		rates.IncomeTax = &parameters
+	}
+
+	return rates, nil
 }
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/eafajri/hr-service.git/internal/cache"
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+)
+
+/*
+CachedPayrollRepository serves the period a date falls in from memory, every other method goes to the database.
+The whole period is cached per date for periodCacheTTL, and every write through this repository that changes a
+period drops the dates it covers, so a period changed here is seen right away. A period changed by another instance
+can be served stale until its entry expires.
+Lookups that fail (including "not found") are never cached.
+*/
+type CachedPayrollRepository struct {
+	payrollRepository *PayrollRepositoryImpl
+	memoryCache       cache.MemoryCache
+}
+
+// periodCacheTTL bounds how long a period changed by another instance is served from memory.
+const periodCacheTTL = 30 * time.Second
+
+func NewCachedPayrollRepository(payrollRepository *PayrollRepositoryImpl, memoryCache cache.MemoryCache) *CachedPayrollRepository {
+	return &CachedPayrollRepository{
+		payrollRepository: payrollRepository,
+		memoryCache:       memoryCache,
+	}
+}
+
+func (r *CachedPayrollRepository) GetPeriodByID(periodID int64) (entity.PayrollPeriod, error) {
+	return r.payrollRepository.GetPeriodByID(periodID)
+}
+
+func (r *CachedPayrollRepository) GetPeriodByEntityDate(date time.Time) (entity.PayrollPeriod, error) {
+	period, err := r.memoryCache.GetOrLoadWithTTL(periodDateCacheKey(date), periodCacheTTL, func() (interface{}, error) {
+		return r.payrollRepository.GetPeriodByEntityDate(date)
+	})
+	if err != nil {
+		return entity.PayrollPeriod{}, err
+	}
+	return period.(entity.PayrollPeriod), nil
+}
+
+// UpdatePeriod drops every date of the old range, they may not belong to the period any more.
+func (r *CachedPayrollRepository) UpdatePeriod(periodID int64, status entity.PayrollPeriodStatus, updates map[string]interface{}) error {
+	period, err := r.payrollRepository.GetPeriodByID(periodID)
+	if err != nil {
+		return err
+	}
+
+	err = r.payrollRepository.UpdatePeriod(periodID, status, updates)
+	r.evictPeriodDates(period)
+	return err
+}
+
+func (r *CachedPayrollRepository) GetLatestClosedPeriod() (entity.PayrollPeriod, error) {
+	return r.payrollRepository.GetLatestClosedPeriod()
+}
+
+func (r *CachedPayrollRepository) ListPeriods(status string) ([]entity.PayrollPeriod, error) {
+	return r.payrollRepository.ListPeriods(status)
+}
+
+func (r *CachedPayrollRepository) GetOverlappingPeriods(startTime time.Time, endTime time.Time, excludeID int64) ([]entity.PayrollPeriod, error) {
+	return r.payrollRepository.GetOverlappingPeriods(startTime, endTime, excludeID)
+}
+
+func (r *CachedPayrollRepository) GetPayslips(periodID int64) ([]entity.PayrollPayslip, error) {
+	return r.payrollRepository.GetPayslips(periodID)
+}
+
+func (r *CachedPayrollRepository) GetPayslipByRunID(runID int64, userID int64) (entity.PayrollPayslip, error) {
+	return r.payrollRepository.GetPayslipByRunID(runID, userID)
+}
+
+func (r *CachedPayrollRepository) GetPayslipsByRunID(runID int64) ([]entity.PayrollPayslip, error) {
+	return r.payrollRepository.GetPayslipsByRunID(runID)
+}
+
+func (r *CachedPayrollRepository) GetPayslipsByTimeRange(startTime time.Time, endTime time.Time, userID *int64) ([]entity.PayrollPayslip, error) {
+	return r.payrollRepository.GetPayslipsByTimeRange(startTime, endTime, userID)
+}
+
+func (r *CachedPayrollRepository) GetCurrentPayrollRun(periodID int64) (entity.PayrollRun, error) {
+	return r.payrollRepository.GetCurrentPayrollRun(periodID)
+}
+
+func (r *CachedPayrollRepository) GetLatestApprovedPayrollRun(periodID int64) (entity.PayrollRun, error) {
+	return r.payrollRepository.GetLatestApprovedPayrollRun(periodID)
+}
+
+func (r *CachedPayrollRepository) GetPayrollRunByVersion(periodID int64, version int) (entity.PayrollRun, error) {
+	return r.payrollRepository.GetPayrollRunByVersion(periodID, version)
+}
+
+func (r *CachedPayrollRepository) ListPayrollRuns(periodID int64) ([]entity.PayrollRun, error) {
+	return r.payrollRepository.ListPayrollRuns(periodID)
+}
+
+func (r *CachedPayrollRepository) CreatePeriods(periods []entity.PayrollPeriod) ([]entity.PayrollPeriod, error) {
+	created, err := r.payrollRepository.CreatePeriods(periods)
+	for _, period := range periods {
+		r.evictPeriodDates(period)
+	}
+	return created, err
+}
+
+func (r *CachedPayrollRepository) UpdatePeriodStatus(periodID int64, from entity.PayrollPeriodStatus, to entity.PayrollPeriodStatus, updatedBy string) error {
+	err := r.payrollRepository.UpdatePeriodStatus(periodID, from, to, updatedBy)
+	r.evictPeriod(periodID)
+	return err
+}
+
+func (r *CachedPayrollRepository) ReopenPayrollPeriod(periodID int64, reopenedBy string) ([]int64, []entity.EmployeeDeduction, error) {
+	supersededIDs, restored, err := r.payrollRepository.ReopenPayrollPeriod(periodID, reopenedBy)
+	r.evictPeriod(periodID)
+	return supersededIDs, restored, err
+}
+
+// CreatePayrollRun marks the period calculated, so its dates are dropped as well.
+func (r *CachedPayrollRepository) CreatePayrollRun(run entity.PayrollRun, payslips []entity.PayrollPayslip, deductions []entity.EmployeeDeduction, auditLog entity.AuditLog) (entity.PayrollRun, error) {
+	created, err := r.payrollRepository.CreatePayrollRun(run, payslips, deductions, auditLog)
+	r.evictPeriod(run.PayrollPeriodID)
+	return created, err
+}
+
+// RerunPayroll moves the period back to calculated, so its dates are dropped as well.
+func (r *CachedPayrollRepository) RerunPayroll(previousRunID int64, from entity.PayrollPeriodStatus, run entity.PayrollRun, payslips []entity.PayrollPayslip, deductions []entity.EmployeeDeduction, auditLog entity.AuditLog) (entity.PayrollRun, error) {
+	created, err := r.payrollRepository.RerunPayroll(previousRunID, from, run, payslips, deductions, auditLog)
+	r.evictPeriod(run.PayrollPeriodID)
+	return created, err
+}
+
+func (r *CachedPayrollRepository) GetStatutoryRatesEffectiveOn(date time.Time) (entity.StatutoryRates, error) {
+	return r.payrollRepository.GetStatutoryRatesEffectiveOn(date)
+}
+
+func (r *CachedPayrollRepository) GetPolicyByID(policyID int64) (entity.PayrollPolicy, error) {
+	return r.payrollRepository.GetPolicyByID(policyID)
+}
+
+func (r *CachedPayrollRepository) GetPolicyEffectiveOn(date time.Time) (entity.PayrollPolicy, error) {
+	return r.payrollRepository.GetPolicyEffectiveOn(date)
+}
+
+func (r *CachedPayrollRepository) GetLatestPolicy() (entity.PayrollPolicy, error) {
+	return r.payrollRepository.GetLatestPolicy()
+}
+
+func (r *CachedPayrollRepository) ListPolicies() ([]entity.PayrollPolicy, error) {
+	return r.payrollRepository.ListPolicies()
+}
+
+func (r *CachedPayrollRepository) CreatePolicy(policy entity.PayrollPolicy) (entity.PayrollPolicy, error) {
+	return r.payrollRepository.CreatePolicy(policy)
+}
+
+// evictPeriod drops the dates of the period, it is called after the write so a lookup racing it cannot cache the old period.
+func (r *CachedPayrollRepository) evictPeriod(periodID int64) {
+	period, err := r.payrollRepository.GetPeriodByID(periodID)
+	if err != nil {
+		return
+	}
+	r.evictPeriodDates(period)
+}
+
+func (r *CachedPayrollRepository) evictPeriodDates(period entity.PayrollPeriod) {
+	for date := period.PeriodStart; !date.After(period.PeriodEnd); date = date.AddDate(0, 0, 1) {
+		r.memoryCache.Delete(periodDateCacheKey(date))
+	}
+}
+
+func periodDateCacheKey(date time.Time) string {
+	return "payroll:period:date:" + date.Format("2006-01-02")
+}
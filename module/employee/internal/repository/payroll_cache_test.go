@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eafajri/hr-service.git/internal/cache"
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/eafajri/hr-service.git/module/employee/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func Test_CachedPayrollRepository_GetPeriodByEntityDate(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	dialector := mysql.New(mysql.Config{
+		Conn: db,
+	})
+	mock.ExpectQuery("SELECT VERSION()").WithArgs().WillReturnRows(
+		mock.NewRows([]string{"version"}).FromCSVString("1"),
+	)
+
+	gDb, _ := gorm.Open(dialector, &gorm.Config{})
+
+	repo := repository.NewCachedPayrollRepository(repository.NewPayrollRepository(gDb), cache.NewMemoryCache(cache.Config{}))
+
+	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	january := []interface{}{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)}
+	periodColumns := []string{"id", "period_start", "period_end", "status"}
+
+	// a date outside every period is not cached
+	mock.ExpectQuery("SELECT (.+) FROM `payroll_periods` WHERE period_start").
+		WillReturnError(gorm.ErrRecordNotFound)
+	_, err := repo.GetPeriodByEntityDate(date)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+	// the period found for the date is returned as read
+	mock.ExpectQuery("SELECT (.+) FROM `payroll_periods` WHERE period_start").
+		WillReturnRows(sqlmock.NewRows(periodColumns).AddRow(1, january[0], january[1], "open"))
+	period, err := repo.GetPeriodByEntityDate(date)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.PayrollStatusOpen, period.Status)
+
+	// a second lookup for the same date is served from memory, the database is not queried at all
+	period, err = repo.GetPeriodByEntityDate(date)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), period.ID)
+	assert.Equal(t, entity.PayrollStatusOpen, period.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// a status change drops the dates of the period, the next lookup reads the new status
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `payroll_periods` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT (.+) FROM `payroll_periods` WHERE `payroll_periods`.`id`").
+		WillReturnRows(sqlmock.NewRows(periodColumns).AddRow(1, january[0], january[1], "locked"))
+	err = repo.UpdatePeriodStatus(1, entity.PayrollStatusOpen, entity.PayrollStatusLocked, "finance")
+	assert.NoError(t, err)
+
+	mock.ExpectQuery("SELECT (.+) FROM `payroll_periods` WHERE period_start").
+		WillReturnRows(sqlmock.NewRows(periodColumns).AddRow(1, january[0], january[1], "locked"))
+	period, err = repo.GetPeriodByEntityDate(date)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.PayrollStatusLocked, period.Status)
+
+	// as does an update of the period, every date of the old range is looked up again
+	mock.ExpectQuery("SELECT (.+) FROM `payroll_periods` WHERE `payroll_periods`.`id`").
+		WillReturnRows(sqlmock.NewRows(periodColumns).AddRow(1, january[0], january[1], "locked"))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `payroll_periods` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err = repo.UpdatePeriod(1, entity.PayrollStatusLocked, map[string]interface{}{"period_end": time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)})
+	assert.NoError(t, err)
+
+	mock.ExpectQuery("SELECT (.+) FROM `payroll_periods` WHERE period_start").
+		WillReturnRows(sqlmock.NewRows(periodColumns).AddRow(2, time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC), "open"))
+	period, err = repo.GetPeriodByEntityDate(date)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), period.ID)
+
+	// periods read by ID are never cached
+	mock.ExpectQuery("SELECT (.+) FROM `payroll_periods` WHERE `payroll_periods`.`id`").
+		WillReturnRows(sqlmock.NewRows(periodColumns).AddRow(1, january[0], january[1], "calculated"))
+	period, err = repo.GetPeriodByID(1)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.PayrollStatusCalculated, period.Status)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
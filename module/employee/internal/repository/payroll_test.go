@@ -0,0 +1,252 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/eafajri/hr-service.git/module/employee/internal/repository"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func Test_PayrollRepositoryImpl_CreatePayrollRun(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	dialector := mysql.New(mysql.Config{
+		Conn: db,
+	})
+	mock.ExpectQuery("SELECT VERSION()").WithArgs().WillReturnRows(
+		mock.NewRows([]string{"version"}).FromCSVString("1"),
+	)
+
+	gDb, _ := gorm.Open(dialector, &gorm.Config{})
+
+	repo := repository.NewPayrollRepository(gDb)
+
+	run := entity.PayrollRun{PayrollPeriodID: 3, TotalGrossPay: decimal.Zero, TotalTakeHome: decimal.Zero, CreatedBy: "finance"}
+	auditLog := entity.AuditLog{Action: "calculate", TableName: "payroll_runs", CreatedBy: "finance"}
+
+	// the first call stores the run, marks the period calculated and writes the audit log
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT (.+) FROM `payroll_runs` WHERE payroll_period_id = (.+) AND superseded_at IS NULL").
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectExec("UPDATE `payroll_periods` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT COALESCE\\(MAX\\(version\\), 0\\) \\+ 1 FROM `payroll_runs`").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+	mock.ExpectExec("INSERT INTO `payroll_runs`").WillReturnResult(sqlmock.NewResult(11, 1))
+	mock.ExpectExec("INSERT INTO `audit_logs`").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	created, err := repo.CreatePayrollRun(run, nil, nil, auditLog)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), created.ID)
+	assert.Equal(t, 1, created.Version)
+
+	// a repeated call gets the current run, nothing is written
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT (.+) FROM `payroll_runs` WHERE payroll_period_id = (.+) AND superseded_at IS NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "payroll_period_id", "created_by"}).AddRow(11, 3, "finance"))
+	mock.ExpectCommit()
+	existing, err := repo.CreatePayrollRun(run, nil, nil, auditLog)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), existing.ID)
+
+	// a period that was reopened or moved on in the meantime is not calculated
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT (.+) FROM `payroll_runs` WHERE payroll_period_id = (.+) AND superseded_at IS NULL").
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectExec("UPDATE `payroll_periods` SET").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	_, err = repo.CreatePayrollRun(run, nil, nil, auditLog)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_PayrollRepositoryImpl_RerunPayroll(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	dialector := mysql.New(mysql.Config{
+		Conn: db,
+	})
+	mock.ExpectQuery("SELECT VERSION()").WithArgs().WillReturnRows(
+		mock.NewRows([]string{"version"}).FromCSVString("1"),
+	)
+
+	gDb, _ := gorm.Open(dialector, &gorm.Config{})
+
+	repo := repository.NewPayrollRepository(gDb)
+
+	run := entity.PayrollRun{PayrollPeriodID: 3, TotalGrossPay: decimal.Zero, TotalTakeHome: decimal.Zero, Reason: "late overtime", CreatedBy: "finance"}
+	auditLog := entity.AuditLog{Action: "rerun", TableName: "payroll_runs", CreatedBy: "finance"}
+
+	// the previous run and its payslips are superseded and the new run is stored as the next version
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE `payroll_runs` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `payroll_payslips` SET").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE `payroll_periods` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT COALESCE\\(MAX\\(version\\), 0\\) \\+ 1 FROM `payroll_runs`").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	mock.ExpectExec("INSERT INTO `payroll_runs`").WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectExec("INSERT INTO `audit_logs`").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	created, err := repo.RerunPayroll(11, entity.PayrollStatusApproved, run, nil, nil, auditLog)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), created.ID)
+	assert.Equal(t, 2, created.Version)
+
+	// a previous run that another rerun superseded in the meantime is not rerun twice
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE `payroll_runs` SET").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	_, err = repo.RerunPayroll(11, entity.PayrollStatusApproved, run, nil, nil, auditLog)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+	// a deduction cancelled after it was read for the calculation is not overwritten
+	readAt := time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC)
+	deductions := []entity.EmployeeDeduction{{ID: 9, Balance: decimal.NewFromInt(400), CarriedOver: decimal.Zero, Status: entity.DeductionStatusActive, UpdatedAt: readAt}}
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE `payroll_runs` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `payroll_payslips` SET").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE `payroll_periods` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT COALESCE\\(MAX\\(version\\), 0\\) \\+ 1 FROM `payroll_runs`").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	mock.ExpectExec("INSERT INTO `payroll_runs`").WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectQuery("SELECT `id`,`updated_at` FROM `employee_deductions` WHERE id IN (.+) FOR UPDATE").
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "updated_at"}).AddRow(9, readAt.Add(time.Minute)))
+	mock.ExpectRollback()
+	_, err = repo.RerunPayroll(11, entity.PayrollStatusApproved, run, nil, deductions, auditLog)
+	assert.Equal(t, entity.ErrDeductionChanged, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_PayrollRepositoryImpl_ReopenPayrollPeriod(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	dialector := mysql.New(mysql.Config{
+		Conn: db,
+	})
+	mock.ExpectQuery("SELECT VERSION()").WithArgs().WillReturnRows(
+		mock.NewRows([]string{"version"}).FromCSVString("1"),
+	)
+
+	gDb, _ := gorm.Open(dialector, &gorm.Config{})
+
+	repo := repository.NewPayrollRepository(gDb)
+
+	// the deductions are restored from the rows locked in the transaction, a cancelled one stays cancelled
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT (.+) FROM `payroll_payslips` WHERE payroll_period_id = (.+) AND superseded_at IS NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "payroll_period_id", "user_id", "deductions"}).
+			AddRow(31, 5, 12, `[{"deduction_id":7,"type":"loan","amount":"400","carried_over_before":"100"},{"deduction_id":8,"type":"one_off","amount":"250"}]`).
+			AddRow(32, 5, 13, `[]`))
+	mock.ExpectQuery("SELECT (.+) FROM `employee_deductions` WHERE id IN (.+) FOR UPDATE").
+		WithArgs(7, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type", "balance", "carried_over", "status"}).
+			AddRow(7, "loan", "1600", "200", "active").
+			AddRow(8, "one_off", "0", "0", "cancelled"))
+	mock.ExpectExec("UPDATE `employee_deductions` SET").
+		WithArgs(decimal.NewFromInt(2000), decimal.NewFromInt(100), entity.DeductionStatusActive, "finance", sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `employee_deductions` SET").
+		WithArgs(decimal.NewFromInt(250), decimal.Zero, entity.DeductionStatusCancelled, "finance", sqlmock.AnyArg(), 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `payroll_runs` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `payroll_payslips` SET").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE `payroll_periods` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	payslipIDs, deductions, err := repo.ReopenPayrollPeriod(5, "finance")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{31, 32}, payslipIDs)
+	assert.Len(t, deductions, 2)
+
+	// a period reopened by another request in the meantime is left alone
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT (.+) FROM `payroll_payslips`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("UPDATE `payroll_runs` SET").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE `payroll_payslips` SET").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE `payroll_periods` SET").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	_, _, err = repo.ReopenPayrollPeriod(5, "finance")
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_PayrollRepositoryImpl_UpdatePeriod(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	dialector := mysql.New(mysql.Config{
+		Conn: db,
+	})
+	mock.ExpectQuery("SELECT VERSION()").WithArgs().WillReturnRows(
+		mock.NewRows([]string{"version"}).FromCSVString("1"),
+	)
+
+	gDb, _ := gorm.Open(dialector, &gorm.Config{})
+
+	repo := repository.NewPayrollRepository(gDb)
+
+	updates := map[string]interface{}{"working_days": 20, "updated_by": "finance"}
+
+	// the period is only updated while it is still in the status the change was checked against
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `payroll_periods` SET (.+) WHERE id = \\? AND status = \\?").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 7, entity.PayrollStatusOpen).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := repo.UpdatePeriod(7, entity.PayrollStatusOpen, updates)
+	assert.NoError(t, err)
+
+	// a period moved on by another request in the meantime is left as it is
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `payroll_periods` SET (.+) WHERE id = \\? AND status = \\?").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 7, entity.PayrollStatusOpen).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	err = repo.UpdatePeriod(7, entity.PayrollStatusOpen, updates)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_PayrollRepositoryImpl_GetPayslipsByTimeRange(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	dialector := mysql.New(mysql.Config{
+		Conn: db,
+	})
+	mock.ExpectQuery("SELECT VERSION()").WithArgs().WillReturnRows(
+		mock.NewRows([]string{"version"}).FromCSVString("1"),
+	)
+
+	gDb, _ := gorm.Open(dialector, &gorm.Config{})
+
+	repo := repository.NewPayrollRepository(gDb)
+
+	// periods are selected by the day they end, so the one from December 10 to January 9 counts towards the new year
+	yearStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2025, 11, 9, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT `payroll_payslips`.`id`(.+) JOIN payroll_periods pp ON pp.id = payroll_payslips.payroll_period_id WHERE pp.period_end >= \\? AND pp.period_end <= \\? AND payroll_payslips.superseded_at IS NULL").
+		WithArgs(yearStart, before).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "payroll_period_id"}).AddRow(1, 12, 13))
+
+	payslips, err := repo.GetPayslipsByTimeRange(yearStart, before, nil)
+	assert.NoError(t, err)
+	assert.Len(t, payslips, 1)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
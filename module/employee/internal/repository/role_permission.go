@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RolePermissionRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewRolePermissionRepository(db *gorm.DB) *RolePermissionRepositoryImpl {
+	return &RolePermissionRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *RolePermissionRepositoryImpl) GetPermissionsByRole(role entity.UserRole) ([]entity.Permission, error) {
+	var permissions []entity.Permission
+	err := r.DB.Model(&entity.RolePermission{}).
+		Where("role = ?", role).
+		Order("permission").
+		Pluck("permission", &permissions).Error
+
+	return permissions, err
+}
+
+func (r *RolePermissionRepositoryImpl) GetAllRolePermissions() ([]entity.RolePermission, error) {
+	var rolePermissions []entity.RolePermission
+	err := r.DB.Order("role, permission").Find(&rolePermissions).Error
+
+	return rolePermissions, err
+}
+
+func (r *RolePermissionRepositoryImpl) CreateRolePermission(rolePermission entity.RolePermission) error {
+	return r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&rolePermission).Error
+}
+
+func (r *RolePermissionRepositoryImpl) DeleteRolePermission(role entity.UserRole, permission entity.Permission) error {
+	return r.DB.Where("role = ? AND permission = ?", role, permission).Delete(&entity.RolePermission{}).Error
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"gorm.io/gorm"
+)
+
+type SalaryRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewSalaryRepository(db *gorm.DB) *SalaryRepositoryImpl {
+	return &SalaryRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *SalaryRepositoryImpl) GetSalariesByUserID(userID int64) ([]entity.UserSalary, error) {
+	var salaries []entity.UserSalary
+	err := r.DB.Where("user_id = ?", userID).Order("effective_from DESC, id DESC").Find(&salaries).Error
+
+	return salaries, err
+}
+
+func (r *SalaryRepositoryImpl) CreateSalary(salary entity.UserSalary) (entity.UserSalary, error) {
+	err := r.DB.Create(&salary).Error
+
+	return salary, err
+}
@@ -28,3 +28,54 @@ func (r *UserRepositoryImpl) GetUserByUsername(username string) (entity.User, er
 
 	return user, err
 }
+
+func (r *UserRepositoryImpl) GetUsersByIDs(userIDs []int64) ([]entity.User, error) {
+	var users []entity.User
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+
+	err := r.DB.Where("id IN ?", userIDs).Find(&users).Error
+
+	return users, err
+}
+
+func (r *UserRepositoryImpl) ListUsers(filter entity.UserFilter, pagination entity.Pagination) ([]entity.User, int64, error) {
+	var (
+		users []entity.User
+		total int64
+	)
+
+	query := r.DB.Model(&entity.User{})
+	if filter.Search != "" {
+		query = query.Where("username ILIKE ?", "%"+filter.Search+"%")
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Order("id").Limit(pagination.Limit).Offset(pagination.Offset()).Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func (r *UserRepositoryImpl) CreateUser(user entity.User) (entity.User, error) {
+	err := r.DB.Create(&user).Error
+
+	return user, err
+}
+
+func (r *UserRepositoryImpl) UpdateUser(userID int64, updates map[string]interface{}) error {
+	return r.DB.Model(&entity.User{}).Where("id = ?", userID).Updates(updates).Error
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"gorm.io/gorm"
+)
+
+type UserSessionRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewUserSessionRepository(db *gorm.DB) *UserSessionRepositoryImpl {
+	return &UserSessionRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *UserSessionRepositoryImpl) CreateSession(session entity.UserSession) error {
+	return r.DB.Create(&session).Error
+}
+
+func (r *UserSessionRepositoryImpl) GetSessionByID(sessionID string) (entity.UserSession, error) {
+	var session entity.UserSession
+	err := r.DB.Where("id = ?", sessionID).First(&session).Error
+
+	return session, err
+}
+
+func (r *UserSessionRepositoryImpl) RevokeSession(sessionID string, revokedAt time.Time) error {
+	return r.DB.Model(&entity.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", revokedAt).Error
+}
+
+func (r *UserSessionRepositoryImpl) RevokeSessionsByUserID(userID int64, revokedAt time.Time) error {
+	return r.DB.Model(&entity.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt).Error
+}
+
+// GetSessionsRevokedSince returns the sessions revoked after since, whether or not they have expired since.
+func (r *UserSessionRepositoryImpl) GetSessionsRevokedSince(since time.Time) ([]entity.UserSession, error) {
+	var sessions []entity.UserSession
+	err := r.DB.Where("revoked_at > ?", since).Find(&sessions).Error
+
+	return sessions, err
+}
@@ -0,0 +1,466 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrSessionRevoked     = errors.New("session has been revoked")
+	ErrUserDeactivated    = errors.New("user account is deactivated")
+	ErrTooManyAttempts    = errors.New("too many failed login attempts, try again later")
+)
+
+var (
+	dummyPasswordHash     []byte
+	dummyPasswordHashOnce sync.Once
+)
+
+type AuthConfig struct {
+	Secret          []byte
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// RevocationSyncInterval is how often the revoked sessions are reloaded from the database, it bounds how
+	// long a session revoked on another instance is still accepted here.
+	RevocationSyncInterval time.Duration
+	LoginThrottle          LoginThrottleConfig
+}
+
+//go:generate mockery --name AuthUseCase --output ./mocks
+type AuthUseCase interface {
+	Authenticate(requestContext entity.UserContext, request entity.LoginRequest) (entity.User, error)
+	UnlockUser(userContext entity.UserContext, userID int64) error
+
+	Login(requestContext entity.UserContext, request entity.LoginRequest) (entity.TokenPair, error)
+	RefreshToken(requestContext entity.UserContext, request entity.RefreshTokenRequest) (entity.TokenPair, error)
+	Logout(userContext entity.UserContext) error
+	RevokeUserSessions(userContext entity.UserContext, userID int64) error
+
+	ValidateAccessToken(token string) (entity.UserContext, error)
+}
+
+type AuthUseCaseImpl struct {
+	config                AuthConfig
+	userRepository        UserRepository
+	userSessionRepository UserSessionRepository
+	auditLogRepository    AuditLogRepository
+	revokedSessions       *revokedSessions
+	loginThrottle         *loginThrottle
+	now                   func() time.Time
+}
+
+func NewAuthUseCase(
+	config AuthConfig,
+	userRepository UserRepository,
+	userSessionRepository UserSessionRepository,
+	auditLogRepository AuditLogRepository,
+) *AuthUseCaseImpl {
+	return &AuthUseCaseImpl{
+		config:                config,
+		userRepository:        userRepository,
+		userSessionRepository: userSessionRepository,
+		auditLogRepository:    auditLogRepository,
+		revokedSessions:       newRevokedSessions(userSessionRepository, config.AccessTokenTTL, config.RevocationSyncInterval),
+		loginThrottle:         newLoginThrottle(config.LoginThrottle),
+		now:                   time.Now,
+	}
+}
+
+/*
+Authenticate verifies a username and password for both the login endpoint and Basic Auth.
+Failures are counted per username and per IP address. After a few failures a username has to wait an
+exponentially growing delay between attempts, and reaching the limit locks the username (or the IP
+address, at a higher limit) for the lockout duration. A successful login resets both.
+*/
+func (a *AuthUseCaseImpl) Authenticate(requestContext entity.UserContext, request entity.LoginRequest) (entity.User, error) {
+	now := a.now()
+	userKey := userThrottleKey(strings.ToLower(request.Username))
+	ipKey := ipThrottleKey(requestContext.IPAddress)
+
+	if !a.loginThrottle.reserve(userKey, ipKey, now) {
+		return entity.User{}, ErrTooManyAttempts
+	}
+
+	user, err := a.userRepository.GetUserByUsername(request.Username)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			a.loginThrottle.cancel(userKey, ipKey)
+			log.Println(
+				"error when GetUserByUsername",
+				zap.String("method", "AuthUseCaseImpl.Authenticate"),
+				zap.String("username", request.Username),
+				zap.Error(err),
+			)
+			return entity.User{}, err
+		}
+
+		// Spend the same bcrypt work as for a wrong password, so response times do not tell which usernames exist.
+		_ = bcrypt.CompareHashAndPassword(getDummyPasswordHash(), []byte(request.Password))
+		a.recordLoginFailure(requestContext, request.Username, now)
+		return entity.User{}, ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
+		a.recordLoginFailure(requestContext, request.Username, now)
+		return entity.User{}, ErrInvalidCredentials
+	}
+
+	if !user.IsActive {
+		a.loginThrottle.cancel(userKey, ipKey)
+		return entity.User{}, ErrUserDeactivated
+	}
+
+	// A successful login clears the address as well, otherwise an office behind one address would add up the
+	// typos of all its users until it is locked out.
+	a.loginThrottle.reset(userKey, ipKey)
+
+	return user, nil
+}
+
+func (a *AuthUseCaseImpl) UnlockUser(userContext entity.UserContext, userID int64) error {
+	user, err := a.userRepository.GetUserByID(userID)
+	if err != nil {
+		log.Println(
+			"error when GetUserByID",
+			zap.String("method", "AuthUseCaseImpl.UnlockUser"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	a.loginThrottle.unlock(userThrottleKey(strings.ToLower(user.Username)))
+
+	a.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "unlock",
+		Target:    "user",
+		TableName: "users",
+		CreatedBy: userContext.Username,
+	}, map[string]interface{}{"user_id": user.ID, "username": user.Username})
+
+	return nil
+}
+
+/*
+Passwords are only verified here; every following request carries a signed access token instead.
+Each login opens a new session, so revoking one device does not log out the others.
+*/
+func (a *AuthUseCaseImpl) Login(requestContext entity.UserContext, request entity.LoginRequest) (entity.TokenPair, error) {
+	user, err := a.Authenticate(requestContext, request)
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+
+	tokenPair, session, err := a.openSession(requestContext, user)
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+
+	a.auditLogRepository.Create(entity.AuditLog{
+		RequestID: requestContext.RequestID,
+		IPAddress: requestContext.IPAddress,
+		Action:    "login",
+		Target:    "session",
+		TableName: "user_sessions",
+		CreatedBy: user.Username,
+	}, session)
+
+	return tokenPair, nil
+}
+
+/*
+Refresh tokens are single use: the session behind it is revoked and a new one is opened.
+*/
+func (a *AuthUseCaseImpl) RefreshToken(requestContext entity.UserContext, request entity.RefreshTokenRequest) (entity.TokenPair, error) {
+	claims, err := a.parseToken(request.RefreshToken, entity.TokenTypeRefresh)
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+
+	session, err := a.userSessionRepository.GetSessionByID(claims.SessionID)
+	if err != nil {
+		return entity.TokenPair{}, ErrInvalidToken
+	}
+
+	if !session.IsActive(a.now()) {
+		return entity.TokenPair{}, ErrSessionRevoked
+	}
+
+	user, err := a.userRepository.GetUserByID(claims.UserID)
+	if err != nil {
+		return entity.TokenPair{}, ErrInvalidToken
+	}
+
+	if !user.IsActive {
+		return entity.TokenPair{}, ErrUserDeactivated
+	}
+
+	err = a.revokeSession(session.ID)
+	if err != nil {
+		log.Println(
+			"error when revokeSession",
+			zap.String("method", "AuthUseCaseImpl.RefreshToken"),
+			zap.String("session_id", session.ID),
+			zap.Error(err),
+		)
+		return entity.TokenPair{}, err
+	}
+
+	tokenPair, _, err := a.openSession(requestContext, user)
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+
+	return tokenPair, nil
+}
+
+func (a *AuthUseCaseImpl) Logout(userContext entity.UserContext) error {
+	if userContext.SessionID == "" {
+		return errors.New("logout requires a token based session")
+	}
+
+	err := a.revokeSession(userContext.SessionID)
+	if err != nil {
+		log.Println(
+			"error when revokeSession",
+			zap.String("method", "AuthUseCaseImpl.Logout"),
+			zap.Any("user_contex", userContext),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	a.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "logout",
+		Target:    "session",
+		TableName: "user_sessions",
+		CreatedBy: userContext.Username,
+	}, map[string]string{"session_id": userContext.SessionID})
+
+	return nil
+}
+
+/*
+Revokes every session of a user, including access tokens that are still within their lifetime.
+*/
+func (a *AuthUseCaseImpl) RevokeUserSessions(userContext entity.UserContext, userID int64) error {
+	revokedAt := a.now()
+
+	err := a.userSessionRepository.RevokeSessionsByUserID(userID, revokedAt)
+	if err != nil {
+		log.Println(
+			"error when RevokeSessionsByUserID",
+			zap.String("method", "AuthUseCaseImpl.RevokeUserSessions"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	// Every session of the user is revoked in the database, reload them so this instance refuses them right away.
+	err = a.revokedSessions.sync(revokedAt, true)
+	if err != nil {
+		log.Println(
+			"error when sync revoked sessions",
+			zap.String("method", "AuthUseCaseImpl.RevokeUserSessions"),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+	}
+
+	a.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "revoke",
+		Target:    "session",
+		TableName: "user_sessions",
+		CreatedBy: userContext.Username,
+	}, map[string]int64{"user_id": userID})
+
+	return nil
+}
+
+/*
+Validation is done from the token and the revoked sessions list, which is reloaded from the database every
+RevocationSyncInterval instead of on every request. Revoking a user revokes each of their sessions, so the
+session is the only thing to check.
+*/
+func (a *AuthUseCaseImpl) ValidateAccessToken(token string) (entity.UserContext, error) {
+	claims, err := a.parseToken(token, entity.TokenTypeAccess)
+	if err != nil {
+		return entity.UserContext{}, err
+	}
+
+	revoked, err := a.revokedSessions.contains(claims.SessionID, a.now())
+	if err != nil {
+		log.Println(
+			"error when sync revoked sessions",
+			zap.String("method", "AuthUseCaseImpl.ValidateAccessToken"),
+			zap.String("session_id", claims.SessionID),
+			zap.Error(err),
+		)
+	}
+	if revoked {
+		return entity.UserContext{}, ErrSessionRevoked
+	}
+
+	return entity.UserContext{
+		UserID:    claims.UserID,
+		Username:  claims.Username,
+		Role:      claims.Role,
+		SessionID: claims.SessionID,
+	}, nil
+}
+
+func (a *AuthUseCaseImpl) recordLoginFailure(requestContext entity.UserContext, username string, now time.Time) {
+	maxFailedAttempts := a.loginThrottle.config.MaxFailedAttempts
+
+	locked, failures := a.loginThrottle.recordFailure(userThrottleKey(strings.ToLower(username)), maxFailedAttempts, now)
+	if locked {
+		a.auditLockout(requestContext, username, "user", failures)
+	}
+
+	locked, failures = a.loginThrottle.recordFailure(ipThrottleKey(requestContext.IPAddress), maxFailedAttempts*ipAttemptsMultiplier, now)
+	if locked {
+		a.auditLockout(requestContext, username, "ip", failures)
+	}
+}
+
+func (a *AuthUseCaseImpl) auditLockout(requestContext entity.UserContext, username string, target string, failures int) {
+	log.Println(
+		"login locked out",
+		zap.String("method", "AuthUseCaseImpl.recordLoginFailure"),
+		zap.String("target", target),
+		zap.String("username", username),
+		zap.String("ip_address", requestContext.IPAddress),
+	)
+
+	a.auditLogRepository.Create(entity.AuditLog{
+		RequestID: requestContext.RequestID,
+		IPAddress: requestContext.IPAddress,
+		Action:    "lockout",
+		Target:    target,
+		TableName: "users",
+		CreatedBy: username,
+	}, map[string]interface{}{
+		"username":         username,
+		"ip_address":       requestContext.IPAddress,
+		"failed_attempts":  failures,
+		"lockout_duration": a.loginThrottle.config.LockoutDuration.String(),
+	})
+}
+
+func (a *AuthUseCaseImpl) openSession(requestContext entity.UserContext, user entity.User) (entity.TokenPair, entity.UserSession, error) {
+	now := a.now()
+
+	session := entity.UserSession{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		IPAddress: requestContext.IPAddress,
+		ExpiresAt: now.Add(a.config.RefreshTokenTTL),
+		CreatedAt: now,
+	}
+
+	err := a.userSessionRepository.CreateSession(session)
+	if err != nil {
+		log.Println(
+			"error when CreateSession",
+			zap.String("method", "AuthUseCaseImpl.openSession"),
+			zap.Int64("user_id", user.ID),
+			zap.Error(err),
+		)
+		return entity.TokenPair{}, entity.UserSession{}, err
+	}
+
+	accessExpiresAt := now.Add(a.config.AccessTokenTTL)
+	accessToken, err := a.signToken(user, session.ID, entity.TokenTypeAccess, now, accessExpiresAt)
+	if err != nil {
+		return entity.TokenPair{}, entity.UserSession{}, err
+	}
+
+	refreshToken, err := a.signToken(user, session.ID, entity.TokenTypeRefresh, now, session.ExpiresAt)
+	if err != nil {
+		return entity.TokenPair{}, entity.UserSession{}, err
+	}
+
+	return entity.TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, session, nil
+}
+
+func (a *AuthUseCaseImpl) revokeSession(sessionID string) error {
+	revokedAt := a.now()
+
+	err := a.userSessionRepository.RevokeSession(sessionID, revokedAt)
+	if err != nil {
+		return err
+	}
+
+	a.revokedSessions.add(sessionID, revokedAt)
+	return nil
+}
+
+func (a *AuthUseCaseImpl) signToken(user entity.User, sessionID string, tokenType entity.TokenType, issuedAt time.Time, expiresAt time.Time) (string, error) {
+	claims := entity.TokenClaims{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Role:      user.Role,
+		SessionID: sessionID,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Subject:   fmt.Sprintf("%d", user.ID),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(a.config.Secret)
+}
+
+func (a *AuthUseCaseImpl) parseToken(token string, tokenType entity.TokenType) (entity.TokenClaims, error) {
+	claims := entity.TokenClaims{}
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		return a.config.Secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithTimeFunc(a.now))
+	if err != nil {
+		return entity.TokenClaims{}, ErrInvalidToken
+	}
+
+	if claims.TokenType != tokenType {
+		return entity.TokenClaims{}, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// getDummyPasswordHash is compared against for unknown usernames, at the cost passwords are hashed with.
+func getDummyPasswordHash() []byte {
+	dummyPasswordHashOnce.Do(func() {
+		dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	})
+	return dummyPasswordHash
+}
@@ -0,0 +1,443 @@
+package usecase_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var testAuthConfig = usecase.AuthConfig{
+	Secret:          []byte("secret"),
+	AccessTokenTTL:  15 * time.Minute,
+	RefreshTokenTTL: time.Hour,
+}
+
+func Test_AuthUseCase_Login(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+
+	tests := []struct {
+		name     string
+		request  entity.LoginRequest
+		mockFunc func(
+			userRepository *mocks.UserRepository,
+			userSessionRepository *mocks.UserSessionRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+	}{
+		{
+			name:    "error - user not found",
+			request: entity.LoginRequest{Username: "user1@example.com", Password: "password"},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				userSessionRepository *mocks.UserSessionRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByUsername", "user1@example.com").
+					Return(entity.User{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: usecase.ErrInvalidCredentials,
+		},
+		{
+			name:    "error - wrong password",
+			request: entity.LoginRequest{Username: "user1@example.com", Password: "wrong"},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				userSessionRepository *mocks.UserSessionRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByUsername", "user1@example.com").
+					Return(entity.User{ID: 1, Password: string(hashedPassword)}, nil)
+			},
+			wantErr: usecase.ErrInvalidCredentials,
+		},
+		{
+			name:    "error - user deactivated",
+			request: entity.LoginRequest{Username: "user1@example.com", Password: "password"},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				userSessionRepository *mocks.UserSessionRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByUsername", "user1@example.com").
+					Return(entity.User{ID: 1, Password: string(hashedPassword), IsActive: false}, nil)
+			},
+			wantErr: usecase.ErrUserDeactivated,
+		},
+		{
+			name:    "error - CreateSession",
+			request: entity.LoginRequest{Username: "user1@example.com", Password: "password"},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				userSessionRepository *mocks.UserSessionRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByUsername", "user1@example.com").
+					Return(entity.User{ID: 1, Password: string(hashedPassword), IsActive: true}, nil)
+				userSessionRepository.On("CreateSession", mock.Anything).
+					Return(gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name:    "success",
+			request: entity.LoginRequest{Username: "user1@example.com", Password: "password"},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				userSessionRepository *mocks.UserSessionRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByUsername", "user1@example.com").
+					Return(entity.User{ID: 1, Username: "user1@example.com", Password: string(hashedPassword), Role: entity.RoleAdmin, IsActive: true}, nil)
+				userSessionRepository.On("CreateSession", mock.Anything).
+					Return(nil)
+				userSessionRepository.On("GetSessionsRevokedSince", mock.Anything).
+					Return([]entity.UserSession{}, nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).
+					Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepository := mocks.NewUserRepository(t)
+			userSessionRepository := mocks.NewUserSessionRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			tt.mockFunc(userRepository, userSessionRepository, auditLogRepository)
+
+			authUc := usecase.NewAuthUseCase(testAuthConfig, userRepository, userSessionRepository, auditLogRepository)
+			res, err := authUc.Login(entity.UserContext{}, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "Bearer", res.TokenType)
+
+			userContext, err := authUc.ValidateAccessToken(res.AccessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1), userContext.UserID)
+			assert.Equal(t, entity.RoleAdmin, userContext.Role)
+			assert.NotEmpty(t, userContext.SessionID)
+
+			_, err = authUc.ValidateAccessToken(res.RefreshToken)
+			assert.Equal(t, usecase.ErrInvalidToken, err)
+		})
+	}
+}
+
+func Test_AuthUseCase_ValidateAccessToken(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+
+	userRepository := mocks.NewUserRepository(t)
+	userSessionRepository := mocks.NewUserSessionRepository(t)
+	auditLogRepository := mocks.NewAuditLogRepository(t)
+
+	userRepository.On("GetUserByUsername", mock.Anything).
+		Return(entity.User{ID: 1, Username: "user1@example.com", Password: string(hashedPassword), IsActive: true}, nil)
+	userSessionRepository.On("CreateSession", mock.Anything).Return(nil)
+	userSessionRepository.On("RevokeSession", mock.Anything, mock.Anything).Return(nil)
+	userSessionRepository.On("GetSessionsRevokedSince", mock.Anything).Return([]entity.UserSession{}, nil)
+	auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	authUc := usecase.NewAuthUseCase(testAuthConfig, userRepository, userSessionRepository, auditLogRepository)
+	tokenPair, err := authUc.Login(entity.UserContext{}, entity.LoginRequest{Username: "user1@example.com", Password: "password"})
+	assert.NoError(t, err)
+
+	otherSecretUc := usecase.NewAuthUseCase(usecase.AuthConfig{Secret: []byte("other")}, userRepository, userSessionRepository, auditLogRepository)
+	_, err = otherSecretUc.ValidateAccessToken(tokenPair.AccessToken)
+	assert.Equal(t, usecase.ErrInvalidToken, err)
+
+	_, err = authUc.ValidateAccessToken("not-a-token")
+	assert.Equal(t, usecase.ErrInvalidToken, err)
+
+	userContext, err := authUc.ValidateAccessToken(tokenPair.AccessToken)
+	assert.NoError(t, err)
+
+	err = authUc.Logout(userContext)
+	assert.NoError(t, err)
+
+	_, err = authUc.ValidateAccessToken(tokenPair.AccessToken)
+	assert.Equal(t, usecase.ErrSessionRevoked, err)
+}
+
+func Test_AuthUseCase_ValidateAccessToken_RevokedElsewhere(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+
+	userRepository := mocks.NewUserRepository(t)
+	userSessionRepository := mocks.NewUserSessionRepository(t)
+	auditLogRepository := mocks.NewAuditLogRepository(t)
+
+	userRepository.On("GetUserByUsername", mock.Anything).
+		Return(entity.User{ID: 1, Username: "user1@example.com", Password: string(hashedPassword), IsActive: true}, nil)
+	userSessionRepository.On("CreateSession", mock.Anything).Return(nil)
+	userSessionRepository.On("GetSessionsRevokedSince", mock.Anything).Return([]entity.UserSession{}, nil).Once()
+	auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	authUc := usecase.NewAuthUseCase(testAuthConfig, userRepository, userSessionRepository, auditLogRepository)
+	tokenPair, err := authUc.Login(entity.UserContext{}, entity.LoginRequest{Username: "user1@example.com", Password: "password"})
+	assert.NoError(t, err)
+
+	userContext, err := authUc.ValidateAccessToken(tokenPair.AccessToken)
+	assert.NoError(t, err)
+
+	// Another instance, or this one after a restart, loads the revocation from the database.
+	revokedAt := time.Now()
+	otherSessionRepository := mocks.NewUserSessionRepository(t)
+	otherSessionRepository.On("GetSessionsRevokedSince", mock.Anything).
+		Return([]entity.UserSession{{ID: userContext.SessionID, UserID: 1, RevokedAt: &revokedAt}}, nil)
+
+	otherInstanceUc := usecase.NewAuthUseCase(testAuthConfig, mocks.NewUserRepository(t), otherSessionRepository, mocks.NewAuditLogRepository(t))
+	_, err = otherInstanceUc.ValidateAccessToken(tokenPair.AccessToken)
+	assert.Equal(t, usecase.ErrSessionRevoked, err)
+
+	// Revoking every session of the user applies right away on the instance that did it.
+	userSessionRepository.On("RevokeSessionsByUserID", int64(1), mock.Anything).Return(nil)
+	userSessionRepository.On("GetSessionsRevokedSince", mock.Anything).
+		Return([]entity.UserSession{{ID: userContext.SessionID, UserID: 1, RevokedAt: &revokedAt}}, nil)
+
+	err = authUc.RevokeUserSessions(entity.UserContext{Username: "admin"}, 1)
+	assert.NoError(t, err)
+
+	_, err = authUc.ValidateAccessToken(tokenPair.AccessToken)
+	assert.Equal(t, usecase.ErrSessionRevoked, err)
+}
+
+func Test_AuthUseCase_RefreshToken(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	user := entity.User{ID: 1, Username: "user1@example.com", Password: string(hashedPassword), IsActive: true}
+
+	tests := []struct {
+		name     string
+		mockFunc func(
+			userRepository *mocks.UserRepository,
+			userSessionRepository *mocks.UserSessionRepository,
+		)
+		wantErr error
+	}{
+		{
+			name: "error - session not found",
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				userSessionRepository *mocks.UserSessionRepository,
+			) {
+				userSessionRepository.On("GetSessionByID", mock.Anything).
+					Return(entity.UserSession{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: usecase.ErrInvalidToken,
+		},
+		{
+			name: "error - session revoked",
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				userSessionRepository *mocks.UserSessionRepository,
+			) {
+				revokedAt := time.Now()
+				userSessionRepository.On("GetSessionByID", mock.Anything).
+					Return(entity.UserSession{ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}, nil)
+			},
+			wantErr: usecase.ErrSessionRevoked,
+		},
+		{
+			name: "error - RevokeSession",
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				userSessionRepository *mocks.UserSessionRepository,
+			) {
+				userSessionRepository.On("GetSessionByID", mock.Anything).
+					Return(entity.UserSession{ID: "session", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+				userRepository.On("GetUserByID", int64(1)).Return(user, nil)
+				userSessionRepository.On("RevokeSession", "session", mock.Anything).
+					Return(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "success",
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				userSessionRepository *mocks.UserSessionRepository,
+			) {
+				userSessionRepository.On("GetSessionByID", mock.Anything).
+					Return(entity.UserSession{ID: "session", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+				userRepository.On("GetUserByID", int64(1)).Return(user, nil)
+				userSessionRepository.On("RevokeSession", "session", mock.Anything).Return(nil)
+				userSessionRepository.On("CreateSession", mock.Anything).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepository := mocks.NewUserRepository(t)
+			userSessionRepository := mocks.NewUserSessionRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			userRepository.On("GetUserByUsername", mock.Anything).Return(user, nil).Once()
+			userSessionRepository.On("CreateSession", mock.Anything).Return(nil).Once()
+			auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+			authUc := usecase.NewAuthUseCase(testAuthConfig, userRepository, userSessionRepository, auditLogRepository)
+			tokenPair, err := authUc.Login(entity.UserContext{}, entity.LoginRequest{Username: user.Username, Password: "password"})
+			assert.NoError(t, err)
+
+			tt.mockFunc(userRepository, userSessionRepository)
+
+			res, err := authUc.RefreshToken(entity.UserContext{}, entity.RefreshTokenRequest{RefreshToken: tokenPair.RefreshToken})
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, tokenPair.RefreshToken, res.RefreshToken)
+
+			_, err = authUc.RefreshToken(entity.UserContext{}, entity.RefreshTokenRequest{RefreshToken: tokenPair.AccessToken})
+			assert.Equal(t, usecase.ErrInvalidToken, err)
+		})
+	}
+}
+
+func Test_AuthUseCase_Authenticate_Lockout(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	user := entity.User{ID: 1, Username: "user1@example.com", Password: string(hashedPassword), IsActive: true}
+	otherUser := entity.User{ID: 2, Username: "user2@example.com", Password: string(hashedPassword), IsActive: true}
+
+	userRepository := mocks.NewUserRepository(t)
+	userSessionRepository := mocks.NewUserSessionRepository(t)
+	auditLogRepository := mocks.NewAuditLogRepository(t)
+
+	userRepository.On("GetUserByUsername", user.Username).Return(user, nil)
+	userRepository.On("GetUserByUsername", otherUser.Username).Return(otherUser, nil)
+	userRepository.On("GetUserByID", user.ID).Return(user, nil)
+	auditLogRepository.On("Create", mock.MatchedBy(func(auditLog entity.AuditLog) bool {
+		return auditLog.Action == "lockout" && auditLog.Target == "user"
+	}), mock.Anything).Return(nil).Once()
+	auditLogRepository.On("Create", mock.MatchedBy(func(auditLog entity.AuditLog) bool {
+		return auditLog.Action == "unlock"
+	}), mock.Anything).Return(nil).Once()
+
+	config := testAuthConfig
+	config.LoginThrottle = usecase.LoginThrottleConfig{MaxFailedAttempts: 3, LockoutDuration: time.Minute}
+	authUc := usecase.NewAuthUseCase(config, userRepository, userSessionRepository, auditLogRepository)
+
+	requestContext := entity.UserContext{IPAddress: "10.0.0.1"}
+	for i := 0; i < 3; i++ {
+		_, err := authUc.Authenticate(requestContext, entity.LoginRequest{Username: user.Username, Password: "wrong"})
+		assert.Equal(t, usecase.ErrInvalidCredentials, err)
+	}
+
+	// the correct password does not help while the account is locked
+	_, err := authUc.Authenticate(requestContext, entity.LoginRequest{Username: user.Username, Password: "password"})
+	assert.Equal(t, usecase.ErrTooManyAttempts, err)
+
+	// other users behind the same address are not affected
+	res, err := authUc.Authenticate(requestContext, entity.LoginRequest{Username: otherUser.Username, Password: "password"})
+	assert.NoError(t, err)
+	assert.Equal(t, otherUser.ID, res.ID)
+
+	err = authUc.UnlockUser(entity.UserContext{Username: "admin"}, user.ID)
+	assert.NoError(t, err)
+
+	res, err = authUc.Authenticate(requestContext, entity.LoginRequest{Username: user.Username, Password: "password"})
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, res.ID)
+}
+
+func Test_AuthUseCase_Authenticate_Throttle(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	user := entity.User{ID: 1, Username: "user1@example.com", Password: string(hashedPassword), IsActive: true}
+
+	config := testAuthConfig
+	config.LoginThrottle = usecase.LoginThrottleConfig{MaxFailedAttempts: 3, LockoutDuration: time.Minute}
+	requestContext := entity.UserContext{IPAddress: "10.0.0.1"}
+
+	newAuthUseCase := func(t *testing.T) (*usecase.AuthUseCaseImpl, *mocks.UserRepository) {
+		userRepository := mocks.NewUserRepository(t)
+		auditLogRepository := mocks.NewAuditLogRepository(t)
+		userRepository.On("GetUserByUsername", mock.Anything).Return(user, nil).Maybe()
+		auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+		return usecase.NewAuthUseCase(config, userRepository, mocks.NewUserSessionRepository(t), auditLogRepository), userRepository
+	}
+
+	t.Run("parallel guesses cannot pass the limit", func(t *testing.T) {
+		authUc, _ := newAuthUseCase(t)
+
+		errs := make(chan error, 20)
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := authUc.Authenticate(requestContext, entity.LoginRequest{Username: user.Username, Password: "wrong"})
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		checked := 0
+		for err := range errs {
+			if errors.Is(err, usecase.ErrInvalidCredentials) {
+				checked++
+			} else {
+				assert.Equal(t, usecase.ErrTooManyAttempts, err)
+			}
+		}
+		assert.LessOrEqual(t, checked, 3)
+	})
+
+	t.Run("a successful login resets the address", func(t *testing.T) {
+		authUc, _ := newAuthUseCase(t)
+
+		// 16 failures from one address, more than the 12 that lock it, split by a successful login
+		for round := 0; round < 2; round++ {
+			for i := 0; i < 8; i++ {
+				username := fmt.Sprintf("user%d-%d@example.com", round, i/2)
+				_, err := authUc.Authenticate(requestContext, entity.LoginRequest{Username: username, Password: "wrong"})
+				assert.Equal(t, usecase.ErrInvalidCredentials, err)
+			}
+
+			_, err := authUc.Authenticate(requestContext, entity.LoginRequest{Username: user.Username, Password: "password"})
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("unlocking a user unlocks the addresses of its attempts", func(t *testing.T) {
+		authUc, userRepository := newAuthUseCase(t)
+		userRepository.On("GetUserByID", user.ID).Return(user, nil)
+
+		for i := 0; i < 12; i++ {
+			username := user.Username
+			if i >= 2 {
+				username = fmt.Sprintf("user%d@example.com", i/2+1)
+			}
+			_, err := authUc.Authenticate(requestContext, entity.LoginRequest{Username: username, Password: "wrong"})
+			assert.Equal(t, usecase.ErrInvalidCredentials, err)
+		}
+
+		_, err := authUc.Authenticate(requestContext, entity.LoginRequest{Username: user.Username, Password: "password"})
+		assert.Equal(t, usecase.ErrTooManyAttempts, err)
+
+		err = authUc.UnlockUser(entity.UserContext{Username: "admin"}, user.ID)
+		assert.NoError(t, err)
+
+		res, err := authUc.Authenticate(requestContext, entity.LoginRequest{Username: user.Username, Password: "password"})
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, res.ID)
+	})
+}
@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/eafajri/hr-service.git/internal/money"
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name DeductionUseCase --output ./mocks
+type DeductionUseCase interface {
+	GetDeductions(userID int64) ([]entity.EmployeeDeduction, error)
+
+	CreateDeduction(userContext entity.UserContext, userID int64, request entity.DeductionRequest) (entity.EmployeeDeduction, error)
+	CancelDeduction(userContext entity.UserContext, userID int64, deductionID int64) (entity.EmployeeDeduction, error)
+}
+
+type DeductionUseCaseImpl struct {
+	deductionRepository DeductionRepository
+	userRepository      UserRepository
+	payrollRepository   PayrollRepository
+	auditLogRepository  AuditLogRepository
+}
+
+func NewDeductionUseCase(
+	deductionRepository DeductionRepository,
+	userRepository UserRepository,
+	payrollRepository PayrollRepository,
+	auditLogRepository AuditLogRepository,
+) *DeductionUseCaseImpl {
+	return &DeductionUseCaseImpl{
+		deductionRepository: deductionRepository,
+		userRepository:      userRepository,
+		payrollRepository:   payrollRepository,
+		auditLogRepository:  auditLogRepository,
+	}
+}
+
+func (d *DeductionUseCaseImpl) GetDeductions(userID int64) ([]entity.EmployeeDeduction, error) {
+	if err := d.ensureUserExists("DeductionUseCaseImpl.GetDeductions", userID); err != nil {
+		return nil, err
+	}
+
+	deductions, err := d.deductionRepository.GetDeductionsByUserID(userID)
+	if err != nil {
+		log.Println(
+			"error when GetDeductionsByUserID",
+			zap.String("method", "DeductionUseCaseImpl.GetDeductions"),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return deductions, nil
+}
+
+/*
+Recurring deductions withhold the amount every period until the optional end date, one off deductions withhold it
+once and loans repay the principal in installments of the amount. The balances are only changed by payroll
+generation, a deduction that should no longer be recovered is cancelled.
+A deduction cannot take effect on or before the end of the latest closed period.
+*/
+func (d *DeductionUseCaseImpl) CreateDeduction(userContext entity.UserContext, userID int64, request entity.DeductionRequest) (entity.EmployeeDeduction, error) {
+	if !request.Type.IsValid() {
+		return entity.EmployeeDeduction{}, errors.New("deduction type must be one of recurring, one_off or loan")
+	}
+
+	description := strings.TrimSpace(request.Description)
+	if description == "" {
+		return entity.EmployeeDeduction{}, errors.New("description is required")
+	}
+
+	if request.Priority < 0 {
+		return entity.EmployeeDeduction{}, errors.New("priority cannot be negative")
+	}
+
+	if !request.Amount.IsPositive() {
+		return entity.EmployeeDeduction{}, errors.New("amount must be greater than zero")
+	}
+
+	if !money.FitsScale(request.Amount) || !money.FitsScale(request.Principal) {
+		return entity.EmployeeDeduction{}, errors.New("amount cannot have more than 2 decimal places")
+	}
+
+	effectiveFrom, err := time.Parse("2006-01-02", request.EffectiveFrom)
+	if err != nil {
+		return entity.EmployeeDeduction{}, errors.New("invalid effective date format, must be YYYY-MM-DD")
+	}
+
+	deduction := entity.EmployeeDeduction{
+		UserID:        userID,
+		Type:          request.Type,
+		Description:   description,
+		Priority:      request.Priority,
+		Amount:        request.Amount,
+		Principal:     decimal.Zero,
+		Balance:       decimal.Zero,
+		CarriedOver:   decimal.Zero,
+		EffectiveFrom: effectiveFrom,
+		Status:        entity.DeductionStatusActive,
+		UpdatedBy:     userContext.Username,
+		CreatedBy:     userContext.Username,
+	}
+
+	switch request.Type {
+	case entity.DeductionRecurring:
+		if request.EffectiveTo != "" {
+			effectiveTo, err := time.Parse("2006-01-02", request.EffectiveTo)
+			if err != nil {
+				return entity.EmployeeDeduction{}, errors.New("invalid end date format, must be YYYY-MM-DD")
+			}
+			if effectiveTo.Before(effectiveFrom) {
+				return entity.EmployeeDeduction{}, errors.New("end date cannot be before the effective date")
+			}
+			deduction.EffectiveTo = &effectiveTo
+		}
+	case entity.DeductionOneOff:
+		deduction.Balance = request.Amount
+	case entity.DeductionLoan:
+		if !request.Principal.IsPositive() {
+			return entity.EmployeeDeduction{}, errors.New("loan principal must be greater than zero")
+		}
+		if request.Amount.GreaterThan(request.Principal) {
+			return entity.EmployeeDeduction{}, errors.New("loan installment cannot be greater than the principal")
+		}
+		deduction.Principal = request.Principal
+		deduction.Balance = request.Principal
+	}
+
+	if err := d.ensureUserExists("DeductionUseCaseImpl.CreateDeduction", userID); err != nil {
+		return entity.EmployeeDeduction{}, err
+	}
+
+	if err := ensureAfterClosedPeriod(d.payrollRepository, userContext, "DeductionUseCaseImpl.CreateDeduction", effectiveFrom); err != nil {
+		return entity.EmployeeDeduction{}, err
+	}
+
+	deduction, err = d.deductionRepository.CreateDeduction(deduction)
+	if err != nil {
+		log.Println(
+			"error when CreateDeduction",
+			zap.String("method", "DeductionUseCaseImpl.CreateDeduction"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("user_id", userID),
+			zap.Any("request", request),
+			zap.Error(err),
+		)
+		return entity.EmployeeDeduction{}, err
+	}
+
+	d.auditLogRepository.Create(entity.AuditLog{
+		RequestID:     userContext.RequestID,
+		IPAddress:     userContext.IPAddress,
+		Action:        "create",
+		Target:        "deduction",
+		TableName:     "employee_deductions",
+		CreatedBy:     userContext.Username,
+		SubjectUserID: &userID,
+	}, deduction)
+
+	return deduction, nil
+}
+
+/*
+Cancelling stops any further recovery, amounts already withheld on generated payslips stay as they are.
+*/
+func (d *DeductionUseCaseImpl) CancelDeduction(userContext entity.UserContext, userID int64, deductionID int64) (entity.EmployeeDeduction, error) {
+	deduction, err := d.deductionRepository.GetDeductionByID(deductionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.EmployeeDeduction{}, errors.New("deduction not found")
+		}
+		log.Println(
+			"error when GetDeductionByID",
+			zap.String("method", "DeductionUseCaseImpl.CancelDeduction"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("deduction_id", deductionID),
+			zap.Error(err),
+		)
+		return entity.EmployeeDeduction{}, err
+	}
+	if deduction.UserID != userID {
+		return entity.EmployeeDeduction{}, errors.New("deduction not found")
+	}
+
+	if deduction.Status != entity.DeductionStatusActive {
+		return entity.EmployeeDeduction{}, errors.New("only active deductions can be cancelled")
+	}
+
+	updates := map[string]interface{}{
+		"status":     entity.DeductionStatusCancelled,
+		"updated_by": userContext.Username,
+	}
+	err = d.deductionRepository.UpdateDeduction(deductionID, updates)
+	if err != nil {
+		log.Println(
+			"error when UpdateDeduction",
+			zap.String("method", "DeductionUseCaseImpl.CancelDeduction"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("deduction_id", deductionID),
+			zap.Error(err),
+		)
+		return entity.EmployeeDeduction{}, err
+	}
+	deduction.Status = entity.DeductionStatusCancelled
+	deduction.UpdatedBy = userContext.Username
+
+	d.auditLogRepository.Create(entity.AuditLog{
+		RequestID:     userContext.RequestID,
+		IPAddress:     userContext.IPAddress,
+		Action:        "update",
+		Target:        "deduction",
+		TableName:     "employee_deductions",
+		CreatedBy:     userContext.Username,
+		SubjectUserID: &userID,
+	}, updates)
+
+	return deduction, nil
+}
+
+func (d *DeductionUseCaseImpl) ensureUserExists(method string, userID int64) error {
+	_, err := d.userRepository.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		log.Println(
+			"error when GetUserByID",
+			zap.String("method", method),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func Test_DeductionUseCase_CreateDeduction(t *testing.T) {
+	loanRequest := entity.DeductionRequest{
+		Type:          entity.DeductionLoan,
+		Description:   " Salary advance ",
+		Priority:      1,
+		Amount:        decimal.NewFromInt(500000),
+		Principal:     decimal.NewFromInt(3000000),
+		EffectiveFrom: "2025-07-01",
+	}
+	closedPeriod := entity.PayrollPeriod{
+		ID:        6,
+		PeriodEnd: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		Status:    entity.PayrollStatusLocked,
+	}
+
+	tests := []struct {
+		name     string
+		request  entity.DeductionRequest
+		mockFunc func(
+			deductionRepository *mocks.DeductionRepository,
+			userRepository *mocks.UserRepository,
+			payrollRepository *mocks.PayrollRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+		wantRes entity.EmployeeDeduction
+	}{
+		{
+			name:    "error - invalid type",
+			request: entity.DeductionRequest{Type: "fine", Description: "Late", Amount: decimal.NewFromInt(10), EffectiveFrom: "2025-07-01"},
+			mockFunc: func(
+				deductionRepository *mocks.DeductionRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("deduction type must be one of recurring, one_off or loan"),
+		},
+		{
+			name: "error - installment greater than the principal",
+			request: func() entity.DeductionRequest {
+				request := loanRequest
+				request.Amount = decimal.NewFromInt(4000000)
+				return request
+			}(),
+			mockFunc: func(
+				deductionRepository *mocks.DeductionRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("loan installment cannot be greater than the principal"),
+		},
+		{
+			name: "error - takes effect inside a closed period",
+			request: func() entity.DeductionRequest {
+				request := loanRequest
+				request.EffectiveFrom = "2025-06-01"
+				return request
+			}(),
+			mockFunc: func(
+				deductionRepository *mocks.DeductionRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{ID: 7}, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(closedPeriod, nil)
+			},
+			wantErr: errors.New("effective date must be after 2025-06-30, the end of the latest closed payroll period"),
+		},
+		{
+			name:    "error - CreateDeduction",
+			request: loanRequest,
+			mockFunc: func(
+				deductionRepository *mocks.DeductionRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{ID: 7}, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(entity.PayrollPeriod{}, gorm.ErrRecordNotFound)
+				deductionRepository.On("CreateDeduction", mock.Anything).Return(entity.EmployeeDeduction{}, gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name:    "success - loan starts with the principal as balance",
+			request: loanRequest,
+			mockFunc: func(
+				deductionRepository *mocks.DeductionRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{ID: 7}, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(closedPeriod, nil)
+				deductionRepository.On("CreateDeduction", mock.Anything).
+					Return(func(deduction entity.EmployeeDeduction) (entity.EmployeeDeduction, error) {
+						deduction.ID = 4
+						return deduction, nil
+					})
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantRes: entity.EmployeeDeduction{
+				ID:            4,
+				UserID:        7,
+				Type:          entity.DeductionLoan,
+				Description:   "Salary advance",
+				Priority:      1,
+				Amount:        decimal.NewFromInt(500000),
+				Principal:     decimal.NewFromInt(3000000),
+				Balance:       decimal.NewFromInt(3000000),
+				CarriedOver:   decimal.Zero,
+				EffectiveFrom: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+				Status:        entity.DeductionStatusActive,
+				UpdatedBy:     "hr",
+				CreatedBy:     "hr",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deductionRepository := mocks.NewDeductionRepository(t)
+			userRepository := mocks.NewUserRepository(t)
+			payrollRepository := mocks.NewPayrollRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			tt.mockFunc(deductionRepository, userRepository, payrollRepository, auditLogRepository)
+
+			usecase := usecase.NewDeductionUseCase(deductionRepository, userRepository, payrollRepository, auditLogRepository)
+			res, err := usecase.CreateDeduction(entity.UserContext{Username: "hr"}, 7, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.Equal(t, tt.wantRes, res)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,9 +2,11 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"time"
 
+	"github.com/eafajri/hr-service.git/internal/money"
 	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -20,31 +22,37 @@ type EmployeeUseCase interface {
 }
 
 type EmployeeUseCaseImpl struct {
-	employeeRepository EmployeeRepository
-	payrollRepository  PayrollRepository
-	auditLogRepository AuditLogRepository
+	payrollConfig             PayrollConfig
+	employeeRepository        EmployeeRepository
+	employeeProfileRepository EmployeeProfileRepository
+	payrollRepository         PayrollRepository
+	auditLogRepository        AuditLogRepository
 }
 
 func NewEmployeeUseCase(
+	payrollConfig PayrollConfig,
 	employeeRepository EmployeeRepository,
+	employeeProfileRepository EmployeeProfileRepository,
 	payrollRepository PayrollRepository,
 	auditLogRepository AuditLogRepository,
 ) *EmployeeUseCaseImpl {
 	return &EmployeeUseCaseImpl{
-		employeeRepository: employeeRepository,
-		payrollRepository:  payrollRepository,
-		auditLogRepository: auditLogRepository,
+		payrollConfig:             payrollConfig,
+		employeeRepository:        employeeRepository,
+		employeeProfileRepository: employeeProfileRepository,
+		payrollRepository:         payrollRepository,
+		auditLogRepository:        auditLogRepository,
 	}
 }
 
 /*
 No rules for late or early check-ins or check-outs; check-in at any time that day counts.
 Submissions on the same day should count as one.
-Users cannot submit on weekends.
+Users cannot submit on weekends or holidays.
 */
 func (e *EmployeeUseCaseImpl) SubmitAttendance(userContext entity.UserContext, request entity.SubmitAttendanceRequest) error {
-	if userContext.UserID != request.UserID {
-		return errors.New("user context does not match request user ID")
+	if err := e.authorizeSubmission(userContext, request.UserID); err != nil {
+		return err
 	}
 
 	attandanceDate, err := time.Parse("2006-01-02", request.Date)
@@ -101,6 +109,14 @@ func (e *EmployeeUseCaseImpl) SubmitAttendance(userContext entity.UserContext, r
 		return errors.New("attendance can only be submitted on weekdays (Monday to Friday)")
 	}
 
+	holiday, err := e.isHoliday("EmployeeUseCaseImpl.SubmitAttendance", attandanceDate)
+	if err != nil {
+		return err
+	}
+	if holiday {
+		return errors.New("attendance cannot be submitted on a holiday")
+	}
+
 	attendance := entity.EmployeeAttendance{
 		UserID:       request.UserID,
 		Date:         attandanceDate,
@@ -110,6 +126,10 @@ func (e *EmployeeUseCaseImpl) SubmitAttendance(userContext entity.UserContext, r
 		UpdatedBy:    userContext.Username,
 	}
 
+	if err := e.ensureEmployedOn("EmployeeUseCaseImpl.SubmitAttendance", request.UserID, attandanceDate); err != nil {
+		return err
+	}
+
 	err = e.employeeRepository.UpsertAttendance(attendance)
 	if err != nil {
 		log.Println(
@@ -123,12 +143,13 @@ func (e *EmployeeUseCaseImpl) SubmitAttendance(userContext entity.UserContext, r
 	}
 
 	e.auditLogRepository.Create(entity.AuditLog{
-		RequestID: userContext.RequestID,
-		IPAddress: userContext.IPAddress,
-		Action:    "submit",
-		Target:    "attendance",
-		TableName: "employee_attendances",
-		CreatedBy: userContext.Username,
+		RequestID:     userContext.RequestID,
+		IPAddress:     userContext.IPAddress,
+		Action:        "submit",
+		Target:        "attendance",
+		TableName:     "employee_attendances",
+		CreatedBy:     userContext.Username,
+		SubjectUserID: &request.UserID,
 	}, attendance)
 
 	return nil
@@ -137,12 +158,12 @@ func (e *EmployeeUseCaseImpl) SubmitAttendance(userContext entity.UserContext, r
 /*
 Overtime must be proposed after they are done working.
 They can submit the number of hours taken for that overtime.
-Overtime cannot be more than 3 hours per day.
-Overtime can be taken any day.
+Overtime durations are limited by the payroll policy in effect on that day (1 to 3 hours by default).
+Overtime can be taken any day, overtime on a holiday is paid at the holiday overtime rate.
 */
 func (e *EmployeeUseCaseImpl) SubmitOvertime(userContext entity.UserContext, request entity.SubmitOvertimeRequest) error {
-	if userContext.UserID != request.UserID {
-		return errors.New("user context does not match request user ID")
+	if err := e.authorizeSubmission(userContext, request.UserID); err != nil {
+		return err
 	}
 
 	overtimeDate, err := time.Parse("2006-01-02", request.Date)
@@ -161,8 +182,13 @@ func (e *EmployeeUseCaseImpl) SubmitOvertime(userContext entity.UserContext, req
 		return errors.New("the overtime cannot be submitted because the payroll period is closed")
 	}
 
-	// When weekdays, It need to ensure that attendance is submitted
-	shouldCheckAttendance := overtimeDate.Weekday() != time.Saturday || overtimeDate.Weekday() != time.Sunday
+	onHoliday, err := e.isHoliday("EmployeeUseCaseImpl.SubmitOvertime", overtimeDate)
+	if err != nil {
+		return err
+	}
+
+	// When working days, It need to ensure that attendance is submitted
+	shouldCheckAttendance := overtimeDate.Weekday() != time.Saturday && overtimeDate.Weekday() != time.Sunday && !onHoliday
 	if shouldCheckAttendance {
 		_, err := e.employeeRepository.GetAttendanceByUserAndDate(request.UserID, overtimeDate)
 		if err != nil {
@@ -180,19 +206,28 @@ func (e *EmployeeUseCaseImpl) SubmitOvertime(userContext entity.UserContext, req
 		}
 	}
 
-	// Only accept durations between 1 and 3 hours
-	if request.Durations < 1 || request.Durations > 3 {
-		return errors.New("overtime durations must be between 1 and 3 hours")
+	policy, err := getPolicyEffectiveOn(e.payrollRepository, "EmployeeUseCaseImpl.SubmitOvertime", overtimeDate)
+	if err != nil {
+		return err
+	}
+
+	if request.Durations < int64(policy.OvertimeMinHours) || request.Durations > int64(policy.OvertimeMaxHours) {
+		return fmt.Errorf("overtime durations must be between %d and %d hours", policy.OvertimeMinHours, policy.OvertimeMaxHours)
 	}
 
 	overtime := entity.EmployeeOvertime{
 		UserID:    request.UserID,
 		Date:      overtimeDate,
 		Durations: int(request.Durations),
+		OnHoliday: onHoliday,
 		CreatedBy: userContext.Username,
 		UpdatedBy: userContext.Username,
 	}
 
+	if err := e.ensureEmployedOn("EmployeeUseCaseImpl.SubmitOvertime", request.UserID, overtimeDate); err != nil {
+		return err
+	}
+
 	err = e.employeeRepository.UpsertOvertime(overtime)
 	if err != nil {
 		log.Println(
@@ -206,12 +241,13 @@ func (e *EmployeeUseCaseImpl) SubmitOvertime(userContext entity.UserContext, req
 	}
 
 	e.auditLogRepository.Create(entity.AuditLog{
-		RequestID: userContext.RequestID,
-		IPAddress: userContext.IPAddress,
-		Action:    "submit",
-		Target:    "overtime",
-		TableName: "employee_overtimes",
-		CreatedBy: userContext.Username,
+		RequestID:     userContext.RequestID,
+		IPAddress:     userContext.IPAddress,
+		Action:        "submit",
+		Target:        "overtime",
+		TableName:     "employee_overtimes",
+		CreatedBy:     userContext.Username,
+		SubjectUserID: &request.UserID,
 	}, overtime)
 
 	return nil
@@ -222,8 +258,12 @@ Employees can attach the amount of money that needs to be reimbursed.
 Employees can attach a description to that reimbursement.
 */
 func (e *EmployeeUseCaseImpl) SubmitReimbursement(userContext entity.UserContext, request entity.SubmitReimbursementRequest) error {
-	if userContext.UserID != request.UserID {
-		return errors.New("user context does not match request user ID")
+	if err := e.authorizeSubmission(userContext, request.UserID); err != nil {
+		return err
+	}
+
+	if !money.FitsScale(request.Amount) {
+		return errors.New("reimbursement amount cannot have more than 2 decimal places")
 	}
 
 	reimbursementDate, err := time.Parse("2006-01-02", request.Date)
@@ -251,6 +291,10 @@ func (e *EmployeeUseCaseImpl) SubmitReimbursement(userContext entity.UserContext
 		UpdatedBy:   userContext.Username,
 	}
 
+	if err := e.ensureEmployedOn("EmployeeUseCaseImpl.SubmitReimbursement", request.UserID, reimbursementDate); err != nil {
+		return err
+	}
+
 	err = e.employeeRepository.UpsertReimbursement(reimbursement)
 	if err != nil {
 		log.Println(
@@ -264,12 +308,13 @@ func (e *EmployeeUseCaseImpl) SubmitReimbursement(userContext entity.UserContext
 	}
 
 	e.auditLogRepository.Create(entity.AuditLog{
-		RequestID: userContext.RequestID,
-		IPAddress: userContext.IPAddress,
-		Action:    "submit",
-		Target:    "reimbursement",
-		TableName: "employee_reimbursements",
-		CreatedBy: userContext.Username,
+		RequestID:     userContext.RequestID,
+		IPAddress:     userContext.IPAddress,
+		Action:        "submit",
+		Target:        "reimbursement",
+		TableName:     "employee_reimbursements",
+		CreatedBy:     userContext.Username,
+		SubjectUserID: &request.UserID,
 	}, reimbursement)
 
 	return nil
@@ -286,22 +331,6 @@ func (e *EmployeeUseCaseImpl) GetPayslipBreakdown(userContext entity.UserContext
 		return nil, err
 	}
 
-	baseSalaries, err := e.employeeRepository.GetEmployeeBaseSalaryByPeriodStart(periodDetails.PeriodStart, &userContext.UserID)
-	if err != nil {
-		log.Println(
-			"error when GetBaseSalaryByUserID",
-			zap.String("method", "EmployeeUseCaseImpl.GetPayslipSummary"),
-			zap.Int64("user_id", userContext.UserID),
-			zap.Error(err),
-		)
-		return nil, err
-	}
-
-	if len(baseSalaries) != 1 {
-		return nil, errors.New("base salary not found for the user in this period")
-	}
-	baseSalaryDetail := baseSalaries[0]
-
 	attendanceRecords, err := e.employeeRepository.GetAllAttendanceByTimeRange(periodDetails.PeriodStart, periodDetails.PeriodEnd, &userContext.UserID)
 	if err != nil {
 		return nil, err
@@ -317,25 +346,36 @@ func (e *EmployeeUseCaseImpl) GetPayslipBreakdown(userContext entity.UserContext
 		return map[int64][]entity.EmployeeReimbursement{}, err
 	}
 
-	calculatedPayslip := entity.PayrollPayslip{}
-	calculatedPayslip.GeneratePayslip(periodDetails, baseSalaryDetail, attendanceRecords, overtimeRecords, reimbursementRecords, userContext.Username)
-
 	payslipDetails := map[string]interface{}{
-		"payslip_summary_calculated": calculatedPayslip,
-		"period_detail":              periodDetails,
-		"attendances":                attendanceRecords,
-		"overtimes":                  overtimeRecords,
-		"reimbursements":             reimbursementRecords,
+		"period_detail":  periodDetails,
+		"attendances":    attendanceRecords,
+		"overtimes":      overtimeRecords,
+		"reimbursements": reimbursementRecords,
 	}
 
-	if periodDetails.Status == "closed" {
-		// collect system generated payslips for closed period.
-		generatedSystemPayslip, err := e.payrollRepository.GetPayslip(userContext.UserID, periodID)
+	// Once the period is closed the live calculation is the payslip before it is approved, only holders of payslip.read_all see it then.
+	if periodDetails.Status == entity.PayrollStatusOpen || userContext.HasPermission(entity.PermissionPayslipReadAll) {
+		calculatedPayslip, err := e.calculatePayslip(userContext, periodDetails, attendanceRecords, overtimeRecords, reimbursementRecords)
+		if err != nil {
+			return nil, err
+		}
+		payslipDetails["payslip_summary_calculated"] = calculatedPayslip
+	}
+
+	run, visible, err := getVisiblePayrollRun(e.payrollRepository, "EmployeeUseCaseImpl.GetPayslipSummary", userContext, periodDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	if visible {
+		// collect system generated payslips once they are approved, from the latest approved run.
+		generatedSystemPayslip, err := e.payrollRepository.GetPayslipByRunID(run.ID, userContext.UserID)
 		if err != nil {
 			log.Println(
-				"error when GetPayslip",
+				"error when GetPayslipByRunID",
 				zap.String("method", "EmployeeUseCaseImpl.GetPayslipSummary"),
 				zap.Int64("user_id", userContext.UserID),
+				zap.Int64("run_id", run.ID),
 				zap.Error(err),
 			)
 			return nil, err
@@ -347,6 +387,137 @@ func (e *EmployeeUseCaseImpl) GetPayslipBreakdown(userContext entity.UserContext
 	return payslipDetails, nil
 }
 
+// calculatePayslip calculates the payslip of the user from the submissions of the period so far.
+func (e *EmployeeUseCaseImpl) calculatePayslip(
+	userContext entity.UserContext,
+	periodDetails entity.PayrollPeriod,
+	attendanceRecords []entity.EmployeeAttendance,
+	overtimeRecords []entity.EmployeeOvertime,
+	reimbursementRecords []entity.EmployeeReimbursement,
+) (entity.PayrollPayslip, error) {
+	baseSalaries, err := e.employeeRepository.GetEmployeeBaseSalaryByPeriod(periodDetails.PeriodStart, periodDetails.PeriodEnd, &userContext.UserID)
+	if err != nil {
+		log.Println(
+			"error when GetBaseSalaryByUserID",
+			zap.String("method", "EmployeeUseCaseImpl.calculatePayslip"),
+			zap.Int64("user_id", userContext.UserID),
+			zap.Error(err),
+		)
+		return entity.PayrollPayslip{}, err
+	}
+
+	if len(baseSalaries) != 1 {
+		return entity.PayrollPayslip{}, errors.New("base salary not found for the user in this period")
+	}
+	baseSalaryDetail := baseSalaries[0]
+
+	payComponents, err := e.employeeRepository.GetAllPayComponentsByTimeRange(periodDetails.PeriodStart, periodDetails.PeriodEnd, &userContext.UserID)
+	if err != nil {
+		return entity.PayrollPayslip{}, err
+	}
+
+	holidays, err := e.employeeRepository.GetAllHolidaysByTimeRange(periodDetails.PeriodStart, periodDetails.PeriodEnd)
+	if err != nil {
+		return entity.PayrollPayslip{}, err
+	}
+
+	deductions, err := e.employeeRepository.GetAllActiveDeductions(periodDetails.PeriodEnd, &userContext.UserID)
+	if err != nil {
+		return entity.PayrollPayslip{}, err
+	}
+
+	policy, err := getPolicyEffectiveOn(e.payrollRepository, "EmployeeUseCaseImpl.calculatePayslip", periodDetails.PeriodStart)
+	if err != nil {
+		return entity.PayrollPayslip{}, err
+	}
+
+	calculatedPayslip := entity.PayrollPayslip{}
+	calculatedPayslip.GeneratePayslip(periodDetails, holidays, policy, e.payrollConfig.Rounding, baseSalaryDetail, attendanceRecords, overtimeRecords, reimbursementRecords, payComponents, userContext.Username)
+
+	statutoryRates, err := getStatutoryRatesEffectiveOn(e.payrollRepository, "EmployeeUseCaseImpl.calculatePayslip", periodDetails.PeriodStart)
+	if err != nil {
+		return entity.PayrollPayslip{}, err
+	}
+
+	finalTaxPeriod := entity.IsFinalTaxPeriod(periodDetails, baseSalaryDetail)
+	yearToDate := entity.IncomeTaxYearToDate{}
+	if statutoryRates.IncomeTax != nil && finalTaxPeriod {
+		yearToDateMap, err := getIncomeTaxYearToDate(e.payrollRepository, "EmployeeUseCaseImpl.calculatePayslip", periodDetails, &userContext.UserID)
+		if err != nil {
+			return entity.PayrollPayslip{}, err
+		}
+		yearToDate = yearToDateMap[userContext.UserID]
+	}
+
+	err = calculatedPayslip.ApplyStatutory(statutoryRates, e.payrollConfig.Rounding, baseSalaryDetail.TaxStatus, finalTaxPeriod, yearToDate)
+	if err != nil {
+		return entity.PayrollPayslip{}, err
+	}
+	calculatedPayslip.ApplyDeductions(periodDetails, deductions, e.payrollConfig.NetPayFloor, e.payrollConfig.Rounding)
+
+	return calculatedPayslip, nil
+}
+
+/*
+Users submit for themselves. Holders of submission.on_behalf (HR, admins) may submit for another user,
+e.g. when the employee was on a client site; CreatedBy then records the actor.
+*/
+func (e *EmployeeUseCaseImpl) authorizeSubmission(userContext entity.UserContext, subjectUserID int64) error {
+	if userContext.UserID == subjectUserID {
+		return nil
+	}
+
+	if !userContext.HasPermission(entity.PermissionSubmitOnBehalf) {
+		return errors.New("user context does not match request user ID")
+	}
+
+	return nil
+}
+
+/*
+Submissions are only accepted between the hire date and the termination date of the employee profile.
+Users without a profile have no recorded employment window and are not restricted, payroll treats them as employed
+throughout every period as well.
+*/
+func (e *EmployeeUseCaseImpl) ensureEmployedOn(method string, userID int64, date time.Time) error {
+	profile, err := e.employeeProfileRepository.GetProfileByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		log.Println(
+			"error when GetProfileByUserID",
+			zap.String("method", method),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if !profile.IsEmployedOn(date) {
+		return errors.New("the date is outside the employment period of the user")
+	}
+
+	return nil
+}
+
+func (e *EmployeeUseCaseImpl) isHoliday(method string, date time.Time) (bool, error) {
+	_, err := e.employeeRepository.GetHolidayByDate(date)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		log.Println(
+			"error when GetHolidayByDate",
+			zap.String("method", method),
+			zap.Time("date", date),
+			zap.Error(err),
+		)
+		return false, err
+	}
+	return true, nil
+}
+
 func (e *EmployeeUseCaseImpl) isPeriodActive(date time.Time) bool {
 	period, err := e.payrollRepository.GetPeriodByEntityDate(date)
 	if err != nil {
@@ -358,7 +529,7 @@ func (e *EmployeeUseCaseImpl) isPeriodActive(date time.Time) bool {
 		return false
 	}
 
-	if period.Status == "closed" {
+	if period.Status.IsClosed() {
 		return false
 	}
 
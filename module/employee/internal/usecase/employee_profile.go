@@ -0,0 +1,351 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name EmployeeProfileUseCase --output ./mocks
+type EmployeeProfileUseCase interface {
+	GetProfile(userID int64) (entity.EmployeeProfile, error)
+	ListProfiles(filter entity.EmployeeProfileFilter) (entity.EmployeeProfileList, error)
+
+	CreateProfile(userContext entity.UserContext, request entity.EmployeeProfileRequest) (entity.EmployeeProfile, error)
+	UpdateProfile(userContext entity.UserContext, userID int64, request entity.EmployeeProfileRequest) (entity.EmployeeProfile, error)
+	DeleteProfile(userContext entity.UserContext, userID int64) error
+}
+
+type EmployeeProfileUseCaseImpl struct {
+	employeeProfileRepository EmployeeProfileRepository
+	userRepository            UserRepository
+	auditLogRepository        AuditLogRepository
+}
+
+func NewEmployeeProfileUseCase(
+	employeeProfileRepository EmployeeProfileRepository,
+	userRepository UserRepository,
+	auditLogRepository AuditLogRepository,
+) *EmployeeProfileUseCaseImpl {
+	return &EmployeeProfileUseCaseImpl{
+		employeeProfileRepository: employeeProfileRepository,
+		userRepository:            userRepository,
+		auditLogRepository:        auditLogRepository,
+	}
+}
+
+func (e *EmployeeProfileUseCaseImpl) GetProfile(userID int64) (entity.EmployeeProfile, error) {
+	return e.getProfile("EmployeeProfileUseCaseImpl.GetProfile", userID)
+}
+
+func (e *EmployeeProfileUseCaseImpl) ListProfiles(filter entity.EmployeeProfileFilter) (entity.EmployeeProfileList, error) {
+	pagination := entity.NewPagination(filter.Page, filter.Limit)
+
+	profiles, total, err := e.employeeProfileRepository.ListProfiles(filter, pagination)
+	if err != nil {
+		log.Println(
+			"error when ListProfiles",
+			zap.String("method", "EmployeeProfileUseCaseImpl.ListProfiles"),
+			zap.Any("filter", filter),
+			zap.Error(err),
+		)
+		return entity.EmployeeProfileList{}, err
+	}
+
+	pagination.Total = total
+	return entity.EmployeeProfileList{
+		Profiles:   profiles,
+		Pagination: pagination,
+	}, nil
+}
+
+/*
+Every user has at most one profile and employee numbers are unique across profiles.
+*/
+func (e *EmployeeProfileUseCaseImpl) CreateProfile(userContext entity.UserContext, request entity.EmployeeProfileRequest) (entity.EmployeeProfile, error) {
+	profile, err := e.buildProfile(request)
+	if err != nil {
+		return entity.EmployeeProfile{}, err
+	}
+
+	_, err = e.userRepository.GetUserByID(request.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.EmployeeProfile{}, errors.New("user not found")
+		}
+		log.Println(
+			"error when GetUserByID",
+			zap.String("method", "EmployeeProfileUseCaseImpl.CreateProfile"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("user_id", request.UserID),
+			zap.Error(err),
+		)
+		return entity.EmployeeProfile{}, err
+	}
+
+	_, err = e.employeeProfileRepository.GetProfileByUserID(request.UserID)
+	if err == nil {
+		return entity.EmployeeProfile{}, errors.New("the user already has an employee profile")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println(
+			"error when GetProfileByUserID",
+			zap.String("method", "EmployeeProfileUseCaseImpl.CreateProfile"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("user_id", request.UserID),
+			zap.Error(err),
+		)
+		return entity.EmployeeProfile{}, err
+	}
+
+	if err := e.ensureEmployeeNumberAvailable(profile.EmployeeNumber, request.UserID); err != nil {
+		return entity.EmployeeProfile{}, err
+	}
+
+	profile.UserID = request.UserID
+	profile.CreatedBy = userContext.Username
+	profile.UpdatedBy = userContext.Username
+
+	profile, err = e.employeeProfileRepository.CreateProfile(profile)
+	if err != nil {
+		log.Println(
+			"error when CreateProfile",
+			zap.String("method", "EmployeeProfileUseCaseImpl.CreateProfile"),
+			zap.Any("user_contex", userContext),
+			zap.Any("request", request),
+			zap.Error(err),
+		)
+		return entity.EmployeeProfile{}, err
+	}
+
+	e.auditLogRepository.Create(entity.AuditLog{
+		RequestID:     userContext.RequestID,
+		IPAddress:     userContext.IPAddress,
+		Action:        "create",
+		Target:        "employee_profile",
+		TableName:     "employee_profiles",
+		CreatedBy:     userContext.Username,
+		SubjectUserID: &profile.UserID,
+	}, profile)
+
+	return profile, nil
+}
+
+/*
+The whole profile is replaced by the request, fields left empty are cleared.
+*/
+func (e *EmployeeProfileUseCaseImpl) UpdateProfile(userContext entity.UserContext, userID int64, request entity.EmployeeProfileRequest) (entity.EmployeeProfile, error) {
+	profile, err := e.buildProfile(request)
+	if err != nil {
+		return entity.EmployeeProfile{}, err
+	}
+
+	existing, err := e.getProfile("EmployeeProfileUseCaseImpl.UpdateProfile", userID)
+	if err != nil {
+		return entity.EmployeeProfile{}, err
+	}
+
+	if profile.EmployeeNumber != existing.EmployeeNumber {
+		if err := e.ensureEmployeeNumberAvailable(profile.EmployeeNumber, userID); err != nil {
+			return entity.EmployeeProfile{}, err
+		}
+	}
+
+	profile.UserID = userID
+	profile.CreatedAt = existing.CreatedAt
+	profile.CreatedBy = existing.CreatedBy
+	profile.UpdatedAt = time.Now()
+	profile.UpdatedBy = userContext.Username
+
+	updates := map[string]interface{}{
+		"employee_number":     profile.EmployeeNumber,
+		"full_name":           profile.FullName,
+		"department":          profile.Department,
+		"job_title":           profile.JobTitle,
+		"hire_date":           profile.HireDate,
+		"termination_date":    profile.TerminationDate,
+		"bank_name":           profile.BankName,
+		"bank_account_number": profile.BankAccountNumber,
+		"bank_account_name":   profile.BankAccountName,
+		"tax_id":              profile.TaxID,
+		"tax_status":          profile.TaxStatus,
+		"updated_at":          profile.UpdatedAt,
+		"updated_by":          profile.UpdatedBy,
+	}
+
+	err = e.employeeProfileRepository.UpdateProfile(userID, updates)
+	if err != nil {
+		log.Println(
+			"error when UpdateProfile",
+			zap.String("method", "EmployeeProfileUseCaseImpl.UpdateProfile"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return entity.EmployeeProfile{}, err
+	}
+
+	e.auditLogRepository.Create(entity.AuditLog{
+		RequestID:     userContext.RequestID,
+		IPAddress:     userContext.IPAddress,
+		Action:        "update",
+		Target:        "employee_profile",
+		TableName:     "employee_profiles",
+		CreatedBy:     userContext.Username,
+		SubjectUserID: &userID,
+	}, map[string]interface{}{"before": existing, "after": profile})
+
+	return profile, nil
+}
+
+func (e *EmployeeProfileUseCaseImpl) DeleteProfile(userContext entity.UserContext, userID int64) error {
+	profile, err := e.getProfile("EmployeeProfileUseCaseImpl.DeleteProfile", userID)
+	if err != nil {
+		return err
+	}
+
+	err = e.employeeProfileRepository.DeleteProfile(userID)
+	if err != nil {
+		log.Println(
+			"error when DeleteProfile",
+			zap.String("method", "EmployeeProfileUseCaseImpl.DeleteProfile"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	e.auditLogRepository.Create(entity.AuditLog{
+		RequestID:     userContext.RequestID,
+		IPAddress:     userContext.IPAddress,
+		Action:        "delete",
+		Target:        "employee_profile",
+		TableName:     "employee_profiles",
+		CreatedBy:     userContext.Username,
+		SubjectUserID: &userID,
+	}, profile)
+
+	return nil
+}
+
+func (e *EmployeeProfileUseCaseImpl) getProfile(method string, userID int64) (entity.EmployeeProfile, error) {
+	profile, err := e.employeeProfileRepository.GetProfileByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.EmployeeProfile{}, errors.New("employee profile not found")
+		}
+		log.Println(
+			"error when GetProfileByUserID",
+			zap.String("method", method),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return entity.EmployeeProfile{}, err
+	}
+	return profile, nil
+}
+
+func (e *EmployeeProfileUseCaseImpl) ensureEmployeeNumberAvailable(employeeNumber string, userID int64) error {
+	profile, err := e.employeeProfileRepository.GetProfileByEmployeeNumber(employeeNumber)
+	if err == nil {
+		if profile.UserID == userID {
+			return nil
+		}
+		return errors.New("employee number is already used by another employee")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println(
+			"error when GetProfileByEmployeeNumber",
+			zap.String("method", "EmployeeProfileUseCaseImpl.ensureEmployeeNumberAvailable"),
+			zap.String("employee_number", employeeNumber),
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
+
+/*
+Employee number, full name and hire date are required. The termination date is the last employed day.
+Bank account numbers and tax IDs (NPWP, 15 digits, or the 16 digit NIK based format) are stored as digits only,
+so they can be exported to bank transfer files and tax reports without further cleanup.
+The tax status is the PTKP status used for PPh 21 (TK/0 to K/3), TK/0 when it is not given.
+*/
+func (e *EmployeeProfileUseCaseImpl) buildProfile(request entity.EmployeeProfileRequest) (entity.EmployeeProfile, error) {
+	profile := entity.EmployeeProfile{
+		EmployeeNumber:  strings.TrimSpace(request.EmployeeNumber),
+		FullName:        strings.TrimSpace(request.FullName),
+		Department:      strings.TrimSpace(request.Department),
+		JobTitle:        strings.TrimSpace(request.JobTitle),
+		BankName:        strings.TrimSpace(request.BankName),
+		BankAccountName: strings.TrimSpace(request.BankAccountName),
+	}
+
+	if profile.EmployeeNumber == "" {
+		return entity.EmployeeProfile{}, errors.New("employee number is required")
+	}
+
+	if profile.FullName == "" {
+		return entity.EmployeeProfile{}, errors.New("full name is required")
+	}
+
+	hireDate, err := time.Parse("2006-01-02", request.HireDate)
+	if err != nil {
+		return entity.EmployeeProfile{}, errors.New("invalid hire date format, must be YYYY-MM-DD")
+	}
+	profile.HireDate = hireDate
+
+	if request.TerminationDate != "" {
+		terminationDate, err := time.Parse("2006-01-02", request.TerminationDate)
+		if err != nil {
+			return entity.EmployeeProfile{}, errors.New("invalid termination date format, must be YYYY-MM-DD")
+		}
+		if terminationDate.Before(hireDate) {
+			return entity.EmployeeProfile{}, errors.New("termination date cannot be before the hire date")
+		}
+		profile.TerminationDate = &terminationDate
+	}
+
+	bankAccountNumber, ok := digitsOnly(request.BankAccountNumber)
+	if !ok {
+		return entity.EmployeeProfile{}, errors.New("bank account number must only contain digits")
+	}
+	profile.BankAccountNumber = bankAccountNumber
+
+	taxID, ok := digitsOnly(request.TaxID)
+	if !ok || (taxID != "" && len(taxID) != 15 && len(taxID) != 16) {
+		return entity.EmployeeProfile{}, errors.New("tax ID must be 15 or 16 digits")
+	}
+	profile.TaxID = taxID
+
+	profile.TaxStatus = strings.ToUpper(strings.TrimSpace(request.TaxStatus))
+	if profile.TaxStatus == "" {
+		profile.TaxStatus = entity.DefaultTaxStatus
+	}
+	if !entity.IsValidTaxStatus(profile.TaxStatus) {
+		return entity.EmployeeProfile{}, errors.New("tax status must be one of TK/0 to TK/3 or K/0 to K/3")
+	}
+
+	return profile, nil
+}
+
+// digitsOnly drops the usual separators (spaces, dots, dashes) and reports whether anything else was left.
+func digitsOnly(value string) (string, bool) {
+	var builder strings.Builder
+	for _, char := range value {
+		switch {
+		case char >= '0' && char <= '9':
+			builder.WriteRune(char)
+		case char == ' ' || char == '.' || char == '-':
+		default:
+			return "", false
+		}
+	}
+	return builder.String(), true
+}
@@ -0,0 +1,231 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func Test_EmployeeProfileUseCase_CreateProfile(t *testing.T) {
+	validRequest := entity.EmployeeProfileRequest{
+		UserID:            7,
+		EmployeeNumber:    "EMP-007",
+		FullName:          " Employee Seven ",
+		Department:        "Engineering",
+		HireDate:          "2024-02-01",
+		BankAccountNumber: "123-456 789",
+		TaxID:             "09.254.294.3-407.000",
+		TaxStatus:         "k/1",
+	}
+
+	tests := []struct {
+		name     string
+		request  entity.EmployeeProfileRequest
+		mockFunc func(
+			employeeProfileRepository *mocks.EmployeeProfileRepository,
+			userRepository *mocks.UserRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+		wantRes entity.EmployeeProfile
+	}{
+		{
+			name:    "error - missing full name",
+			request: entity.EmployeeProfileRequest{UserID: 7, EmployeeNumber: "EMP-007", HireDate: "2024-02-01"},
+			mockFunc: func(
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("full name is required"),
+		},
+		{
+			name:    "error - invalid tax ID",
+			request: entity.EmployeeProfileRequest{UserID: 7, EmployeeNumber: "EMP-007", FullName: "Employee", HireDate: "2024-02-01", TaxID: "1234"},
+			mockFunc: func(
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("tax ID must be 15 or 16 digits"),
+		},
+		{
+			name:    "error - invalid tax status",
+			request: entity.EmployeeProfileRequest{UserID: 7, EmployeeNumber: "EMP-007", FullName: "Employee", HireDate: "2024-02-01", TaxStatus: "K/4"},
+			mockFunc: func(
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("tax status must be one of TK/0 to TK/3 or K/0 to K/3"),
+		},
+		{
+			name:    "error - user not found",
+			request: validRequest,
+			mockFunc: func(
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("user not found"),
+		},
+		{
+			name:    "error - profile already exists",
+			request: validRequest,
+			mockFunc: func(
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{ID: 7}, nil)
+				employeeProfileRepository.On("GetProfileByUserID", int64(7)).Return(entity.EmployeeProfile{UserID: 7}, nil)
+			},
+			wantErr: errors.New("the user already has an employee profile"),
+		},
+		{
+			name:    "error - employee number taken",
+			request: validRequest,
+			mockFunc: func(
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{ID: 7}, nil)
+				employeeProfileRepository.On("GetProfileByUserID", int64(7)).Return(entity.EmployeeProfile{}, gorm.ErrRecordNotFound)
+				employeeProfileRepository.On("GetProfileByEmployeeNumber", "EMP-007").Return(entity.EmployeeProfile{UserID: 8}, nil)
+			},
+			wantErr: errors.New("employee number is already used by another employee"),
+		},
+		{
+			name:    "success",
+			request: validRequest,
+			mockFunc: func(
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{ID: 7}, nil)
+				employeeProfileRepository.On("GetProfileByUserID", int64(7)).Return(entity.EmployeeProfile{}, gorm.ErrRecordNotFound)
+				employeeProfileRepository.On("GetProfileByEmployeeNumber", "EMP-007").Return(entity.EmployeeProfile{}, gorm.ErrRecordNotFound)
+				employeeProfileRepository.On("CreateProfile", mock.Anything).
+					Return(func(profile entity.EmployeeProfile) (entity.EmployeeProfile, error) {
+						return profile, nil
+					})
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantRes: entity.EmployeeProfile{
+				UserID:            7,
+				EmployeeNumber:    "EMP-007",
+				FullName:          "Employee Seven",
+				Department:        "Engineering",
+				HireDate:          time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				BankAccountNumber: "123456789",
+				TaxID:             "092542943407000",
+				TaxStatus:         "K/1",
+				CreatedBy:         "hr",
+				UpdatedBy:         "hr",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employeeProfileRepository := mocks.NewEmployeeProfileRepository(t)
+			userRepository := mocks.NewUserRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			tt.mockFunc(employeeProfileRepository, userRepository, auditLogRepository)
+
+			usecase := usecase.NewEmployeeProfileUseCase(employeeProfileRepository, userRepository, auditLogRepository)
+			res, err := usecase.CreateProfile(entity.UserContext{Username: "hr"}, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.Equal(t, tt.wantRes, res)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_EmployeeProfileUseCase_UpdateProfile(t *testing.T) {
+	existing := entity.EmployeeProfile{UserID: 7, EmployeeNumber: "EMP-007", FullName: "Employee Seven", CreatedBy: "admin"}
+	request := entity.EmployeeProfileRequest{EmployeeNumber: "EMP-007", FullName: "Employee Seven", JobTitle: "Engineer", HireDate: "2024-02-01"}
+
+	tests := []struct {
+		name     string
+		mockFunc func(
+			employeeProfileRepository *mocks.EmployeeProfileRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+	}{
+		{
+			name: "error - profile not found",
+			mockFunc: func(
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				employeeProfileRepository.On("GetProfileByUserID", int64(7)).Return(entity.EmployeeProfile{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("employee profile not found"),
+		},
+		{
+			name: "error - UpdateProfile",
+			mockFunc: func(
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				employeeProfileRepository.On("GetProfileByUserID", int64(7)).Return(existing, nil)
+				employeeProfileRepository.On("UpdateProfile", int64(7), mock.Anything).Return(gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name: "success",
+			mockFunc: func(
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				employeeProfileRepository.On("GetProfileByUserID", int64(7)).Return(existing, nil)
+				employeeProfileRepository.On("UpdateProfile", int64(7), mock.MatchedBy(func(updates map[string]interface{}) bool {
+					return updates["job_title"] == "Engineer" && updates["updated_by"] == "hr"
+				})).Return(nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employeeProfileRepository := mocks.NewEmployeeProfileRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			tt.mockFunc(employeeProfileRepository, auditLogRepository)
+
+			usecase := usecase.NewEmployeeProfileUseCase(employeeProfileRepository, mocks.NewUserRepository(t), auditLogRepository)
+			res, err := usecase.UpdateProfile(entity.UserContext{Username: "hr"}, 7, request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "Engineer", res.JobTitle)
+			assert.Equal(t, "admin", res.CreatedBy)
+			assert.Equal(t, "hr", res.UpdatedBy)
+		})
+	}
+}
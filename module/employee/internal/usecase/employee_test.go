@@ -3,10 +3,12 @@ package usecase_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
 	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
 	"github.com/eafajri/hr-service.git/module/employee/internal/usecase/mocks"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
@@ -21,6 +23,7 @@ func Test_EmployeeUseCase_SubmitAttendance(t *testing.T) {
 			employeeRepository *mocks.EmployeeRepository,
 			payrollRepository *mocks.PayrollRepository,
 			auditLogRepository *mocks.AuditLogRepository,
+			employeeProfileRepository *mocks.EmployeeProfileRepository,
 		)
 		wantErr error
 	}{
@@ -36,6 +39,7 @@ func Test_EmployeeUseCase_SubmitAttendance(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 			},
 			wantErr: errors.New("user context does not match request user ID"),
@@ -49,6 +53,7 @@ func Test_EmployeeUseCase_SubmitAttendance(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 			},
 			wantErr: errors.New("invalid date format, must be YYYY-MM-DD"),
@@ -62,9 +67,10 @@ func Test_EmployeeUseCase_SubmitAttendance(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
-					Return(entity.PayrollPeriod{ID: 1, Status: "closed"}, nil)
+					Return(entity.PayrollPeriod{ID: 1, Status: "locked"}, nil)
 			},
 			wantErr: errors.New("the attendance cannot be submitted because the payroll period is closed"),
 		},
@@ -78,6 +84,7 @@ func Test_EmployeeUseCase_SubmitAttendance(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
 					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
@@ -95,6 +102,7 @@ func Test_EmployeeUseCase_SubmitAttendance(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
 					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
@@ -112,6 +120,7 @@ func Test_EmployeeUseCase_SubmitAttendance(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
 					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
@@ -129,6 +138,7 @@ func Test_EmployeeUseCase_SubmitAttendance(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
 					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
@@ -146,12 +156,33 @@ func Test_EmployeeUseCase_SubmitAttendance(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
 					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
 			},
 			wantErr: errors.New("attendance can only be submitted on weekdays (Monday to Friday)"),
 		},
+		{
+			name: "error - check-in on a holiday",
+			request: entity.SubmitAttendanceRequest{
+				Date:         "2023-12-25",
+				CheckInTime:  "2023-12-25T08:00:00Z",
+				CheckOutTime: "2023-12-25T18:00:00Z",
+			},
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+			) {
+				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
+					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeRepository.On("GetHolidayByDate", time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC)).
+					Return(entity.Holiday{ID: 3, Date: time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC), Name: "Christmas Day"}, nil)
+			},
+			wantErr: errors.New("attendance cannot be submitted on a holiday"),
+		},
 		{
 			name: "error - upsert attendance",
 			request: entity.SubmitAttendanceRequest{
@@ -163,14 +194,101 @@ func Test_EmployeeUseCase_SubmitAttendance(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
 					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeRepository.On("GetHolidayByDate", mock.Anything).
+					Return(entity.Holiday{}, gorm.ErrRecordNotFound)
+				employeeProfileRepository.On("GetProfileByUserID", mock.Anything).
+					Return(entity.EmployeeProfile{}, gorm.ErrRecordNotFound)
 				employeeRepository.On("UpsertAttendance", mock.Anything).
 					Return(errors.New("database error"))
 			},
 			wantErr: errors.New("database error"),
 		},
+		{
+			name: "error - date outside the employment period",
+			request: entity.SubmitAttendanceRequest{
+				Date:         "2023-12-01",
+				CheckInTime:  "2023-12-01T08:00:00Z",
+				CheckOutTime: "2023-12-01T18:00:00Z",
+			},
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+			) {
+				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
+					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeRepository.On("GetHolidayByDate", mock.Anything).
+					Return(entity.Holiday{}, gorm.ErrRecordNotFound)
+				employeeProfileRepository.On("GetProfileByUserID", mock.Anything).
+					Return(entity.EmployeeProfile{HireDate: time.Date(2023, 12, 4, 0, 0, 0, 0, time.UTC)}, nil)
+			},
+			wantErr: errors.New("the date is outside the employment period of the user"),
+		},
+		{
+			name: "success - user without an employee profile is not restricted",
+			request: entity.SubmitAttendanceRequest{
+				Date:         "2023-12-01",
+				CheckInTime:  "2023-12-01T08:00:00Z",
+				CheckOutTime: "2023-12-01T18:00:00Z",
+			},
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+			) {
+				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
+					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeRepository.On("GetHolidayByDate", mock.Anything).
+					Return(entity.Holiday{}, gorm.ErrRecordNotFound)
+				employeeProfileRepository.On("GetProfileByUserID", int64(0)).
+					Return(entity.EmployeeProfile{}, gorm.ErrRecordNotFound)
+				employeeRepository.On("UpsertAttendance", mock.Anything).
+					Return(nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success - submit attendance on behalf of another user",
+			userContext: entity.UserContext{
+				UserID:      112,
+				Username:    "hr",
+				Role:        entity.RoleHR,
+				Permissions: []entity.Permission{entity.PermissionSubmitOnBehalf},
+			},
+			request: entity.SubmitAttendanceRequest{
+				UserID:       332,
+				Date:         "2023-12-01",
+				CheckInTime:  "2023-12-01T08:00:00Z",
+				CheckOutTime: "2023-12-01T18:00:00Z",
+			},
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+			) {
+				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
+					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeRepository.On("GetHolidayByDate", mock.Anything).
+					Return(entity.Holiday{}, gorm.ErrRecordNotFound)
+				employeeProfileRepository.On("GetProfileByUserID", mock.Anything).
+					Return(entity.EmployeeProfile{}, gorm.ErrRecordNotFound)
+				employeeRepository.On("UpsertAttendance", mock.MatchedBy(func(attendance entity.EmployeeAttendance) bool {
+					return attendance.UserID == 332 && attendance.CreatedBy == "hr"
+				})).Return(nil)
+				auditLogRepository.On("Create", mock.MatchedBy(func(auditLog entity.AuditLog) bool {
+					return auditLog.CreatedBy == "hr" && auditLog.SubjectUserID != nil && *auditLog.SubjectUserID == 332
+				}), mock.Anything).Return(nil)
+			},
+			wantErr: nil,
+		},
 		{
 			name: "success - submit attendance",
 			request: entity.SubmitAttendanceRequest{
@@ -182,9 +300,14 @@ func Test_EmployeeUseCase_SubmitAttendance(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
 					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeRepository.On("GetHolidayByDate", mock.Anything).
+					Return(entity.Holiday{}, gorm.ErrRecordNotFound)
+				employeeProfileRepository.On("GetProfileByUserID", mock.Anything).
+					Return(entity.EmployeeProfile{}, gorm.ErrRecordNotFound)
 				employeeRepository.On("UpsertAttendance", mock.Anything).
 					Return(nil)
 				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
@@ -198,11 +321,12 @@ func Test_EmployeeUseCase_SubmitAttendance(t *testing.T) {
 			employeeRepository := mocks.NewEmployeeRepository(t)
 			payrollRepository := mocks.NewPayrollRepository(t)
 			auditLogRepository := mocks.NewAuditLogRepository(t)
+			employeeProfileRepository := mocks.NewEmployeeProfileRepository(t)
 
-			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository)
+			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository, employeeProfileRepository)
 
-			usecase := usecase.NewEmployeeUseCase(employeeRepository, payrollRepository, auditLogRepository)
-			err := usecase.SubmitAttendance(entity.UserContext{}, tt.request)
+			usecase := usecase.NewEmployeeUseCase(usecase.PayrollConfig{}, employeeRepository, employeeProfileRepository, payrollRepository, auditLogRepository)
+			err := usecase.SubmitAttendance(tt.userContext, tt.request)
 			if tt.wantErr != nil {
 				assert.Equal(t, tt.wantErr.Error(), err.Error())
 			} else {
@@ -221,6 +345,7 @@ func Test_EmployeeUseCase_SubmitOvertime(t *testing.T) {
 			employeeRepository *mocks.EmployeeRepository,
 			payrollRepository *mocks.PayrollRepository,
 			auditLogRepository *mocks.AuditLogRepository,
+			employeeProfileRepository *mocks.EmployeeProfileRepository,
 		)
 		wantErr error
 	}{
@@ -236,6 +361,7 @@ func Test_EmployeeUseCase_SubmitOvertime(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 			},
 			wantErr: errors.New("user context does not match request user ID"),
@@ -249,6 +375,7 @@ func Test_EmployeeUseCase_SubmitOvertime(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 			},
 			wantErr: errors.New("invalid date format, must be YYYY-MM-DD"),
@@ -262,24 +389,28 @@ func Test_EmployeeUseCase_SubmitOvertime(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
-					Return(entity.PayrollPeriod{ID: 1, Status: "closed"}, errors.New(""))
+					Return(entity.PayrollPeriod{ID: 1, Status: "locked"}, errors.New(""))
 			},
 			wantErr: errors.New("the overtime cannot be submitted because the payroll period is closed"),
 		},
 		{
 			name: "error - have no attendance record",
 			request: entity.SubmitOvertimeRequest{
-				Date: "2023-12-02",
+				Date: "2023-12-01",
 			},
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
 					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeRepository.On("GetHolidayByDate", mock.Anything).
+					Return(entity.Holiday{}, gorm.ErrRecordNotFound)
 				employeeRepository.On("GetAttendanceByUserAndDate", mock.Anything, mock.Anything).
 					Return(entity.EmployeeAttendance{}, gorm.ErrRecordNotFound)
 			},
@@ -288,15 +419,18 @@ func Test_EmployeeUseCase_SubmitOvertime(t *testing.T) {
 		{
 			name: "error - get attendance record",
 			request: entity.SubmitOvertimeRequest{
-				Date: "2023-12-02",
+				Date: "2023-12-01",
 			},
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
 					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeRepository.On("GetHolidayByDate", mock.Anything).
+					Return(entity.Holiday{}, gorm.ErrRecordNotFound)
 				employeeRepository.On("GetAttendanceByUserAndDate", mock.Anything, mock.Anything).
 					Return(entity.EmployeeAttendance{}, gorm.ErrInvalidDB)
 			},
@@ -305,37 +439,48 @@ func Test_EmployeeUseCase_SubmitOvertime(t *testing.T) {
 		{
 			name: "error - invalid durations",
 			request: entity.SubmitOvertimeRequest{
-				Date:      "2023-12-02",
+				Date:      "2023-12-01",
 				Durations: 6,
 			},
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
 					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeRepository.On("GetHolidayByDate", mock.Anything).
+					Return(entity.Holiday{}, gorm.ErrRecordNotFound)
 				employeeRepository.On("GetAttendanceByUserAndDate", mock.Anything, mock.Anything).
 					Return(entity.EmployeeAttendance{}, nil)
-
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
 			},
 			wantErr: errors.New("overtime durations must be between 1 and 3 hours"),
 		},
 		{
 			name: "error - upsert",
 			request: entity.SubmitOvertimeRequest{
-				Date:      "2023-12-02",
+				Date:      "2023-12-01",
 				Durations: 2,
 			},
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
 					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeRepository.On("GetHolidayByDate", mock.Anything).
+					Return(entity.Holiday{}, gorm.ErrRecordNotFound)
 				employeeRepository.On("GetAttendanceByUserAndDate", mock.Anything, mock.Anything).
 					Return(entity.EmployeeAttendance{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				employeeProfileRepository.On("GetProfileByUserID", mock.Anything).
+					Return(entity.EmployeeProfile{}, gorm.ErrRecordNotFound)
 				employeeRepository.On("UpsertOvertime", mock.Anything).Return(gorm.ErrInvalidDB)
 
 			},
@@ -344,23 +489,84 @@ func Test_EmployeeUseCase_SubmitOvertime(t *testing.T) {
 		{
 			name: "success",
 			request: entity.SubmitOvertimeRequest{
-				Date:      "2023-12-02",
+				Date:      "2023-12-01",
 				Durations: 2,
 			},
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
 					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeRepository.On("GetHolidayByDate", mock.Anything).
+					Return(entity.Holiday{}, gorm.ErrRecordNotFound)
 				employeeRepository.On("GetAttendanceByUserAndDate", mock.Anything, mock.Anything).
 					Return(entity.EmployeeAttendance{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				employeeProfileRepository.On("GetProfileByUserID", mock.Anything).
+					Return(entity.EmployeeProfile{}, gorm.ErrRecordNotFound)
 				employeeRepository.On("UpsertOvertime", mock.Anything).Return(nil)
 				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
 			},
 			wantErr: nil,
 		},
+		{
+			name: "success - overtime on a weekend does not need attendance",
+			request: entity.SubmitOvertimeRequest{
+				Date:      "2023-12-02",
+				Durations: 2,
+			},
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+			) {
+				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
+					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeRepository.On("GetHolidayByDate", mock.Anything).
+					Return(entity.Holiday{}, gorm.ErrRecordNotFound)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				employeeProfileRepository.On("GetProfileByUserID", mock.Anything).
+					Return(entity.EmployeeProfile{}, gorm.ErrRecordNotFound)
+				employeeRepository.On("UpsertOvertime", mock.MatchedBy(func(overtime entity.EmployeeOvertime) bool {
+					return !overtime.OnHoliday
+				})).Return(nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success - overtime on a holiday is flagged and does not need attendance",
+			request: entity.SubmitOvertimeRequest{
+				Date:      "2023-12-25",
+				Durations: 3,
+			},
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+			) {
+				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
+					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeRepository.On("GetHolidayByDate", time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC)).
+					Return(entity.Holiday{ID: 3, Date: time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC), Name: "Christmas Day"}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				employeeProfileRepository.On("GetProfileByUserID", mock.Anything).
+					Return(entity.EmployeeProfile{}, gorm.ErrRecordNotFound)
+				employeeRepository.On("UpsertOvertime", mock.MatchedBy(func(overtime entity.EmployeeOvertime) bool {
+					return overtime.OnHoliday && overtime.Durations == 3
+				})).Return(nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantErr: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -368,10 +574,11 @@ func Test_EmployeeUseCase_SubmitOvertime(t *testing.T) {
 			employeeRepository := mocks.NewEmployeeRepository(t)
 			payrollRepository := mocks.NewPayrollRepository(t)
 			auditLogRepository := mocks.NewAuditLogRepository(t)
+			employeeProfileRepository := mocks.NewEmployeeProfileRepository(t)
 
-			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository)
+			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository, employeeProfileRepository)
 
-			usecase := usecase.NewEmployeeUseCase(employeeRepository, payrollRepository, auditLogRepository)
+			usecase := usecase.NewEmployeeUseCase(usecase.PayrollConfig{}, employeeRepository, employeeProfileRepository, payrollRepository, auditLogRepository)
 			err := usecase.SubmitOvertime(entity.UserContext{}, tt.request)
 			if tt.wantErr != nil {
 				assert.Equal(t, tt.wantErr.Error(), err.Error())
@@ -391,6 +598,7 @@ func Test_EmployeeUseCase_SubmitReimbursement(t *testing.T) {
 			employeeRepository *mocks.EmployeeRepository,
 			payrollRepository *mocks.PayrollRepository,
 			auditLogRepository *mocks.AuditLogRepository,
+			employeeProfileRepository *mocks.EmployeeProfileRepository,
 		)
 		wantErr error
 	}{
@@ -406,6 +614,7 @@ func Test_EmployeeUseCase_SubmitReimbursement(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 			},
 			wantErr: errors.New("user context does not match request user ID"),
@@ -419,6 +628,7 @@ func Test_EmployeeUseCase_SubmitReimbursement(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 			},
 			wantErr: errors.New("invalid date format, must be YYYY-MM-DD"),
@@ -432,9 +642,10 @@ func Test_EmployeeUseCase_SubmitReimbursement(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
-					Return(entity.PayrollPeriod{ID: 1, Status: "closed"}, errors.New(""))
+					Return(entity.PayrollPeriod{ID: 1, Status: "locked"}, errors.New(""))
 			},
 			wantErr: errors.New("the reimbursement cannot be submitted because the payroll period is closed"),
 		},
@@ -447,9 +658,12 @@ func Test_EmployeeUseCase_SubmitReimbursement(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
 					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeProfileRepository.On("GetProfileByUserID", mock.Anything).
+					Return(entity.EmployeeProfile{}, gorm.ErrRecordNotFound)
 				employeeRepository.On("UpsertReimbursement", mock.Anything).Return(gorm.ErrInvalidDB)
 			},
 			wantErr: gorm.ErrInvalidDB,
@@ -463,9 +677,12 @@ func Test_EmployeeUseCase_SubmitReimbursement(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByEntityDate", mock.Anything).
 					Return(entity.PayrollPeriod{ID: 1, Status: "open"}, nil)
+				employeeProfileRepository.On("GetProfileByUserID", mock.Anything).
+					Return(entity.EmployeeProfile{}, gorm.ErrRecordNotFound)
 				employeeRepository.On("UpsertReimbursement", mock.Anything).Return(nil)
 				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
 			},
@@ -478,10 +695,11 @@ func Test_EmployeeUseCase_SubmitReimbursement(t *testing.T) {
 			employeeRepository := mocks.NewEmployeeRepository(t)
 			payrollRepository := mocks.NewPayrollRepository(t)
 			auditLogRepository := mocks.NewAuditLogRepository(t)
+			employeeProfileRepository := mocks.NewEmployeeProfileRepository(t)
 
-			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository)
+			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository, employeeProfileRepository)
 
-			usecase := usecase.NewEmployeeUseCase(employeeRepository, payrollRepository, auditLogRepository)
+			usecase := usecase.NewEmployeeUseCase(usecase.PayrollConfig{}, employeeRepository, employeeProfileRepository, payrollRepository, auditLogRepository)
 			err := usecase.SubmitReimbursement(entity.UserContext{}, tt.request)
 			if tt.wantErr != nil {
 				assert.Equal(t, tt.wantErr.Error(), err.Error())
@@ -502,6 +720,8 @@ func Test_EmployeeUseCase_GetPayslipBreakdown(t *testing.T) {
 			auditLogRepository *mocks.AuditLogRepository,
 		)
 		wantErr error
+		// wantKeys are the payslips in the response, out of the live calculation and the approved payslip.
+		wantKeys []string
 	}{
 		{
 			name: "error - GetPeriodByID",
@@ -516,17 +736,70 @@ func Test_EmployeeUseCase_GetPayslipBreakdown(t *testing.T) {
 			wantErr: gorm.ErrInvalidDB,
 		},
 		{
-			name: "error - GetEmployeeBaseSalaryByPeriodStart",
+			name: "error - GetAllAttendanceByTimeRange",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "open"}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{}, gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name: "error - GetAllOvertimeByTimeRange",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "open"}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name: "error - GetAllReimbursementByTimeRange",
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{}, nil)
-				employeeRepository.On("GetEmployeeBaseSalaryByPeriodStart", mock.Anything, mock.Anything).
+					Return(entity.PayrollPeriod{Status: "open"}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name: "error - GetEmployeeBaseSalaryByPeriod",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "open"}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeBaseSalary{}, gorm.ErrInvalidDB)
-
 			},
 			wantErr: gorm.ErrInvalidDB,
 		},
@@ -538,111 +811,184 @@ func Test_EmployeeUseCase_GetPayslipBreakdown(t *testing.T) {
 				auditLogRepository *mocks.AuditLogRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{}, nil)
-				employeeRepository.On("GetEmployeeBaseSalaryByPeriodStart", mock.Anything, mock.Anything).
+					Return(entity.PayrollPeriod{Status: "open"}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeBaseSalary{}, nil)
-
 			},
 			wantErr: errors.New("base salary not found for the user in this period"),
 		},
 		{
-			name: "error - GetAllAttendanceByTimeRange",
+			name: "error - GetPayslipByRunID",
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{}, nil)
-				employeeRepository.On("GetEmployeeBaseSalaryByPeriodStart", mock.Anything, mock.Anything).
-					Return([]entity.EmployeeBaseSalary{{UserID: 1, BaseSalary: 2000}}, nil)
+					Return(entity.PayrollPeriod{Status: "approved"}, nil)
 				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
-					Return([]entity.EmployeeAttendance{}, gorm.ErrInvalidDB)
+					Return([]entity.EmployeeAttendance{}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{ID: 3, Version: 1}, nil)
+				payrollRepository.On("GetPayslipByRunID", int64(3), mock.Anything).
+					Return(entity.PayrollPayslip{}, gorm.ErrInvalidDB)
 			},
 			wantErr: gorm.ErrInvalidDB,
 		},
 		{
-			name: "error - GetAllOvertimeByTimeRange",
+			name: "success - open period shows the live calculation",
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{}, nil)
-				employeeRepository.On("GetEmployeeBaseSalaryByPeriodStart", mock.Anything, mock.Anything).
-					Return([]entity.EmployeeBaseSalary{{UserID: 1, BaseSalary: 2000}}, nil)
+					Return(entity.PayrollPeriod{Status: "open"}, nil)
 				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeAttendance{}, nil)
 				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
-					Return([]entity.EmployeeOvertime{}, gorm.ErrInvalidDB)
-			},
-			wantErr: gorm.ErrInvalidDB,
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{UserID: 1, BaseSalary: decimal.NewFromInt(2000)}}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+			},
+			wantKeys: []string{"payslip_summary_calculated"},
 		},
 		{
-			name: "error - GetAllReimbursementByTimeRange",
+			name: "success - locked period shows no payslip before approval",
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{}, nil)
-				employeeRepository.On("GetEmployeeBaseSalaryByPeriodStart", mock.Anything, mock.Anything).
-					Return([]entity.EmployeeBaseSalary{{UserID: 1, BaseSalary: 2000}}, nil)
+					Return(entity.PayrollPeriod{Status: "locked"}, nil)
 				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeAttendance{}, nil)
 				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeOvertime{}, nil)
 				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
-					Return([]entity.EmployeeReimbursement{}, gorm.ErrInvalidDB)
+					Return([]entity.EmployeeReimbursement{}, nil)
 			},
-			wantErr: gorm.ErrInvalidDB,
+			wantKeys: []string{},
 		},
 		{
-			name: "error - GetPayslip",
+			name: "success - calculated period shows no payslip before approval",
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{Status: "closed"}, nil)
-				payrollRepository.On("GetPayslip", mock.Anything, mock.Anything).
-					Return(entity.PayrollPayslip{}, gorm.ErrInvalidDB)
-
-				employeeRepository.On("GetEmployeeBaseSalaryByPeriodStart", mock.Anything, mock.Anything).
-					Return([]entity.EmployeeBaseSalary{{UserID: 1, BaseSalary: 2000}}, nil)
+					Return(entity.PayrollPeriod{Status: "calculated"}, nil)
 				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeAttendance{}, nil)
 				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeOvertime{}, nil)
 				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeReimbursement{}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{}, gorm.ErrRecordNotFound)
 			},
-			wantErr: gorm.ErrInvalidDB,
+			wantKeys: []string{},
 		},
 		{
-			name: "success",
+			name: "success - approved period shows the approved payslip only",
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{Status: "closed"}, nil)
-				payrollRepository.On("GetPayslip", mock.Anything, mock.Anything).
+					Return(entity.PayrollPeriod{Status: "approved"}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{ID: 3, Version: 1}, nil)
+				payrollRepository.On("GetPayslipByRunID", int64(3), mock.Anything).
 					Return(entity.PayrollPayslip{}, nil)
-
-				employeeRepository.On("GetEmployeeBaseSalaryByPeriodStart", mock.Anything, mock.Anything).
-					Return([]entity.EmployeeBaseSalary{{UserID: 1, BaseSalary: 2000}}, nil)
+			},
+			wantKeys: []string{"payslip_summary_admin_generated"},
+		},
+		{
+			name: "success - paid period shows the approved payslip only",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "paid"}, nil)
 				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeAttendance{}, nil)
 				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeOvertime{}, nil)
 				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeReimbursement{}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{ID: 3, Version: 1}, nil)
+				payrollRepository.On("GetPayslipByRunID", int64(3), mock.Anything).
+					Return(entity.PayrollPayslip{}, nil)
 			},
+			wantKeys: []string{"payslip_summary_admin_generated"},
+		},
+		{
+			name:        "success - payslip.read_all holders see the live calculation of a calculated period",
+			userContext: entity.UserContext{Permissions: []entity.Permission{entity.PermissionPayslipReadAll}},
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "calculated"}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{UserID: 1, BaseSalary: decimal.NewFromInt(2000)}}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{}, gorm.ErrRecordNotFound)
+			},
+			wantKeys: []string{"payslip_summary_calculated"},
 		},
 	}
 
@@ -654,12 +1000,19 @@ func Test_EmployeeUseCase_GetPayslipBreakdown(t *testing.T) {
 
 			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository)
 
-			usecase := usecase.NewEmployeeUseCase(employeeRepository, payrollRepository, auditLogRepository)
-			_, err := usecase.GetPayslipBreakdown(entity.UserContext{}, 123)
+			usecase := usecase.NewEmployeeUseCase(usecase.PayrollConfig{}, employeeRepository, mocks.NewEmployeeProfileRepository(t), payrollRepository, auditLogRepository)
+			res, err := usecase.GetPayslipBreakdown(tt.userContext, 123)
 			if tt.wantErr != nil {
 				assert.Equal(t, tt.wantErr.Error(), err.Error())
 			} else {
 				assert.NoError(t, err)
+				payslips := []string{}
+				for _, key := range []string{"payslip_summary_calculated", "payslip_summary_admin_generated"} {
+					if _, ok := res.(map[string]interface{})[key]; ok {
+						payslips = append(payslips, key)
+					}
+				}
+				assert.Equal(t, tt.wantKeys, payslips)
 			}
 		})
 	}
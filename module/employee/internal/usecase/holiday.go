@@ -0,0 +1,596 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	HolidayImportCSV  = "csv"
+	HolidayImportICal = "ical"
+)
+
+//go:generate mockery --name HolidayUseCase --output ./mocks
+type HolidayUseCase interface {
+	ListHolidays(year int) ([]entity.Holiday, error)
+
+	CreateHoliday(userContext entity.UserContext, request entity.HolidayRequest) (entity.Holiday, error)
+	UpdateHoliday(userContext entity.UserContext, holidayID int64, request entity.HolidayRequest) (entity.Holiday, error)
+	DeleteHoliday(userContext entity.UserContext, holidayID int64) error
+	ImportHolidays(userContext entity.UserContext, year int, format string, content []byte) (entity.HolidayImportResult, error)
+}
+
+type HolidayUseCaseImpl struct {
+	holidayRepository  HolidayRepository
+	payrollRepository  PayrollRepository
+	auditLogRepository AuditLogRepository
+}
+
+func NewHolidayUseCase(
+	holidayRepository HolidayRepository,
+	payrollRepository PayrollRepository,
+	auditLogRepository AuditLogRepository,
+) *HolidayUseCaseImpl {
+	return &HolidayUseCaseImpl{
+		holidayRepository:  holidayRepository,
+		payrollRepository:  payrollRepository,
+		auditLogRepository: auditLogRepository,
+	}
+}
+
+func (h *HolidayUseCaseImpl) ListHolidays(year int) ([]entity.Holiday, error) {
+	if year < 2000 || year > 9999 {
+		return nil, errors.New("year must be between 2000 and 9999")
+	}
+
+	holidays, err := h.holidayRepository.GetHolidaysByTimeRange(yearBounds(year))
+	if err != nil {
+		log.Println(
+			"error when GetHolidaysByTimeRange",
+			zap.String("method", "HolidayUseCaseImpl.ListHolidays"),
+			zap.Int("year", year),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return holidays, nil
+}
+
+/*
+A date can only be a holiday once. Holidays cannot be added on or before the end of the latest closed period,
+whose working days and payslips are final. The working days of the period the date falls in are recalculated.
+*/
+func (h *HolidayUseCaseImpl) CreateHoliday(userContext entity.UserContext, request entity.HolidayRequest) (entity.Holiday, error) {
+	date, name, err := parseHolidayRequest(request)
+	if err != nil {
+		return entity.Holiday{}, err
+	}
+
+	if err := h.ensureChangeable(userContext, "HolidayUseCaseImpl.CreateHoliday", date); err != nil {
+		return entity.Holiday{}, err
+	}
+
+	if err := h.ensureDateIsFree(userContext, "HolidayUseCaseImpl.CreateHoliday", date, 0); err != nil {
+		return entity.Holiday{}, err
+	}
+
+	holiday, err := h.holidayRepository.CreateHoliday(entity.Holiday{
+		Date:      date,
+		Name:      name,
+		UpdatedBy: userContext.Username,
+		CreatedBy: userContext.Username,
+	})
+	if err != nil {
+		log.Println(
+			"error when CreateHoliday",
+			zap.String("method", "HolidayUseCaseImpl.CreateHoliday"),
+			zap.Any("user_contex", userContext),
+			zap.Any("request", request),
+			zap.Error(err),
+		)
+		return entity.Holiday{}, err
+	}
+
+	if err := h.recalculateWorkingDays(userContext, "HolidayUseCaseImpl.CreateHoliday", date, date); err != nil {
+		return entity.Holiday{}, err
+	}
+
+	h.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "create",
+		Target:    "holiday",
+		TableName: "holidays",
+		CreatedBy: userContext.Username,
+	}, holiday)
+
+	return holiday, nil
+}
+
+// Both the current and the new date must be after the latest closed period, the periods of both are recalculated.
+func (h *HolidayUseCaseImpl) UpdateHoliday(userContext entity.UserContext, holidayID int64, request entity.HolidayRequest) (entity.Holiday, error) {
+	holiday, err := h.getHoliday(userContext, "HolidayUseCaseImpl.UpdateHoliday", holidayID)
+	if err != nil {
+		return entity.Holiday{}, err
+	}
+
+	date, name, err := parseHolidayRequest(request)
+	if err != nil {
+		return entity.Holiday{}, err
+	}
+
+	for _, changed := range []time.Time{holiday.Date, date} {
+		if err := h.ensureChangeable(userContext, "HolidayUseCaseImpl.UpdateHoliday", changed); err != nil {
+			return entity.Holiday{}, err
+		}
+	}
+
+	if err := h.ensureDateIsFree(userContext, "HolidayUseCaseImpl.UpdateHoliday", date, holidayID); err != nil {
+		return entity.Holiday{}, err
+	}
+
+	updates := map[string]interface{}{
+		"date":       date,
+		"name":       name,
+		"updated_by": userContext.Username,
+	}
+
+	err = h.holidayRepository.UpdateHoliday(holidayID, updates)
+	if err != nil {
+		log.Println(
+			"error when UpdateHoliday",
+			zap.String("method", "HolidayUseCaseImpl.UpdateHoliday"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("holiday_id", holidayID),
+			zap.Any("updates", updates),
+			zap.Error(err),
+		)
+		return entity.Holiday{}, err
+	}
+
+	for _, changed := range []time.Time{holiday.Date, date} {
+		if err := h.recalculateWorkingDays(userContext, "HolidayUseCaseImpl.UpdateHoliday", changed, changed); err != nil {
+			return entity.Holiday{}, err
+		}
+	}
+
+	h.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "update",
+		Target:    "holiday",
+		TableName: "holidays",
+		CreatedBy: userContext.Username,
+	}, updates)
+
+	holiday.Date = date
+	holiday.Name = name
+	holiday.UpdatedBy = userContext.Username
+	return holiday, nil
+}
+
+func (h *HolidayUseCaseImpl) DeleteHoliday(userContext entity.UserContext, holidayID int64) error {
+	holiday, err := h.getHoliday(userContext, "HolidayUseCaseImpl.DeleteHoliday", holidayID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.ensureChangeable(userContext, "HolidayUseCaseImpl.DeleteHoliday", holiday.Date); err != nil {
+		return err
+	}
+
+	err = h.holidayRepository.DeleteHoliday(holidayID)
+	if err != nil {
+		log.Println(
+			"error when DeleteHoliday",
+			zap.String("method", "HolidayUseCaseImpl.DeleteHoliday"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("holiday_id", holidayID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if err := h.recalculateWorkingDays(userContext, "HolidayUseCaseImpl.DeleteHoliday", holiday.Date, holiday.Date); err != nil {
+		return err
+	}
+
+	h.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "delete",
+		Target:    "holiday",
+		TableName: "holidays",
+		CreatedBy: userContext.Username,
+	}, holiday)
+
+	return nil
+}
+
+/*
+ImportHolidays adds the holidays of a yearly list, either a CSV with date (YYYY-MM-DD) and name columns or an
+iCal calendar whose events are all-day holidays. The format is sniffed from the content when it is empty.
+Every date must fall in the year and after the latest closed period, otherwise nothing is imported. Dates that
+already are holidays are renamed, names of several entries on the same date are joined. The working days of the
+periods the imported dates fall in are recalculated.
+*/
+func (h *HolidayUseCaseImpl) ImportHolidays(userContext entity.UserContext, year int, format string, content []byte) (entity.HolidayImportResult, error) {
+	if year < 2000 || year > 9999 {
+		return entity.HolidayImportResult{}, errors.New("year must be between 2000 and 9999")
+	}
+
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	if format == "" {
+		format = HolidayImportCSV
+		if bytes.HasPrefix(bytes.TrimSpace(content), []byte("BEGIN:VCALENDAR")) {
+			format = HolidayImportICal
+		}
+	}
+
+	var entries []entity.HolidayRequest
+	var err error
+	switch format {
+	case HolidayImportCSV:
+		entries, err = parseHolidayCSV(content)
+	case HolidayImportICal:
+		entries, err = parseHolidayICal(content, year)
+	default:
+		return entity.HolidayImportResult{}, errors.New("format must be csv or ical")
+	}
+	if err != nil {
+		return entity.HolidayImportResult{}, err
+	}
+
+	if len(entries) == 0 {
+		return entity.HolidayImportResult{}, errors.New("the file has no holidays")
+	}
+
+	from, to := yearBounds(year)
+	names := make(map[time.Time][]string)
+	for _, entry := range entries {
+		date, name, err := parseHolidayRequest(entry)
+		if err != nil {
+			return entity.HolidayImportResult{}, fmt.Errorf("%s: %w", entry.Date, err)
+		}
+		if date.Before(from) || date.After(to) {
+			return entity.HolidayImportResult{}, fmt.Errorf("%s is not in %d", entry.Date, year)
+		}
+		names[date] = append(names[date], name)
+	}
+
+	holidays := make([]entity.Holiday, 0, len(names))
+	for date, dateNames := range names {
+		holidays = append(holidays, entity.Holiday{
+			Date:      date,
+			Name:      strings.Join(dateNames, " / "),
+			UpdatedBy: userContext.Username,
+			CreatedBy: userContext.Username,
+		})
+	}
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date.Before(holidays[j].Date) })
+
+	if err := h.ensureChangeable(userContext, "HolidayUseCaseImpl.ImportHolidays", holidays[0].Date); err != nil {
+		return entity.HolidayImportResult{}, err
+	}
+
+	existing, err := h.holidayRepository.GetHolidaysByTimeRange(from, to)
+	if err != nil {
+		log.Println(
+			"error when GetHolidaysByTimeRange",
+			zap.String("method", "HolidayUseCaseImpl.ImportHolidays"),
+			zap.Any("user_contex", userContext),
+			zap.Int("year", year),
+			zap.Error(err),
+		)
+		return entity.HolidayImportResult{}, err
+	}
+
+	existingNames := make(map[time.Time]string, len(existing))
+	for _, holiday := range existing {
+		existingNames[holiday.Date] = holiday.Name
+	}
+
+	result := entity.HolidayImportResult{}
+	for _, holiday := range holidays {
+		name, exists := existingNames[holiday.Date]
+		if !exists {
+			result.Created++
+		} else if name != holiday.Name {
+			result.Updated++
+		}
+	}
+
+	result.Holidays, err = h.holidayRepository.UpsertHolidays(holidays)
+	if err != nil {
+		log.Println(
+			"error when UpsertHolidays",
+			zap.String("method", "HolidayUseCaseImpl.ImportHolidays"),
+			zap.Any("user_contex", userContext),
+			zap.Int("year", year),
+			zap.Error(err),
+		)
+		return entity.HolidayImportResult{}, err
+	}
+
+	if err := h.recalculateWorkingDays(userContext, "HolidayUseCaseImpl.ImportHolidays", holidays[0].Date, holidays[len(holidays)-1].Date); err != nil {
+		return entity.HolidayImportResult{}, err
+	}
+
+	h.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "import",
+		Target:    "holiday",
+		TableName: "holidays",
+		CreatedBy: userContext.Username,
+	}, result)
+
+	return result, nil
+}
+
+func (h *HolidayUseCaseImpl) getHoliday(userContext entity.UserContext, method string, holidayID int64) (entity.Holiday, error) {
+	holiday, err := h.holidayRepository.GetHolidayByID(holidayID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Holiday{}, errors.New("holiday not found")
+		}
+		log.Println(
+			"error when GetHolidayByID",
+			zap.String("method", method),
+			zap.Any("user_contex", userContext),
+			zap.Int64("holiday_id", holidayID),
+			zap.Error(err),
+		)
+		return entity.Holiday{}, err
+	}
+	return holiday, nil
+}
+
+// ensureChangeable rejects dates on or before the end of the latest closed period.
+func (h *HolidayUseCaseImpl) ensureChangeable(userContext entity.UserContext, method string, date time.Time) error {
+	closedPeriod, err := h.payrollRepository.GetLatestClosedPeriod()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println(
+			"error when GetLatestClosedPeriod",
+			zap.String("method", method),
+			zap.Any("user_contex", userContext),
+			zap.Error(err),
+		)
+		return err
+	}
+	if err == nil && !date.After(closedPeriod.PeriodEnd) {
+		return fmt.Errorf(
+			"holidays on or before %s, the end of the latest closed payroll period, cannot be changed",
+			closedPeriod.PeriodEnd.Format("2006-01-02"),
+		)
+	}
+	return nil
+}
+
+/*
+recalculateWorkingDays sets the working days of the periods sharing a day with [from, to] to their weekdays that are
+not holidays. Periods whose payslips have been calculated are left as they are, they are final or get reopened.
+*/
+func (h *HolidayUseCaseImpl) recalculateWorkingDays(userContext entity.UserContext, method string, from time.Time, to time.Time) error {
+	periods, err := h.payrollRepository.GetOverlappingPeriods(from, to, 0)
+	if err != nil {
+		log.Println(
+			"error when GetOverlappingPeriods",
+			zap.String("method", method),
+			zap.Any("user_contex", userContext),
+			zap.Time("from", from),
+			zap.Time("to", to),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	for _, period := range periods {
+		if period.Status.HasPayslips() {
+			continue
+		}
+
+		holidays, err := h.holidayRepository.GetHolidaysByTimeRange(period.PeriodStart, period.PeriodEnd)
+		if err != nil {
+			log.Println(
+				"error when GetHolidaysByTimeRange",
+				zap.String("method", method),
+				zap.Any("user_contex", userContext),
+				zap.Int64("period_id", period.ID),
+				zap.Error(err),
+			)
+			return err
+		}
+
+		workingDays := entity.CountWorkingDays(period.PeriodStart, period.PeriodEnd, holidays)
+		if workingDays == period.WorkingDays {
+			continue
+		}
+
+		updates := map[string]interface{}{
+			"working_days": workingDays,
+			"updated_by":   userContext.Username,
+		}
+		err = h.payrollRepository.UpdatePeriod(period.ID, period.Status, updates)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("payroll period %d is no longer %s, it was changed by another request", period.ID, period.Status)
+		}
+		if err != nil {
+			log.Println(
+				"error when UpdatePeriod",
+				zap.String("method", method),
+				zap.Any("user_contex", userContext),
+				zap.Int64("period_id", period.ID),
+				zap.Any("updates", updates),
+				zap.Error(err),
+			)
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *HolidayUseCaseImpl) ensureDateIsFree(userContext entity.UserContext, method string, date time.Time, excludeID int64) error {
+	holidays, err := h.holidayRepository.GetHolidaysByTimeRange(date, date)
+	if err != nil {
+		log.Println(
+			"error when GetHolidaysByTimeRange",
+			zap.String("method", method),
+			zap.Any("user_contex", userContext),
+			zap.Time("date", date),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	for _, holiday := range holidays {
+		if holiday.ID != excludeID {
+			return fmt.Errorf("%s is already a holiday (%s)", date.Format("2006-01-02"), holiday.Name)
+		}
+	}
+	return nil
+}
+
+func parseHolidayRequest(request entity.HolidayRequest) (time.Time, string, error) {
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(request.Date))
+	if err != nil {
+		return time.Time{}, "", errors.New("invalid date format, must be YYYY-MM-DD")
+	}
+
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return time.Time{}, "", errors.New("name is required")
+	}
+	return date, name, nil
+}
+
+// parseHolidayCSV reads date,name rows, a first row that is not a date is taken as the header.
+func parseHolidayCSV(content []byte) ([]entity.HolidayRequest, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var entries []entity.HolidayRequest
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		if line == 1 {
+			if _, err := time.Parse("2006-01-02", strings.TrimSpace(record[0])); err != nil {
+				continue
+			}
+		}
+
+		if len(record) < 2 {
+			return nil, fmt.Errorf("line %d must have a date and a name", line)
+		}
+		entries = append(entries, entity.HolidayRequest{Date: record[0], Name: record[1]})
+	}
+	return entries, nil
+}
+
+/*
+parseHolidayICal reads the VEVENTs of a calendar, DTSTART is the holiday and SUMMARY its name. An event whose
+DTEND is more than a day later is a holiday on every day until DTEND, which is exclusive. Only the days in year
+are expanded, an event outside of it keeps its DTSTART so the import rejects it.
+*/
+func parseHolidayICal(content []byte, year int) ([]entity.HolidayRequest, error) {
+	yearStart, yearEnd := yearBounds(year)
+
+	// Lines starting with a space or a tab continue the previous line.
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid iCal: %w", err)
+	}
+
+	var entries []entity.HolidayRequest
+	var inEvent bool
+	var start, end, summary string
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent = true
+			start, end, summary = "", "", ""
+		case name == "END" && value == "VEVENT":
+			inEvent = false
+			from, err := parseICalDate(start)
+			if err != nil {
+				return nil, err
+			}
+			to := from
+			if end != "" {
+				if to, err = parseICalDate(end); err != nil {
+					return nil, err
+				}
+				to = to.AddDate(0, 0, -1)
+			}
+			if to.After(yearEnd) {
+				to = yearEnd
+			}
+			if from.Before(yearStart) && !to.Before(yearStart) {
+				from = yearStart
+			}
+			if to.Before(from) {
+				to = from
+			}
+			for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+				entries = append(entries, entity.HolidayRequest{Date: day.Format("2006-01-02"), Name: summary})
+			}
+		case inEvent && name == "DTSTART":
+			start = value
+		case inEvent && name == "DTEND":
+			end = value
+		case inEvent && name == "SUMMARY":
+			summary = strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\\`, `\`).Replace(value)
+		}
+	}
+	return entries, nil
+}
+
+func parseICalDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid iCal date %q", value)
+	}
+
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid iCal date %q", value)
+	}
+	return date, nil
+}
+
+func yearBounds(year int) (time.Time, time.Time) {
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,362 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func Test_HolidayUseCase_CreateHoliday(t *testing.T) {
+	request := entity.HolidayRequest{Date: "2025-08-17", Name: " Independence Day "}
+
+	tests := []struct {
+		name     string
+		request  entity.HolidayRequest
+		mockFunc func(
+			holidayRepository *mocks.HolidayRepository,
+			payrollRepository *mocks.PayrollRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+		wantRes entity.Holiday
+	}{
+		{
+			name:    "error - name is required",
+			request: entity.HolidayRequest{Date: "2025-08-17"},
+			mockFunc: func(
+				holidayRepository *mocks.HolidayRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("name is required"),
+		},
+		{
+			name:    "error - date is in a closed period",
+			request: request,
+			mockFunc: func(
+				holidayRepository *mocks.HolidayRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetLatestClosedPeriod").
+					Return(entity.PayrollPeriod{PeriodEnd: time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)}, nil)
+			},
+			wantErr: errors.New("holidays on or before 2025-08-31, the end of the latest closed payroll period, cannot be changed"),
+		},
+		{
+			name:    "error - date is already a holiday",
+			request: request,
+			mockFunc: func(
+				holidayRepository *mocks.HolidayRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetLatestClosedPeriod").Return(entity.PayrollPeriod{}, gorm.ErrRecordNotFound)
+				holidayRepository.On("GetHolidaysByTimeRange", time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)).
+					Return([]entity.Holiday{{ID: 4, Name: "Hari Kemerdekaan"}}, nil)
+			},
+			wantErr: errors.New("2025-08-17 is already a holiday (Hari Kemerdekaan)"),
+		},
+		{
+			name:    "success",
+			request: request,
+			mockFunc: func(
+				holidayRepository *mocks.HolidayRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetLatestClosedPeriod").Return(entity.PayrollPeriod{}, gorm.ErrRecordNotFound)
+				holidayRepository.On("GetHolidaysByTimeRange", mock.Anything, mock.Anything).Return([]entity.Holiday{}, nil)
+				holidayRepository.On("CreateHoliday", mock.Anything).
+					Return(func(holiday entity.Holiday) (entity.Holiday, error) {
+						holiday.ID = 9
+						return holiday, nil
+					})
+				payrollRepository.On("GetOverlappingPeriods", mock.Anything, mock.Anything, int64(0)).Return([]entity.PayrollPeriod{}, nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantRes: entity.Holiday{
+				ID:        9,
+				Date:      time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC),
+				Name:      "Independence Day",
+				UpdatedBy: "hr",
+				CreatedBy: "hr",
+			},
+		},
+		{
+			name:    "success - working days of the period are recalculated until it is calculated",
+			request: entity.HolidayRequest{Date: "2025-08-18", Name: "Independence Day"},
+			mockFunc: func(
+				holidayRepository *mocks.HolidayRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				date := time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+				payrollRepository.On("GetLatestClosedPeriod").Return(entity.PayrollPeriod{}, gorm.ErrRecordNotFound)
+				holidayRepository.On("GetHolidaysByTimeRange", date, date).Return([]entity.Holiday{}, nil)
+				holidayRepository.On("CreateHoliday", mock.Anything).
+					Return(func(holiday entity.Holiday) (entity.Holiday, error) {
+						holiday.ID = 9
+						return holiday, nil
+					})
+				payrollRepository.On("GetOverlappingPeriods", date, date, int64(0)).
+					Return([]entity.PayrollPeriod{
+						{
+							ID:          5,
+							PeriodStart: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+							PeriodEnd:   time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC),
+							WorkingDays: 21,
+							Status:      entity.PayrollStatusLocked,
+						},
+						{
+							ID:          6,
+							PeriodStart: time.Date(2025, 8, 10, 0, 0, 0, 0, time.UTC),
+							PeriodEnd:   time.Date(2025, 9, 9, 0, 0, 0, 0, time.UTC),
+							WorkingDays: 22,
+							Status:      entity.PayrollStatusCalculated,
+						},
+					}, nil)
+				holidayRepository.On("GetHolidaysByTimeRange", time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)).
+					Return([]entity.Holiday{{ID: 9, Date: date, Name: "Independence Day"}}, nil)
+				payrollRepository.On("UpdatePeriod", int64(5), entity.PayrollStatusLocked, map[string]interface{}{"working_days": 20, "updated_by": "hr"}).Return(nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantRes: entity.Holiday{
+				ID:        9,
+				Date:      time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC),
+				Name:      "Independence Day",
+				UpdatedBy: "hr",
+				CreatedBy: "hr",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			holidayRepository := mocks.NewHolidayRepository(t)
+			payrollRepository := mocks.NewPayrollRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			tt.mockFunc(holidayRepository, payrollRepository, auditLogRepository)
+
+			usecase := usecase.NewHolidayUseCase(holidayRepository, payrollRepository, auditLogRepository)
+			res, err := usecase.CreateHoliday(entity.UserContext{Username: "hr"}, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.Equal(t, tt.wantRes, res)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_HolidayUseCase_ImportHolidays(t *testing.T) {
+	csv := "date,name\n2025-01-01,New Year's Day\n2025-03-31,Idul Fitri\n2025-03-31,\"Eid al-Fitr, day 1\"\n"
+	ical := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20250101\r\n" +
+		"DTEND;VALUE=DATE:20250102\r\n" +
+		"SUMMARY:New Year's Day\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20250331\r\n" +
+		"DTEND;VALUE=DATE:20250402\r\n" +
+		"SUMMARY:Eid al-Fitr\\, joint\r\n" +
+		"  leave\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	tests := []struct {
+		name     string
+		format   string
+		content  string
+		mockFunc func(
+			holidayRepository *mocks.HolidayRepository,
+			payrollRepository *mocks.PayrollRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+		check   func(t *testing.T, result entity.HolidayImportResult)
+	}{
+		{
+			name:    "error - unknown format",
+			format:  "xlsx",
+			content: csv,
+			mockFunc: func(
+				holidayRepository *mocks.HolidayRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("format must be csv or ical"),
+		},
+		{
+			name:    "error - row without a name",
+			content: "2025-01-01\n",
+			mockFunc: func(
+				holidayRepository *mocks.HolidayRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("line 1 must have a date and a name"),
+		},
+		{
+			name:    "error - date outside the year",
+			content: "2025-12-31,New Year's Eve\n2026-01-01,New Year's Day\n",
+			mockFunc: func(
+				holidayRepository *mocks.HolidayRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("2026-01-01 is not in 2025"),
+		},
+		{
+			name: "error - ical event outside the year",
+			content: "BEGIN:VCALENDAR\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"DTSTART;VALUE=DATE:20241225\r\n" +
+				"DTEND;VALUE=DATE:20241227\r\n" +
+				"SUMMARY:Christmas\r\n" +
+				"END:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+			mockFunc: func(
+				holidayRepository *mocks.HolidayRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("2024-12-25 is not in 2025"),
+		},
+		{
+			name:    "error - date is in a closed period",
+			content: csv,
+			mockFunc: func(
+				holidayRepository *mocks.HolidayRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetLatestClosedPeriod").
+					Return(entity.PayrollPeriod{PeriodEnd: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)}, nil)
+			},
+			wantErr: errors.New("holidays on or before 2025-01-31, the end of the latest closed payroll period, cannot be changed"),
+		},
+		{
+			name:    "success - csv with a header, names of the same date are joined",
+			format:  usecase.HolidayImportCSV,
+			content: csv,
+			mockFunc: func(
+				holidayRepository *mocks.HolidayRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetLatestClosedPeriod").Return(entity.PayrollPeriod{}, gorm.ErrRecordNotFound)
+				holidayRepository.On("GetHolidaysByTimeRange", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)).
+					Return([]entity.Holiday{{ID: 1, Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Name: "New Year's Day"}}, nil)
+				holidayRepository.On("UpsertHolidays", mock.Anything).
+					Return(func(holidays []entity.Holiday) ([]entity.Holiday, error) {
+						return holidays, nil
+					})
+				payrollRepository.On("GetOverlappingPeriods", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), mock.Anything, int64(0)).
+					Return([]entity.PayrollPeriod{}, nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			check: func(t *testing.T, result entity.HolidayImportResult) {
+				assert.Equal(t, 1, result.Created)
+				assert.Equal(t, 0, result.Updated)
+				assert.Len(t, result.Holidays, 2)
+				assert.Equal(t, time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), result.Holidays[1].Date)
+				assert.Equal(t, "Idul Fitri / Eid al-Fitr, day 1", result.Holidays[1].Name)
+				assert.Equal(t, "hr", result.Holidays[1].CreatedBy)
+			},
+		},
+		{
+			name:    "success - ical is sniffed, multi-day events cover every day",
+			content: ical,
+			mockFunc: func(
+				holidayRepository *mocks.HolidayRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetLatestClosedPeriod").Return(entity.PayrollPeriod{}, gorm.ErrRecordNotFound)
+				holidayRepository.On("GetHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{{ID: 1, Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Name: "Tahun Baru"}}, nil)
+				holidayRepository.On("UpsertHolidays", mock.Anything).
+					Return(func(holidays []entity.Holiday) ([]entity.Holiday, error) {
+						return holidays, nil
+					})
+				payrollRepository.On("GetOverlappingPeriods", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), mock.Anything, int64(0)).
+					Return([]entity.PayrollPeriod{}, nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			check: func(t *testing.T, result entity.HolidayImportResult) {
+				assert.Equal(t, 2, result.Created)
+				assert.Equal(t, 1, result.Updated)
+				assert.Len(t, result.Holidays, 3)
+				assert.Equal(t, "New Year's Day", result.Holidays[0].Name)
+				assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), result.Holidays[2].Date)
+				assert.Equal(t, "Eid al-Fitr, joint leave", result.Holidays[2].Name)
+			},
+		},
+		{
+			name: "success - ical events are only expanded within the year",
+			content: "BEGIN:VCALENDAR\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"DTSTART;VALUE=DATE:20241230\r\n" +
+				"DTEND;VALUE=DATE:99991231\r\n" +
+				"SUMMARY:Sabbatical\r\n" +
+				"END:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+			mockFunc: func(
+				holidayRepository *mocks.HolidayRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetLatestClosedPeriod").Return(entity.PayrollPeriod{}, gorm.ErrRecordNotFound)
+				holidayRepository.On("GetHolidaysByTimeRange", mock.Anything, mock.Anything).Return([]entity.Holiday{}, nil)
+				holidayRepository.On("UpsertHolidays", mock.Anything).
+					Return(func(holidays []entity.Holiday) ([]entity.Holiday, error) {
+						return holidays, nil
+					})
+				payrollRepository.On("GetOverlappingPeriods", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), int64(0)).
+					Return([]entity.PayrollPeriod{}, nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			check: func(t *testing.T, result entity.HolidayImportResult) {
+				assert.Equal(t, 365, result.Created)
+				assert.Len(t, result.Holidays, 365)
+				assert.Equal(t, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), result.Holidays[364].Date)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			holidayRepository := mocks.NewHolidayRepository(t)
+			payrollRepository := mocks.NewPayrollRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			tt.mockFunc(holidayRepository, payrollRepository, auditLogRepository)
+
+			usecase := usecase.NewHolidayUseCase(holidayRepository, payrollRepository, auditLogRepository)
+			res, err := usecase.ImportHolidays(entity.UserContext{Username: "hr"}, 2025, tt.format, []byte(tt.content))
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				tt.check(t, res)
+			}
+		})
+	}
+}
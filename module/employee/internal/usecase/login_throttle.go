@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+)
+
+type LoginThrottleConfig struct {
+	// MaxFailedAttempts is the number of consecutive failures for a username before it is locked.
+	// An IP address is allowed ipAttemptsMultiplier times as many, since kiosks share one address.
+	MaxFailedAttempts int
+	LockoutDuration   time.Duration
+}
+
+const (
+	// failures allowed before every further attempt has to wait
+	freeLoginAttempts    = 2
+	baseLoginBackoff     = time.Second
+	ipAttemptsMultiplier = 4
+	maxTrackedLoginKeys  = 10000
+)
+
+type loginAttempt struct {
+	failures int
+	// attempts that passed the check and have not failed or succeeded yet
+	pending     int
+	lastAttempt time.Time
+	lastFailure time.Time
+	lockedUntil time.Time
+	// the IP keys the attempts on a username came from, unlocking the username unlocks them as well
+	ipKeys map[string]struct{}
+}
+
+/*
+loginThrottle keeps failed-attempt counters in memory, keyed by "user:<username>" or "ip:<address>".
+Counters are per instance; they are reset on a successful login or after a quiet LockoutDuration.
+An attempt is reserved before the password is checked and then finished with recordFailure, cancel or reset, so
+attempts made in parallel count against the limits before any of them has failed.
+*/
+type loginThrottle struct {
+	mu       sync.Mutex
+	config   LoginThrottleConfig
+	attempts map[string]*loginAttempt
+}
+
+func newLoginThrottle(config LoginThrottleConfig) *loginThrottle {
+	if config.MaxFailedAttempts <= 0 {
+		config.MaxFailedAttempts = 5
+	}
+	if config.LockoutDuration <= 0 {
+		config.LockoutDuration = 15 * time.Minute
+	}
+
+	return &loginThrottle{
+		config:   config,
+		attempts: make(map[string]*loginAttempt),
+	}
+}
+
+/*
+reserve checks the username and the IP address and counts the attempt as pending on both in one step. The username
+has to wait out its backoff, the IP address is only refused once locked: backing off after a few failures would also
+slow down every other user behind the same address. It returns false when the attempt is refused.
+*/
+func (t *loginThrottle) reserve(userKey string, ipKey string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.retryAfter(userKey, now) > 0 || t.isLocked(ipKey, t.config.MaxFailedAttempts*ipAttemptsMultiplier, now) {
+		return false
+	}
+
+	userAttempt := t.attempt(userKey, now)
+	if userAttempt.ipKeys == nil {
+		userAttempt.ipKeys = make(map[string]struct{})
+	}
+	userAttempt.ipKeys[ipKey] = struct{}{}
+
+	for _, key := range []string{userKey, ipKey} {
+		attempt := t.attempt(key, now)
+		attempt.pending++
+		attempt.lastAttempt = now
+	}
+	return true
+}
+
+// retryAfter returns how long the username has to wait before it may attempt to log in again.
+func (t *loginThrottle) retryAfter(key string, now time.Time) time.Duration {
+	attempt, ok := t.activeAttempt(key, now)
+	if !ok {
+		return 0
+	}
+
+	if now.Before(attempt.lockedUntil) {
+		return attempt.lockedUntil.Sub(now)
+	}
+
+	// the attempts in flight may still lock the username
+	if attempt.failures+attempt.pending >= t.config.MaxFailedAttempts {
+		return t.config.LockoutDuration
+	}
+
+	last := attempt.lastFailure
+	if attempt.lastAttempt.After(last) {
+		last = attempt.lastAttempt
+	}
+
+	nextAllowed := last.Add(t.backoff(attempt.failures + attempt.pending))
+	if now.Before(nextAllowed) {
+		return nextAllowed.Sub(now)
+	}
+	return 0
+}
+
+func (t *loginThrottle) isLocked(key string, maxFailedAttempts int, now time.Time) bool {
+	attempt, ok := t.activeAttempt(key, now)
+	if !ok {
+		return false
+	}
+	return now.Before(attempt.lockedUntil) || attempt.failures+attempt.pending >= maxFailedAttempts
+}
+
+// recordFailure turns a reserved attempt into a failure and reports whether it locked the key.
+func (t *loginThrottle) recordFailure(key string, maxFailedAttempts int, now time.Time) (bool, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	attempt := t.attempt(key, now)
+	if attempt.pending > 0 {
+		attempt.pending--
+	}
+	attempt.failures++
+	attempt.lastFailure = now
+
+	if attempt.failures >= maxFailedAttempts && !now.Before(attempt.lockedUntil) {
+		attempt.lockedUntil = now.Add(t.config.LockoutDuration)
+		return true, attempt.failures
+	}
+	return false, attempt.failures
+}
+
+// cancel releases reserved attempts that neither failed nor succeeded, such as a database error.
+func (t *loginThrottle) cancel(keys ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, key := range keys {
+		if attempt, ok := t.attempts[key]; ok && attempt.pending > 0 {
+			attempt.pending--
+		}
+	}
+}
+
+// reset forgets the keys after a successful login.
+func (t *loginThrottle) reset(keys ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, key := range keys {
+		delete(t.attempts, key)
+	}
+}
+
+// unlock forgets the username and the IP addresses its attempts came from.
+func (t *loginThrottle) unlock(userKey string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if attempt, ok := t.attempts[userKey]; ok {
+		for ipKey := range attempt.ipKeys {
+			delete(t.attempts, ipKey)
+		}
+	}
+	delete(t.attempts, userKey)
+}
+
+func (t *loginThrottle) attempt(key string, now time.Time) *loginAttempt {
+	attempt, ok := t.activeAttempt(key, now)
+	if !ok {
+		if len(t.attempts) >= maxTrackedLoginKeys {
+			t.prune(now)
+		}
+		attempt = &loginAttempt{}
+		t.attempts[key] = attempt
+	}
+	return attempt
+}
+
+func (t *loginThrottle) activeAttempt(key string, now time.Time) (*loginAttempt, bool) {
+	attempt, ok := t.attempts[key]
+	if !ok {
+		return nil, false
+	}
+
+	if t.isExpired(attempt, now) {
+		delete(t.attempts, key)
+		return nil, false
+	}
+	return attempt, true
+}
+
+func (t *loginThrottle) isExpired(attempt *loginAttempt, now time.Time) bool {
+	return attempt.pending == 0 && !now.Before(attempt.lockedUntil) && now.Sub(attempt.lastFailure) > t.config.LockoutDuration
+}
+
+func (t *loginThrottle) prune(now time.Time) {
+	for key, attempt := range t.attempts {
+		if t.isExpired(attempt, now) {
+			delete(t.attempts, key)
+		}
+	}
+}
+
+// backoff doubles the wait for every failure past the free attempts, capped at the lockout duration.
+func (t *loginThrottle) backoff(failures int) time.Duration {
+	if failures <= freeLoginAttempts {
+		return 0
+	}
+
+	delay := baseLoginBackoff
+	for i := freeLoginAttempts + 1; i < failures; i++ {
+		delay *= 2
+		if delay >= t.config.LockoutDuration {
+			return t.config.LockoutDuration
+		}
+	}
+	return delay
+}
+
+func userThrottleKey(username string) string {
+	return "user:" + username
+}
+
+func ipThrottleKey(ipAddress string) string {
+	return "ip:" + ipAddress
+}
@@ -0,0 +1,193 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuthUseCase is an autogenerated mock type for the AuthUseCase type
+type AuthUseCase struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: requestContext, request
+func (_m *AuthUseCase) Authenticate(requestContext entity.UserContext, request entity.LoginRequest) (entity.User, error) {
+	ret := _m.Called(requestContext, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.LoginRequest) (entity.User, error)); ok {
+		return rf(requestContext, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.LoginRequest) entity.User); ok {
+		r0 = rf(requestContext, request)
+	} else {
+		r0 = ret.Get(0).(entity.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, entity.LoginRequest) error); ok {
+		r1 = rf(requestContext, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: requestContext, request
+func (_m *AuthUseCase) Login(requestContext entity.UserContext, request entity.LoginRequest) (entity.TokenPair, error) {
+	ret := _m.Called(requestContext, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 entity.TokenPair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.LoginRequest) (entity.TokenPair, error)); ok {
+		return rf(requestContext, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.LoginRequest) entity.TokenPair); ok {
+		r0 = rf(requestContext, request)
+	} else {
+		r0 = ret.Get(0).(entity.TokenPair)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, entity.LoginRequest) error); ok {
+		r1 = rf(requestContext, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: userContext
+func (_m *AuthUseCase) Logout(userContext entity.UserContext) error {
+	ret := _m.Called(userContext)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext) error); ok {
+		r0 = rf(userContext)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefreshToken provides a mock function with given fields: requestContext, request
+func (_m *AuthUseCase) RefreshToken(requestContext entity.UserContext, request entity.RefreshTokenRequest) (entity.TokenPair, error) {
+	ret := _m.Called(requestContext, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshToken")
+	}
+
+	var r0 entity.TokenPair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.RefreshTokenRequest) (entity.TokenPair, error)); ok {
+		return rf(requestContext, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.RefreshTokenRequest) entity.TokenPair); ok {
+		r0 = rf(requestContext, request)
+	} else {
+		r0 = ret.Get(0).(entity.TokenPair)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, entity.RefreshTokenRequest) error); ok {
+		r1 = rf(requestContext, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeUserSessions provides a mock function with given fields: userContext, userID
+func (_m *AuthUseCase) RevokeUserSessions(userContext entity.UserContext, userID int64) error {
+	ret := _m.Called(userContext, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) error); ok {
+		r0 = rf(userContext, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnlockUser provides a mock function with given fields: userContext, userID
+func (_m *AuthUseCase) UnlockUser(userContext entity.UserContext, userID int64) error {
+	ret := _m.Called(userContext, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlockUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) error); ok {
+		r0 = rf(userContext, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ValidateAccessToken provides a mock function with given fields: token
+func (_m *AuthUseCase) ValidateAccessToken(token string) (entity.UserContext, error) {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateAccessToken")
+	}
+
+	var r0 entity.UserContext
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (entity.UserContext, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.UserContext); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(entity.UserContext)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAuthUseCase creates a new instance of AuthUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuthUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuthUseCase {
+	mock := &AuthUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,131 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeductionRepository is an autogenerated mock type for the DeductionRepository type
+type DeductionRepository struct {
+	mock.Mock
+}
+
+// CreateDeduction provides a mock function with given fields: deduction
+func (_m *DeductionRepository) CreateDeduction(deduction entity.EmployeeDeduction) (entity.EmployeeDeduction, error) {
+	ret := _m.Called(deduction)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDeduction")
+	}
+
+	var r0 entity.EmployeeDeduction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.EmployeeDeduction) (entity.EmployeeDeduction, error)); ok {
+		return rf(deduction)
+	}
+	if rf, ok := ret.Get(0).(func(entity.EmployeeDeduction) entity.EmployeeDeduction); ok {
+		r0 = rf(deduction)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeeDeduction)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.EmployeeDeduction) error); ok {
+		r1 = rf(deduction)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeductionByID provides a mock function with given fields: deductionID
+func (_m *DeductionRepository) GetDeductionByID(deductionID int64) (entity.EmployeeDeduction, error) {
+	ret := _m.Called(deductionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeductionByID")
+	}
+
+	var r0 entity.EmployeeDeduction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (entity.EmployeeDeduction, error)); ok {
+		return rf(deductionID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entity.EmployeeDeduction); ok {
+		r0 = rf(deductionID)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeeDeduction)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(deductionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeductionsByUserID provides a mock function with given fields: userID
+func (_m *DeductionRepository) GetDeductionsByUserID(userID int64) ([]entity.EmployeeDeduction, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeductionsByUserID")
+	}
+
+	var r0 []entity.EmployeeDeduction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.EmployeeDeduction, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.EmployeeDeduction); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.EmployeeDeduction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateDeduction provides a mock function with given fields: deductionID, updates
+func (_m *DeductionRepository) UpdateDeduction(deductionID int64, updates map[string]interface{}) error {
+	ret := _m.Called(deductionID, updates)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDeduction")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, map[string]interface{}) error); ok {
+		r0 = rf(deductionID, updates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDeductionRepository creates a new instance of DeductionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeductionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeductionRepository {
+	mock := &DeductionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
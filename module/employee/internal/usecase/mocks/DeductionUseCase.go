@@ -0,0 +1,113 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeductionUseCase is an autogenerated mock type for the DeductionUseCase type
+type DeductionUseCase struct {
+	mock.Mock
+}
+
+// CancelDeduction provides a mock function with given fields: userContext, userID, deductionID
+func (_m *DeductionUseCase) CancelDeduction(userContext entity.UserContext, userID int64, deductionID int64) (entity.EmployeeDeduction, error) {
+	ret := _m.Called(userContext, userID, deductionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelDeduction")
+	}
+
+	var r0 entity.EmployeeDeduction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, int64) (entity.EmployeeDeduction, error)); ok {
+		return rf(userContext, userID, deductionID)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, int64) entity.EmployeeDeduction); ok {
+		r0 = rf(userContext, userID, deductionID)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeeDeduction)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64, int64) error); ok {
+		r1 = rf(userContext, userID, deductionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateDeduction provides a mock function with given fields: userContext, userID, request
+func (_m *DeductionUseCase) CreateDeduction(userContext entity.UserContext, userID int64, request entity.DeductionRequest) (entity.EmployeeDeduction, error) {
+	ret := _m.Called(userContext, userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDeduction")
+	}
+
+	var r0 entity.EmployeeDeduction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.DeductionRequest) (entity.EmployeeDeduction, error)); ok {
+		return rf(userContext, userID, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.DeductionRequest) entity.EmployeeDeduction); ok {
+		r0 = rf(userContext, userID, request)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeeDeduction)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64, entity.DeductionRequest) error); ok {
+		r1 = rf(userContext, userID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeductions provides a mock function with given fields: userID
+func (_m *DeductionUseCase) GetDeductions(userID int64) ([]entity.EmployeeDeduction, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeductions")
+	}
+
+	var r0 []entity.EmployeeDeduction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.EmployeeDeduction, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.EmployeeDeduction); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.EmployeeDeduction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDeductionUseCase creates a new instance of DeductionUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeductionUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeductionUseCase {
+	mock := &DeductionUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
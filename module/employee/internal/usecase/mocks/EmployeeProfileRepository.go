@@ -0,0 +1,246 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// EmployeeProfileRepository is an autogenerated mock type for the EmployeeProfileRepository type
+type EmployeeProfileRepository struct {
+	mock.Mock
+}
+
+// CreateProfile provides a mock function with given fields: profile
+func (_m *EmployeeProfileRepository) CreateProfile(profile entity.EmployeeProfile) (entity.EmployeeProfile, error) {
+	ret := _m.Called(profile)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateProfile")
+	}
+
+	var r0 entity.EmployeeProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.EmployeeProfile) (entity.EmployeeProfile, error)); ok {
+		return rf(profile)
+	}
+	if rf, ok := ret.Get(0).(func(entity.EmployeeProfile) entity.EmployeeProfile); ok {
+		r0 = rf(profile)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeeProfile)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.EmployeeProfile) error); ok {
+		r1 = rf(profile)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteProfile provides a mock function with given fields: userID
+func (_m *EmployeeProfileRepository) DeleteProfile(userID int64) error {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProfile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetProfileByEmployeeNumber provides a mock function with given fields: employeeNumber
+func (_m *EmployeeProfileRepository) GetProfileByEmployeeNumber(employeeNumber string) (entity.EmployeeProfile, error) {
+	ret := _m.Called(employeeNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfileByEmployeeNumber")
+	}
+
+	var r0 entity.EmployeeProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (entity.EmployeeProfile, error)); ok {
+		return rf(employeeNumber)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.EmployeeProfile); ok {
+		r0 = rf(employeeNumber)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeeProfile)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(employeeNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfileByUserID provides a mock function with given fields: userID
+func (_m *EmployeeProfileRepository) GetProfileByUserID(userID int64) (entity.EmployeeProfile, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfileByUserID")
+	}
+
+	var r0 entity.EmployeeProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (entity.EmployeeProfile, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entity.EmployeeProfile); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeeProfile)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfilesByUserIDs provides a mock function with given fields: userIDs
+func (_m *EmployeeProfileRepository) GetProfilesByUserIDs(userIDs []int64) ([]entity.EmployeeProfile, error) {
+	ret := _m.Called(userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfilesByUserIDs")
+	}
+
+	var r0 []entity.EmployeeProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]int64) ([]entity.EmployeeProfile, error)); ok {
+		return rf(userIDs)
+	}
+	if rf, ok := ret.Get(0).(func([]int64) []entity.EmployeeProfile); ok {
+		r0 = rf(userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.EmployeeProfile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]int64) error); ok {
+		r1 = rf(userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfilesEmployedBetween provides a mock function with given fields: startTime, endTime
+func (_m *EmployeeProfileRepository) GetProfilesEmployedBetween(startTime time.Time, endTime time.Time) ([]entity.EmployeeProfile, error) {
+	ret := _m.Called(startTime, endTime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfilesEmployedBetween")
+	}
+
+	var r0 []entity.EmployeeProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) ([]entity.EmployeeProfile, error)); ok {
+		return rf(startTime, endTime)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) []entity.EmployeeProfile); ok {
+		r0 = rf(startTime, endTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.EmployeeProfile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time) error); ok {
+		r1 = rf(startTime, endTime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListProfiles provides a mock function with given fields: filter, pagination
+func (_m *EmployeeProfileRepository) ListProfiles(filter entity.EmployeeProfileFilter, pagination entity.Pagination) ([]entity.EmployeeProfile, int64, error) {
+	ret := _m.Called(filter, pagination)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListProfiles")
+	}
+
+	var r0 []entity.EmployeeProfile
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(entity.EmployeeProfileFilter, entity.Pagination) ([]entity.EmployeeProfile, int64, error)); ok {
+		return rf(filter, pagination)
+	}
+	if rf, ok := ret.Get(0).(func(entity.EmployeeProfileFilter, entity.Pagination) []entity.EmployeeProfile); ok {
+		r0 = rf(filter, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.EmployeeProfile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.EmployeeProfileFilter, entity.Pagination) int64); ok {
+		r1 = rf(filter, pagination)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(entity.EmployeeProfileFilter, entity.Pagination) error); ok {
+		r2 = rf(filter, pagination)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// UpdateProfile provides a mock function with given fields: userID, updates
+func (_m *EmployeeProfileRepository) UpdateProfile(userID int64, updates map[string]interface{}) error {
+	ret := _m.Called(userID, updates)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, map[string]interface{}) error); ok {
+		r0 = rf(userID, updates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewEmployeeProfileRepository creates a new instance of EmployeeProfileRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmployeeProfileRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmployeeProfileRepository {
+	mock := &EmployeeProfileRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
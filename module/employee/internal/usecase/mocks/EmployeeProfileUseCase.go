@@ -0,0 +1,157 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmployeeProfileUseCase is an autogenerated mock type for the EmployeeProfileUseCase type
+type EmployeeProfileUseCase struct {
+	mock.Mock
+}
+
+// CreateProfile provides a mock function with given fields: userContext, request
+func (_m *EmployeeProfileUseCase) CreateProfile(userContext entity.UserContext, request entity.EmployeeProfileRequest) (entity.EmployeeProfile, error) {
+	ret := _m.Called(userContext, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateProfile")
+	}
+
+	var r0 entity.EmployeeProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.EmployeeProfileRequest) (entity.EmployeeProfile, error)); ok {
+		return rf(userContext, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.EmployeeProfileRequest) entity.EmployeeProfile); ok {
+		r0 = rf(userContext, request)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeeProfile)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, entity.EmployeeProfileRequest) error); ok {
+		r1 = rf(userContext, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteProfile provides a mock function with given fields: userContext, userID
+func (_m *EmployeeProfileUseCase) DeleteProfile(userContext entity.UserContext, userID int64) error {
+	ret := _m.Called(userContext, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProfile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) error); ok {
+		r0 = rf(userContext, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetProfile provides a mock function with given fields: userID
+func (_m *EmployeeProfileUseCase) GetProfile(userID int64) (entity.EmployeeProfile, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfile")
+	}
+
+	var r0 entity.EmployeeProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (entity.EmployeeProfile, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entity.EmployeeProfile); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeeProfile)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListProfiles provides a mock function with given fields: filter
+func (_m *EmployeeProfileUseCase) ListProfiles(filter entity.EmployeeProfileFilter) (entity.EmployeeProfileList, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListProfiles")
+	}
+
+	var r0 entity.EmployeeProfileList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.EmployeeProfileFilter) (entity.EmployeeProfileList, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(entity.EmployeeProfileFilter) entity.EmployeeProfileList); ok {
+		r0 = rf(filter)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeeProfileList)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.EmployeeProfileFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProfile provides a mock function with given fields: userContext, userID, request
+func (_m *EmployeeProfileUseCase) UpdateProfile(userContext entity.UserContext, userID int64, request entity.EmployeeProfileRequest) (entity.EmployeeProfile, error) {
+	ret := _m.Called(userContext, userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 entity.EmployeeProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.EmployeeProfileRequest) (entity.EmployeeProfile, error)); ok {
+		return rf(userContext, userID, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.EmployeeProfileRequest) entity.EmployeeProfile); ok {
+		r0 = rf(userContext, userID, request)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeeProfile)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64, entity.EmployeeProfileRequest) error); ok {
+		r1 = rf(userContext, userID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewEmployeeProfileUseCase creates a new instance of EmployeeProfileUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmployeeProfileUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmployeeProfileUseCase {
+	mock := &EmployeeProfileUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -14,6 +14,36 @@ type EmployeeRepository struct {
 	mock.Mock
 }
 
+// GetAllActiveDeductions provides a mock function with given fields: periodEnd, userID
+func (_m *EmployeeRepository) GetAllActiveDeductions(periodEnd time.Time, userID *int64) ([]entity.EmployeeDeduction, error) {
+	ret := _m.Called(periodEnd, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllActiveDeductions")
+	}
+
+	var r0 []entity.EmployeeDeduction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, *int64) ([]entity.EmployeeDeduction, error)); ok {
+		return rf(periodEnd, userID)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, *int64) []entity.EmployeeDeduction); ok {
+		r0 = rf(periodEnd, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.EmployeeDeduction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, *int64) error); ok {
+		r1 = rf(periodEnd, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAllAttendanceByTimeRange provides a mock function with given fields: startTime, endTime, userID
 func (_m *EmployeeRepository) GetAllAttendanceByTimeRange(startTime time.Time, endTime time.Time, userID *int64) ([]entity.EmployeeAttendance, error) {
 	ret := _m.Called(startTime, endTime, userID)
@@ -44,6 +74,36 @@ func (_m *EmployeeRepository) GetAllAttendanceByTimeRange(startTime time.Time, e
 	return r0, r1
 }
 
+// GetAllHolidaysByTimeRange provides a mock function with given fields: startTime, endTime
+func (_m *EmployeeRepository) GetAllHolidaysByTimeRange(startTime time.Time, endTime time.Time) ([]entity.Holiday, error) {
+	ret := _m.Called(startTime, endTime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllHolidaysByTimeRange")
+	}
+
+	var r0 []entity.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) ([]entity.Holiday, error)); ok {
+		return rf(startTime, endTime)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) []entity.Holiday); ok {
+		r0 = rf(startTime, endTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Holiday)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time) error); ok {
+		r1 = rf(startTime, endTime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAllOvertimeByTimeRange provides a mock function with given fields: startTime, endTime, userID
 func (_m *EmployeeRepository) GetAllOvertimeByTimeRange(startTime time.Time, endTime time.Time, userID *int64) ([]entity.EmployeeOvertime, error) {
 	ret := _m.Called(startTime, endTime, userID)
@@ -74,6 +134,36 @@ func (_m *EmployeeRepository) GetAllOvertimeByTimeRange(startTime time.Time, end
 	return r0, r1
 }
 
+// GetAllPayComponentsByTimeRange provides a mock function with given fields: startTime, endTime, userID
+func (_m *EmployeeRepository) GetAllPayComponentsByTimeRange(startTime time.Time, endTime time.Time, userID *int64) ([]entity.EmployeePayComponent, error) {
+	ret := _m.Called(startTime, endTime, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllPayComponentsByTimeRange")
+	}
+
+	var r0 []entity.EmployeePayComponent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, *int64) ([]entity.EmployeePayComponent, error)); ok {
+		return rf(startTime, endTime, userID)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, *int64) []entity.EmployeePayComponent); ok {
+		r0 = rf(startTime, endTime, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.EmployeePayComponent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time, *int64) error); ok {
+		r1 = rf(startTime, endTime, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAllReimbursementByTimeRange provides a mock function with given fields: startTime, endTime, userID
 func (_m *EmployeeRepository) GetAllReimbursementByTimeRange(startTime time.Time, endTime time.Time, userID *int64) ([]entity.EmployeeReimbursement, error) {
 	ret := _m.Called(startTime, endTime, userID)
@@ -132,29 +222,87 @@ func (_m *EmployeeRepository) GetAttendanceByUserAndDate(userID int64, date time
 	return r0, r1
 }
 
-// GetEmployeeBaseSalaryByPeriodStart provides a mock function with given fields: periodStartTime, userID
-func (_m *EmployeeRepository) GetEmployeeBaseSalaryByPeriodStart(periodStartTime time.Time, userID *int64) ([]entity.EmployeeBaseSalary, error) {
-	ret := _m.Called(periodStartTime, userID)
+// GetDeductionsByIDs provides a mock function with given fields: deductionIDs
+func (_m *EmployeeRepository) GetDeductionsByIDs(deductionIDs []int64) ([]entity.EmployeeDeduction, error) {
+	ret := _m.Called(deductionIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeductionsByIDs")
+	}
+
+	var r0 []entity.EmployeeDeduction
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]int64) ([]entity.EmployeeDeduction, error)); ok {
+		return rf(deductionIDs)
+	}
+	if rf, ok := ret.Get(0).(func([]int64) []entity.EmployeeDeduction); ok {
+		r0 = rf(deductionIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.EmployeeDeduction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]int64) error); ok {
+		r1 = rf(deductionIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEmployeeBaseSalaryByPeriod provides a mock function with given fields: periodStart, periodEnd, userID
+func (_m *EmployeeRepository) GetEmployeeBaseSalaryByPeriod(periodStart time.Time, periodEnd time.Time, userID *int64) ([]entity.EmployeeBaseSalary, error) {
+	ret := _m.Called(periodStart, periodEnd, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetEmployeeBaseSalaryByPeriodStart")
+		panic("no return value specified for GetEmployeeBaseSalaryByPeriod")
 	}
 
 	var r0 []entity.EmployeeBaseSalary
 	var r1 error
-	if rf, ok := ret.Get(0).(func(time.Time, *int64) ([]entity.EmployeeBaseSalary, error)); ok {
-		return rf(periodStartTime, userID)
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, *int64) ([]entity.EmployeeBaseSalary, error)); ok {
+		return rf(periodStart, periodEnd, userID)
 	}
-	if rf, ok := ret.Get(0).(func(time.Time, *int64) []entity.EmployeeBaseSalary); ok {
-		r0 = rf(periodStartTime, userID)
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, *int64) []entity.EmployeeBaseSalary); ok {
+		r0 = rf(periodStart, periodEnd, userID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.EmployeeBaseSalary)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(time.Time, *int64) error); ok {
-		r1 = rf(periodStartTime, userID)
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time, *int64) error); ok {
+		r1 = rf(periodStart, periodEnd, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHolidayByDate provides a mock function with given fields: date
+func (_m *EmployeeRepository) GetHolidayByDate(date time.Time) (entity.Holiday, error) {
+	ret := _m.Called(date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHolidayByDate")
+	}
+
+	var r0 entity.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (entity.Holiday, error)); ok {
+		return rf(date)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) entity.Holiday); ok {
+		r0 = rf(date)
+	} else {
+		r0 = ret.Get(0).(entity.Holiday)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(date)
 	} else {
 		r1 = ret.Error(1)
 	}
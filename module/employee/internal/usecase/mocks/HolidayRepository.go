@@ -0,0 +1,181 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// HolidayRepository is an autogenerated mock type for the HolidayRepository type
+type HolidayRepository struct {
+	mock.Mock
+}
+
+// CreateHoliday provides a mock function with given fields: holiday
+func (_m *HolidayRepository) CreateHoliday(holiday entity.Holiday) (entity.Holiday, error) {
+	ret := _m.Called(holiday)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateHoliday")
+	}
+
+	var r0 entity.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.Holiday) (entity.Holiday, error)); ok {
+		return rf(holiday)
+	}
+	if rf, ok := ret.Get(0).(func(entity.Holiday) entity.Holiday); ok {
+		r0 = rf(holiday)
+	} else {
+		r0 = ret.Get(0).(entity.Holiday)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.Holiday) error); ok {
+		r1 = rf(holiday)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteHoliday provides a mock function with given fields: holidayID
+func (_m *HolidayRepository) DeleteHoliday(holidayID int64) error {
+	ret := _m.Called(holidayID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteHoliday")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(holidayID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetHolidayByID provides a mock function with given fields: holidayID
+func (_m *HolidayRepository) GetHolidayByID(holidayID int64) (entity.Holiday, error) {
+	ret := _m.Called(holidayID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHolidayByID")
+	}
+
+	var r0 entity.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (entity.Holiday, error)); ok {
+		return rf(holidayID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entity.Holiday); ok {
+		r0 = rf(holidayID)
+	} else {
+		r0 = ret.Get(0).(entity.Holiday)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(holidayID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHolidaysByTimeRange provides a mock function with given fields: startTime, endTime
+func (_m *HolidayRepository) GetHolidaysByTimeRange(startTime time.Time, endTime time.Time) ([]entity.Holiday, error) {
+	ret := _m.Called(startTime, endTime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHolidaysByTimeRange")
+	}
+
+	var r0 []entity.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) ([]entity.Holiday, error)); ok {
+		return rf(startTime, endTime)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) []entity.Holiday); ok {
+		r0 = rf(startTime, endTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Holiday)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time) error); ok {
+		r1 = rf(startTime, endTime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateHoliday provides a mock function with given fields: holidayID, updates
+func (_m *HolidayRepository) UpdateHoliday(holidayID int64, updates map[string]interface{}) error {
+	ret := _m.Called(holidayID, updates)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateHoliday")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, map[string]interface{}) error); ok {
+		r0 = rf(holidayID, updates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertHolidays provides a mock function with given fields: holidays
+func (_m *HolidayRepository) UpsertHolidays(holidays []entity.Holiday) ([]entity.Holiday, error) {
+	ret := _m.Called(holidays)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertHolidays")
+	}
+
+	var r0 []entity.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]entity.Holiday) ([]entity.Holiday, error)); ok {
+		return rf(holidays)
+	}
+	if rf, ok := ret.Get(0).(func([]entity.Holiday) []entity.Holiday); ok {
+		r0 = rf(holidays)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Holiday)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]entity.Holiday) error); ok {
+		r1 = rf(holidays)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewHolidayRepository creates a new instance of HolidayRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHolidayRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HolidayRepository {
+	mock := &HolidayRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,159 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HolidayUseCase is an autogenerated mock type for the HolidayUseCase type
+type HolidayUseCase struct {
+	mock.Mock
+}
+
+// CreateHoliday provides a mock function with given fields: userContext, request
+func (_m *HolidayUseCase) CreateHoliday(userContext entity.UserContext, request entity.HolidayRequest) (entity.Holiday, error) {
+	ret := _m.Called(userContext, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateHoliday")
+	}
+
+	var r0 entity.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.HolidayRequest) (entity.Holiday, error)); ok {
+		return rf(userContext, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.HolidayRequest) entity.Holiday); ok {
+		r0 = rf(userContext, request)
+	} else {
+		r0 = ret.Get(0).(entity.Holiday)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, entity.HolidayRequest) error); ok {
+		r1 = rf(userContext, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteHoliday provides a mock function with given fields: userContext, holidayID
+func (_m *HolidayUseCase) DeleteHoliday(userContext entity.UserContext, holidayID int64) error {
+	ret := _m.Called(userContext, holidayID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteHoliday")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) error); ok {
+		r0 = rf(userContext, holidayID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ImportHolidays provides a mock function with given fields: userContext, year, format, content
+func (_m *HolidayUseCase) ImportHolidays(userContext entity.UserContext, year int, format string, content []byte) (entity.HolidayImportResult, error) {
+	ret := _m.Called(userContext, year, format, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportHolidays")
+	}
+
+	var r0 entity.HolidayImportResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int, string, []byte) (entity.HolidayImportResult, error)); ok {
+		return rf(userContext, year, format, content)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int, string, []byte) entity.HolidayImportResult); ok {
+		r0 = rf(userContext, year, format, content)
+	} else {
+		r0 = ret.Get(0).(entity.HolidayImportResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int, string, []byte) error); ok {
+		r1 = rf(userContext, year, format, content)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListHolidays provides a mock function with given fields: year
+func (_m *HolidayUseCase) ListHolidays(year int) ([]entity.Holiday, error) {
+	ret := _m.Called(year)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListHolidays")
+	}
+
+	var r0 []entity.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]entity.Holiday, error)); ok {
+		return rf(year)
+	}
+	if rf, ok := ret.Get(0).(func(int) []entity.Holiday); ok {
+		r0 = rf(year)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Holiday)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(year)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateHoliday provides a mock function with given fields: userContext, holidayID, request
+func (_m *HolidayUseCase) UpdateHoliday(userContext entity.UserContext, holidayID int64, request entity.HolidayRequest) (entity.Holiday, error) {
+	ret := _m.Called(userContext, holidayID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateHoliday")
+	}
+
+	var r0 entity.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.HolidayRequest) (entity.Holiday, error)); ok {
+		return rf(userContext, holidayID, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.HolidayRequest) entity.Holiday); ok {
+		r0 = rf(userContext, holidayID, request)
+	} else {
+		r0 = ret.Get(0).(entity.Holiday)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64, entity.HolidayRequest) error); ok {
+		r1 = rf(userContext, holidayID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewHolidayUseCase creates a new instance of HolidayUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHolidayUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HolidayUseCase {
+	mock := &HolidayUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,263 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PayComponentRepository is an autogenerated mock type for the PayComponentRepository type
+type PayComponentRepository struct {
+	mock.Mock
+}
+
+// CreateAssignment provides a mock function with given fields: assignment
+func (_m *PayComponentRepository) CreateAssignment(assignment entity.EmployeePayComponent) (entity.EmployeePayComponent, error) {
+	ret := _m.Called(assignment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAssignment")
+	}
+
+	var r0 entity.EmployeePayComponent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.EmployeePayComponent) (entity.EmployeePayComponent, error)); ok {
+		return rf(assignment)
+	}
+	if rf, ok := ret.Get(0).(func(entity.EmployeePayComponent) entity.EmployeePayComponent); ok {
+		r0 = rf(assignment)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeePayComponent)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.EmployeePayComponent) error); ok {
+		r1 = rf(assignment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateComponent provides a mock function with given fields: component
+func (_m *PayComponentRepository) CreateComponent(component entity.PayComponent) (entity.PayComponent, error) {
+	ret := _m.Called(component)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateComponent")
+	}
+
+	var r0 entity.PayComponent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.PayComponent) (entity.PayComponent, error)); ok {
+		return rf(component)
+	}
+	if rf, ok := ret.Get(0).(func(entity.PayComponent) entity.PayComponent); ok {
+		r0 = rf(component)
+	} else {
+		r0 = ret.Get(0).(entity.PayComponent)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.PayComponent) error); ok {
+		r1 = rf(component)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAssignmentByID provides a mock function with given fields: assignmentID
+func (_m *PayComponentRepository) GetAssignmentByID(assignmentID int64) (entity.EmployeePayComponent, error) {
+	ret := _m.Called(assignmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAssignmentByID")
+	}
+
+	var r0 entity.EmployeePayComponent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (entity.EmployeePayComponent, error)); ok {
+		return rf(assignmentID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entity.EmployeePayComponent); ok {
+		r0 = rf(assignmentID)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeePayComponent)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(assignmentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAssignmentsByUserID provides a mock function with given fields: userID
+func (_m *PayComponentRepository) GetAssignmentsByUserID(userID int64) ([]entity.EmployeePayComponent, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAssignmentsByUserID")
+	}
+
+	var r0 []entity.EmployeePayComponent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.EmployeePayComponent, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.EmployeePayComponent); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.EmployeePayComponent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetComponentByCode provides a mock function with given fields: code
+func (_m *PayComponentRepository) GetComponentByCode(code string) (entity.PayComponent, error) {
+	ret := _m.Called(code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetComponentByCode")
+	}
+
+	var r0 entity.PayComponent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (entity.PayComponent, error)); ok {
+		return rf(code)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.PayComponent); ok {
+		r0 = rf(code)
+	} else {
+		r0 = ret.Get(0).(entity.PayComponent)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetComponentByID provides a mock function with given fields: componentID
+func (_m *PayComponentRepository) GetComponentByID(componentID int64) (entity.PayComponent, error) {
+	ret := _m.Called(componentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetComponentByID")
+	}
+
+	var r0 entity.PayComponent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (entity.PayComponent, error)); ok {
+		return rf(componentID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entity.PayComponent); ok {
+		r0 = rf(componentID)
+	} else {
+		r0 = ret.Get(0).(entity.PayComponent)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(componentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListComponents provides a mock function with no fields
+func (_m *PayComponentRepository) ListComponents() ([]entity.PayComponent, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListComponents")
+	}
+
+	var r0 []entity.PayComponent
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.PayComponent, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.PayComponent); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayComponent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateAssignment provides a mock function with given fields: assignmentID, updates
+func (_m *PayComponentRepository) UpdateAssignment(assignmentID int64, updates map[string]interface{}) error {
+	ret := _m.Called(assignmentID, updates)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAssignment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, map[string]interface{}) error); ok {
+		r0 = rf(assignmentID, updates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateComponent provides a mock function with given fields: componentID, updates
+func (_m *PayComponentRepository) UpdateComponent(componentID int64, updates map[string]interface{}) error {
+	ret := _m.Called(componentID, updates)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateComponent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, map[string]interface{}) error); ok {
+		r0 = rf(componentID, updates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPayComponentRepository creates a new instance of PayComponentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPayComponentRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PayComponentRepository {
+	mock := &PayComponentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
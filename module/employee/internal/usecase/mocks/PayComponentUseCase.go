@@ -0,0 +1,199 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PayComponentUseCase is an autogenerated mock type for the PayComponentUseCase type
+type PayComponentUseCase struct {
+	mock.Mock
+}
+
+// AssignComponent provides a mock function with given fields: userContext, userID, request
+func (_m *PayComponentUseCase) AssignComponent(userContext entity.UserContext, userID int64, request entity.PayComponentAssignmentRequest) (entity.EmployeePayComponent, error) {
+	ret := _m.Called(userContext, userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignComponent")
+	}
+
+	var r0 entity.EmployeePayComponent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.PayComponentAssignmentRequest) (entity.EmployeePayComponent, error)); ok {
+		return rf(userContext, userID, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.PayComponentAssignmentRequest) entity.EmployeePayComponent); ok {
+		r0 = rf(userContext, userID, request)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeePayComponent)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64, entity.PayComponentAssignmentRequest) error); ok {
+		r1 = rf(userContext, userID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateComponent provides a mock function with given fields: userContext, request
+func (_m *PayComponentUseCase) CreateComponent(userContext entity.UserContext, request entity.PayComponentRequest) (entity.PayComponent, error) {
+	ret := _m.Called(userContext, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateComponent")
+	}
+
+	var r0 entity.PayComponent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.PayComponentRequest) (entity.PayComponent, error)); ok {
+		return rf(userContext, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.PayComponentRequest) entity.PayComponent); ok {
+		r0 = rf(userContext, request)
+	} else {
+		r0 = ret.Get(0).(entity.PayComponent)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, entity.PayComponentRequest) error); ok {
+		r1 = rf(userContext, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EndAssignment provides a mock function with given fields: userContext, userID, assignmentID, request
+func (_m *PayComponentUseCase) EndAssignment(userContext entity.UserContext, userID int64, assignmentID int64, request entity.EndPayComponentAssignmentRequest) (entity.EmployeePayComponent, error) {
+	ret := _m.Called(userContext, userID, assignmentID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EndAssignment")
+	}
+
+	var r0 entity.EmployeePayComponent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, int64, entity.EndPayComponentAssignmentRequest) (entity.EmployeePayComponent, error)); ok {
+		return rf(userContext, userID, assignmentID, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, int64, entity.EndPayComponentAssignmentRequest) entity.EmployeePayComponent); ok {
+		r0 = rf(userContext, userID, assignmentID, request)
+	} else {
+		r0 = ret.Get(0).(entity.EmployeePayComponent)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64, int64, entity.EndPayComponentAssignmentRequest) error); ok {
+		r1 = rf(userContext, userID, assignmentID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAssignments provides a mock function with given fields: userID
+func (_m *PayComponentUseCase) GetAssignments(userID int64) ([]entity.EmployeePayComponent, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAssignments")
+	}
+
+	var r0 []entity.EmployeePayComponent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.EmployeePayComponent, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.EmployeePayComponent); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.EmployeePayComponent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListComponents provides a mock function with no fields
+func (_m *PayComponentUseCase) ListComponents() ([]entity.PayComponent, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListComponents")
+	}
+
+	var r0 []entity.PayComponent
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.PayComponent, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.PayComponent); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayComponent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateComponent provides a mock function with given fields: userContext, componentID, request
+func (_m *PayComponentUseCase) UpdateComponent(userContext entity.UserContext, componentID int64, request entity.PayComponentRequest) (entity.PayComponent, error) {
+	ret := _m.Called(userContext, componentID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateComponent")
+	}
+
+	var r0 entity.PayComponent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.PayComponentRequest) (entity.PayComponent, error)); ok {
+		return rf(userContext, componentID, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.PayComponentRequest) entity.PayComponent); ok {
+		r0 = rf(userContext, componentID, request)
+	} else {
+		r0 = ret.Get(0).(entity.PayComponent)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64, entity.PayComponentRequest) error); ok {
+		r1 = rf(userContext, componentID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPayComponentUseCase creates a new instance of PayComponentUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPayComponentUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PayComponentUseCase {
+	mock := &PayComponentUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
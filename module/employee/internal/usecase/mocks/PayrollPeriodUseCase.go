@@ -0,0 +1,143 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PayrollPeriodUseCase is an autogenerated mock type for the PayrollPeriodUseCase type
+type PayrollPeriodUseCase struct {
+	mock.Mock
+}
+
+// CreatePeriod provides a mock function with given fields: userContext, request
+func (_m *PayrollPeriodUseCase) CreatePeriod(userContext entity.UserContext, request entity.PayrollPeriodRequest) (entity.PayrollPeriod, error) {
+	ret := _m.Called(userContext, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePeriod")
+	}
+
+	var r0 entity.PayrollPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.PayrollPeriodRequest) (entity.PayrollPeriod, error)); ok {
+		return rf(userContext, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.PayrollPeriodRequest) entity.PayrollPeriod); ok {
+		r0 = rf(userContext, request)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollPeriod)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, entity.PayrollPeriodRequest) error); ok {
+		r1 = rf(userContext, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GeneratePeriods provides a mock function with given fields: userContext, request
+func (_m *PayrollPeriodUseCase) GeneratePeriods(userContext entity.UserContext, request entity.GeneratePayrollPeriodsRequest) ([]entity.PayrollPeriod, error) {
+	ret := _m.Called(userContext, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GeneratePeriods")
+	}
+
+	var r0 []entity.PayrollPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.GeneratePayrollPeriodsRequest) ([]entity.PayrollPeriod, error)); ok {
+		return rf(userContext, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.GeneratePayrollPeriodsRequest) []entity.PayrollPeriod); ok {
+		r0 = rf(userContext, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayrollPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, entity.GeneratePayrollPeriodsRequest) error); ok {
+		r1 = rf(userContext, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPeriods provides a mock function with given fields: status
+func (_m *PayrollPeriodUseCase) ListPeriods(status string) ([]entity.PayrollPeriod, error) {
+	ret := _m.Called(status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPeriods")
+	}
+
+	var r0 []entity.PayrollPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]entity.PayrollPeriod, error)); ok {
+		return rf(status)
+	}
+	if rf, ok := ret.Get(0).(func(string) []entity.PayrollPeriod); ok {
+		r0 = rf(status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayrollPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePeriod provides a mock function with given fields: userContext, periodID, request
+func (_m *PayrollPeriodUseCase) UpdatePeriod(userContext entity.UserContext, periodID int64, request entity.PayrollPeriodRequest) (entity.PayrollPeriod, error) {
+	ret := _m.Called(userContext, periodID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePeriod")
+	}
+
+	var r0 entity.PayrollPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.PayrollPeriodRequest) (entity.PayrollPeriod, error)); ok {
+		return rf(userContext, periodID, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.PayrollPeriodRequest) entity.PayrollPeriod); ok {
+		r0 = rf(userContext, periodID, request)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollPeriod)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64, entity.PayrollPeriodRequest) error); ok {
+		r1 = rf(userContext, periodID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPayrollPeriodUseCase creates a new instance of PayrollPeriodUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPayrollPeriodUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PayrollPeriodUseCase {
+	mock := &PayrollPeriodUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
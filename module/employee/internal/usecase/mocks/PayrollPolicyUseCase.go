@@ -0,0 +1,141 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PayrollPolicyUseCase is an autogenerated mock type for the PayrollPolicyUseCase type
+type PayrollPolicyUseCase struct {
+	mock.Mock
+}
+
+// CreatePolicy provides a mock function with given fields: userContext, request
+func (_m *PayrollPolicyUseCase) CreatePolicy(userContext entity.UserContext, request entity.PayrollPolicyRequest) (entity.PayrollPolicy, error) {
+	ret := _m.Called(userContext, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePolicy")
+	}
+
+	var r0 entity.PayrollPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.PayrollPolicyRequest) (entity.PayrollPolicy, error)); ok {
+		return rf(userContext, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.PayrollPolicyRequest) entity.PayrollPolicy); ok {
+		r0 = rf(userContext, request)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollPolicy)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, entity.PayrollPolicyRequest) error); ok {
+		r1 = rf(userContext, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPolicy provides a mock function with given fields: policyID
+func (_m *PayrollPolicyUseCase) GetPolicy(policyID int64) (entity.PayrollPolicy, error) {
+	ret := _m.Called(policyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPolicy")
+	}
+
+	var r0 entity.PayrollPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (entity.PayrollPolicy, error)); ok {
+		return rf(policyID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entity.PayrollPolicy); ok {
+		r0 = rf(policyID)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollPolicy)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(policyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatutoryRates provides a mock function with given fields: date
+func (_m *PayrollPolicyUseCase) GetStatutoryRates(date string) (entity.StatutoryRates, error) {
+	ret := _m.Called(date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatutoryRates")
+	}
+
+	var r0 entity.StatutoryRates
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (entity.StatutoryRates, error)); ok {
+		return rf(date)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.StatutoryRates); ok {
+		r0 = rf(date)
+	} else {
+		r0 = ret.Get(0).(entity.StatutoryRates)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPolicies provides a mock function with no fields
+func (_m *PayrollPolicyUseCase) ListPolicies() ([]entity.PayrollPolicy, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPolicies")
+	}
+
+	var r0 []entity.PayrollPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.PayrollPolicy, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.PayrollPolicy); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayrollPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPayrollPolicyUseCase creates a new instance of PayrollPolicyUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPayrollPolicyUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PayrollPolicyUseCase {
+	mock := &PayrollPolicyUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -14,63 +14,283 @@ type PayrollRepository struct {
 	mock.Mock
 }
 
-// ClosePayrollPeriod provides a mock function with given fields: periodID
-func (_m *PayrollRepository) ClosePayrollPeriod(periodID int64) error {
+// CreatePayrollRun provides a mock function with given fields: run, payslips, deductions, auditLog
+func (_m *PayrollRepository) CreatePayrollRun(run entity.PayrollRun, payslips []entity.PayrollPayslip, deductions []entity.EmployeeDeduction, auditLog entity.AuditLog) (entity.PayrollRun, error) {
+	ret := _m.Called(run, payslips, deductions, auditLog)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePayrollRun")
+	}
+
+	var r0 entity.PayrollRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.PayrollRun, []entity.PayrollPayslip, []entity.EmployeeDeduction, entity.AuditLog) (entity.PayrollRun, error)); ok {
+		return rf(run, payslips, deductions, auditLog)
+	}
+	if rf, ok := ret.Get(0).(func(entity.PayrollRun, []entity.PayrollPayslip, []entity.EmployeeDeduction, entity.AuditLog) entity.PayrollRun); ok {
+		r0 = rf(run, payslips, deductions, auditLog)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollRun)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.PayrollRun, []entity.PayrollPayslip, []entity.EmployeeDeduction, entity.AuditLog) error); ok {
+		r1 = rf(run, payslips, deductions, auditLog)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreatePeriods provides a mock function with given fields: periods
+func (_m *PayrollRepository) CreatePeriods(periods []entity.PayrollPeriod) ([]entity.PayrollPeriod, error) {
+	ret := _m.Called(periods)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePeriods")
+	}
+
+	var r0 []entity.PayrollPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]entity.PayrollPeriod) ([]entity.PayrollPeriod, error)); ok {
+		return rf(periods)
+	}
+	if rf, ok := ret.Get(0).(func([]entity.PayrollPeriod) []entity.PayrollPeriod); ok {
+		r0 = rf(periods)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayrollPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]entity.PayrollPeriod) error); ok {
+		r1 = rf(periods)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreatePolicy provides a mock function with given fields: policy
+func (_m *PayrollRepository) CreatePolicy(policy entity.PayrollPolicy) (entity.PayrollPolicy, error) {
+	ret := _m.Called(policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePolicy")
+	}
+
+	var r0 entity.PayrollPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.PayrollPolicy) (entity.PayrollPolicy, error)); ok {
+		return rf(policy)
+	}
+	if rf, ok := ret.Get(0).(func(entity.PayrollPolicy) entity.PayrollPolicy); ok {
+		r0 = rf(policy)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollPolicy)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.PayrollPolicy) error); ok {
+		r1 = rf(policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCurrentPayrollRun provides a mock function with given fields: periodID
+func (_m *PayrollRepository) GetCurrentPayrollRun(periodID int64) (entity.PayrollRun, error) {
 	ret := _m.Called(periodID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ClosePayrollPeriod")
+		panic("no return value specified for GetCurrentPayrollRun")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
+	var r0 entity.PayrollRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (entity.PayrollRun, error)); ok {
+		return rf(periodID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entity.PayrollRun); ok {
 		r0 = rf(periodID)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(entity.PayrollRun)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(periodID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// CreatePayslipsByPeriod provides a mock function with given fields: payslips
-func (_m *PayrollRepository) CreatePayslipsByPeriod(payslips []entity.PayrollPayslip) error {
-	ret := _m.Called(payslips)
+// GetLatestApprovedPayrollRun provides a mock function with given fields: periodID
+func (_m *PayrollRepository) GetLatestApprovedPayrollRun(periodID int64) (entity.PayrollRun, error) {
+	ret := _m.Called(periodID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreatePayslipsByPeriod")
+		panic("no return value specified for GetLatestApprovedPayrollRun")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func([]entity.PayrollPayslip) error); ok {
-		r0 = rf(payslips)
+	var r0 entity.PayrollRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (entity.PayrollRun, error)); ok {
+		return rf(periodID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entity.PayrollRun); ok {
+		r0 = rf(periodID)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(entity.PayrollRun)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(periodID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// GetPayslip provides a mock function with given fields: userID, periodID
-func (_m *PayrollRepository) GetPayslip(userID int64, periodID int64) (entity.PayrollPayslip, error) {
-	ret := _m.Called(userID, periodID)
+// GetLatestClosedPeriod provides a mock function with no fields
+func (_m *PayrollRepository) GetLatestClosedPeriod() (entity.PayrollPeriod, error) {
+	ret := _m.Called()
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPayslip")
+		panic("no return value specified for GetLatestClosedPeriod")
+	}
+
+	var r0 entity.PayrollPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (entity.PayrollPeriod, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() entity.PayrollPeriod); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(entity.PayrollPeriod)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestPolicy provides a mock function with no fields
+func (_m *PayrollRepository) GetLatestPolicy() (entity.PayrollPolicy, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestPolicy")
+	}
+
+	var r0 entity.PayrollPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (entity.PayrollPolicy, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() entity.PayrollPolicy); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(entity.PayrollPolicy)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOverlappingPeriods provides a mock function with given fields: startTime, endTime, excludeID
+func (_m *PayrollRepository) GetOverlappingPeriods(startTime time.Time, endTime time.Time, excludeID int64) ([]entity.PayrollPeriod, error) {
+	ret := _m.Called(startTime, endTime, excludeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOverlappingPeriods")
+	}
+
+	var r0 []entity.PayrollPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, int64) ([]entity.PayrollPeriod, error)); ok {
+		return rf(startTime, endTime, excludeID)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, int64) []entity.PayrollPeriod); ok {
+		r0 = rf(startTime, endTime, excludeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayrollPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time, int64) error); ok {
+		r1 = rf(startTime, endTime, excludeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayrollRunByVersion provides a mock function with given fields: periodID, version
+func (_m *PayrollRepository) GetPayrollRunByVersion(periodID int64, version int) (entity.PayrollRun, error) {
+	ret := _m.Called(periodID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayrollRunByVersion")
+	}
+
+	var r0 entity.PayrollRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int) (entity.PayrollRun, error)); ok {
+		return rf(periodID, version)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int) entity.PayrollRun); ok {
+		r0 = rf(periodID, version)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollRun)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int) error); ok {
+		r1 = rf(periodID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayslipByRunID provides a mock function with given fields: runID, userID
+func (_m *PayrollRepository) GetPayslipByRunID(runID int64, userID int64) (entity.PayrollPayslip, error) {
+	ret := _m.Called(runID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayslipByRunID")
 	}
 
 	var r0 entity.PayrollPayslip
 	var r1 error
 	if rf, ok := ret.Get(0).(func(int64, int64) (entity.PayrollPayslip, error)); ok {
-		return rf(userID, periodID)
+		return rf(runID, userID)
 	}
 	if rf, ok := ret.Get(0).(func(int64, int64) entity.PayrollPayslip); ok {
-		r0 = rf(userID, periodID)
+		r0 = rf(runID, userID)
 	} else {
 		r0 = ret.Get(0).(entity.PayrollPayslip)
 	}
 
 	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
-		r1 = rf(userID, periodID)
+		r1 = rf(runID, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -108,6 +328,66 @@ func (_m *PayrollRepository) GetPayslips(periodID int64) ([]entity.PayrollPaysli
 	return r0, r1
 }
 
+// GetPayslipsByRunID provides a mock function with given fields: runID
+func (_m *PayrollRepository) GetPayslipsByRunID(runID int64) ([]entity.PayrollPayslip, error) {
+	ret := _m.Called(runID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayslipsByRunID")
+	}
+
+	var r0 []entity.PayrollPayslip
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.PayrollPayslip, error)); ok {
+		return rf(runID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.PayrollPayslip); ok {
+		r0 = rf(runID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayrollPayslip)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(runID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayslipsByTimeRange provides a mock function with given fields: startTime, endTime, userID
+func (_m *PayrollRepository) GetPayslipsByTimeRange(startTime time.Time, endTime time.Time, userID *int64) ([]entity.PayrollPayslip, error) {
+	ret := _m.Called(startTime, endTime, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayslipsByTimeRange")
+	}
+
+	var r0 []entity.PayrollPayslip
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, *int64) ([]entity.PayrollPayslip, error)); ok {
+		return rf(startTime, endTime, userID)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, *int64) []entity.PayrollPayslip); ok {
+		r0 = rf(startTime, endTime, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayrollPayslip)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time, *int64) error); ok {
+		r1 = rf(startTime, endTime, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPeriodByEntityDate provides a mock function with given fields: date
 func (_m *PayrollRepository) GetPeriodByEntityDate(date time.Time) (entity.PayrollPeriod, error) {
 	ret := _m.Called(date)
@@ -164,6 +444,283 @@ func (_m *PayrollRepository) GetPeriodByID(periodID int64) (entity.PayrollPeriod
 	return r0, r1
 }
 
+// GetPolicyByID provides a mock function with given fields: policyID
+func (_m *PayrollRepository) GetPolicyByID(policyID int64) (entity.PayrollPolicy, error) {
+	ret := _m.Called(policyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPolicyByID")
+	}
+
+	var r0 entity.PayrollPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (entity.PayrollPolicy, error)); ok {
+		return rf(policyID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entity.PayrollPolicy); ok {
+		r0 = rf(policyID)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollPolicy)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(policyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPolicyEffectiveOn provides a mock function with given fields: date
+func (_m *PayrollRepository) GetPolicyEffectiveOn(date time.Time) (entity.PayrollPolicy, error) {
+	ret := _m.Called(date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPolicyEffectiveOn")
+	}
+
+	var r0 entity.PayrollPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (entity.PayrollPolicy, error)); ok {
+		return rf(date)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) entity.PayrollPolicy); ok {
+		r0 = rf(date)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollPolicy)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatutoryRatesEffectiveOn provides a mock function with given fields: date
+func (_m *PayrollRepository) GetStatutoryRatesEffectiveOn(date time.Time) (entity.StatutoryRates, error) {
+	ret := _m.Called(date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatutoryRatesEffectiveOn")
+	}
+
+	var r0 entity.StatutoryRates
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (entity.StatutoryRates, error)); ok {
+		return rf(date)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) entity.StatutoryRates); ok {
+		r0 = rf(date)
+	} else {
+		r0 = ret.Get(0).(entity.StatutoryRates)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPayrollRuns provides a mock function with given fields: periodID
+func (_m *PayrollRepository) ListPayrollRuns(periodID int64) ([]entity.PayrollRun, error) {
+	ret := _m.Called(periodID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPayrollRuns")
+	}
+
+	var r0 []entity.PayrollRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.PayrollRun, error)); ok {
+		return rf(periodID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.PayrollRun); ok {
+		r0 = rf(periodID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayrollRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(periodID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPeriods provides a mock function with given fields: status
+func (_m *PayrollRepository) ListPeriods(status string) ([]entity.PayrollPeriod, error) {
+	ret := _m.Called(status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPeriods")
+	}
+
+	var r0 []entity.PayrollPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]entity.PayrollPeriod, error)); ok {
+		return rf(status)
+	}
+	if rf, ok := ret.Get(0).(func(string) []entity.PayrollPeriod); ok {
+		r0 = rf(status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayrollPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPolicies provides a mock function with no fields
+func (_m *PayrollRepository) ListPolicies() ([]entity.PayrollPolicy, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPolicies")
+	}
+
+	var r0 []entity.PayrollPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.PayrollPolicy, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.PayrollPolicy); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayrollPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReopenPayrollPeriod provides a mock function with given fields: periodID, reopenedBy
+func (_m *PayrollRepository) ReopenPayrollPeriod(periodID int64, reopenedBy string) ([]int64, []entity.EmployeeDeduction, error) {
+	ret := _m.Called(periodID, reopenedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReopenPayrollPeriod")
+	}
+
+	var r0 []int64
+	var r1 []entity.EmployeeDeduction
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int64, string) ([]int64, []entity.EmployeeDeduction, error)); ok {
+		return rf(periodID, reopenedBy)
+	}
+	if rf, ok := ret.Get(0).(func(int64, string) []int64); ok {
+		r0 = rf(periodID, reopenedBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, string) []entity.EmployeeDeduction); ok {
+		r1 = rf(periodID, reopenedBy)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]entity.EmployeeDeduction)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(int64, string) error); ok {
+		r2 = rf(periodID, reopenedBy)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// RerunPayroll provides a mock function with given fields: previousRunID, from, run, payslips, deductions, auditLog
+func (_m *PayrollRepository) RerunPayroll(previousRunID int64, from entity.PayrollPeriodStatus, run entity.PayrollRun, payslips []entity.PayrollPayslip, deductions []entity.EmployeeDeduction, auditLog entity.AuditLog) (entity.PayrollRun, error) {
+	ret := _m.Called(previousRunID, from, run, payslips, deductions, auditLog)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RerunPayroll")
+	}
+
+	var r0 entity.PayrollRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, entity.PayrollPeriodStatus, entity.PayrollRun, []entity.PayrollPayslip, []entity.EmployeeDeduction, entity.AuditLog) (entity.PayrollRun, error)); ok {
+		return rf(previousRunID, from, run, payslips, deductions, auditLog)
+	}
+	if rf, ok := ret.Get(0).(func(int64, entity.PayrollPeriodStatus, entity.PayrollRun, []entity.PayrollPayslip, []entity.EmployeeDeduction, entity.AuditLog) entity.PayrollRun); ok {
+		r0 = rf(previousRunID, from, run, payslips, deductions, auditLog)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollRun)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, entity.PayrollPeriodStatus, entity.PayrollRun, []entity.PayrollPayslip, []entity.EmployeeDeduction, entity.AuditLog) error); ok {
+		r1 = rf(previousRunID, from, run, payslips, deductions, auditLog)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePeriod provides a mock function with given fields: periodID, status, updates
+func (_m *PayrollRepository) UpdatePeriod(periodID int64, status entity.PayrollPeriodStatus, updates map[string]interface{}) error {
+	ret := _m.Called(periodID, status, updates)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePeriod")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, entity.PayrollPeriodStatus, map[string]interface{}) error); ok {
+		r0 = rf(periodID, status, updates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePeriodStatus provides a mock function with given fields: periodID, from, to, updatedBy
+func (_m *PayrollRepository) UpdatePeriodStatus(periodID int64, from entity.PayrollPeriodStatus, to entity.PayrollPeriodStatus, updatedBy string) error {
+	ret := _m.Called(periodID, from, to, updatedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePeriodStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, entity.PayrollPeriodStatus, entity.PayrollPeriodStatus, string) error); ok {
+		r0 = rf(periodID, from, to, updatedBy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewPayrollRepository creates a new instance of PayrollRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPayrollRepository(t interface {
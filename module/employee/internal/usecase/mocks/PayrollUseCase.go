@@ -0,0 +1,375 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PayrollUseCase is an autogenerated mock type for the PayrollUseCase type
+type PayrollUseCase struct {
+	mock.Mock
+}
+
+// ApprovePayrollPeriod provides a mock function with given fields: userContext, periodID
+func (_m *PayrollUseCase) ApprovePayrollPeriod(userContext entity.UserContext, periodID int64) error {
+	ret := _m.Called(userContext, periodID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApprovePayrollPeriod")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) error); ok {
+		r0 = rf(userContext, periodID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ArchivePayrollPeriod provides a mock function with given fields: userContext, periodID
+func (_m *PayrollUseCase) ArchivePayrollPeriod(userContext entity.UserContext, periodID int64) error {
+	ret := _m.Called(userContext, periodID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ArchivePayrollPeriod")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) error); ok {
+		r0 = rf(userContext, periodID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClosePayrollPeriod provides a mock function with given fields: userContex, periodID
+func (_m *PayrollUseCase) ClosePayrollPeriod(userContex entity.UserContext, periodID int64) error {
+	ret := _m.Called(userContex, periodID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClosePayrollPeriod")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) error); ok {
+		r0 = rf(userContex, periodID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DiffPayrollRuns provides a mock function with given fields: userContext, periodID, fromVersion, toVersion
+func (_m *PayrollUseCase) DiffPayrollRuns(userContext entity.UserContext, periodID int64, fromVersion int, toVersion int) (entity.PayrollRunDiff, error) {
+	ret := _m.Called(userContext, periodID, fromVersion, toVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiffPayrollRuns")
+	}
+
+	var r0 entity.PayrollRunDiff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, int, int) (entity.PayrollRunDiff, error)); ok {
+		return rf(userContext, periodID, fromVersion, toVersion)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, int, int) entity.PayrollRunDiff); ok {
+		r0 = rf(userContext, periodID, fromVersion, toVersion)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollRunDiff)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64, int, int) error); ok {
+		r1 = rf(userContext, periodID, fromVersion, toVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportPayrollSummaryCSV provides a mock function with given fields: userContext, periodID, groupBy
+func (_m *PayrollUseCase) ExportPayrollSummaryCSV(userContext entity.UserContext, periodID int64, groupBy string) ([]byte, error) {
+	ret := _m.Called(userContext, periodID, groupBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportPayrollSummaryCSV")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, string) ([]byte, error)); ok {
+		return rf(userContext, periodID, groupBy)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, string) []byte); ok {
+		r0 = rf(userContext, periodID, groupBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64, string) error); ok {
+		r1 = rf(userContext, periodID, groupBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GeneratePayslipsByPeriodID provides a mock function with given fields: userContext, periodID
+func (_m *PayrollUseCase) GeneratePayslipsByPeriodID(userContext entity.UserContext, periodID int64) (entity.PayrollRun, error) {
+	ret := _m.Called(userContext, periodID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GeneratePayslipsByPeriodID")
+	}
+
+	var r0 entity.PayrollRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) (entity.PayrollRun, error)); ok {
+		return rf(userContext, periodID)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) entity.PayrollRun); ok {
+		r0 = rf(userContext, periodID)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollRun)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64) error); ok {
+		r1 = rf(userContext, periodID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayrollSummary provides a mock function with given fields: userContext, periodID, groupBy
+func (_m *PayrollUseCase) GetPayrollSummary(userContext entity.UserContext, periodID int64, groupBy string) (entity.PayrollSummary, error) {
+	ret := _m.Called(userContext, periodID, groupBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayrollSummary")
+	}
+
+	var r0 entity.PayrollSummary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, string) (entity.PayrollSummary, error)); ok {
+		return rf(userContext, periodID, groupBy)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, string) entity.PayrollSummary); ok {
+		r0 = rf(userContext, periodID, groupBy)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollSummary)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64, string) error); ok {
+		r1 = rf(userContext, periodID, groupBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayslip provides a mock function with given fields: userContext, userID, periodID
+func (_m *PayrollUseCase) GetPayslip(userContext entity.UserContext, userID int64, periodID int64) (entity.PayrollPayslip, error) {
+	ret := _m.Called(userContext, userID, periodID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayslip")
+	}
+
+	var r0 entity.PayrollPayslip
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, int64) (entity.PayrollPayslip, error)); ok {
+		return rf(userContext, userID, periodID)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, int64) entity.PayrollPayslip); ok {
+		r0 = rf(userContext, userID, periodID)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollPayslip)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64, int64) error); ok {
+		r1 = rf(userContext, userID, periodID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayslips provides a mock function with given fields: userContext, periodID
+func (_m *PayrollUseCase) GetPayslips(userContext entity.UserContext, periodID int64) ([]entity.PayrollPayslip, error) {
+	ret := _m.Called(userContext, periodID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayslips")
+	}
+
+	var r0 []entity.PayrollPayslip
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) ([]entity.PayrollPayslip, error)); ok {
+		return rf(userContext, periodID)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) []entity.PayrollPayslip); ok {
+		r0 = rf(userContext, periodID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayrollPayslip)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64) error); ok {
+		r1 = rf(userContext, periodID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPayrollRuns provides a mock function with given fields: userContext, periodID
+func (_m *PayrollUseCase) ListPayrollRuns(userContext entity.UserContext, periodID int64) ([]entity.PayrollRun, error) {
+	ret := _m.Called(userContext, periodID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPayrollRuns")
+	}
+
+	var r0 []entity.PayrollRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) ([]entity.PayrollRun, error)); ok {
+		return rf(userContext, periodID)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) []entity.PayrollRun); ok {
+		r0 = rf(userContext, periodID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PayrollRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64) error); ok {
+		r1 = rf(userContext, periodID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkPayrollPeriodPaid provides a mock function with given fields: userContext, periodID
+func (_m *PayrollUseCase) MarkPayrollPeriodPaid(userContext entity.UserContext, periodID int64) error {
+	ret := _m.Called(userContext, periodID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkPayrollPeriodPaid")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) error); ok {
+		r0 = rf(userContext, periodID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PreviewPayroll provides a mock function with given fields: userContext, periodID
+func (_m *PayrollUseCase) PreviewPayroll(userContext entity.UserContext, periodID int64) (entity.PayrollPreview, error) {
+	ret := _m.Called(userContext, periodID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreviewPayroll")
+	}
+
+	var r0 entity.PayrollPreview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) (entity.PayrollPreview, error)); ok {
+		return rf(userContext, periodID)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) entity.PayrollPreview); ok {
+		r0 = rf(userContext, periodID)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollPreview)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64) error); ok {
+		r1 = rf(userContext, periodID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReopenPayrollPeriod provides a mock function with given fields: userContext, periodID, request
+func (_m *PayrollUseCase) ReopenPayrollPeriod(userContext entity.UserContext, periodID int64, request entity.ReopenPayrollPeriodRequest) error {
+	ret := _m.Called(userContext, periodID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReopenPayrollPeriod")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.ReopenPayrollPeriodRequest) error); ok {
+		r0 = rf(userContext, periodID, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RerunPayroll provides a mock function with given fields: userContext, periodID, request
+func (_m *PayrollUseCase) RerunPayroll(userContext entity.UserContext, periodID int64, request entity.RerunPayrollRequest) (entity.PayrollRun, error) {
+	ret := _m.Called(userContext, periodID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RerunPayroll")
+	}
+
+	var r0 entity.PayrollRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.RerunPayrollRequest) (entity.PayrollRun, error)); ok {
+		return rf(userContext, periodID, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.RerunPayrollRequest) entity.PayrollRun); ok {
+		r0 = rf(userContext, periodID, request)
+	} else {
+		r0 = ret.Get(0).(entity.PayrollRun)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64, entity.RerunPayrollRequest) error); ok {
+		r1 = rf(userContext, periodID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPayrollUseCase creates a new instance of PayrollUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPayrollUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PayrollUseCase {
+	mock := &PayrollUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,123 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PermissionUseCase is an autogenerated mock type for the PermissionUseCase type
+type PermissionUseCase struct {
+	mock.Mock
+}
+
+// GetPermissionsByRole provides a mock function with given fields: role
+func (_m *PermissionUseCase) GetPermissionsByRole(role entity.UserRole) ([]entity.Permission, error) {
+	ret := _m.Called(role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPermissionsByRole")
+	}
+
+	var r0 []entity.Permission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserRole) ([]entity.Permission, error)); ok {
+		return rf(role)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserRole) []entity.Permission); ok {
+		r0 = rf(role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Permission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserRole) error); ok {
+		r1 = rf(role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrantPermission provides a mock function with given fields: userContext, role, permission
+func (_m *PermissionUseCase) GrantPermission(userContext entity.UserContext, role entity.UserRole, permission entity.Permission) error {
+	ret := _m.Called(userContext, role, permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GrantPermission")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.UserRole, entity.Permission) error); ok {
+		r0 = rf(userContext, role, permission)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListRolePermissions provides a mock function with no fields
+func (_m *PermissionUseCase) ListRolePermissions() (map[entity.UserRole][]entity.Permission, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRolePermissions")
+	}
+
+	var r0 map[entity.UserRole][]entity.Permission
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (map[entity.UserRole][]entity.Permission, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() map[entity.UserRole][]entity.Permission); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[entity.UserRole][]entity.Permission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokePermission provides a mock function with given fields: userContext, role, permission
+func (_m *PermissionUseCase) RevokePermission(userContext entity.UserContext, role entity.UserRole, permission entity.Permission) error {
+	ret := _m.Called(userContext, role, permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokePermission")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.UserRole, entity.Permission) error); ok {
+		r0 = rf(userContext, role, permission)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPermissionUseCase creates a new instance of PermissionUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPermissionUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PermissionUseCase {
+	mock := &PermissionUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
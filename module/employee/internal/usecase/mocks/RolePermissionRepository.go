@@ -0,0 +1,123 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RolePermissionRepository is an autogenerated mock type for the RolePermissionRepository type
+type RolePermissionRepository struct {
+	mock.Mock
+}
+
+// CreateRolePermission provides a mock function with given fields: rolePermission
+func (_m *RolePermissionRepository) CreateRolePermission(rolePermission entity.RolePermission) error {
+	ret := _m.Called(rolePermission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRolePermission")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.RolePermission) error); ok {
+		r0 = rf(rolePermission)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRolePermission provides a mock function with given fields: role, permission
+func (_m *RolePermissionRepository) DeleteRolePermission(role entity.UserRole, permission entity.Permission) error {
+	ret := _m.Called(role, permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRolePermission")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserRole, entity.Permission) error); ok {
+		r0 = rf(role, permission)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllRolePermissions provides a mock function with no fields
+func (_m *RolePermissionRepository) GetAllRolePermissions() ([]entity.RolePermission, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllRolePermissions")
+	}
+
+	var r0 []entity.RolePermission
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.RolePermission, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.RolePermission); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.RolePermission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPermissionsByRole provides a mock function with given fields: role
+func (_m *RolePermissionRepository) GetPermissionsByRole(role entity.UserRole) ([]entity.Permission, error) {
+	ret := _m.Called(role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPermissionsByRole")
+	}
+
+	var r0 []entity.Permission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserRole) ([]entity.Permission, error)); ok {
+		return rf(role)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserRole) []entity.Permission); ok {
+		r0 = rf(role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Permission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserRole) error); ok {
+		r1 = rf(role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRolePermissionRepository creates a new instance of RolePermissionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRolePermissionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RolePermissionRepository {
+	mock := &RolePermissionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
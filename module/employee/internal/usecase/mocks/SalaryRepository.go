@@ -0,0 +1,85 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SalaryRepository is an autogenerated mock type for the SalaryRepository type
+type SalaryRepository struct {
+	mock.Mock
+}
+
+// CreateSalary provides a mock function with given fields: salary
+func (_m *SalaryRepository) CreateSalary(salary entity.UserSalary) (entity.UserSalary, error) {
+	ret := _m.Called(salary)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSalary")
+	}
+
+	var r0 entity.UserSalary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserSalary) (entity.UserSalary, error)); ok {
+		return rf(salary)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserSalary) entity.UserSalary); ok {
+		r0 = rf(salary)
+	} else {
+		r0 = ret.Get(0).(entity.UserSalary)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserSalary) error); ok {
+		r1 = rf(salary)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSalariesByUserID provides a mock function with given fields: userID
+func (_m *SalaryRepository) GetSalariesByUserID(userID int64) ([]entity.UserSalary, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSalariesByUserID")
+	}
+
+	var r0 []entity.UserSalary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.UserSalary, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.UserSalary); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.UserSalary)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSalaryRepository creates a new instance of SalaryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSalaryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SalaryRepository {
+	mock := &SalaryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
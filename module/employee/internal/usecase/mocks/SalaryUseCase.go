@@ -0,0 +1,85 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SalaryUseCase is an autogenerated mock type for the SalaryUseCase type
+type SalaryUseCase struct {
+	mock.Mock
+}
+
+// GetSalaryHistory provides a mock function with given fields: userID
+func (_m *SalaryUseCase) GetSalaryHistory(userID int64) ([]entity.UserSalary, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSalaryHistory")
+	}
+
+	var r0 []entity.UserSalary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.UserSalary, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.UserSalary); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.UserSalary)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordSalaryChange provides a mock function with given fields: userContext, userID, request
+func (_m *SalaryUseCase) RecordSalaryChange(userContext entity.UserContext, userID int64, request entity.SalaryChangeRequest) (entity.UserSalary, error) {
+	ret := _m.Called(userContext, userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordSalaryChange")
+	}
+
+	var r0 entity.UserSalary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.SalaryChangeRequest) (entity.UserSalary, error)); ok {
+		return rf(userContext, userID, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.SalaryChangeRequest) entity.UserSalary); ok {
+		r0 = rf(userContext, userID, request)
+	} else {
+		r0 = ret.Get(0).(entity.UserSalary)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, int64, entity.SalaryChangeRequest) error); ok {
+		r1 = rf(userContext, userID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSalaryUseCase creates a new instance of SalaryUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSalaryUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SalaryUseCase {
+	mock := &SalaryUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -12,6 +12,34 @@ type UserRepository struct {
 	mock.Mock
 }
 
+// CreateUser provides a mock function with given fields: user
+func (_m *UserRepository) CreateUser(user entity.User) (entity.User, error) {
+	ret := _m.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
+	}
+
+	var r0 entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.User) (entity.User, error)); ok {
+		return rf(user)
+	}
+	if rf, ok := ret.Get(0).(func(entity.User) entity.User); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(entity.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.User) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserByID provides a mock function with given fields: userID
 func (_m *UserRepository) GetUserByID(userID int64) (entity.User, error) {
 	ret := _m.Called(userID)
@@ -68,6 +96,91 @@ func (_m *UserRepository) GetUserByUsername(username string) (entity.User, error
 	return r0, r1
 }
 
+// GetUsersByIDs provides a mock function with given fields: userIDs
+func (_m *UserRepository) GetUsersByIDs(userIDs []int64) ([]entity.User, error) {
+	ret := _m.Called(userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsersByIDs")
+	}
+
+	var r0 []entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]int64) ([]entity.User, error)); ok {
+		return rf(userIDs)
+	}
+	if rf, ok := ret.Get(0).(func([]int64) []entity.User); ok {
+		r0 = rf(userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]int64) error); ok {
+		r1 = rf(userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUsers provides a mock function with given fields: filter, pagination
+func (_m *UserRepository) ListUsers(filter entity.UserFilter, pagination entity.Pagination) ([]entity.User, int64, error) {
+	ret := _m.Called(filter, pagination)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 []entity.User
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(entity.UserFilter, entity.Pagination) ([]entity.User, int64, error)); ok {
+		return rf(filter, pagination)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserFilter, entity.Pagination) []entity.User); ok {
+		r0 = rf(filter, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserFilter, entity.Pagination) int64); ok {
+		r1 = rf(filter, pagination)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(entity.UserFilter, entity.Pagination) error); ok {
+		r2 = rf(filter, pagination)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// UpdateUser provides a mock function with given fields: userID, updates
+func (_m *UserRepository) UpdateUser(userID int64, updates map[string]interface{}) error {
+	ret := _m.Called(userID, updates)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, map[string]interface{}) error); ok {
+		r0 = rf(userID, updates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserRepository creates a new instance of UserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepository(t interface {
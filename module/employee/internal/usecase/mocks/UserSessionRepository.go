@@ -0,0 +1,141 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// UserSessionRepository is an autogenerated mock type for the UserSessionRepository type
+type UserSessionRepository struct {
+	mock.Mock
+}
+
+// CreateSession provides a mock function with given fields: session
+func (_m *UserSessionRepository) CreateSession(session entity.UserSession) error {
+	ret := _m.Called(session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserSession) error); ok {
+		r0 = rf(session)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSessionByID provides a mock function with given fields: sessionID
+func (_m *UserSessionRepository) GetSessionByID(sessionID string) (entity.UserSession, error) {
+	ret := _m.Called(sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSessionByID")
+	}
+
+	var r0 entity.UserSession
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (entity.UserSession, error)); ok {
+		return rf(sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.UserSession); ok {
+		r0 = rf(sessionID)
+	} else {
+		r0 = ret.Get(0).(entity.UserSession)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessionsRevokedSince provides a mock function with given fields: since
+func (_m *UserSessionRepository) GetSessionsRevokedSince(since time.Time) ([]entity.UserSession, error) {
+	ret := _m.Called(since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSessionsRevokedSince")
+	}
+
+	var r0 []entity.UserSession
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) ([]entity.UserSession, error)); ok {
+		return rf(since)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) []entity.UserSession); ok {
+		r0 = rf(since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.UserSession)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeSession provides a mock function with given fields: sessionID, revokedAt
+func (_m *UserSessionRepository) RevokeSession(sessionID string, revokedAt time.Time) error {
+	ret := _m.Called(sessionID, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(sessionID, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeSessionsByUserID provides a mock function with given fields: userID, revokedAt
+func (_m *UserSessionRepository) RevokeSessionsByUserID(userID int64, revokedAt time.Time) error {
+	ret := _m.Called(userID, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSessionsByUserID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, time.Time) error); ok {
+		r0 = rf(userID, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewUserSessionRepository creates a new instance of UserSessionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserSessionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserSessionRepository {
+	mock := &UserSessionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -12,6 +12,98 @@ type UserUseCase struct {
 	mock.Mock
 }
 
+// ChangeRole provides a mock function with given fields: userContext, userID, request
+func (_m *UserUseCase) ChangeRole(userContext entity.UserContext, userID int64, request entity.ChangeRoleRequest) error {
+	ret := _m.Called(userContext, userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.ChangeRoleRequest) error); ok {
+		r0 = rf(userContext, userID, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateUser provides a mock function with given fields: userContext, request
+func (_m *UserUseCase) CreateUser(userContext entity.UserContext, request entity.CreateUserRequest) (entity.User, error) {
+	ret := _m.Called(userContext, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
+	}
+
+	var r0 entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.CreateUserRequest) (entity.User, error)); ok {
+		return rf(userContext, request)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserContext, entity.CreateUserRequest) entity.User); ok {
+		r0 = rf(userContext, request)
+	} else {
+		r0 = ret.Get(0).(entity.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserContext, entity.CreateUserRequest) error); ok {
+		r1 = rf(userContext, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeactivateUser provides a mock function with given fields: userContext, userID
+func (_m *UserUseCase) DeactivateUser(userContext entity.UserContext, userID int64) error {
+	ret := _m.Called(userContext, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeactivateUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64) error); ok {
+		r0 = rf(userContext, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetUserByID provides a mock function with given fields: userID
+func (_m *UserUseCase) GetUserByID(userID int64) (entity.User, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (entity.User, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entity.User); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(entity.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserByUsernaname provides a mock function with given fields: username
 func (_m *UserUseCase) GetUserByUsernaname(username string) (entity.User, error) {
 	ret := _m.Called(username)
@@ -40,6 +132,52 @@ func (_m *UserUseCase) GetUserByUsernaname(username string) (entity.User, error)
 	return r0, r1
 }
 
+// ListUsers provides a mock function with given fields: filter
+func (_m *UserUseCase) ListUsers(filter entity.UserFilter) (entity.UserList, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 entity.UserList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserFilter) (entity.UserList, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserFilter) entity.UserList); ok {
+		r0 = rf(filter)
+	} else {
+		r0 = ret.Get(0).(entity.UserList)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetPassword provides a mock function with given fields: userContext, userID, request
+func (_m *UserUseCase) ResetPassword(userContext entity.UserContext, userID int64, request entity.ResetPasswordRequest) error {
+	ret := _m.Called(userContext, userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.UserContext, int64, entity.ResetPasswordRequest) error); ok {
+		r0 = rf(userContext, userID, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserUseCase creates a new instance of UserUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserUseCase(t interface {
@@ -0,0 +1,435 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/eafajri/hr-service.git/internal/money"
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name PayComponentUseCase --output ./mocks
+type PayComponentUseCase interface {
+	ListComponents() ([]entity.PayComponent, error)
+	GetAssignments(userID int64) ([]entity.EmployeePayComponent, error)
+
+	CreateComponent(userContext entity.UserContext, request entity.PayComponentRequest) (entity.PayComponent, error)
+	UpdateComponent(userContext entity.UserContext, componentID int64, request entity.PayComponentRequest) (entity.PayComponent, error)
+	AssignComponent(userContext entity.UserContext, userID int64, request entity.PayComponentAssignmentRequest) (entity.EmployeePayComponent, error)
+	EndAssignment(userContext entity.UserContext, userID int64, assignmentID int64, request entity.EndPayComponentAssignmentRequest) (entity.EmployeePayComponent, error)
+}
+
+type PayComponentUseCaseImpl struct {
+	payComponentRepository PayComponentRepository
+	userRepository         UserRepository
+	payrollRepository      PayrollRepository
+	auditLogRepository     AuditLogRepository
+}
+
+func NewPayComponentUseCase(
+	payComponentRepository PayComponentRepository,
+	userRepository UserRepository,
+	payrollRepository PayrollRepository,
+	auditLogRepository AuditLogRepository,
+) *PayComponentUseCaseImpl {
+	return &PayComponentUseCaseImpl{
+		payComponentRepository: payComponentRepository,
+		userRepository:         userRepository,
+		payrollRepository:      payrollRepository,
+		auditLogRepository:     auditLogRepository,
+	}
+}
+
+func (p *PayComponentUseCaseImpl) ListComponents() ([]entity.PayComponent, error) {
+	components, err := p.payComponentRepository.ListComponents()
+	if err != nil {
+		log.Println(
+			"error when ListComponents",
+			zap.String("method", "PayComponentUseCaseImpl.ListComponents"),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return components, nil
+}
+
+func (p *PayComponentUseCaseImpl) GetAssignments(userID int64) ([]entity.EmployeePayComponent, error) {
+	if err := p.ensureUserExists("PayComponentUseCaseImpl.GetAssignments", userID); err != nil {
+		return nil, err
+	}
+
+	assignments, err := p.payComponentRepository.GetAssignmentsByUserID(userID)
+	if err != nil {
+		log.Println(
+			"error when GetAssignmentsByUserID",
+			zap.String("method", "PayComponentUseCaseImpl.GetAssignments"),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return assignments, nil
+}
+
+/*
+Component codes are unique and stay the same once created, they identify the earning lines on the payslips.
+*/
+func (p *PayComponentUseCaseImpl) CreateComponent(userContext entity.UserContext, request entity.PayComponentRequest) (entity.PayComponent, error) {
+	code := strings.ToUpper(strings.TrimSpace(request.Code))
+	if code == "" {
+		return entity.PayComponent{}, errors.New("code is required")
+	}
+
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return entity.PayComponent{}, errors.New("name is required")
+	}
+
+	if !request.Kind.IsValid() {
+		return entity.PayComponent{}, errors.New("invalid pay component kind")
+	}
+
+	_, err := p.payComponentRepository.GetComponentByCode(code)
+	if err == nil {
+		return entity.PayComponent{}, errors.New("pay component code already exists")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println(
+			"error when GetComponentByCode",
+			zap.String("method", "PayComponentUseCaseImpl.CreateComponent"),
+			zap.Any("user_contex", userContext),
+			zap.String("code", code),
+			zap.Error(err),
+		)
+		return entity.PayComponent{}, err
+	}
+
+	isActive := true
+	if request.IsActive != nil {
+		isActive = *request.IsActive
+	}
+
+	component, err := p.payComponentRepository.CreateComponent(entity.PayComponent{
+		Code:      code,
+		Name:      name,
+		Kind:      request.Kind,
+		IsActive:  isActive,
+		UpdatedBy: userContext.Username,
+		CreatedBy: userContext.Username,
+	})
+	if err != nil {
+		log.Println(
+			"error when CreateComponent",
+			zap.String("method", "PayComponentUseCaseImpl.CreateComponent"),
+			zap.Any("user_contex", userContext),
+			zap.Any("request", request),
+			zap.Error(err),
+		)
+		return entity.PayComponent{}, err
+	}
+
+	p.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "create",
+		Target:    "pay_component",
+		TableName: "pay_components",
+		CreatedBy: userContext.Username,
+	}, component)
+
+	return component, nil
+}
+
+/*
+Only the name and the active flag can be updated. Deactivating a component stops new assignments,
+the existing ones keep being paid until they are ended.
+*/
+func (p *PayComponentUseCaseImpl) UpdateComponent(userContext entity.UserContext, componentID int64, request entity.PayComponentRequest) (entity.PayComponent, error) {
+	component, err := p.getComponent("PayComponentUseCaseImpl.UpdateComponent", componentID)
+	if err != nil {
+		return entity.PayComponent{}, err
+	}
+
+	updates := map[string]interface{}{
+		"updated_by": userContext.Username,
+	}
+	if name := strings.TrimSpace(request.Name); name != "" {
+		updates["name"] = name
+		component.Name = name
+	}
+	if request.IsActive != nil {
+		updates["is_active"] = *request.IsActive
+		component.IsActive = *request.IsActive
+	}
+	component.UpdatedBy = userContext.Username
+
+	err = p.payComponentRepository.UpdateComponent(componentID, updates)
+	if err != nil {
+		log.Println(
+			"error when UpdateComponent",
+			zap.String("method", "PayComponentUseCaseImpl.UpdateComponent"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("component_id", componentID),
+			zap.Error(err),
+		)
+		return entity.PayComponent{}, err
+	}
+
+	p.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "update",
+		Target:    "pay_component",
+		TableName: "pay_components",
+		CreatedBy: userContext.Username,
+	}, updates)
+
+	return component, nil
+}
+
+/*
+Assignments are effective dated like salaries, they cannot start on or before the end of the latest closed period.
+An employee cannot have two assignments of the same component overlapping each other, the running one has to be
+ended before the amount or method changes.
+*/
+func (p *PayComponentUseCaseImpl) AssignComponent(userContext entity.UserContext, userID int64, request entity.PayComponentAssignmentRequest) (entity.EmployeePayComponent, error) {
+	if !request.Method.IsValid() {
+		return entity.EmployeePayComponent{}, errors.New("calculation method must be one of fixed or per_attendance_day")
+	}
+
+	if !request.Amount.IsPositive() {
+		return entity.EmployeePayComponent{}, errors.New("amount must be greater than zero")
+	}
+
+	if !money.FitsScale(request.Amount) {
+		return entity.EmployeePayComponent{}, errors.New("amount cannot have more than 2 decimal places")
+	}
+
+	effectiveFrom, err := time.Parse("2006-01-02", request.EffectiveFrom)
+	if err != nil {
+		return entity.EmployeePayComponent{}, errors.New("invalid effective date format, must be YYYY-MM-DD")
+	}
+
+	var effectiveTo *time.Time
+	if request.EffectiveTo != "" {
+		parsed, err := time.Parse("2006-01-02", request.EffectiveTo)
+		if err != nil {
+			return entity.EmployeePayComponent{}, errors.New("invalid end date format, must be YYYY-MM-DD")
+		}
+		if parsed.Before(effectiveFrom) {
+			return entity.EmployeePayComponent{}, errors.New("end date cannot be before the effective date")
+		}
+		effectiveTo = &parsed
+	}
+
+	if err := p.ensureUserExists("PayComponentUseCaseImpl.AssignComponent", userID); err != nil {
+		return entity.EmployeePayComponent{}, err
+	}
+
+	component, err := p.getComponent("PayComponentUseCaseImpl.AssignComponent", request.PayComponentID)
+	if err != nil {
+		return entity.EmployeePayComponent{}, err
+	}
+	if !component.IsActive {
+		return entity.EmployeePayComponent{}, errors.New("pay component is not active")
+	}
+
+	if err := ensureAfterClosedPeriod(p.payrollRepository, userContext, "PayComponentUseCaseImpl.AssignComponent", effectiveFrom); err != nil {
+		return entity.EmployeePayComponent{}, err
+	}
+
+	assignments, err := p.payComponentRepository.GetAssignmentsByUserID(userID)
+	if err != nil {
+		log.Println(
+			"error when GetAssignmentsByUserID",
+			zap.String("method", "PayComponentUseCaseImpl.AssignComponent"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return entity.EmployeePayComponent{}, err
+	}
+
+	assignment := entity.EmployeePayComponent{
+		UserID:         userID,
+		PayComponentID: component.ID,
+		Method:         request.Method,
+		Amount:         request.Amount,
+		EffectiveFrom:  effectiveFrom,
+		EffectiveTo:    effectiveTo,
+		UpdatedBy:      userContext.Username,
+		CreatedBy:      userContext.Username,
+	}
+
+	for _, existing := range assignments {
+		if existing.PayComponentID != component.ID {
+			continue
+		}
+		if overlaps(existing, assignment) {
+			return entity.EmployeePayComponent{}, errors.New("the employee already has this pay component in the given dates")
+		}
+	}
+
+	assignment, err = p.payComponentRepository.CreateAssignment(assignment)
+	if err != nil {
+		log.Println(
+			"error when CreateAssignment",
+			zap.String("method", "PayComponentUseCaseImpl.AssignComponent"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("user_id", userID),
+			zap.Any("request", request),
+			zap.Error(err),
+		)
+		return entity.EmployeePayComponent{}, err
+	}
+	assignment.PayComponent = component
+
+	p.auditLogRepository.Create(entity.AuditLog{
+		RequestID:     userContext.RequestID,
+		IPAddress:     userContext.IPAddress,
+		Action:        "create",
+		Target:        "employee_pay_component",
+		TableName:     "employee_pay_components",
+		CreatedBy:     userContext.Username,
+		SubjectUserID: &userID,
+	}, assignment)
+
+	return assignment, nil
+}
+
+/*
+Ending an assignment sets the last day it is paid for, which cannot fall inside a closed period.
+*/
+func (p *PayComponentUseCaseImpl) EndAssignment(userContext entity.UserContext, userID int64, assignmentID int64, request entity.EndPayComponentAssignmentRequest) (entity.EmployeePayComponent, error) {
+	effectiveTo, err := time.Parse("2006-01-02", request.EffectiveTo)
+	if err != nil {
+		return entity.EmployeePayComponent{}, errors.New("invalid end date format, must be YYYY-MM-DD")
+	}
+
+	assignment, err := p.payComponentRepository.GetAssignmentByID(assignmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.EmployeePayComponent{}, errors.New("pay component assignment not found")
+		}
+		log.Println(
+			"error when GetAssignmentByID",
+			zap.String("method", "PayComponentUseCaseImpl.EndAssignment"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("assignment_id", assignmentID),
+			zap.Error(err),
+		)
+		return entity.EmployeePayComponent{}, err
+	}
+	if assignment.UserID != userID {
+		return entity.EmployeePayComponent{}, errors.New("pay component assignment not found")
+	}
+
+	if effectiveTo.Before(assignment.EffectiveFrom) {
+		return entity.EmployeePayComponent{}, errors.New("end date cannot be before the effective date")
+	}
+
+	if err := ensureAfterClosedPeriod(p.payrollRepository, userContext, "PayComponentUseCaseImpl.EndAssignment", effectiveTo); err != nil {
+		return entity.EmployeePayComponent{}, err
+	}
+
+	updates := map[string]interface{}{
+		"effective_to": effectiveTo,
+		"updated_by":   userContext.Username,
+	}
+	err = p.payComponentRepository.UpdateAssignment(assignmentID, updates)
+	if err != nil {
+		log.Println(
+			"error when UpdateAssignment",
+			zap.String("method", "PayComponentUseCaseImpl.EndAssignment"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("assignment_id", assignmentID),
+			zap.Error(err),
+		)
+		return entity.EmployeePayComponent{}, err
+	}
+	assignment.EffectiveTo = &effectiveTo
+	assignment.UpdatedBy = userContext.Username
+
+	p.auditLogRepository.Create(entity.AuditLog{
+		RequestID:     userContext.RequestID,
+		IPAddress:     userContext.IPAddress,
+		Action:        "update",
+		Target:        "employee_pay_component",
+		TableName:     "employee_pay_components",
+		CreatedBy:     userContext.Username,
+		SubjectUserID: &userID,
+	}, updates)
+
+	return assignment, nil
+}
+
+func (p *PayComponentUseCaseImpl) getComponent(method string, componentID int64) (entity.PayComponent, error) {
+	component, err := p.payComponentRepository.GetComponentByID(componentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.PayComponent{}, errors.New("pay component not found")
+		}
+		log.Println(
+			"error when GetComponentByID",
+			zap.String("method", method),
+			zap.Int64("component_id", componentID),
+			zap.Error(err),
+		)
+		return entity.PayComponent{}, err
+	}
+	return component, nil
+}
+
+func (p *PayComponentUseCaseImpl) ensureUserExists(method string, userID int64) error {
+	_, err := p.userRepository.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		log.Println(
+			"error when GetUserByID",
+			zap.String("method", method),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// overlaps reports whether two assignments share at least one day, a nil EffectiveTo is open ended.
+func overlaps(a entity.EmployeePayComponent, b entity.EmployeePayComponent) bool {
+	if a.EffectiveTo != nil && a.EffectiveTo.Before(b.EffectiveFrom) {
+		return false
+	}
+	if b.EffectiveTo != nil && b.EffectiveTo.Before(a.EffectiveFrom) {
+		return false
+	}
+	return true
+}
+
+// ensureAfterClosedPeriod rejects dates on or before the end of the latest closed period, whose payslips are final.
+func ensureAfterClosedPeriod(payrollRepository PayrollRepository, userContext entity.UserContext, method string, date time.Time) error {
+	closedPeriod, err := payrollRepository.GetLatestClosedPeriod()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println(
+			"error when GetLatestClosedPeriod",
+			zap.String("method", method),
+			zap.Any("user_contex", userContext),
+			zap.Error(err),
+		)
+		return err
+	}
+	if err == nil && !date.After(closedPeriod.PeriodEnd) {
+		return fmt.Errorf(
+			"effective date must be after %s, the end of the latest closed payroll period",
+			closedPeriod.PeriodEnd.Format("2006-01-02"),
+		)
+	}
+	return nil
+}
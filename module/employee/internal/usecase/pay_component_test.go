@@ -0,0 +1,266 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func Test_PayComponentUseCase_CreateComponent(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  entity.PayComponentRequest
+		mockFunc func(
+			payComponentRepository *mocks.PayComponentRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+		wantRes entity.PayComponent
+	}{
+		{
+			name:    "error - invalid kind",
+			request: entity.PayComponentRequest{Code: "meal", Name: "Meal allowance", Kind: "bonus"},
+			mockFunc: func(
+				payComponentRepository *mocks.PayComponentRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("invalid pay component kind"),
+		},
+		{
+			name:    "error - code already exists",
+			request: entity.PayComponentRequest{Code: "meal", Name: "Meal allowance", Kind: entity.PayComponentKindEarning},
+			mockFunc: func(
+				payComponentRepository *mocks.PayComponentRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payComponentRepository.On("GetComponentByCode", "MEAL").Return(entity.PayComponent{ID: 1, Code: "MEAL"}, nil)
+			},
+			wantErr: errors.New("pay component code already exists"),
+		},
+		{
+			name:    "success",
+			request: entity.PayComponentRequest{Code: " meal ", Name: "Meal allowance", Kind: entity.PayComponentKindEarning},
+			mockFunc: func(
+				payComponentRepository *mocks.PayComponentRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payComponentRepository.On("GetComponentByCode", "MEAL").Return(entity.PayComponent{}, gorm.ErrRecordNotFound)
+				payComponentRepository.On("CreateComponent", mock.Anything).
+					Return(func(component entity.PayComponent) (entity.PayComponent, error) {
+						component.ID = 3
+						return component, nil
+					})
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantRes: entity.PayComponent{
+				ID:        3,
+				Code:      "MEAL",
+				Name:      "Meal allowance",
+				Kind:      entity.PayComponentKindEarning,
+				IsActive:  true,
+				UpdatedBy: "hr",
+				CreatedBy: "hr",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payComponentRepository := mocks.NewPayComponentRepository(t)
+			userRepository := mocks.NewUserRepository(t)
+			payrollRepository := mocks.NewPayrollRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			tt.mockFunc(payComponentRepository, auditLogRepository)
+
+			usecase := usecase.NewPayComponentUseCase(payComponentRepository, userRepository, payrollRepository, auditLogRepository)
+			res, err := usecase.CreateComponent(entity.UserContext{Username: "hr"}, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.Equal(t, tt.wantRes, res)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_PayComponentUseCase_AssignComponent(t *testing.T) {
+	validRequest := entity.PayComponentAssignmentRequest{
+		PayComponentID: 3,
+		Method:         entity.CalculationPerAttendanceDay,
+		Amount:         decimal.NewFromInt(25000),
+		EffectiveFrom:  "2025-07-01",
+	}
+	mealAllowance := entity.PayComponent{ID: 3, Code: "MEAL", Name: "Meal allowance", Kind: entity.PayComponentKindEarning, IsActive: true}
+	closedPeriod := entity.PayrollPeriod{
+		ID:        6,
+		PeriodEnd: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		Status:    entity.PayrollStatusLocked,
+	}
+	june30 := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		request  entity.PayComponentAssignmentRequest
+		mockFunc func(
+			payComponentRepository *mocks.PayComponentRepository,
+			userRepository *mocks.UserRepository,
+			payrollRepository *mocks.PayrollRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+		wantRes entity.EmployeePayComponent
+	}{
+		{
+			name: "error - invalid method",
+			request: entity.PayComponentAssignmentRequest{
+				PayComponentID: 3,
+				Method:         "per_hour",
+				Amount:         decimal.NewFromInt(25000),
+				EffectiveFrom:  "2025-07-01",
+			},
+			mockFunc: func(
+				payComponentRepository *mocks.PayComponentRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("calculation method must be one of fixed or per_attendance_day"),
+		},
+		{
+			name: "error - ends before it starts",
+			request: func() entity.PayComponentAssignmentRequest {
+				request := validRequest
+				request.EffectiveTo = "2025-06-30"
+				return request
+			}(),
+			mockFunc: func(
+				payComponentRepository *mocks.PayComponentRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("end date cannot be before the effective date"),
+		},
+		{
+			name:    "error - component is not active",
+			request: validRequest,
+			mockFunc: func(
+				payComponentRepository *mocks.PayComponentRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{ID: 7}, nil)
+				payComponentRepository.On("GetComponentByID", int64(3)).Return(entity.PayComponent{ID: 3, IsActive: false}, nil)
+			},
+			wantErr: errors.New("pay component is not active"),
+		},
+		{
+			name: "error - starts inside a closed period",
+			request: func() entity.PayComponentAssignmentRequest {
+				request := validRequest
+				request.EffectiveFrom = "2025-06-15"
+				return request
+			}(),
+			mockFunc: func(
+				payComponentRepository *mocks.PayComponentRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{ID: 7}, nil)
+				payComponentRepository.On("GetComponentByID", int64(3)).Return(mealAllowance, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(closedPeriod, nil)
+			},
+			wantErr: errors.New("effective date must be after 2025-06-30, the end of the latest closed payroll period"),
+		},
+		{
+			name:    "error - overlaps a running assignment",
+			request: validRequest,
+			mockFunc: func(
+				payComponentRepository *mocks.PayComponentRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{ID: 7}, nil)
+				payComponentRepository.On("GetComponentByID", int64(3)).Return(mealAllowance, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(closedPeriod, nil)
+				payComponentRepository.On("GetAssignmentsByUserID", int64(7)).
+					Return([]entity.EmployeePayComponent{
+						{ID: 1, UserID: 7, PayComponentID: 3, EffectiveFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+					}, nil)
+			},
+			wantErr: errors.New("the employee already has this pay component in the given dates"),
+		},
+		{
+			name:    "success - previous assignment already ended",
+			request: validRequest,
+			mockFunc: func(
+				payComponentRepository *mocks.PayComponentRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{ID: 7}, nil)
+				payComponentRepository.On("GetComponentByID", int64(3)).Return(mealAllowance, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(closedPeriod, nil)
+				payComponentRepository.On("GetAssignmentsByUserID", int64(7)).
+					Return([]entity.EmployeePayComponent{
+						{ID: 1, UserID: 7, PayComponentID: 3, EffectiveFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), EffectiveTo: &june30},
+						{ID: 2, UserID: 7, PayComponentID: 4, EffectiveFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+					}, nil)
+				payComponentRepository.On("CreateAssignment", mock.Anything).
+					Return(func(assignment entity.EmployeePayComponent) (entity.EmployeePayComponent, error) {
+						assignment.ID = 5
+						return assignment, nil
+					})
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantRes: entity.EmployeePayComponent{
+				ID:             5,
+				UserID:         7,
+				PayComponentID: 3,
+				Method:         entity.CalculationPerAttendanceDay,
+				Amount:         decimal.NewFromInt(25000),
+				EffectiveFrom:  time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedBy:      "hr",
+				CreatedBy:      "hr",
+				PayComponent:   mealAllowance,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payComponentRepository := mocks.NewPayComponentRepository(t)
+			userRepository := mocks.NewUserRepository(t)
+			payrollRepository := mocks.NewPayrollRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			tt.mockFunc(payComponentRepository, userRepository, payrollRepository, auditLogRepository)
+
+			usecase := usecase.NewPayComponentUseCase(payComponentRepository, userRepository, payrollRepository, auditLogRepository)
+			res, err := usecase.AssignComponent(entity.UserContext{Username: "hr"}, 7, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.Equal(t, tt.wantRes, res)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,40 +1,82 @@
 package usecase
 
 import (
+	"bytes"
+	"encoding/csv"
 	"errors"
+	"fmt"
 	"log"
+	"strings"
 
+	"github.com/eafajri/hr-service.git/internal/money"
 	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// ErrInvalidPayrollSummaryGroup is returned when the summary is grouped by anything else than the department or role.
+var ErrInvalidPayrollSummaryGroup = fmt.Errorf("payroll summary can only be grouped by %s or %s", entity.PayrollSummaryByDepartment, entity.PayrollSummaryByRole)
+
+// PayrollConfig holds the settings shared by every payslip calculation.
+type PayrollConfig struct {
+	Rounding money.RoundingPolicy
+	// NetPayFloor is the take home pay deductions never go below, what does not fit is carried into the next period.
+	NetPayFloor decimal.Decimal
+	// OvertimeWarningHours is the overtime in a period above which a payroll preview warns, 0 turns the warning off.
+	OvertimeWarningHours int
+}
+
 //go:generate mockery --name PayrollUseCase --output ./mocks
 type PayrollUseCase interface {
-	GetPayslip(userID int64, periodID int64) (entity.PayrollPayslip, error)
-	GetPayslips(periodID int64) ([]entity.PayrollPayslip, error)
+	GetPayslip(userContext entity.UserContext, userID int64, periodID int64) (entity.PayrollPayslip, error)
+	GetPayslips(userContext entity.UserContext, periodID int64) ([]entity.PayrollPayslip, error)
+	GetPayrollSummary(userContext entity.UserContext, periodID int64, groupBy string) (entity.PayrollSummary, error)
+	ExportPayrollSummaryCSV(userContext entity.UserContext, periodID int64, groupBy string) ([]byte, error)
 	ClosePayrollPeriod(userContex entity.UserContext, periodID int64) error
-	GeneratePayslipsByPeriodID(userContext entity.UserContext, periodID int64) error
+	ReopenPayrollPeriod(userContext entity.UserContext, periodID int64, request entity.ReopenPayrollPeriodRequest) error
+	GeneratePayslipsByPeriodID(userContext entity.UserContext, periodID int64) (entity.PayrollRun, error)
+	PreviewPayroll(userContext entity.UserContext, periodID int64) (entity.PayrollPreview, error)
+	ApprovePayrollPeriod(userContext entity.UserContext, periodID int64) error
+	MarkPayrollPeriodPaid(userContext entity.UserContext, periodID int64) error
+	ArchivePayrollPeriod(userContext entity.UserContext, periodID int64) error
+	RerunPayroll(userContext entity.UserContext, periodID int64, request entity.RerunPayrollRequest) (entity.PayrollRun, error)
+	ListPayrollRuns(userContext entity.UserContext, periodID int64) ([]entity.PayrollRun, error)
+	DiffPayrollRuns(userContext entity.UserContext, periodID int64, fromVersion int, toVersion int) (entity.PayrollRunDiff, error)
 }
 
 type PayrollUseCaseImpl struct {
-	payrollRepository  PayrollRepository
-	employeeRepository EmployeeRepository
-	auditLogRepository AuditLogRepository
+	payrollConfig             PayrollConfig
+	payrollRepository         PayrollRepository
+	employeeRepository        EmployeeRepository
+	employeeProfileRepository EmployeeProfileRepository
+	userRepository            UserRepository
+	auditLogRepository        AuditLogRepository
 }
 
 func NewPayrollUseCase(
+	payrollConfig PayrollConfig,
 	payrollRepository PayrollRepository,
 	employeeRepository EmployeeRepository,
+	employeeProfileRepository EmployeeProfileRepository,
+	userRepository UserRepository,
 	auditLogRepository AuditLogRepository,
 ) *PayrollUseCaseImpl {
 	return &PayrollUseCaseImpl{
-		payrollRepository:  payrollRepository,
-		employeeRepository: employeeRepository,
-		auditLogRepository: auditLogRepository,
+		payrollConfig:             payrollConfig,
+		payrollRepository:         payrollRepository,
+		employeeRepository:        employeeRepository,
+		employeeProfileRepository: employeeProfileRepository,
+		userRepository:            userRepository,
+		auditLogRepository:        auditLogRepository,
 	}
 }
 
-func (p *PayrollUseCaseImpl) GetPayslip(userID int64, periodID int64) (entity.PayrollPayslip, error) {
+/*
+Payslips are hidden until they are approved, except from the users who calculate and approve them. Employees see
+the latest approved run of the period, reviewers the current one.
+*/
+func (p *PayrollUseCaseImpl) GetPayslip(userContext entity.UserContext, userID int64, periodID int64) (entity.PayrollPayslip, error) {
 	payrollPeriod, err := p.payrollRepository.GetPeriodByID(periodID)
 	if err != nil {
 		log.Println(
@@ -47,30 +89,37 @@ func (p *PayrollUseCaseImpl) GetPayslip(userID int64, periodID int64) (entity.Pa
 		return entity.PayrollPayslip{}, err
 	}
 
-	if payrollPeriod.Status == "open" {
-		return entity.PayrollPayslip{}, errors.New("the payroll period is still open")
+	run, err := p.ensurePayslipsVisible(userContext, payrollPeriod, "PayrollUseCaseImpl.GetPayslip")
+	if err != nil {
+		return entity.PayrollPayslip{}, err
 	}
 
-	payslip, err := p.payrollRepository.GetPayslip(userID, periodID)
+	payslip, err := p.payrollRepository.GetPayslipByRunID(run.ID, userID)
 	if err != nil {
 		log.Println(
-			"error when GetPayslip",
+			"error when GetPayslipByRunID",
 			zap.String("method", "PayrollUseCaseImpl.GetPayslip"),
 			zap.Int64("user_id", userID),
 			zap.Int64("period_id", periodID),
+			zap.Int64("run_id", run.ID),
 			zap.Error(err),
 		)
 		return entity.PayrollPayslip{}, err
 	}
 
-	return payslip, nil
+	payslips, err := p.attachEmployeeProfiles([]entity.PayrollPayslip{payslip})
+	if err != nil {
+		return entity.PayrollPayslip{}, err
+	}
+
+	return payslips[0], nil
 }
 
 /*
-The summary contains take-home pay of each employee.
-The summary contains the total take-home pay of all employees.
+The summary contains take-home pay of each employee, their totals are in GetPayrollSummary.
+Like GetPayslip it is only available once the payslips are approved, or calculated for their reviewers.
 */
-func (p *PayrollUseCaseImpl) GetPayslips(periodID int64) ([]entity.PayrollPayslip, error) {
+func (p *PayrollUseCaseImpl) GetPayslips(userContext entity.UserContext, periodID int64) ([]entity.PayrollPayslip, error) {
 	payrollPeriod, err := p.payrollRepository.GetPeriodByID(periodID)
 	if err != nil {
 		log.Println(
@@ -82,161 +131,961 @@ func (p *PayrollUseCaseImpl) GetPayslips(periodID int64) ([]entity.PayrollPaysli
 		return []entity.PayrollPayslip{}, err
 	}
 
-	if payrollPeriod.Status == "open" {
-		return []entity.PayrollPayslip{}, errors.New("the payroll period is still open")
+	run, err := p.ensurePayslipsVisible(userContext, payrollPeriod, "PayrollUseCaseImpl.GetPayslips")
+	if err != nil {
+		return []entity.PayrollPayslip{}, err
 	}
 
-	payslips, err := p.payrollRepository.GetPayslips(periodID)
+	payslips, err := p.payrollRepository.GetPayslipsByRunID(run.ID)
 	if err != nil {
 		log.Println(
-			"error when GetPayslips",
+			"error when GetPayslipsByRunID",
 			zap.String("method", "PayrollUseCaseImpl.GetPayslips"),
 			zap.Int64("period_id", periodID),
+			zap.Int64("run_id", run.ID),
 			zap.Error(err),
 		)
 		return nil, err
 	}
 
-	var totalTakeHome float64
+	return p.attachEmployeeProfiles(payslips)
+}
+
+// ensurePayslipsVisible returns the run whose payslips the user may read, or why there is none yet.
+func (p *PayrollUseCaseImpl) ensurePayslipsVisible(userContext entity.UserContext, period entity.PayrollPeriod, method string) (entity.PayrollRun, error) {
+	run, visible, err := getVisiblePayrollRun(p.payrollRepository, method, userContext, period)
+	if err != nil {
+		return entity.PayrollRun{}, err
+	}
+	if visible {
+		return run, nil
+	}
+	if !period.Status.HasPayslips() {
+		return entity.PayrollRun{}, errors.New("the payslips of the payroll period are not calculated yet")
+	}
+	return entity.PayrollRun{}, errors.New("the payslips of the payroll period are not approved yet")
+}
+
+/*
+getVisiblePayrollRun returns the run whose payslips the user may read: the current run for the users who review
+payslips, the latest approved run for everybody else, so after a rerun employees keep seeing the approved version
+until the new one is approved. It reports false when the user may not read any payslip of the period yet.
+*/
+func getVisiblePayrollRun(payrollRepository PayrollRepository, method string, userContext entity.UserContext, period entity.PayrollPeriod) (entity.PayrollRun, bool, error) {
+	if !period.Status.HasPayslips() {
+		return entity.PayrollRun{}, false, nil
+	}
+
+	var run entity.PayrollRun
+	var err error
+	if userContext.ReviewsPayslips() {
+		run, err = payrollRepository.GetCurrentPayrollRun(period.ID)
+	} else {
+		run, err = payrollRepository.GetLatestApprovedPayrollRun(period.ID)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.PayrollRun{}, false, nil
+		}
+		log.Println(
+			"error when getVisiblePayrollRun",
+			zap.String("method", method),
+			zap.Any("user_contex", userContext),
+			zap.Int64("period_id", period.ID),
+			zap.Error(err),
+		)
+		return entity.PayrollRun{}, false, err
+	}
+
+	return run, true, nil
+}
+
+// attachEmployeeProfiles loads the profiles of all payslip owners in one query. Users without a profile keep a nil Employee.
+func (p *PayrollUseCaseImpl) attachEmployeeProfiles(payslips []entity.PayrollPayslip) ([]entity.PayrollPayslip, error) {
+	userIDs := make([]int64, 0, len(payslips))
 	for _, payslip := range payslips {
-		totalTakeHome += payslip.TotalTakeHome
+		userIDs = append(userIDs, payslip.UserID)
+	}
+
+	profiles, err := p.employeeProfileRepository.GetProfilesByUserIDs(userIDs)
+	if err != nil {
+		log.Println(
+			"error when GetProfilesByUserIDs",
+			zap.String("method", "PayrollUseCaseImpl.attachEmployeeProfiles"),
+			zap.Int64s("user_ids", userIDs),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	profileByUserID := make(map[int64]entity.EmployeeProfile, len(profiles))
+	for _, profile := range profiles {
+		profileByUserID[profile.UserID] = profile
+	}
+
+	for i := range payslips {
+		if profile, ok := profileByUserID[payslips[i].UserID]; ok {
+			payslips[i].Employee = &profile
+		}
 	}
 
 	return payslips, nil
 }
 
 /*
-Once payroll is run, attendance, overtime, and reimbursement records from that period cannot affect the payslip.
-Payroll for each attendance period can only be run once.
+The summary report adds up the payslips GetPayslips returns into the headcount and the total, average, minimum and
+maximum of each amount. groupBy splits it by the department or the role of the employees, empty reports all of them
+at once.
+*/
+func (p *PayrollUseCaseImpl) GetPayrollSummary(userContext entity.UserContext, periodID int64, groupBy string) (entity.PayrollSummary, error) {
+	if groupBy != "" && groupBy != entity.PayrollSummaryByDepartment && groupBy != entity.PayrollSummaryByRole {
+		return entity.PayrollSummary{}, ErrInvalidPayrollSummaryGroup
+	}
+
+	payrollPeriod, err := p.payrollRepository.GetPeriodByID(periodID)
+	if err != nil {
+		log.Println(
+			"error when GetPeriodByID",
+			zap.String("method", "PayrollUseCaseImpl.GetPayrollSummary"),
+			zap.Int64("period_id", periodID),
+			zap.Error(err),
+		)
+		return entity.PayrollSummary{}, err
+	}
+
+	run, err := p.ensurePayslipsVisible(userContext, payrollPeriod, "PayrollUseCaseImpl.GetPayrollSummary")
+	if err != nil {
+		return entity.PayrollSummary{}, err
+	}
+
+	payslips, err := p.payrollRepository.GetPayslipsByRunID(run.ID)
+	if err != nil {
+		log.Println(
+			"error when GetPayslipsByRunID",
+			zap.String("method", "PayrollUseCaseImpl.GetPayrollSummary"),
+			zap.Int64("period_id", periodID),
+			zap.Int64("run_id", run.ID),
+			zap.Error(err),
+		)
+		return entity.PayrollSummary{}, err
+	}
+
+	groupOf, err := p.getPayrollSummaryGroups(payslips, groupBy)
+	if err != nil {
+		return entity.PayrollSummary{}, err
+	}
+
+	return entity.NewPayrollSummary(run, payslips, groupBy, groupOf), nil
+}
+
+// ExportPayrollSummaryCSV is GetPayrollSummary as a CSV file, one row per group and a last row for all employees.
+func (p *PayrollUseCaseImpl) ExportPayrollSummaryCSV(userContext entity.UserContext, periodID int64, groupBy string) ([]byte, error) {
+	summary, err := p.GetPayrollSummary(userContext, periodID, groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	var content bytes.Buffer
+	writer := csv.NewWriter(&content)
+	if err := writer.WriteAll(summary.CSVRecords()); err != nil {
+		log.Println(
+			"error when WriteAll",
+			zap.String("method", "PayrollUseCaseImpl.ExportPayrollSummaryCSV"),
+			zap.Int64("period_id", periodID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return content.Bytes(), nil
+}
+
+// getPayrollSummaryGroups returns the group of each payslip, employees without a department or role are unassigned.
+func (p *PayrollUseCaseImpl) getPayrollSummaryGroups(payslips []entity.PayrollPayslip, groupBy string) (func(entity.PayrollPayslip) string, error) {
+	if groupBy == "" || len(payslips) == 0 {
+		return nil, nil
+	}
+
+	userIDs := make([]int64, 0, len(payslips))
+	for _, payslip := range payslips {
+		userIDs = append(userIDs, payslip.UserID)
+	}
+
+	groupByUserID := make(map[int64]string, len(payslips))
+	if groupBy == entity.PayrollSummaryByDepartment {
+		profiles, err := p.employeeProfileRepository.GetProfilesByUserIDs(userIDs)
+		if err != nil {
+			log.Println(
+				"error when GetProfilesByUserIDs",
+				zap.String("method", "PayrollUseCaseImpl.getPayrollSummaryGroups"),
+				zap.Int64s("user_ids", userIDs),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+
+		for _, profile := range profiles {
+			groupByUserID[profile.UserID] = profile.Department
+		}
+	} else {
+		users, err := p.userRepository.GetUsersByIDs(userIDs)
+		if err != nil {
+			log.Println(
+				"error when GetUsersByIDs",
+				zap.String("method", "PayrollUseCaseImpl.getPayrollSummaryGroups"),
+				zap.Int64s("user_ids", userIDs),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+
+		for _, user := range users {
+			groupByUserID[user.ID] = string(user.Role)
+		}
+	}
+
+	return func(payslip entity.PayrollPayslip) string {
+		if group := groupByUserID[payslip.UserID]; group != "" {
+			return group
+		}
+		return entity.PayrollSummaryUnassigned
+	}, nil
+}
+
+/*
+Closing locks the period: attendance, overtime, and reimbursement records from that period cannot be submitted
+any more and cannot affect the payslip. Payroll for each attendance period can only be run once, on a locked period.
 */
 func (p *PayrollUseCaseImpl) ClosePayrollPeriod(userContext entity.UserContext, periodID int64) error {
+	return p.transitionPayrollPeriod(userContext, periodID, entity.PayrollPeriodLock, "PayrollUseCaseImpl.ClosePayrollPeriod")
+}
+
+// Approving releases the calculated payslips to the employees, from then on they are final and cannot be reopened.
+func (p *PayrollUseCaseImpl) ApprovePayrollPeriod(userContext entity.UserContext, periodID int64) error {
+	return p.transitionPayrollPeriod(userContext, periodID, entity.PayrollPeriodApprove, "PayrollUseCaseImpl.ApprovePayrollPeriod")
+}
+
+// MarkPayrollPeriodPaid records that the approved take home pay has been transferred.
+func (p *PayrollUseCaseImpl) MarkPayrollPeriodPaid(userContext entity.UserContext, periodID int64) error {
+	return p.transitionPayrollPeriod(userContext, periodID, entity.PayrollPeriodPay, "PayrollUseCaseImpl.MarkPayrollPeriodPaid")
+}
+
+func (p *PayrollUseCaseImpl) ArchivePayrollPeriod(userContext entity.UserContext, periodID int64) error {
+	return p.transitionPayrollPeriod(userContext, periodID, entity.PayrollPeriodArchive, "PayrollUseCaseImpl.ArchivePayrollPeriod")
+}
+
+// transitionPayrollPeriod moves the period one step through its lifecycle when the guard of the transition allows it.
+func (p *PayrollUseCaseImpl) transitionPayrollPeriod(userContext entity.UserContext, periodID int64, transition entity.PayrollPeriodTransition, method string) error {
 	payrollPeriod, err := p.payrollRepository.GetPeriodByID(periodID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("payroll period not found")
+		}
 		log.Println(
 			"error when GetPeriodByID",
-			zap.String("method", "PayrollUseCaseImpl.GetPayslip"),
+			zap.String("method", method),
+			zap.Any("user_contex", userContext),
 			zap.Int64("period_id", periodID),
 			zap.Error(err),
 		)
 		return err
 	}
 
-	if payrollPeriod.Status == "closed" {
-		return errors.New("the payroll period is already closed")
+	if err := transition.Guard(payrollPeriod); err != nil {
+		return err
 	}
 
-	err = p.payrollRepository.ClosePayrollPeriod(periodID)
+	err = p.payrollRepository.UpdatePeriodStatus(periodID, transition.From, transition.To, userContext.Username)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return periodChangedError(periodID, transition)
+		}
 		log.Println(
-			"error when GetPayslip",
-			zap.String("method", "PayrollUseCaseImpl.ClosePayrollPeriod"),
+			"error when UpdatePeriodStatus",
+			zap.String("method", method),
+			zap.Any("user_contex", userContext),
 			zap.Int64("period_id", periodID),
 			zap.Error(err),
 		)
 		return err
 	}
 
+	p.auditTransition(userContext, payrollPeriod, transition)
+
+	return nil
+}
+
+func (p *PayrollUseCaseImpl) auditTransition(userContext entity.UserContext, period entity.PayrollPeriod, transition entity.PayrollPeriodTransition) {
 	p.auditLogRepository.Create(entity.AuditLog{
 		RequestID: userContext.RequestID,
 		IPAddress: userContext.IPAddress,
-		Action:    "update",
-		Target:    "reimbursement",
-		TableName: "payroll_period",
+		Action:    transition.Action,
+		Target:    "payroll_period",
+		TableName: "payroll_periods",
 		CreatedBy: userContext.Username,
-	}, payrollPeriod)
+	}, map[string]interface{}{
+		"period": period,
+		"from":   transition.From,
+		"to":     transition.To,
+	})
+}
 
-	return err
+// periodChangedError is returned when another request moved the period between reading and updating it.
+func periodChangedError(periodID int64, transition entity.PayrollPeriodTransition) error {
+	return fmt.Errorf("payroll period %d is no longer %s, it was changed by another request", periodID, transition.From)
 }
 
-func (p *PayrollUseCaseImpl) GeneratePayslipsByPeriodID(userContext entity.UserContext, periodID int64) error {
-	periodDetails, err := p.payrollRepository.GetPeriodByID(periodID)
+/*
+Reopening undoes a wrong close. Only the latest closed period can be reopened, later periods were calculated on
+top of its deduction balances and year to date tax, and only while it is locked or calculated and none of its runs
+was ever approved, approved payslips are final and can only be corrected by a rerun. Its payslips are kept but superseded, the deductions they recovered are restored and the period takes
+submissions again until it is closed and generated once more.
+A reason is required and recorded in the audit log with every superseded payslip and restored deduction.
+*/
+func (p *PayrollUseCaseImpl) ReopenPayrollPeriod(userContext entity.UserContext, periodID int64, request entity.ReopenPayrollPeriodRequest) error {
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		return errors.New("a reason is required to reopen a payroll period")
+	}
+
+	payrollPeriod, err := p.payrollRepository.GetPeriodByID(periodID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("payroll period not found")
+		}
 		log.Println(
 			"error when GetPeriodByID",
-			zap.String("method", "PayrollUseCaseImpl.ClosePayrollPeriod"),
+			zap.String("method", "PayrollUseCaseImpl.ReopenPayrollPeriod"),
+			zap.Any("user_contex", userContext),
 			zap.Int64("period_id", periodID),
 			zap.Error(err),
 		)
 		return err
 	}
 
-	if periodDetails.Status == "open" {
-		return errors.New("unable to process open period")
+	if !payrollPeriod.Status.IsClosed() || payrollPeriod.Status.IsReleased() {
+		return fmt.Errorf("only locked or calculated payroll periods can be reopened, payroll period %d is %s", periodID, payrollPeriod.Status)
 	}
 
-	employeeBaseSalaries, err := p.employeeRepository.GetEmployeeBaseSalaryByPeriodStart(periodDetails.PeriodStart, nil)
+	latestClosed, err := p.payrollRepository.GetLatestClosedPeriod()
 	if err != nil {
 		log.Println(
-			"error when GetEmployeeBaseSalaryByPeriodID",
-			zap.String("method", "PayrollUseCaseImpl.ClosePayrollPeriod"),
+			"error when GetLatestClosedPeriod",
+			zap.String("method", "PayrollUseCaseImpl.ReopenPayrollPeriod"),
+			zap.Any("user_contex", userContext),
 			zap.Int64("period_id", periodID),
 			zap.Error(err),
 		)
 		return err
 	}
 
-	attendanceRecordsMap, err := p.getEmployeeAttendanceByPeriodID(periodDetails)
+	if latestClosed.ID != periodID {
+		return fmt.Errorf(
+			"only the latest closed payroll period can be reopened, reopen payroll period %d (%s to %s) first",
+			latestClosed.ID,
+			latestClosed.PeriodStart.Format("2006-01-02"),
+			latestClosed.PeriodEnd.Format("2006-01-02"),
+		)
+	}
+
+	if payrollPeriod.Status == entity.PayrollStatusCalculated {
+		_, err := p.payrollRepository.GetLatestApprovedPayrollRun(periodID)
+		if err == nil {
+			return fmt.Errorf("payroll period %d has approved payslips, rerun its payroll instead of reopening it", periodID)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println(
+				"error when GetLatestApprovedPayrollRun",
+				zap.String("method", "PayrollUseCaseImpl.ReopenPayrollPeriod"),
+				zap.Any("user_contex", userContext),
+				zap.Int64("period_id", periodID),
+				zap.Error(err),
+			)
+			return err
+		}
+	}
+
+	payslipIDs, deductions, err := p.payrollRepository.ReopenPayrollPeriod(periodID, userContext.Username)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("payroll period %d is no longer %s, it was changed by another request", periodID, payrollPeriod.Status)
+		}
 		log.Println(
-			"error when GetAllAttendanceByPeriodID",
-			zap.String("method", "PayrollUseCaseImpl.ClosePayrollPeriod"),
+			"error when ReopenPayrollPeriod",
+			zap.String("method", "PayrollUseCaseImpl.ReopenPayrollPeriod"),
+			zap.Any("user_contex", userContext),
 			zap.Int64("period_id", periodID),
 			zap.Error(err),
 		)
 		return err
 	}
 
-	overtimeRecordsMap, err := p.getEmployeeOvertimeByPeriodID(periodDetails)
+	p.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "reopen",
+		Target:    "payroll_period",
+		TableName: "payroll_periods",
+		CreatedBy: userContext.Username,
+	}, map[string]interface{}{
+		"period":                 payrollPeriod,
+		"reason":                 reason,
+		"superseded_payslip_ids": payslipIDs,
+		"restored_deductions":    deductions,
+	})
+
+	return nil
+}
+
+// restoreDeductions returns the deductions recovered by the payslips with the balances they had before.
+func (p *PayrollUseCaseImpl) restoreDeductions(userContext entity.UserContext, payslips []entity.PayrollPayslip) ([]entity.EmployeeDeduction, error) {
+	lines := make(map[int64]entity.PayslipDeduction)
+	deductionIDs := []int64{}
+	for _, payslip := range payslips {
+		for _, line := range payslip.Deductions {
+			lines[line.DeductionID] = line
+			deductionIDs = append(deductionIDs, line.DeductionID)
+		}
+	}
+
+	if len(deductionIDs) == 0 {
+		return []entity.EmployeeDeduction{}, nil
+	}
+
+	deductions, err := p.employeeRepository.GetDeductionsByIDs(deductionIDs)
 	if err != nil {
 		log.Println(
-			"error when GetAllOvertimeByPeriodID",
+			"error when GetDeductionsByIDs",
+			zap.String("method", "PayrollUseCaseImpl.restoreDeductions"),
+			zap.Any("user_contex", userContext),
+			zap.Int64s("deduction_ids", deductionIDs),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	for i := range deductions {
+		deductions[i].Restore(lines[deductions[i].ID])
+		deductions[i].UpdatedBy = userContext.Username
+	}
+	return deductions, nil
+}
+
+/*
+Generating calculates the payslips of a locked period and stores them as a payroll run, with the deduction
+balances, the calculated status and the audit log, in one transaction. Payroll can only be run once per period:
+calling it again, also while the first call is still running, returns the existing run.
+*/
+func (p *PayrollUseCaseImpl) GeneratePayslipsByPeriodID(userContext entity.UserContext, periodID int64) (entity.PayrollRun, error) {
+	periodDetails, err := p.payrollRepository.GetPeriodByID(periodID)
+	if err != nil {
+		log.Println(
+			"error when GetPeriodByID",
 			zap.String("method", "PayrollUseCaseImpl.ClosePayrollPeriod"),
 			zap.Int64("period_id", periodID),
 			zap.Error(err),
 		)
-		return err
+		return entity.PayrollRun{}, err
 	}
 
-	reimbursementRecordsMap, err := p.getEmployeeReimbursementByPeriodID(periodDetails)
+	if periodDetails.Status.HasPayslips() {
+		run, err := p.payrollRepository.GetCurrentPayrollRun(periodID)
+		if err == nil {
+			return run, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println(
+				"error when GetCurrentPayrollRun",
+				zap.String("method", "PayrollUseCaseImpl.GeneratePayslipsByPeriodID"),
+				zap.Int64("period_id", periodID),
+				zap.Error(err),
+			)
+			return entity.PayrollRun{}, err
+		}
+	}
+
+	if err := entity.PayrollPeriodCalculate.Guard(periodDetails); err != nil {
+		return entity.PayrollRun{}, err
+	}
+
+	payslips, deductions, err := p.calculatePayslips(userContext, periodDetails, nil, "PayrollUseCaseImpl.GeneratePayslipsByPeriodID")
 	if err != nil {
+		return entity.PayrollRun{}, err
+	}
+
+	run, err := p.payrollRepository.CreatePayrollRun(entity.NewPayrollRun(periodDetails, payslips, userContext), payslips, deductions, entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    entity.PayrollPeriodCalculate.Action,
+		Target:    "payroll_run",
+		TableName: "payroll_runs",
+		CreatedBy: userContext.Username,
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.PayrollRun{}, periodChangedError(periodID, entity.PayrollPeriodCalculate)
+		}
+		if errors.Is(err, entity.ErrDeductionChanged) {
+			return entity.PayrollRun{}, err
+		}
 		log.Println(
-			"error when GetAllReimbursementByPeriodID",
-			zap.String("method", "PayrollUseCaseImpl.ClosePayrollPeriod"),
+			"error when CreatePayrollRun",
+			zap.String("method", "PayrollUseCaseImpl.GeneratePayslipsByPeriodID"),
 			zap.Int64("period_id", periodID),
 			zap.Error(err),
 		)
-		return err
+		return entity.PayrollRun{}, err
 	}
 
-	payslips := make([]entity.PayrollPayslip, 0, len(employeeBaseSalaries))
-	for _, employeeBaseSalary := range employeeBaseSalaries {
-		payslip := entity.PayrollPayslip{}
-		payslip.GeneratePayslip(periodDetails, employeeBaseSalary, attendanceRecordsMap[employeeBaseSalary.UserID], overtimeRecordsMap[employeeBaseSalary.UserID], reimbursementRecordsMap[employeeBaseSalary.UserID], userContext.Username)
+	return run, nil
+}
 
-		payslips = append(payslips, payslip)
+/*
+PreviewPayroll calculates the payslips of a period that is not calculated yet, also while it is still open, on its
+current data with the same calculation as generating them, and stores nothing. The warnings point at what is likely
+to be corrected before the period is closed: employees without a salary, without attendance or with more overtime
+than PayrollConfig.OvertimeWarningHours.
+*/
+func (p *PayrollUseCaseImpl) PreviewPayroll(userContext entity.UserContext, periodID int64) (entity.PayrollPreview, error) {
+	payrollPeriod, err := p.payrollRepository.GetPeriodByID(periodID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.PayrollPreview{}, errors.New("payroll period not found")
+		}
+		log.Println(
+			"error when GetPeriodByID",
+			zap.String("method", "PayrollUseCaseImpl.PreviewPayroll"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("period_id", periodID),
+			zap.Error(err),
+		)
+		return entity.PayrollPreview{}, err
 	}
 
-	err = p.payrollRepository.CreatePayslipsByPeriod(payslips)
+	if payrollPeriod.Status.HasPayslips() {
+		return entity.PayrollPreview{}, fmt.Errorf("payroll period %d is already %s, read its payslips instead", periodID, payrollPeriod.Status)
+	}
+
+	payslips, _, err := p.calculatePayslips(userContext, payrollPeriod, nil, "PayrollUseCaseImpl.PreviewPayroll")
+	if err != nil {
+		return entity.PayrollPreview{}, err
+	}
+
+	profiles, err := p.employeeProfileRepository.GetProfilesEmployedBetween(payrollPeriod.PeriodStart, payrollPeriod.PeriodEnd)
 	if err != nil {
 		log.Println(
-			"error when CreatePayslipsByPeriod",
-			zap.String("method", "PayrollUseCaseImpl.ClosePayrollPeriod"),
+			"error when GetProfilesEmployedBetween",
+			zap.String("method", "PayrollUseCaseImpl.PreviewPayroll"),
+			zap.Any("user_contex", userContext),
 			zap.Int64("period_id", periodID),
 			zap.Error(err),
 		)
-		return err
+		return entity.PayrollPreview{}, err
 	}
 
-	p.auditLogRepository.Create(entity.AuditLog{
+	profileByUserID := make(map[int64]entity.EmployeeProfile, len(profiles))
+	for _, profile := range profiles {
+		profileByUserID[profile.UserID] = profile
+	}
+
+	calculated := make(map[int64]bool, len(payslips))
+	for i := range payslips {
+		calculated[payslips[i].UserID] = true
+		if profile, ok := profileByUserID[payslips[i].UserID]; ok {
+			payslips[i].Employee = &profile
+		}
+	}
+
+	preview := entity.NewPayrollPreview(payrollPeriod, payslips)
+	for _, profile := range profiles {
+		if !calculated[profile.UserID] {
+			preview.Warn(profile.UserID, entity.PayrollWarningMissingSalary, "employed during the period but has no salary, no payslip is calculated")
+		}
+	}
+
+	for _, payslip := range payslips {
+		if payslip.AttendanceDays == 0 {
+			preview.Warn(payslip.UserID, entity.PayrollWarningNoAttendance, "has no attendance in the period")
+		}
+
+		overtimeHours := payslip.OvertimeHours + payslip.HolidayOvertimeHours
+		if p.payrollConfig.OvertimeWarningHours > 0 && overtimeHours > p.payrollConfig.OvertimeWarningHours {
+			preview.Warn(payslip.UserID, entity.PayrollWarningUnusualOvertime, fmt.Sprintf("has %d overtime hours in the period, more than %d", overtimeHours, p.payrollConfig.OvertimeWarningHours))
+		}
+	}
+
+	return preview, nil
+}
+
+/*
+Rerunning recalculates the payslips of a calculated or approved period after a correction. The new run is stored as
+the next version and supersedes the current run with its payslips, the deductions they recovered are restored first
+and recovered again by the new payslips. The period goes back to calculated and needs to be approved again,
+employees keep seeing the latest approved version until then. Like reopening, only the latest closed period can be
+rerun, and a reason is required and recorded on the run. A deduction changed while the payslips are calculated, for
+example cancelled, fails the rerun rather than being overwritten with the balance calculated before.
+*/
+func (p *PayrollUseCaseImpl) RerunPayroll(userContext entity.UserContext, periodID int64, request entity.RerunPayrollRequest) (entity.PayrollRun, error) {
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		return entity.PayrollRun{}, errors.New("a reason is required to rerun a payroll")
+	}
+
+	payrollPeriod, err := p.payrollRepository.GetPeriodByID(periodID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.PayrollRun{}, errors.New("payroll period not found")
+		}
+		log.Println(
+			"error when GetPeriodByID",
+			zap.String("method", "PayrollUseCaseImpl.RerunPayroll"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("period_id", periodID),
+			zap.Error(err),
+		)
+		return entity.PayrollRun{}, err
+	}
+
+	if payrollPeriod.Status != entity.PayrollStatusCalculated && payrollPeriod.Status != entity.PayrollStatusApproved {
+		return entity.PayrollRun{}, fmt.Errorf("only calculated or approved payroll periods can be rerun, payroll period %d is %s", periodID, payrollPeriod.Status)
+	}
+
+	latestClosed, err := p.payrollRepository.GetLatestClosedPeriod()
+	if err != nil {
+		log.Println(
+			"error when GetLatestClosedPeriod",
+			zap.String("method", "PayrollUseCaseImpl.RerunPayroll"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("period_id", periodID),
+			zap.Error(err),
+		)
+		return entity.PayrollRun{}, err
+	}
+
+	if latestClosed.ID != periodID {
+		return entity.PayrollRun{}, fmt.Errorf(
+			"only the latest closed payroll period can be rerun, payroll period %d (%s to %s) was closed after it",
+			latestClosed.ID,
+			latestClosed.PeriodStart.Format("2006-01-02"),
+			latestClosed.PeriodEnd.Format("2006-01-02"),
+		)
+	}
+
+	previousRun, err := p.payrollRepository.GetCurrentPayrollRun(periodID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.PayrollRun{}, fmt.Errorf("payroll period %d has no payroll run to rerun", periodID)
+		}
+		log.Println(
+			"error when GetCurrentPayrollRun",
+			zap.String("method", "PayrollUseCaseImpl.RerunPayroll"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("period_id", periodID),
+			zap.Error(err),
+		)
+		return entity.PayrollRun{}, err
+	}
+
+	previousPayslips, err := p.payrollRepository.GetPayslipsByRunID(previousRun.ID)
+	if err != nil {
+		log.Println(
+			"error when GetPayslipsByRunID",
+			zap.String("method", "PayrollUseCaseImpl.RerunPayroll"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("run_id", previousRun.ID),
+			zap.Error(err),
+		)
+		return entity.PayrollRun{}, err
+	}
+
+	restored, err := p.restoreDeductions(userContext, previousPayslips)
+	if err != nil {
+		return entity.PayrollRun{}, err
+	}
+
+	payslips, deductions, err := p.calculatePayslips(userContext, payrollPeriod, restored, "PayrollUseCaseImpl.RerunPayroll")
+	if err != nil {
+		return entity.PayrollRun{}, err
+	}
+
+	run := entity.NewPayrollRun(payrollPeriod, payslips, userContext)
+	run.Reason = reason
+
+	run, err = p.payrollRepository.RerunPayroll(previousRun.ID, payrollPeriod.Status, run, payslips, deductions, entity.AuditLog{
 		RequestID: userContext.RequestID,
 		IPAddress: userContext.IPAddress,
-		Action:    "create",
-		Target:    "payslips",
-		TableName: "payroll_peyslips",
+		Action:    "rerun",
+		Target:    "payroll_run",
+		TableName: "payroll_runs",
 		CreatedBy: userContext.Username,
-	}, payslips)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.PayrollRun{}, fmt.Errorf("payroll period %d was changed by another request, its payroll run %d is no longer current", periodID, previousRun.ID)
+		}
+		if errors.Is(err, entity.ErrDeductionChanged) {
+			return entity.PayrollRun{}, err
+		}
+		log.Println(
+			"error when RerunPayroll",
+			zap.String("method", "PayrollUseCaseImpl.RerunPayroll"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("period_id", periodID),
+			zap.Error(err),
+		)
+		return entity.PayrollRun{}, err
+	}
 
-	return nil
+	return run, nil
+}
+
+func (p *PayrollUseCaseImpl) ListPayrollRuns(userContext entity.UserContext, periodID int64) ([]entity.PayrollRun, error) {
+	runs, err := p.payrollRepository.ListPayrollRuns(periodID)
+	if err != nil {
+		log.Println(
+			"error when ListPayrollRuns",
+			zap.String("method", "PayrollUseCaseImpl.ListPayrollRuns"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("period_id", periodID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return runs, nil
+}
+
+/*
+DiffPayrollRuns lists the per-employee changes in each payslip amount from one run version of the period to another.
+Without versions the current run is compared with the version before it. Runs that are not approved can only be
+compared by the users who review payslips.
+*/
+func (p *PayrollUseCaseImpl) DiffPayrollRuns(userContext entity.UserContext, periodID int64, fromVersion int, toVersion int) (entity.PayrollRunDiff, error) {
+	toRun, err := p.getPayrollRunByVersion(periodID, toVersion)
+	if err != nil {
+		return entity.PayrollRunDiff{}, err
+	}
+
+	if fromVersion == 0 {
+		fromVersion = toRun.Version - 1
+		if fromVersion < 1 {
+			return entity.PayrollRunDiff{}, fmt.Errorf("payroll run version %d is the first of payroll period %d, there is no version to compare it with", toRun.Version, periodID)
+		}
+	}
+	if fromVersion == toRun.Version {
+		return entity.PayrollRunDiff{}, errors.New("two different payroll run versions are required")
+	}
+
+	fromRun, err := p.getPayrollRunByVersion(periodID, fromVersion)
+	if err != nil {
+		return entity.PayrollRunDiff{}, err
+	}
+
+	for _, run := range []entity.PayrollRun{fromRun, toRun} {
+		if !run.IsApproved() && !userContext.ReviewsPayslips() {
+			return entity.PayrollRunDiff{}, fmt.Errorf("payroll run version %d is not approved yet", run.Version)
+		}
+	}
+
+	fromPayslips, err := p.payrollRepository.GetPayslipsByRunID(fromRun.ID)
+	if err != nil {
+		log.Println(
+			"error when GetPayslipsByRunID",
+			zap.String("method", "PayrollUseCaseImpl.DiffPayrollRuns"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("run_id", fromRun.ID),
+			zap.Error(err),
+		)
+		return entity.PayrollRunDiff{}, err
+	}
+
+	toPayslips, err := p.payrollRepository.GetPayslipsByRunID(toRun.ID)
+	if err != nil {
+		log.Println(
+			"error when GetPayslipsByRunID",
+			zap.String("method", "PayrollUseCaseImpl.DiffPayrollRuns"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("run_id", toRun.ID),
+			zap.Error(err),
+		)
+		return entity.PayrollRunDiff{}, err
+	}
+
+	return entity.DiffPayrollRuns(fromRun, fromPayslips, toRun, toPayslips), nil
+}
+
+// getPayrollRunByVersion returns the run with the version, the current run of the period for version 0.
+func (p *PayrollUseCaseImpl) getPayrollRunByVersion(periodID int64, version int) (entity.PayrollRun, error) {
+	var run entity.PayrollRun
+	var err error
+	if version == 0 {
+		run, err = p.payrollRepository.GetCurrentPayrollRun(periodID)
+	} else {
+		run, err = p.payrollRepository.GetPayrollRunByVersion(periodID, version)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) && version == 0 {
+			return entity.PayrollRun{}, fmt.Errorf("payroll period %d has no current payroll run", periodID)
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.PayrollRun{}, fmt.Errorf("payroll run version %d of payroll period %d not found", version, periodID)
+		}
+		log.Println(
+			"error when getPayrollRunByVersion",
+			zap.String("method", "PayrollUseCaseImpl.getPayrollRunByVersion"),
+			zap.Int64("period_id", periodID),
+			zap.Int("version", version),
+			zap.Error(err),
+		)
+		return entity.PayrollRun{}, err
+	}
+	return run, nil
+}
+
+/*
+calculatePayslips calculates the payslips of every employee with a salary in the period and the deduction balances
+they leave. Restored are the deductions a superseded run recovered, with the balances they had before it, they
+replace the stored balances of the same deductions.
+*/
+func (p *PayrollUseCaseImpl) calculatePayslips(
+	userContext entity.UserContext,
+	periodDetails entity.PayrollPeriod,
+	restored []entity.EmployeeDeduction,
+	method string,
+) ([]entity.PayrollPayslip, []entity.EmployeeDeduction, error) {
+	employeeBaseSalaries, err := p.employeeRepository.GetEmployeeBaseSalaryByPeriod(periodDetails.PeriodStart, periodDetails.PeriodEnd, nil)
+	if err != nil {
+		log.Println(
+			"error when GetEmployeeBaseSalaryByPeriodID",
+			zap.String("method", method),
+			zap.Int64("period_id", periodDetails.ID),
+			zap.Error(err),
+		)
+		return nil, nil, err
+	}
+
+	attendanceRecordsMap, err := p.getEmployeeAttendanceByPeriodID(periodDetails)
+	if err != nil {
+		log.Println(
+			"error when GetAllAttendanceByPeriodID",
+			zap.String("method", method),
+			zap.Int64("period_id", periodDetails.ID),
+			zap.Error(err),
+		)
+		return nil, nil, err
+	}
+
+	overtimeRecordsMap, err := p.getEmployeeOvertimeByPeriodID(periodDetails)
+	if err != nil {
+		log.Println(
+			"error when GetAllOvertimeByPeriodID",
+			zap.String("method", method),
+			zap.Int64("period_id", periodDetails.ID),
+			zap.Error(err),
+		)
+		return nil, nil, err
+	}
+
+	reimbursementRecordsMap, err := p.getEmployeeReimbursementByPeriodID(periodDetails)
+	if err != nil {
+		log.Println(
+			"error when GetAllReimbursementByPeriodID",
+			zap.String("method", method),
+			zap.Int64("period_id", periodDetails.ID),
+			zap.Error(err),
+		)
+		return nil, nil, err
+	}
+
+	payComponentsMap, err := p.getEmployeePayComponentsByPeriodID(periodDetails)
+	if err != nil {
+		log.Println(
+			"error when GetAllPayComponentsByPeriodID",
+			zap.String("method", method),
+			zap.Int64("period_id", periodDetails.ID),
+			zap.Error(err),
+		)
+		return nil, nil, err
+	}
+
+	holidays, err := p.employeeRepository.GetAllHolidaysByTimeRange(periodDetails.PeriodStart, periodDetails.PeriodEnd)
+	if err != nil {
+		log.Println(
+			"error when GetAllHolidaysByTimeRange",
+			zap.String("method", method),
+			zap.Int64("period_id", periodDetails.ID),
+			zap.Error(err),
+		)
+		return nil, nil, err
+	}
+
+	deductionsMap, err := p.getEmployeeDeductionsByPeriodID(periodDetails, restored)
+	if err != nil {
+		log.Println(
+			"error when GetAllActiveDeductions",
+			zap.String("method", method),
+			zap.Int64("period_id", periodDetails.ID),
+			zap.Error(err),
+		)
+		return nil, nil, err
+	}
+
+	policy, err := getPolicyEffectiveOn(p.payrollRepository, method, periodDetails.PeriodStart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	statutoryRates, err := getStatutoryRatesEffectiveOn(p.payrollRepository, method, periodDetails.PeriodStart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	yearToDateMap := map[int64]entity.IncomeTaxYearToDate{}
+	for _, employeeBaseSalary := range employeeBaseSalaries {
+		if statutoryRates.IncomeTax != nil && entity.IsFinalTaxPeriod(periodDetails, employeeBaseSalary) {
+			yearToDateMap, err = getIncomeTaxYearToDate(p.payrollRepository, method, periodDetails, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+			break
+		}
+	}
+
+	payslips := make([]entity.PayrollPayslip, 0, len(employeeBaseSalaries))
+	deductions := []entity.EmployeeDeduction{}
+	recovered := map[int64]bool{}
+	for _, employeeBaseSalary := range employeeBaseSalaries {
+		payslip := entity.PayrollPayslip{}
+		payslip.GeneratePayslip(periodDetails, holidays, policy, p.payrollConfig.Rounding, employeeBaseSalary, attendanceRecordsMap[employeeBaseSalary.UserID], overtimeRecordsMap[employeeBaseSalary.UserID], reimbursementRecordsMap[employeeBaseSalary.UserID], payComponentsMap[employeeBaseSalary.UserID], userContext.Username)
+
+		err = payslip.ApplyStatutory(statutoryRates, p.payrollConfig.Rounding, employeeBaseSalary.TaxStatus, entity.IsFinalTaxPeriod(periodDetails, employeeBaseSalary), yearToDateMap[employeeBaseSalary.UserID])
+		if err != nil {
+			log.Println(
+				"error when ApplyStatutory",
+				zap.String("method", method),
+				zap.Int64("period_id", periodDetails.ID),
+				zap.Int64("user_id", employeeBaseSalary.UserID),
+				zap.Error(err),
+			)
+			return nil, nil, err
+		}
+
+		for _, deduction := range payslip.ApplyDeductions(periodDetails, deductionsMap[employeeBaseSalary.UserID], p.payrollConfig.NetPayFloor, p.payrollConfig.Rounding) {
+			deduction.UpdatedBy = userContext.Username
+			deductions = append(deductions, deduction)
+			recovered[deduction.ID] = true
+		}
+
+		payslips = append(payslips, payslip)
+	}
+
+	// restored deductions the payslips did not recover again keep the balances they had before the previous run.
+	for _, deduction := range restored {
+		if !recovered[deduction.ID] {
+			deductions = append(deductions, deduction)
+		}
+	}
+
+	return payslips, deductions, nil
 }
 
 func (p *PayrollUseCaseImpl) getEmployeeAttendanceByPeriodID(periodDetails entity.PayrollPeriod) (map[int64][]entity.EmployeeAttendance, error) {
@@ -290,3 +1139,47 @@ func (p *PayrollUseCaseImpl) getEmployeeReimbursementByPeriodID(periodDetails en
 
 	return reimbursementRecordsMap, nil
 }
+
+func (p *PayrollUseCaseImpl) getEmployeePayComponentsByPeriodID(periodDetails entity.PayrollPeriod) (map[int64][]entity.EmployeePayComponent, error) {
+	payComponents, err := p.employeeRepository.GetAllPayComponentsByTimeRange(periodDetails.PeriodStart, periodDetails.PeriodEnd, nil)
+	if err != nil {
+		return map[int64][]entity.EmployeePayComponent{}, err
+	}
+
+	payComponentsMap := make(map[int64][]entity.EmployeePayComponent)
+	for _, payComponent := range payComponents {
+		payComponentsMap[payComponent.UserID] = append(payComponentsMap[payComponent.UserID], payComponent)
+	}
+
+	return payComponentsMap, nil
+}
+
+func (p *PayrollUseCaseImpl) getEmployeeDeductionsByPeriodID(periodDetails entity.PayrollPeriod, restored []entity.EmployeeDeduction) (map[int64][]entity.EmployeeDeduction, error) {
+	deductions, err := p.employeeRepository.GetAllActiveDeductions(periodDetails.PeriodEnd, nil)
+	if err != nil {
+		return map[int64][]entity.EmployeeDeduction{}, err
+	}
+
+	restoredByID := make(map[int64]entity.EmployeeDeduction, len(restored))
+	for _, deduction := range restored {
+		restoredByID[deduction.ID] = deduction
+	}
+
+	deductionsMap := make(map[int64][]entity.EmployeeDeduction)
+	for _, deduction := range deductions {
+		if restoredDeduction, ok := restoredByID[deduction.ID]; ok {
+			deduction = restoredDeduction
+			delete(restoredByID, deduction.ID)
+		}
+		deductionsMap[deduction.UserID] = append(deductionsMap[deduction.UserID], deduction)
+	}
+
+	// deductions settled by the superseded run are active again once restored.
+	for _, deduction := range restored {
+		if _, ok := restoredByID[deduction.ID]; ok && deduction.Status == entity.DeductionStatusActive {
+			deductionsMap[deduction.UserID] = append(deductionsMap[deduction.UserID], deduction)
+		}
+	}
+
+	return deductionsMap, nil
+}
@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name PayrollPeriodUseCase --output ./mocks
+type PayrollPeriodUseCase interface {
+	ListPeriods(status string) ([]entity.PayrollPeriod, error)
+
+	CreatePeriod(userContext entity.UserContext, request entity.PayrollPeriodRequest) (entity.PayrollPeriod, error)
+	GeneratePeriods(userContext entity.UserContext, request entity.GeneratePayrollPeriodsRequest) ([]entity.PayrollPeriod, error)
+	UpdatePeriod(userContext entity.UserContext, periodID int64, request entity.PayrollPeriodRequest) (entity.PayrollPeriod, error)
+}
+
+type PayrollPeriodUseCaseImpl struct {
+	payrollRepository  PayrollRepository
+	holidayRepository  HolidayRepository
+	auditLogRepository AuditLogRepository
+}
+
+func NewPayrollPeriodUseCase(
+	payrollRepository PayrollRepository,
+	holidayRepository HolidayRepository,
+	auditLogRepository AuditLogRepository,
+) *PayrollPeriodUseCaseImpl {
+	return &PayrollPeriodUseCaseImpl{
+		payrollRepository:  payrollRepository,
+		holidayRepository:  holidayRepository,
+		auditLogRepository: auditLogRepository,
+	}
+}
+
+func (p *PayrollPeriodUseCaseImpl) ListPeriods(status string) ([]entity.PayrollPeriod, error) {
+	if status != "" && !entity.PayrollPeriodStatus(status).IsValid() {
+		return nil, errors.New("status must be open, locked, calculated, approved, paid or archived")
+	}
+
+	periods, err := p.payrollRepository.ListPeriods(status)
+	if err != nil {
+		log.Println(
+			"error when ListPeriods",
+			zap.String("method", "PayrollPeriodUseCaseImpl.ListPeriods"),
+			zap.String("status", status),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return periods, nil
+}
+
+func (p *PayrollPeriodUseCaseImpl) CreatePeriod(userContext entity.UserContext, request entity.PayrollPeriodRequest) (entity.PayrollPeriod, error) {
+	period, err := p.buildPeriod(userContext, request)
+	if err != nil {
+		return entity.PayrollPeriod{}, err
+	}
+
+	periods, err := p.createPeriods(userContext, "PayrollPeriodUseCaseImpl.CreatePeriod", []entity.PayrollPeriod{period})
+	if err != nil {
+		return entity.PayrollPeriod{}, err
+	}
+	return periods[0], nil
+}
+
+/*
+GeneratePeriods creates the twelve periods of a year that end on the cut-off day of each month, their working days
+are the weekdays that are not holidays. Nothing is created when any of them overlaps an existing period.
+*/
+func (p *PayrollPeriodUseCaseImpl) GeneratePeriods(userContext entity.UserContext, request entity.GeneratePayrollPeriodsRequest) ([]entity.PayrollPeriod, error) {
+	if request.Year < 2000 || request.Year > 9999 {
+		return nil, errors.New("year must be between 2000 and 9999")
+	}
+
+	if request.CutOffDay < 1 || request.CutOffDay > 31 {
+		return nil, errors.New("cut-off day must be between 1 and 31")
+	}
+
+	holidays, err := p.getHolidays(userContext, "PayrollPeriodUseCaseImpl.GeneratePeriods",
+		time.Date(request.Year-1, time.December, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(request.Year, time.December, 31, 0, 0, 0, 0, time.UTC),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	periods := entity.PayrollPeriodsForYear(request.Year, request.CutOffDay, holidays, userContext.Username)
+	return p.createPeriods(userContext, "PayrollPeriodUseCaseImpl.GeneratePeriods", periods)
+}
+
+/*
+Only open periods can be edited, closed ones are calculated or about to be. The new dates cannot overlap another period.
+*/
+func (p *PayrollPeriodUseCaseImpl) UpdatePeriod(userContext entity.UserContext, periodID int64, request entity.PayrollPeriodRequest) (entity.PayrollPeriod, error) {
+	current, err := p.payrollRepository.GetPeriodByID(periodID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.PayrollPeriod{}, errors.New("payroll period not found")
+		}
+		log.Println(
+			"error when GetPeriodByID",
+			zap.String("method", "PayrollPeriodUseCaseImpl.UpdatePeriod"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("period_id", periodID),
+			zap.Error(err),
+		)
+		return entity.PayrollPeriod{}, err
+	}
+
+	if current.Status != entity.PayrollStatusOpen {
+		return entity.PayrollPeriod{}, errors.New("only open payroll periods can be edited")
+	}
+
+	period, err := p.buildPeriod(userContext, request)
+	if err != nil {
+		return entity.PayrollPeriod{}, err
+	}
+
+	err = p.ensureNoOverlap(userContext, "PayrollPeriodUseCaseImpl.UpdatePeriod", period.PeriodStart, period.PeriodEnd, periodID)
+	if err != nil {
+		return entity.PayrollPeriod{}, err
+	}
+
+	updates := map[string]interface{}{
+		"period_start": period.PeriodStart,
+		"period_end":   period.PeriodEnd,
+		"working_days": period.WorkingDays,
+		"updated_by":   userContext.Username,
+	}
+
+	err = p.payrollRepository.UpdatePeriod(periodID, entity.PayrollStatusOpen, updates)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.PayrollPeriod{}, fmt.Errorf("payroll period %d is no longer %s, it was changed by another request", periodID, entity.PayrollStatusOpen)
+	}
+	if err != nil {
+		log.Println(
+			"error when UpdatePeriod",
+			zap.String("method", "PayrollPeriodUseCaseImpl.UpdatePeriod"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("period_id", periodID),
+			zap.Any("updates", updates),
+			zap.Error(err),
+		)
+		return entity.PayrollPeriod{}, err
+	}
+
+	p.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "update",
+		Target:    "payroll_period",
+		TableName: "payroll_periods",
+		CreatedBy: userContext.Username,
+	}, updates)
+
+	current.PeriodStart = period.PeriodStart
+	current.PeriodEnd = period.PeriodEnd
+	current.WorkingDays = period.WorkingDays
+	current.UpdatedBy = userContext.Username
+	return current, nil
+}
+
+func (p *PayrollPeriodUseCaseImpl) buildPeriod(userContext entity.UserContext, request entity.PayrollPeriodRequest) (entity.PayrollPeriod, error) {
+	periodStart, err := time.Parse("2006-01-02", request.PeriodStart)
+	if err != nil {
+		return entity.PayrollPeriod{}, errors.New("invalid period start format, must be YYYY-MM-DD")
+	}
+
+	periodEnd, err := time.Parse("2006-01-02", request.PeriodEnd)
+	if err != nil {
+		return entity.PayrollPeriod{}, errors.New("invalid period end format, must be YYYY-MM-DD")
+	}
+
+	if periodEnd.Before(periodStart) {
+		return entity.PayrollPeriod{}, errors.New("period end cannot be before the period start")
+	}
+
+	days := int(periodEnd.Sub(periodStart).Hours()/24) + 1
+	if request.WorkingDays < 0 || request.WorkingDays > days {
+		return entity.PayrollPeriod{}, fmt.Errorf("working days must be between 0 (weekdays of the period that are not holidays) and %d", days)
+	}
+
+	var holidays []entity.Holiday
+	if request.WorkingDays == 0 {
+		holidays, err = p.getHolidays(userContext, "PayrollPeriodUseCaseImpl.buildPeriod", periodStart, periodEnd)
+		if err != nil {
+			return entity.PayrollPeriod{}, err
+		}
+	}
+
+	period := entity.NewPayrollPeriod(periodStart, periodEnd, request.WorkingDays, holidays, userContext.Username)
+	if period.WorkingDays == 0 {
+		return entity.PayrollPeriod{}, errors.New("the period has no working days")
+	}
+	return period, nil
+}
+
+func (p *PayrollPeriodUseCaseImpl) getHolidays(userContext entity.UserContext, method string, from time.Time, to time.Time) ([]entity.Holiday, error) {
+	holidays, err := p.holidayRepository.GetHolidaysByTimeRange(from, to)
+	if err != nil {
+		log.Println(
+			"error when GetHolidaysByTimeRange",
+			zap.String("method", method),
+			zap.Any("user_contex", userContext),
+			zap.Time("from", from),
+			zap.Time("to", to),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return holidays, nil
+}
+
+// createPeriods stores the periods, which must be in date order and not overlap each other, when none of them overlaps an existing period.
+func (p *PayrollPeriodUseCaseImpl) createPeriods(userContext entity.UserContext, method string, periods []entity.PayrollPeriod) ([]entity.PayrollPeriod, error) {
+	err := p.ensureNoOverlap(userContext, method, periods[0].PeriodStart, periods[len(periods)-1].PeriodEnd, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err = p.payrollRepository.CreatePeriods(periods)
+	if err != nil {
+		log.Println(
+			"error when CreatePeriods",
+			zap.String("method", method),
+			zap.Any("user_contex", userContext),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	p.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "create",
+		Target:    "payroll_period",
+		TableName: "payroll_periods",
+		CreatedBy: userContext.Username,
+	}, periods)
+
+	return periods, nil
+}
+
+func (p *PayrollPeriodUseCaseImpl) ensureNoOverlap(userContext entity.UserContext, method string, periodStart time.Time, periodEnd time.Time, excludeID int64) error {
+	overlapping, err := p.payrollRepository.GetOverlappingPeriods(periodStart, periodEnd, excludeID)
+	if err != nil {
+		log.Println(
+			"error when GetOverlappingPeriods",
+			zap.String("method", method),
+			zap.Any("user_contex", userContext),
+			zap.Time("period_start", periodStart),
+			zap.Time("period_end", periodEnd),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if len(overlapping) > 0 {
+		return fmt.Errorf(
+			"the period overlaps payroll period %d (%s to %s)",
+			overlapping[0].ID,
+			overlapping[0].PeriodStart.Format("2006-01-02"),
+			overlapping[0].PeriodEnd.Format("2006-01-02"),
+		)
+	}
+	return nil
+}
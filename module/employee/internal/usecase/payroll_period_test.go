@@ -0,0 +1,279 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func Test_PayrollPeriodUseCase_GeneratePeriods(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  entity.GeneratePayrollPeriodsRequest
+		mockFunc func(
+			payrollRepository *mocks.PayrollRepository,
+			holidayRepository *mocks.HolidayRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+		check   func(t *testing.T, periods []entity.PayrollPeriod)
+	}{
+		{
+			name:    "error - invalid cut-off day",
+			request: entity.GeneratePayrollPeriodsRequest{Year: 2025, CutOffDay: 0},
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				holidayRepository *mocks.HolidayRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("cut-off day must be between 1 and 31"),
+		},
+		{
+			name:    "error - overlaps an existing period",
+			request: entity.GeneratePayrollPeriodsRequest{Year: 2025, CutOffDay: 9},
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				holidayRepository *mocks.HolidayRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				holidayRepository.On("GetHolidaysByTimeRange", time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)).
+					Return([]entity.Holiday{}, nil)
+				payrollRepository.On("GetOverlappingPeriods", time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 9, 0, 0, 0, 0, time.UTC), int64(0)).
+					Return([]entity.PayrollPeriod{{
+						ID:          15,
+						PeriodStart: time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC),
+					}}, nil)
+			},
+			wantErr: errors.New("the period overlaps payroll period 15 (2024-12-10 to 2025-01-09)"),
+		},
+		{
+			name:    "success - cut-off day past the end of short months",
+			request: entity.GeneratePayrollPeriodsRequest{Year: 2025, CutOffDay: 30},
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				holidayRepository *mocks.HolidayRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				holidayRepository.On("GetHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{
+						{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Name: "New Year's Day"},
+						{Date: time.Date(2025, 1, 27, 0, 0, 0, 0, time.UTC), Name: "Isra Mi'raj"},
+						{Date: time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC), Name: "Chinese New Year"},
+						{Date: time.Date(2025, 3, 29, 0, 0, 0, 0, time.UTC), Name: "Nyepi"},
+					}, nil)
+				payrollRepository.On("GetOverlappingPeriods", mock.Anything, mock.Anything, int64(0)).
+					Return([]entity.PayrollPeriod{}, nil)
+				payrollRepository.On("CreatePeriods", mock.Anything).
+					Return(func(periods []entity.PayrollPeriod) ([]entity.PayrollPeriod, error) {
+						return periods, nil
+					})
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			check: func(t *testing.T, periods []entity.PayrollPeriod) {
+				assert.Len(t, periods, 12)
+				assert.Equal(t, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), periods[0].PeriodStart)
+				assert.Equal(t, time.Date(2025, 1, 30, 0, 0, 0, 0, time.UTC), periods[0].PeriodEnd)
+				assert.Equal(t, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), periods[1].PeriodStart)
+				assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), periods[1].PeriodEnd)
+				assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), periods[2].PeriodStart)
+				assert.Equal(t, 20, periods[0].WorkingDays)
+				assert.Equal(t, 21, periods[1].WorkingDays)
+				assert.Equal(t, 20, periods[2].WorkingDays)
+				assert.Equal(t, entity.PayrollStatusOpen, periods[11].Status)
+				assert.Equal(t, "finance", periods[11].CreatedBy)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payrollRepository := mocks.NewPayrollRepository(t)
+			holidayRepository := mocks.NewHolidayRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			tt.mockFunc(payrollRepository, holidayRepository, auditLogRepository)
+
+			usecase := usecase.NewPayrollPeriodUseCase(payrollRepository, holidayRepository, auditLogRepository)
+			res, err := usecase.GeneratePeriods(entity.UserContext{Username: "finance"}, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				tt.check(t, res)
+			}
+		})
+	}
+}
+
+func Test_PayrollPeriodUseCase_UpdatePeriod(t *testing.T) {
+	request := entity.PayrollPeriodRequest{PeriodStart: "2025-07-01", PeriodEnd: "2025-07-31"}
+
+	tests := []struct {
+		name     string
+		request  entity.PayrollPeriodRequest
+		mockFunc func(
+			payrollRepository *mocks.PayrollRepository,
+			holidayRepository *mocks.HolidayRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+		wantRes entity.PayrollPeriod
+	}{
+		{
+			name:    "error - period not found",
+			request: request,
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				holidayRepository *mocks.HolidayRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(7)).Return(entity.PayrollPeriod{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("payroll period not found"),
+		},
+		{
+			name:    "error - period is closed",
+			request: request,
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				holidayRepository *mocks.HolidayRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(7)).Return(entity.PayrollPeriod{ID: 7, Status: entity.PayrollStatusLocked}, nil)
+			},
+			wantErr: errors.New("only open payroll periods can be edited"),
+		},
+		{
+			name:    "error - end before start",
+			request: entity.PayrollPeriodRequest{PeriodStart: "2025-07-31", PeriodEnd: "2025-07-01"},
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				holidayRepository *mocks.HolidayRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(7)).Return(entity.PayrollPeriod{ID: 7, Status: entity.PayrollStatusOpen}, nil)
+			},
+			wantErr: errors.New("period end cannot be before the period start"),
+		},
+		{
+			name:    "error - more working days than days",
+			request: entity.PayrollPeriodRequest{PeriodStart: "2025-07-01", PeriodEnd: "2025-07-10", WorkingDays: 11},
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				holidayRepository *mocks.HolidayRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(7)).Return(entity.PayrollPeriod{ID: 7, Status: entity.PayrollStatusOpen}, nil)
+			},
+			wantErr: errors.New("working days must be between 0 (weekdays of the period that are not holidays) and 10"),
+		},
+		{
+			name:    "error - overlaps another period",
+			request: request,
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				holidayRepository *mocks.HolidayRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(7)).Return(entity.PayrollPeriod{ID: 7, Status: entity.PayrollStatusOpen}, nil)
+				holidayRepository.On("GetHolidaysByTimeRange", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)).
+					Return([]entity.Holiday{}, nil)
+				payrollRepository.On("GetOverlappingPeriods", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC), int64(7)).
+					Return([]entity.PayrollPeriod{{
+						ID:          8,
+						PeriodStart: time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2025, 8, 30, 0, 0, 0, 0, time.UTC),
+					}}, nil)
+			},
+			wantErr: errors.New("the period overlaps payroll period 8 (2025-07-31 to 2025-08-30)"),
+		},
+		{
+			name:    "error - UpdatePeriod",
+			request: request,
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				holidayRepository *mocks.HolidayRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(7)).Return(entity.PayrollPeriod{ID: 7, Status: entity.PayrollStatusOpen}, nil)
+				holidayRepository.On("GetHolidaysByTimeRange", mock.Anything, mock.Anything).Return([]entity.Holiday{}, nil)
+				payrollRepository.On("GetOverlappingPeriods", mock.Anything, mock.Anything, int64(7)).Return([]entity.PayrollPeriod{}, nil)
+				payrollRepository.On("UpdatePeriod", int64(7), entity.PayrollStatusOpen, mock.Anything).Return(gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name:    "error - period locked by another request meanwhile",
+			request: request,
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				holidayRepository *mocks.HolidayRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(7)).Return(entity.PayrollPeriod{ID: 7, Status: entity.PayrollStatusOpen}, nil)
+				holidayRepository.On("GetHolidaysByTimeRange", mock.Anything, mock.Anything).Return([]entity.Holiday{}, nil)
+				payrollRepository.On("GetOverlappingPeriods", mock.Anything, mock.Anything, int64(7)).Return([]entity.PayrollPeriod{}, nil)
+				payrollRepository.On("UpdatePeriod", int64(7), entity.PayrollStatusOpen, mock.Anything).Return(gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("payroll period 7 is no longer open, it was changed by another request"),
+		},
+		{
+			name:    "success - working days default to the weekdays that are not holidays",
+			request: request,
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				holidayRepository *mocks.HolidayRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(7)).Return(entity.PayrollPeriod{ID: 7, Status: entity.PayrollStatusOpen, CreatedBy: "system"}, nil)
+				holidayRepository.On("GetHolidaysByTimeRange", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)).
+					Return([]entity.Holiday{{Date: time.Date(2025, 7, 17, 0, 0, 0, 0, time.UTC), Name: "Company anniversary"}}, nil)
+				payrollRepository.On("GetOverlappingPeriods", mock.Anything, mock.Anything, int64(7)).Return([]entity.PayrollPeriod{}, nil)
+				payrollRepository.On("UpdatePeriod", int64(7), entity.PayrollStatusOpen, map[string]interface{}{
+					"period_start": time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+					"period_end":   time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC),
+					"working_days": 22,
+					"updated_by":   "finance",
+				}).Return(nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantRes: entity.PayrollPeriod{
+				ID:          7,
+				PeriodStart: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+				PeriodEnd:   time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC),
+				WorkingDays: 22,
+				Status:      entity.PayrollStatusOpen,
+				UpdatedBy:   "finance",
+				CreatedBy:   "system",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payrollRepository := mocks.NewPayrollRepository(t)
+			holidayRepository := mocks.NewHolidayRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			tt.mockFunc(payrollRepository, holidayRepository, auditLogRepository)
+
+			usecase := usecase.NewPayrollPeriodUseCase(payrollRepository, holidayRepository, auditLogRepository)
+			res, err := usecase.UpdatePeriod(entity.UserContext{Username: "finance"}, 7, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.Equal(t, tt.wantRes, res)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
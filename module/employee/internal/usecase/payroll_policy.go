@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name PayrollPolicyUseCase --output ./mocks
+type PayrollPolicyUseCase interface {
+	GetPolicy(policyID int64) (entity.PayrollPolicy, error)
+	ListPolicies() ([]entity.PayrollPolicy, error)
+	GetStatutoryRates(date string) (entity.StatutoryRates, error)
+
+	CreatePolicy(userContext entity.UserContext, request entity.PayrollPolicyRequest) (entity.PayrollPolicy, error)
+}
+
+type PayrollPolicyUseCaseImpl struct {
+	payrollRepository  PayrollRepository
+	auditLogRepository AuditLogRepository
+}
+
+func NewPayrollPolicyUseCase(
+	payrollRepository PayrollRepository,
+	auditLogRepository AuditLogRepository,
+) *PayrollPolicyUseCaseImpl {
+	return &PayrollPolicyUseCaseImpl{
+		payrollRepository:  payrollRepository,
+		auditLogRepository: auditLogRepository,
+	}
+}
+
+func (p *PayrollPolicyUseCaseImpl) GetPolicy(policyID int64) (entity.PayrollPolicy, error) {
+	policy, err := p.payrollRepository.GetPolicyByID(policyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.PayrollPolicy{}, errors.New("payroll policy not found")
+		}
+		log.Println(
+			"error when GetPolicyByID",
+			zap.String("method", "PayrollPolicyUseCaseImpl.GetPolicy"),
+			zap.Int64("policy_id", policyID),
+			zap.Error(err),
+		)
+		return entity.PayrollPolicy{}, err
+	}
+	return policy, nil
+}
+
+func (p *PayrollPolicyUseCaseImpl) ListPolicies() ([]entity.PayrollPolicy, error) {
+	policies, err := p.payrollRepository.ListPolicies()
+	if err != nil {
+		log.Println(
+			"error when ListPolicies",
+			zap.String("method", "PayrollPolicyUseCaseImpl.ListPolicies"),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return policies, nil
+}
+
+// GetStatutoryRates returns the contribution and PPh 21 tables in effect on the date, today when it is empty.
+func (p *PayrollPolicyUseCaseImpl) GetStatutoryRates(date string) (entity.StatutoryRates, error) {
+	effectiveOn := time.Now()
+	if date != "" {
+		parsedDate, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return entity.StatutoryRates{}, errors.New("invalid date format, must be YYYY-MM-DD")
+		}
+		effectiveOn = parsedDate
+	}
+
+	return getStatutoryRatesEffectiveOn(p.payrollRepository, "PayrollPolicyUseCaseImpl.GetStatutoryRates", effectiveOn)
+}
+
+/*
+Editing a policy means creating the next version. The new version cannot take effect on or before the end of
+the latest closed period, so payslips that were already generated keep matching the version they reference.
+*/
+func (p *PayrollPolicyUseCaseImpl) CreatePolicy(userContext entity.UserContext, request entity.PayrollPolicyRequest) (entity.PayrollPolicy, error) {
+	policy, err := p.buildPolicy(request)
+	if err != nil {
+		return entity.PayrollPolicy{}, err
+	}
+
+	closedPeriod, err := p.payrollRepository.GetLatestClosedPeriod()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println(
+			"error when GetLatestClosedPeriod",
+			zap.String("method", "PayrollPolicyUseCaseImpl.CreatePolicy"),
+			zap.Any("user_contex", userContext),
+			zap.Error(err),
+		)
+		return entity.PayrollPolicy{}, err
+	}
+	if err == nil && !policy.EffectiveFrom.After(closedPeriod.PeriodEnd) {
+		return entity.PayrollPolicy{}, fmt.Errorf(
+			"effective date must be after %s, the end of the latest closed payroll period",
+			closedPeriod.PeriodEnd.Format("2006-01-02"),
+		)
+	}
+
+	latest, err := p.payrollRepository.GetLatestPolicy()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println(
+			"error when GetLatestPolicy",
+			zap.String("method", "PayrollPolicyUseCaseImpl.CreatePolicy"),
+			zap.Any("user_contex", userContext),
+			zap.Error(err),
+		)
+		return entity.PayrollPolicy{}, err
+	}
+
+	policy.Version = latest.Version + 1
+	policy.CreatedBy = userContext.Username
+
+	policy, err = p.payrollRepository.CreatePolicy(policy)
+	if err != nil {
+		log.Println(
+			"error when CreatePolicy",
+			zap.String("method", "PayrollPolicyUseCaseImpl.CreatePolicy"),
+			zap.Any("user_contex", userContext),
+			zap.Any("request", request),
+			zap.Error(err),
+		)
+		return entity.PayrollPolicy{}, err
+	}
+
+	p.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "create",
+		Target:    "payroll_policy",
+		TableName: "payroll_policies",
+		CreatedBy: userContext.Username,
+	}, policy)
+
+	return policy, nil
+}
+
+func (p *PayrollPolicyUseCaseImpl) buildPolicy(request entity.PayrollPolicyRequest) (entity.PayrollPolicy, error) {
+	effectiveFrom, err := time.Parse("2006-01-02", request.EffectiveFrom)
+	if err != nil {
+		return entity.PayrollPolicy{}, errors.New("invalid effective date format, must be YYYY-MM-DD")
+	}
+
+	if request.HoursPerDay < 1 || request.HoursPerDay > 24 {
+		return entity.PayrollPolicy{}, errors.New("hours per day must be between 1 and 24")
+	}
+
+	if !request.AttendanceHoursRounding.IsValid() {
+		return entity.PayrollPolicy{}, errors.New("attendance hours rounding must be one of down, nearest or up")
+	}
+
+	if request.MaxAttendanceHoursPerDay < 0 || request.MaxAttendanceHoursPerDay > 24 {
+		return entity.PayrollPolicy{}, errors.New("max attendance hours per day must be between 0 (no cap) and 24")
+	}
+
+	if request.OvertimeMultiplier.LessThan(decimal.NewFromInt(1)) {
+		return entity.PayrollPolicy{}, errors.New("overtime multiplier must be at least 1")
+	}
+
+	if !request.HolidayOvertimeMultiplier.IsZero() && request.HolidayOvertimeMultiplier.LessThan(decimal.NewFromInt(1)) {
+		return entity.PayrollPolicy{}, errors.New("holiday overtime multiplier must be 0 (same as overtime) or at least 1")
+	}
+
+	if request.OvertimeMinHours < 1 || request.OvertimeMaxHours < request.OvertimeMinHours || request.OvertimeMaxHours > 24 {
+		return entity.PayrollPolicy{}, errors.New("overtime hours must satisfy 1 <= min <= max <= 24")
+	}
+
+	return entity.PayrollPolicy{
+		EffectiveFrom:             effectiveFrom,
+		HoursPerDay:               request.HoursPerDay,
+		AttendanceHoursRounding:   request.AttendanceHoursRounding,
+		MaxAttendanceHoursPerDay:  request.MaxAttendanceHoursPerDay,
+		OvertimeMultiplier:        request.OvertimeMultiplier,
+		HolidayOvertimeMultiplier: request.HolidayOvertimeMultiplier,
+		OvertimeMinHours:          request.OvertimeMinHours,
+		OvertimeMaxHours:          request.OvertimeMaxHours,
+		Description:               strings.TrimSpace(request.Description),
+	}, nil
+}
+
+// getPolicyEffectiveOn loads the policy version in effect on the date. Payslips use the one in effect on the first day of the period.
+func getPolicyEffectiveOn(payrollRepository PayrollRepository, method string, date time.Time) (entity.PayrollPolicy, error) {
+	policy, err := payrollRepository.GetPolicyEffectiveOn(date)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.PayrollPolicy{}, fmt.Errorf("no payroll policy is in effect on %s", date.Format("2006-01-02"))
+		}
+		log.Println(
+			"error when GetPolicyEffectiveOn",
+			zap.String("method", method),
+			zap.Time("date", date),
+			zap.Error(err),
+		)
+		return entity.PayrollPolicy{}, err
+	}
+	return policy, nil
+}
@@ -0,0 +1,163 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// defaultPayrollPolicy matches the seeded first policy version.
+var defaultPayrollPolicy = entity.PayrollPolicy{
+	ID:                      1,
+	Version:                 1,
+	HoursPerDay:             8,
+	AttendanceHoursRounding: entity.HoursRoundingDown,
+	OvertimeMultiplier:      decimal.NewFromInt(2),
+	OvertimeMinHours:        1,
+	OvertimeMaxHours:        3,
+}
+
+func Test_PayrollPolicyUseCase_CreatePolicy(t *testing.T) {
+	validRequest := entity.PayrollPolicyRequest{
+		EffectiveFrom:            "2025-07-01",
+		HoursPerDay:              7,
+		AttendanceHoursRounding:  entity.HoursRoundingNearest,
+		MaxAttendanceHoursPerDay: 9,
+		OvertimeMultiplier:       decimal.RequireFromString("1.5"),
+		OvertimeMinHours:         1,
+		OvertimeMaxHours:         4,
+		Description:              " Shorter days ",
+	}
+	closedPeriod := entity.PayrollPeriod{
+		ID:        6,
+		PeriodEnd: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		Status:    entity.PayrollStatusLocked,
+	}
+
+	tests := []struct {
+		name     string
+		request  entity.PayrollPolicyRequest
+		mockFunc func(
+			payrollRepository *mocks.PayrollRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+		wantRes entity.PayrollPolicy
+	}{
+		{
+			name: "error - invalid rounding",
+			request: entity.PayrollPolicyRequest{
+				EffectiveFrom:           "2025-07-01",
+				HoursPerDay:             8,
+				AttendanceHoursRounding: "half",
+			},
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("attendance hours rounding must be one of down, nearest or up"),
+		},
+		{
+			name: "error - overtime limits",
+			request: entity.PayrollPolicyRequest{
+				EffectiveFrom:           "2025-07-01",
+				HoursPerDay:             8,
+				AttendanceHoursRounding: entity.HoursRoundingDown,
+				OvertimeMultiplier:      decimal.NewFromInt(2),
+				OvertimeMinHours:        3,
+				OvertimeMaxHours:        1,
+			},
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("overtime hours must satisfy 1 <= min <= max <= 24"),
+		},
+		{
+			name: "error - takes effect inside a closed period",
+			request: func() entity.PayrollPolicyRequest {
+				request := validRequest
+				request.EffectiveFrom = "2025-06-15"
+				return request
+			}(),
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetLatestClosedPeriod").Return(closedPeriod, nil)
+			},
+			wantErr: errors.New("effective date must be after 2025-06-30, the end of the latest closed payroll period"),
+		},
+		{
+			name:    "error - CreatePolicy",
+			request: validRequest,
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetLatestClosedPeriod").Return(closedPeriod, nil)
+				payrollRepository.On("GetLatestPolicy").Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("CreatePolicy", mock.Anything).Return(entity.PayrollPolicy{}, gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name:    "success - next version",
+			request: validRequest,
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetLatestClosedPeriod").Return(closedPeriod, nil)
+				payrollRepository.On("GetLatestPolicy").Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("CreatePolicy", mock.Anything).
+					Return(func(policy entity.PayrollPolicy) (entity.PayrollPolicy, error) {
+						policy.ID = 2
+						return policy, nil
+					})
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantRes: entity.PayrollPolicy{
+				ID:                       2,
+				Version:                  2,
+				EffectiveFrom:            time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+				HoursPerDay:              7,
+				AttendanceHoursRounding:  entity.HoursRoundingNearest,
+				MaxAttendanceHoursPerDay: 9,
+				OvertimeMultiplier:       decimal.RequireFromString("1.5"),
+				OvertimeMinHours:         1,
+				OvertimeMaxHours:         4,
+				Description:              "Shorter days",
+				CreatedBy:                "admin",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payrollRepository := mocks.NewPayrollRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			tt.mockFunc(payrollRepository, auditLogRepository)
+
+			usecase := usecase.NewPayrollPolicyUseCase(payrollRepository, auditLogRepository)
+			res, err := usecase.CreatePolicy(entity.UserContext{Username: "admin"}, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.Equal(t, tt.wantRes, res)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -3,22 +3,28 @@ package usecase_test
 import (
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/eafajri/hr-service.git/internal/money"
 	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
 	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
 	"github.com/eafajri/hr-service.git/module/employee/internal/usecase/mocks"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 )
 
 func Test_PayrollUseCase_GetPayslip(t *testing.T) {
+	approvedAt := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name     string
 		mockFunc func(
 			employeeRepository *mocks.EmployeeRepository,
 			payrollRepository *mocks.PayrollRepository,
 			auditLogRepository *mocks.AuditLogRepository,
+			employeeProfileRepository *mocks.EmployeeProfileRepository,
 		)
 		wantErr error
 		wantRes entity.PayrollPayslip
@@ -29,6 +35,7 @@ func Test_PayrollUseCase_GetPayslip(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
 					Return(entity.PayrollPeriod{}, gorm.ErrSubQueryRequired)
@@ -41,41 +48,101 @@ func Test_PayrollUseCase_GetPayslip(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
 					Return(entity.PayrollPeriod{Status: "open"}, nil)
 			},
-			wantErr: errors.New("the payroll period is still open"),
+			wantErr: errors.New("the payslips of the payroll period are not calculated yet"),
+		},
+		{
+			name: "error - payslips are not approved yet",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "calculated"}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("the payslips of the payroll period are not approved yet"),
+		},
+		{
+			name: "error - GetLatestApprovedPayrollRun",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "approved"}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{}, gorm.ErrSubQueryRequired)
+			},
+			wantErr: gorm.ErrSubQueryRequired,
 		},
 		{
-			name: "error - GetPayslip",
+			name: "error - GetPayslipByRunID",
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{Status: "closed"}, nil)
-				payrollRepository.On("GetPayslip", mock.Anything, mock.Anything).
+					Return(entity.PayrollPeriod{Status: "approved"}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{ID: 3, Version: 1, ApprovedAt: &approvedAt}, nil)
+				payrollRepository.On("GetPayslipByRunID", int64(3), mock.Anything).
 					Return(entity.PayrollPayslip{}, gorm.ErrSubQueryRequired)
 			},
 			wantErr: gorm.ErrSubQueryRequired,
 		},
+		{
+			name: "success - employees keep seeing the approved version after a rerun",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "calculated"}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{ID: 3, Version: 1, ApprovedAt: &approvedAt}, nil)
+				payrollRepository.On("GetPayslipByRunID", int64(3), mock.Anything).
+					Return(entity.PayrollPayslip{UserID: 1, TotalTakeHome: decimal.NewFromInt(20000)}, nil)
+				employeeProfileRepository.On("GetProfilesByUserIDs", []int64{1}).
+					Return([]entity.EmployeeProfile{}, nil)
+			},
+			wantRes: entity.PayrollPayslip{UserID: 1, TotalTakeHome: decimal.NewFromInt(20000)},
+		},
 		{
 			name: "Success",
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{Status: "closed"}, nil)
-				payrollRepository.On("GetPayslip", mock.Anything, mock.Anything).
-					Return(entity.PayrollPayslip{BaseSalary: 21000, TotalTakeHome: 21000}, nil)
+					Return(entity.PayrollPeriod{Status: "approved"}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{ID: 3, Version: 1, ApprovedAt: &approvedAt}, nil)
+				payrollRepository.On("GetPayslipByRunID", int64(3), mock.Anything).
+					Return(entity.PayrollPayslip{UserID: 1, BaseSalary: decimal.NewFromInt(21000), TotalTakeHome: decimal.NewFromInt(21000)}, nil)
+				employeeProfileRepository.On("GetProfilesByUserIDs", []int64{1}).
+					Return([]entity.EmployeeProfile{{UserID: 1, FullName: "Employee One"}}, nil)
 			},
 			wantRes: entity.PayrollPayslip{
-				BaseSalary:    21000,
-				TotalTakeHome: 21000,
+				UserID:        1,
+				BaseSalary:    decimal.NewFromInt(21000),
+				TotalTakeHome: decimal.NewFromInt(21000),
+				Employee:      &entity.EmployeeProfile{UserID: 1, FullName: "Employee One"},
 			},
 		},
 	}
@@ -85,11 +152,12 @@ func Test_PayrollUseCase_GetPayslip(t *testing.T) {
 			employeeRepository := mocks.NewEmployeeRepository(t)
 			payrollRepository := mocks.NewPayrollRepository(t)
 			auditLogRepository := mocks.NewAuditLogRepository(t)
+			employeeProfileRepository := mocks.NewEmployeeProfileRepository(t)
 
-			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository)
+			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository, employeeProfileRepository)
 
-			usecase := usecase.NewPayrollUseCase(payrollRepository, employeeRepository, auditLogRepository)
-			res, err := usecase.GetPayslip(0, 0)
+			usecase := usecase.NewPayrollUseCase(usecase.PayrollConfig{}, payrollRepository, employeeRepository, employeeProfileRepository, mocks.NewUserRepository(t), auditLogRepository)
+			res, err := usecase.GetPayslip(entity.UserContext{Role: entity.RoleEmployee}, 0, 0)
 			if tt.wantErr != nil {
 				assert.Equal(t, tt.wantErr.Error(), err.Error())
 			} else {
@@ -101,12 +169,16 @@ func Test_PayrollUseCase_GetPayslip(t *testing.T) {
 }
 
 func Test_PayrollUseCase_GetPayslips(t *testing.T) {
+	approvedAt := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
-		name     string
-		mockFunc func(
+		name        string
+		userContext entity.UserContext
+		mockFunc    func(
 			employeeRepository *mocks.EmployeeRepository,
 			payrollRepository *mocks.PayrollRepository,
 			auditLogRepository *mocks.AuditLogRepository,
+			employeeProfileRepository *mocks.EmployeeProfileRepository,
 		)
 		wantErr error
 		wantRes []entity.PayrollPayslip
@@ -117,6 +189,7 @@ func Test_PayrollUseCase_GetPayslips(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
 					Return(entity.PayrollPeriod{}, gorm.ErrSubQueryRequired)
@@ -129,42 +202,78 @@ func Test_PayrollUseCase_GetPayslips(t *testing.T) {
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
 					Return(entity.PayrollPeriod{Status: "open"}, nil)
 			},
-			wantErr: errors.New("the payroll period is still open"),
+			wantErr: errors.New("the payslips of the payroll period are not calculated yet"),
+		},
+		{
+			name:        "error - calculated payslips are hidden from auditors",
+			userContext: entity.UserContext{Role: entity.RoleAuditor, Permissions: []entity.Permission{entity.PermissionPayslipReadAll}},
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "calculated"}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("the payslips of the payroll period are not approved yet"),
 		},
 		{
-			name: "error - GetPayslips",
+			name: "error - GetPayslipsByRunID",
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{Status: "closed"}, nil)
-				payrollRepository.On("GetPayslips", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "approved"}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{ID: 3, Version: 1, ApprovedAt: &approvedAt}, nil)
+				payrollRepository.On("GetPayslipsByRunID", int64(3)).
 					Return([]entity.PayrollPayslip{}, gorm.ErrSubQueryRequired)
 			},
 			wantErr: gorm.ErrSubQueryRequired,
 		},
 		{
-			name: "Success",
+			name:        "success - calculated payslips are visible to their approvers",
+			userContext: entity.UserContext{Role: entity.RoleFinance, Permissions: []entity.Permission{entity.PermissionPayrollApprove}},
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{Status: "closed"}, nil)
-				payrollRepository.On("GetPayslips", mock.Anything).
-					Return([]entity.PayrollPayslip{{BaseSalary: 21000, TotalTakeHome: 21000}}, nil)
+					Return(entity.PayrollPeriod{Status: "calculated"}, nil)
+				payrollRepository.On("GetCurrentPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{ID: 4, Version: 2}, nil)
+				payrollRepository.On("GetPayslipsByRunID", int64(4)).
+					Return([]entity.PayrollPayslip{
+						{UserID: 1, BaseSalary: decimal.NewFromInt(21000), TotalTakeHome: decimal.NewFromInt(21000)},
+						{UserID: 2, BaseSalary: decimal.NewFromInt(15000), TotalTakeHome: decimal.NewFromInt(15000)},
+					}, nil)
+				employeeProfileRepository.On("GetProfilesByUserIDs", []int64{1, 2}).
+					Return([]entity.EmployeeProfile{{UserID: 1, FullName: "Employee One"}}, nil)
 			},
 			wantRes: []entity.PayrollPayslip{
 				{
-					BaseSalary:    21000,
-					TotalTakeHome: 21000,
+					UserID:        1,
+					BaseSalary:    decimal.NewFromInt(21000),
+					TotalTakeHome: decimal.NewFromInt(21000),
+					Employee:      &entity.EmployeeProfile{UserID: 1, FullName: "Employee One"},
+				},
+				{
+					UserID:        2,
+					BaseSalary:    decimal.NewFromInt(15000),
+					TotalTakeHome: decimal.NewFromInt(15000),
 				},
 			},
 		},
@@ -175,11 +284,12 @@ func Test_PayrollUseCase_GetPayslips(t *testing.T) {
 			employeeRepository := mocks.NewEmployeeRepository(t)
 			payrollRepository := mocks.NewPayrollRepository(t)
 			auditLogRepository := mocks.NewAuditLogRepository(t)
+			employeeProfileRepository := mocks.NewEmployeeProfileRepository(t)
 
-			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository)
+			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository, employeeProfileRepository)
 
-			usecase := usecase.NewPayrollUseCase(payrollRepository, employeeRepository, auditLogRepository)
-			res, err := usecase.GetPayslips(0)
+			usecase := usecase.NewPayrollUseCase(usecase.PayrollConfig{}, payrollRepository, employeeRepository, employeeProfileRepository, mocks.NewUserRepository(t), auditLogRepository)
+			res, err := usecase.GetPayslips(tt.userContext, 0)
 			if tt.wantErr != nil {
 				assert.Equal(t, tt.wantErr.Error(), err.Error())
 			} else {
@@ -213,19 +323,19 @@ func Test_PayrollUseCase_ClosePayrollPeriod(t *testing.T) {
 			wantErr: gorm.ErrSubQueryRequired,
 		},
 		{
-			name: "error - period is already closed",
+			name: "error - period is already locked",
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{Status: "closed"}, nil)
+					Return(entity.PayrollPeriod{Status: "locked"}, nil)
 			},
-			wantErr: errors.New("the payroll period is already closed"),
+			wantErr: errors.New("only open payroll periods can be locked, payroll period 0 is locked"),
 		},
 		{
-			name: "error - ClosePayrollPeriod",
+			name: "error - UpdatePeriodStatus",
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
@@ -233,11 +343,25 @@ func Test_PayrollUseCase_ClosePayrollPeriod(t *testing.T) {
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
 					Return(entity.PayrollPeriod{Status: "open"}, nil)
-				payrollRepository.On("ClosePayrollPeriod", mock.Anything, mock.Anything).
+				payrollRepository.On("UpdatePeriodStatus", int64(0), entity.PayrollStatusOpen, entity.PayrollStatusLocked, "finance").
 					Return(gorm.ErrSubQueryRequired)
 			},
 			wantErr: gorm.ErrSubQueryRequired,
 		},
+		{
+			name: "error - period was locked by another request",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "open"}, nil)
+				payrollRepository.On("UpdatePeriodStatus", int64(0), entity.PayrollStatusOpen, entity.PayrollStatusLocked, "finance").
+					Return(gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("payroll period 0 is no longer open, it was changed by another request"),
+		},
 		{
 			name: "success",
 			mockFunc: func(
@@ -247,9 +371,11 @@ func Test_PayrollUseCase_ClosePayrollPeriod(t *testing.T) {
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
 					Return(entity.PayrollPeriod{Status: "open"}, nil)
-				payrollRepository.On("ClosePayrollPeriod", mock.Anything, mock.Anything).
+				payrollRepository.On("UpdatePeriodStatus", int64(0), entity.PayrollStatusOpen, entity.PayrollStatusLocked, "finance").
 					Return(nil)
-				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+				auditLogRepository.On("Create", mock.MatchedBy(func(auditLog entity.AuditLog) bool {
+					return auditLog.Action == "lock" && auditLog.TableName == "payroll_periods"
+				}), mock.Anything).Return(nil)
 			},
 			wantErr: nil,
 		},
@@ -260,11 +386,12 @@ func Test_PayrollUseCase_ClosePayrollPeriod(t *testing.T) {
 			employeeRepository := mocks.NewEmployeeRepository(t)
 			payrollRepository := mocks.NewPayrollRepository(t)
 			auditLogRepository := mocks.NewAuditLogRepository(t)
+			employeeProfileRepository := mocks.NewEmployeeProfileRepository(t)
 
 			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository)
 
-			usecase := usecase.NewPayrollUseCase(payrollRepository, employeeRepository, auditLogRepository)
-			err := usecase.ClosePayrollPeriod(entity.UserContext{}, 0)
+			usecase := usecase.NewPayrollUseCase(usecase.PayrollConfig{}, payrollRepository, employeeRepository, employeeProfileRepository, mocks.NewUserRepository(t), auditLogRepository)
+			err := usecase.ClosePayrollPeriod(entity.UserContext{Username: "finance"}, 0)
 			if tt.wantErr != nil {
 				assert.Equal(t, tt.wantErr.Error(), err.Error())
 			} else {
@@ -274,15 +401,265 @@ func Test_PayrollUseCase_ClosePayrollPeriod(t *testing.T) {
 	}
 }
 
-func Test_PayrollUseCase_GeneratePayslipsByPeriodID(t *testing.T) {
+func Test_PayrollUseCase_ApprovePayrollPeriod(t *testing.T) {
+	tests := []struct {
+		name     string
+		mockFunc func(
+			payrollRepository *mocks.PayrollRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+	}{
+		{
+			name: "error - period is not found",
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).Return(entity.PayrollPeriod{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("payroll period not found"),
+		},
+		{
+			name: "error - payslips are not calculated",
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).
+					Return(entity.PayrollPeriod{ID: 5, Status: entity.PayrollStatusLocked}, nil)
+			},
+			wantErr: errors.New("only calculated payroll periods can be approved, payroll period 5 is locked"),
+		},
+		{
+			name: "success",
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).
+					Return(entity.PayrollPeriod{ID: 5, Status: entity.PayrollStatusCalculated}, nil)
+				payrollRepository.On("UpdatePeriodStatus", int64(5), entity.PayrollStatusCalculated, entity.PayrollStatusApproved, "finance").
+					Return(nil)
+				auditLogRepository.On("Create", mock.MatchedBy(func(auditLog entity.AuditLog) bool {
+					return auditLog.Action == "approve" && auditLog.CreatedBy == "finance"
+				}), mock.MatchedBy(func(payload map[string]interface{}) bool {
+					return payload["from"] == entity.PayrollStatusCalculated && payload["to"] == entity.PayrollStatusApproved
+				})).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payrollRepository := mocks.NewPayrollRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			tt.mockFunc(payrollRepository, auditLogRepository)
+
+			usecase := usecase.NewPayrollUseCase(usecase.PayrollConfig{}, payrollRepository, mocks.NewEmployeeRepository(t), mocks.NewEmployeeProfileRepository(t), mocks.NewUserRepository(t), auditLogRepository)
+			err := usecase.ApprovePayrollPeriod(entity.UserContext{Username: "finance"}, 5)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_PayrollUseCase_ReopenPayrollPeriod(t *testing.T) {
+	request := entity.ReopenPayrollPeriodRequest{Reason: "Closed before the overtime of the last week was submitted"}
+	closedPeriod := entity.PayrollPeriod{
+		ID:          5,
+		Status:      entity.PayrollStatusLocked,
+		PeriodStart: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC),
+	}
+
 	tests := []struct {
 		name     string
+		request  entity.ReopenPayrollPeriodRequest
 		mockFunc func(
 			employeeRepository *mocks.EmployeeRepository,
 			payrollRepository *mocks.PayrollRepository,
 			auditLogRepository *mocks.AuditLogRepository,
 		)
 		wantErr error
+	}{
+		{
+			name:    "error - reason is required",
+			request: entity.ReopenPayrollPeriodRequest{Reason: "  "},
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("a reason is required to reopen a payroll period"),
+		},
+		{
+			name:    "error - period is open",
+			request: request,
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).
+					Return(entity.PayrollPeriod{ID: 5, Status: entity.PayrollStatusOpen}, nil)
+			},
+			wantErr: errors.New("only locked or calculated payroll periods can be reopened, payroll period 5 is open"),
+		},
+		{
+			name:    "error - payslips are already approved",
+			request: request,
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).
+					Return(entity.PayrollPeriod{ID: 5, Status: entity.PayrollStatusApproved}, nil)
+			},
+			wantErr: errors.New("only locked or calculated payroll periods can be reopened, payroll period 5 is approved"),
+		},
+		{
+			name:    "error - a later period is calculated",
+			request: request,
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).Return(closedPeriod, nil)
+				payrollRepository.On("GetLatestClosedPeriod").
+					Return(entity.PayrollPeriod{
+						ID:          6,
+						Status:      entity.PayrollStatusCalculated,
+						PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+					}, nil)
+			},
+			wantErr: errors.New("only the latest closed payroll period can be reopened, reopen payroll period 6 (2025-06-01 to 2025-06-30) first"),
+		},
+		{
+			name:    "error - a run of the period was approved before a rerun",
+			request: request,
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				rerunPeriod := closedPeriod
+				rerunPeriod.Status = entity.PayrollStatusCalculated
+				payrollRepository.On("GetPeriodByID", int64(5)).Return(rerunPeriod, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(rerunPeriod, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", int64(5)).Return(entity.PayrollRun{ID: 7, Version: 1}, nil)
+			},
+			wantErr: errors.New("payroll period 5 has approved payslips, rerun its payroll instead of reopening it"),
+		},
+		{
+			name:    "error - ReopenPayrollPeriod",
+			request: request,
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).Return(closedPeriod, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(closedPeriod, nil)
+				payrollRepository.On("ReopenPayrollPeriod", int64(5), "finance").
+					Return(nil, nil, gorm.ErrInvalidTransaction)
+			},
+			wantErr: gorm.ErrInvalidTransaction,
+		},
+		{
+			name:    "success - payslips are superseded and deductions restored",
+			request: request,
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).Return(closedPeriod, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(closedPeriod, nil)
+				restored := []entity.EmployeeDeduction{
+					{ID: 7, Type: entity.DeductionLoan, Balance: decimal.NewFromInt(2000), Status: entity.DeductionStatusActive, UpdatedBy: "finance"},
+				}
+				payrollRepository.On("ReopenPayrollPeriod", int64(5), "finance").Return([]int64{31, 32}, restored, nil)
+				auditLogRepository.On("Create", mock.MatchedBy(func(auditLog entity.AuditLog) bool {
+					return auditLog.Action == "reopen" && auditLog.TableName == "payroll_periods"
+				}), mock.MatchedBy(func(payload map[string]interface{}) bool {
+					return payload["reason"] == request.Reason &&
+						assert.ObjectsAreEqual([]int64{31, 32}, payload["superseded_payslip_ids"]) &&
+						assert.ObjectsAreEqual(restored, payload["restored_deductions"])
+				})).Return(nil)
+			},
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employeeRepository := mocks.NewEmployeeRepository(t)
+			payrollRepository := mocks.NewPayrollRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+			employeeProfileRepository := mocks.NewEmployeeProfileRepository(t)
+
+			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository)
+
+			usecase := usecase.NewPayrollUseCase(usecase.PayrollConfig{}, payrollRepository, employeeRepository, employeeProfileRepository, mocks.NewUserRepository(t), auditLogRepository)
+			err := usecase.ReopenPayrollPeriod(entity.UserContext{Username: "finance"}, 5, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_PayrollUseCase_GeneratePayslipsByPeriodID(t *testing.T) {
+	statutoryRates := entity.StatutoryRates{
+		Contributions: []entity.StatutoryContributionRate{
+			{Code: "BPJS_KES", EmployeeRate: decimal.RequireFromString("0.01"), EmployerRate: decimal.RequireFromString("0.04"), WageCap: decimal.NewFromInt(12000000), TaxableBenefit: true},
+			{Code: "JHT", EmployeeRate: decimal.RequireFromString("0.02"), EmployerRate: decimal.RequireFromString("0.037"), TaxDeductible: true},
+			{Code: "JP", EmployeeRate: decimal.RequireFromString("0.01"), EmployerRate: decimal.RequireFromString("0.02"), WageCap: decimal.NewFromInt(10042300), TaxDeductible: true},
+			{Code: "JKK", EmployerRate: decimal.RequireFromString("0.0024"), TaxableBenefit: true},
+			{Code: "JKM", EmployerRate: decimal.RequireFromString("0.003"), TaxableBenefit: true},
+		},
+		PTKP: []entity.PTKPAllowance{
+			{TaxStatus: "TK/0", AnnualAmount: decimal.NewFromInt(54000000), TERCategory: "A"},
+			{TaxStatus: "K/1", AnnualAmount: decimal.NewFromInt(63000000), TERCategory: "B"},
+		},
+		TERRates: []entity.TERRate{
+			{TERCategory: "A", MinIncome: decimal.Zero, Rate: decimal.Zero},
+			{TERCategory: "A", MinIncome: decimal.NewFromInt(10050001), Rate: decimal.RequireFromString("0.02")},
+			{TERCategory: "A", MinIncome: decimal.NewFromInt(10350001), Rate: decimal.RequireFromString("0.0225")},
+			{TERCategory: "A", MinIncome: decimal.NewFromInt(10700001), Rate: decimal.RequireFromString("0.025")},
+		},
+		ProgressiveRates: []entity.ProgressiveTaxRate{
+			{MinIncome: decimal.Zero, Rate: decimal.RequireFromString("0.05")},
+			{MinIncome: decimal.NewFromInt(60000000), Rate: decimal.RequireFromString("0.15")},
+		},
+		IncomeTax: &entity.IncomeTaxParameters{
+			OccupationalCostRate:       decimal.RequireFromString("0.05"),
+			OccupationalCostMonthlyCap: decimal.NewFromInt(500000),
+		},
+	}
+
+	runID := int64(7)
+
+	tests := []struct {
+		name     string
+		mockFunc func(
+			employeeRepository *mocks.EmployeeRepository,
+			payrollRepository *mocks.PayrollRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		payrollConfig usecase.PayrollConfig
+		wantErr       error
 	}{
 		{
 			name: "error - GetPeriodByID",
@@ -306,7 +683,48 @@ func Test_PayrollUseCase_GeneratePayslipsByPeriodID(t *testing.T) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
 					Return(entity.PayrollPeriod{Status: "open"}, nil)
 			},
-			wantErr: errors.New("unable to process open period"),
+			wantErr: errors.New("only locked payroll periods can be calculated, payroll period 0 is open"),
+		},
+		{
+			name: "success - a calculated period returns its current run",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "approved"}, nil)
+				payrollRepository.On("GetCurrentPayrollRun", int64(0)).
+					Return(entity.PayrollRun{ID: runID}, nil)
+			},
+		},
+		{
+			name: "error - GetCurrentPayrollRun",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "calculated"}, nil)
+				payrollRepository.On("GetCurrentPayrollRun", int64(0)).
+					Return(entity.PayrollRun{}, gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name: "error - payslips calculated without a run",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "calculated"}, nil)
+				payrollRepository.On("GetCurrentPayrollRun", int64(0)).
+					Return(entity.PayrollRun{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("only locked payroll periods can be calculated, payroll period 0 is calculated"),
 		},
 		{
 			name: "error - ClosePayrollPeriod",
@@ -316,8 +734,8 @@ func Test_PayrollUseCase_GeneratePayslipsByPeriodID(t *testing.T) {
 				auditLogRepository *mocks.AuditLogRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{Status: "closed"}, nil)
-				employeeRepository.On("GetEmployeeBaseSalaryByPeriodStart", mock.Anything, mock.Anything).
+					Return(entity.PayrollPeriod{Status: "locked"}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeBaseSalary{}, gorm.ErrSubQueryRequired)
 			},
 			wantErr: gorm.ErrSubQueryRequired,
@@ -330,8 +748,8 @@ func Test_PayrollUseCase_GeneratePayslipsByPeriodID(t *testing.T) {
 				auditLogRepository *mocks.AuditLogRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{Status: "closed"}, nil)
-				employeeRepository.On("GetEmployeeBaseSalaryByPeriodStart", mock.Anything, mock.Anything).
+					Return(entity.PayrollPeriod{Status: "locked"}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeBaseSalary{}, nil)
 				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeAttendance{}, gorm.ErrSubQueryRequired)
@@ -346,8 +764,8 @@ func Test_PayrollUseCase_GeneratePayslipsByPeriodID(t *testing.T) {
 				auditLogRepository *mocks.AuditLogRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{Status: "closed"}, nil)
-				employeeRepository.On("GetEmployeeBaseSalaryByPeriodStart", mock.Anything, mock.Anything).
+					Return(entity.PayrollPeriod{Status: "locked"}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeBaseSalary{}, nil)
 				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeAttendance{}, nil)
@@ -364,8 +782,8 @@ func Test_PayrollUseCase_GeneratePayslipsByPeriodID(t *testing.T) {
 				auditLogRepository *mocks.AuditLogRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{Status: "closed"}, nil)
-				employeeRepository.On("GetEmployeeBaseSalaryByPeriodStart", mock.Anything, mock.Anything).
+					Return(entity.PayrollPeriod{Status: "locked"}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeBaseSalary{}, nil)
 				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeAttendance{}, nil)
@@ -377,15 +795,15 @@ func Test_PayrollUseCase_GeneratePayslipsByPeriodID(t *testing.T) {
 			wantErr: gorm.ErrSubQueryRequired,
 		},
 		{
-			name: "error - CreatePayslipsByPeriod",
+			name: "error - CreatePayrollRun",
 			mockFunc: func(
 				employeeRepository *mocks.EmployeeRepository,
 				payrollRepository *mocks.PayrollRepository,
 				auditLogRepository *mocks.AuditLogRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{Status: "closed"}, nil)
-				employeeRepository.On("GetEmployeeBaseSalaryByPeriodStart", mock.Anything, mock.Anything).
+					Return(entity.PayrollPeriod{Status: "locked"}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeBaseSalary{}, nil)
 				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeAttendance{}, nil)
@@ -393,12 +811,54 @@ func Test_PayrollUseCase_GeneratePayslipsByPeriodID(t *testing.T) {
 					Return([]entity.EmployeeOvertime{}, nil)
 				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeReimbursement{}, nil)
-				payrollRepository.On("CreatePayslipsByPeriod", mock.Anything).
-					Return(gorm.ErrSubQueryRequired)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(entity.PayrollRun{}, gorm.ErrSubQueryRequired)
 
 			},
 			wantErr: gorm.ErrSubQueryRequired,
 		},
+		{
+			name: "error - period was calculated by another request",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "locked"}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(entity.PayrollRun{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("payroll period 0 is no longer locked, it was changed by another request"),
+		},
 		{
 			name: "success",
 			mockFunc: func(
@@ -407,8 +867,8 @@ func Test_PayrollUseCase_GeneratePayslipsByPeriodID(t *testing.T) {
 				auditLogRepository *mocks.AuditLogRepository,
 			) {
 				payrollRepository.On("GetPeriodByID", mock.Anything).
-					Return(entity.PayrollPeriod{Status: "closed"}, nil)
-				employeeRepository.On("GetEmployeeBaseSalaryByPeriodStart", mock.Anything, mock.Anything).
+					Return(entity.PayrollPeriod{Status: "locked"}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeBaseSalary{{UserID: 12}}, nil)
 				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeAttendance{{ID: 33}}, nil)
@@ -416,28 +876,1728 @@ func Test_PayrollUseCase_GeneratePayslipsByPeriodID(t *testing.T) {
 					Return([]entity.EmployeeOvertime{{ID: 412}}, nil)
 				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
 					Return([]entity.EmployeeReimbursement{{ID: 41}}, nil)
-				payrollRepository.On("CreatePayslipsByPeriod", mock.Anything).
-					Return(nil)
-				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("CreatePayrollRun", mock.MatchedBy(func(run entity.PayrollRun) bool {
+					return run.PayslipCount == 1 && run.CreatedBy == "admin" && run.RequestID == "req-1"
+				}), mock.Anything, mock.Anything, mock.MatchedBy(func(auditLog entity.AuditLog) bool {
+					return auditLog.Action == "calculate" && auditLog.TableName == "payroll_runs" && auditLog.CreatedBy == "admin"
+				})).Return(entity.PayrollRun{ID: runID}, nil)
 			},
 		},
-	}
+		{
+			name: "success - employee terminated during the period",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				terminationDate := time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			employeeRepository := mocks.NewEmployeeRepository(t)
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{
+						Status:      "locked",
+						PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+						WorkingDays: 23,
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{UserID: 12, BaseSalary: decimal.NewFromInt(2300), TerminationDate: &terminationDate}}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{
+						{
+							UserID:       12,
+							Date:         time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+							CheckInTime:  time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC),
+							CheckOutTime: time.Date(2024, 1, 10, 17, 0, 0, 0, time.UTC),
+						},
+						{
+							UserID:       12,
+							Date:         time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+							CheckInTime:  time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+							CheckOutTime: time.Date(2024, 1, 15, 17, 0, 0, 0, time.UTC),
+						},
+					}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					// only the attendance before the termination counts, 10 of the 23 weekdays were employed, 0.4348 at the column scale
+					return len(payslips) == 1 &&
+						payslips[0].AttendanceDays == 1 &&
+						payslips[0].AttendancePay.Equal(decimal.NewFromInt(100)) &&
+						payslips[0].ProrationFactor.Equal(decimal.RequireFromString("0.4348"))
+				}), mock.Anything, mock.Anything).Return(entity.PayrollRun{ID: runID}, nil)
+			},
+		},
+		{
+			name: "success - payroll policy rules are applied",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{
+						Status:      "locked",
+						PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+						WorkingDays: 20,
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{UserID: 12, BaseSalary: decimal.NewFromInt(1600)}}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{
+						{
+							UserID:       12,
+							Date:         time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+							CheckInTime:  time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC),
+							CheckOutTime: time.Date(2024, 1, 10, 18, 40, 0, 0, time.UTC),
+						},
+						{
+							UserID:       12,
+							Date:         time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC),
+							CheckInTime:  time.Date(2024, 1, 11, 9, 0, 0, 0, time.UTC),
+							CheckOutTime: time.Date(2024, 1, 11, 13, 30, 0, 0, time.UTC),
+						},
+					}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{{UserID: 12, Date: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), Durations: 2}}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).
+					Return(entity.PayrollPolicy{
+						ID:                       2,
+						HoursPerDay:              8,
+						AttendanceHoursRounding:  entity.HoursRoundingNearest,
+						MaxAttendanceHoursPerDay: 8,
+						OvertimeMultiplier:       decimal.RequireFromString("1.5"),
+					}, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					// 9h40m is rounded to 10 and capped at 8 hours, 4h30m is rounded to 5, overtime is paid at 1.5x of 10 an hour
+					return len(payslips) == 1 &&
+						payslips[0].PayrollPolicyID == 2 &&
+						payslips[0].AttendanceHours == 13 &&
+						payslips[0].AttendancePay.Equal(decimal.NewFromInt(130)) &&
+						payslips[0].OvertimePay.Equal(decimal.NewFromInt(30))
+				}), mock.Anything, mock.Anything).Return(entity.PayrollRun{ID: runID}, nil)
+			},
+		},
+		{
+			name: "success - holiday overtime is paid at the holiday multiplier",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{
+						Status:      "locked",
+						PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+						WorkingDays: 20,
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{UserID: 12, BaseSalary: decimal.NewFromInt(1600)}}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{
+						// submitted before the 1st was declared a holiday, the 13th stopped being one after the submission
+						{UserID: 12, Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Durations: 3},
+						{UserID: 12, Date: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), Durations: 2},
+						{UserID: 12, Date: time.Date(2024, 1, 13, 0, 0, 0, 0, time.UTC), Durations: 1, OnHoliday: true},
+					}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{{Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Name: "New Year"}}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(entity.PayrollPolicy{
+						ID:                        2,
+						HoursPerDay:               8,
+						OvertimeMultiplier:        decimal.RequireFromString("1.5"),
+						HolidayOvertimeMultiplier: decimal.NewFromInt(2),
+					}, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					// 10 an hour, 3 weekday and weekend hours at 1.5x and 3 holiday hours at 2x
+					if len(payslips) != 1 {
+						return false
+					}
+					payslip := payslips[0]
+					var holidayLine entity.PayslipLine
+					for _, line := range payslip.Lines {
+						if line.Code == entity.PayslipLineCodeHolidayOvertime {
+							holidayLine = line
+						}
+					}
+					return payslip.OvertimeHours == 3 &&
+						payslip.OvertimePay.Equal(decimal.NewFromInt(45)) &&
+						payslip.HolidayOvertimeHours == 3 &&
+						payslip.HolidayOvertimePay.Equal(decimal.NewFromInt(60)) &&
+						holidayLine.Rate.Equal(decimal.NewFromInt(20)) &&
+						holidayLine.Amount.Equal(decimal.NewFromInt(60)) &&
+						payslip.GrossPay.Equal(decimal.NewFromInt(105))
+				}), mock.Anything, mock.Anything).Return(entity.PayrollRun{ID: runID}, nil)
+			},
+		},
+		{
+			name: "success - attendance on a date declared a holiday afterwards is paid as holiday overtime",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{
+						Status:      "locked",
+						PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+						WorkingDays: 20,
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{UserID: 12, BaseSalary: decimal.NewFromInt(1600)}}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{
+						{UserID: 12, Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), CheckInTime: time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC), CheckOutTime: time.Date(2024, 1, 2, 17, 0, 0, 0, time.UTC)},
+						{UserID: 12, Date: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), CheckInTime: time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC), CheckOutTime: time.Date(2024, 1, 3, 17, 0, 0, 0, time.UTC)},
+					}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{{Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Name: "Collective leave"}}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(entity.PayrollPolicy{
+						ID:                        2,
+						HoursPerDay:               8,
+						OvertimeMultiplier:        decimal.RequireFromString("1.5"),
+						HolidayOvertimeMultiplier: decimal.NewFromInt(2),
+					}, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					// 10 an hour, the 8 hours of the 2nd are paid at 2x and only the 3rd is an attendance day
+					return len(payslips) == 1 &&
+						payslips[0].AttendanceDays == 1 &&
+						payslips[0].AttendancePay.Equal(decimal.NewFromInt(80)) &&
+						payslips[0].HolidayOvertimeHours == 8 &&
+						payslips[0].HolidayOvertimePay.Equal(decimal.NewFromInt(160))
+				}), mock.Anything, mock.Anything).Return(entity.PayrollRun{ID: runID}, nil)
+			},
+		},
+		{
+			name: "error - no payroll policy in effect",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "locked", PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(entity.PayrollPolicy{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("no payroll policy is in effect on 2024-01-01"),
+		},
+		{
+			name: "success - pay is rounded without drift",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{
+						Status:      "locked",
+						PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+						WorkingDays: 21,
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{UserID: 12, BaseSalary: decimal.NewFromInt(5000000)}}, nil)
+				attendances := []entity.EmployeeAttendance{}
+				for day := 1; day <= 21; day++ {
+					attendances = append(attendances, entity.EmployeeAttendance{
+						UserID:       12,
+						Date:         time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC),
+						CheckInTime:  time.Date(2024, 1, day, 9, 0, 0, 0, time.UTC),
+						CheckOutTime: time.Date(2024, 1, day, 17, 0, 0, 0, time.UTC),
+					})
+				}
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return(attendances, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{{UserID: 12, Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Durations: 1}}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{{UserID: 12, Date: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), Amount: decimal.RequireFromString("10.50")}}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					// 21 full days pay exactly the salary, one overtime hour of 59523.809... and the reimbursement of 10.50 are rounded
+					// half up to rupiah when their lines are added, the take home pay is the sum of the rounded lines
+					return len(payslips) == 1 &&
+						payslips[0].SalarySegments[0].OvertimePay.Equal(decimal.NewFromInt(59524)) &&
+						payslips[0].AttendancePay.Equal(decimal.NewFromInt(5000000)) &&
+						payslips[0].OvertimePay.Equal(decimal.NewFromInt(59524)) &&
+						payslips[0].ReimbursementTotal.Equal(decimal.NewFromInt(11)) &&
+						payslips[0].TotalTakeHome.Equal(decimal.NewFromInt(5059535)) &&
+						payslips[0].LineTotal(entity.PayslipLineEarning).Sub(payslips[0].LineTotal(entity.PayslipLineDeduction)).
+							Equal(payslips[0].TotalTakeHome)
+				}), mock.Anything, mock.Anything).Return(entity.PayrollRun{ID: runID}, nil)
+			},
+		},
+		{
+			name: "success - salary changed during the period",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{
+						Status:      "locked",
+						PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+						WorkingDays: 23,
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{
+						UserID:     12,
+						BaseSalary: decimal.NewFromInt(2300),
+						SalaryChanges: []entity.UserSalary{
+							{UserID: 12, Amount: decimal.NewFromInt(3000), EffectiveFrom: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+							{UserID: 12, Amount: decimal.NewFromInt(4600), EffectiveFrom: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+						},
+					}}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{
+						{
+							UserID:       12,
+							Date:         time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+							CheckInTime:  time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC),
+							CheckOutTime: time.Date(2024, 1, 10, 17, 0, 0, 0, time.UTC),
+						},
+						{
+							UserID:       12,
+							Date:         time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+							CheckInTime:  time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+							CheckOutTime: time.Date(2024, 1, 15, 17, 0, 0, 0, time.UTC),
+						},
+					}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{{UserID: 12, Date: time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC), Durations: 2}}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					// 100 a day before the raise, 200 a day from the 15th, the last change of the day wins
+					if len(payslips) != 1 || len(payslips[0].SalarySegments) != 2 {
+						return false
+					}
+					segments := payslips[0].SalarySegments
+					return segments[0].To.Equal(time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)) &&
+						segments[0].WorkingDays == 10 &&
+						segments[0].AttendancePay.Equal(decimal.NewFromInt(100)) &&
+						segments[1].BaseSalary.Equal(decimal.NewFromInt(4600)) &&
+						segments[1].WorkingDays == 13 &&
+						segments[1].AttendancePay.Equal(decimal.NewFromInt(200)) &&
+						segments[1].OvertimePay.Equal(decimal.NewFromInt(100)) &&
+						payslips[0].BaseSalary.Equal(decimal.NewFromInt(4600)) &&
+						payslips[0].AttendancePay.Equal(decimal.NewFromInt(300)) &&
+						payslips[0].TotalTakeHome.Equal(decimal.NewFromInt(400))
+				}), mock.Anything, mock.Anything).Return(entity.PayrollRun{ID: runID}, nil)
+			},
+		},
+		{
+			name: "success - days before the first salary took effect are not paid",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{
+						Status:      "locked",
+						PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+						WorkingDays: 23,
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{
+						UserID:        12,
+						BaseSalary:    decimal.NewFromInt(4600),
+						EffectiveFrom: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+						SalaryChanges: []entity.UserSalary{
+							{UserID: 12, Amount: decimal.NewFromInt(4600), EffectiveFrom: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+						},
+					}}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{
+						{
+							UserID:       12,
+							Date:         time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+							CheckInTime:  time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC),
+							CheckOutTime: time.Date(2024, 1, 10, 17, 0, 0, 0, time.UTC),
+						},
+						{
+							UserID:       12,
+							Date:         time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+							CheckInTime:  time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+							CheckOutTime: time.Date(2024, 1, 15, 17, 0, 0, 0, time.UTC),
+						},
+					}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					// the attendance on the 10th falls before the first salary, only the 15th is paid
+					if len(payslips) != 1 || len(payslips[0].SalarySegments) != 2 {
+						return false
+					}
+					segments := payslips[0].SalarySegments
+					return segments[0].To.Equal(time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)) &&
+						segments[0].BaseSalary.IsZero() &&
+						segments[0].AttendancePay.IsZero() &&
+						segments[1].BaseSalary.Equal(decimal.NewFromInt(4600)) &&
+						segments[1].AttendancePay.Equal(decimal.NewFromInt(200)) &&
+						payslips[0].BaseSalary.Equal(decimal.NewFromInt(4600)) &&
+						payslips[0].AttendancePay.Equal(decimal.NewFromInt(200))
+				}), mock.Anything, mock.Anything).Return(entity.PayrollRun{ID: runID}, nil)
+			},
+		},
+		{
+			name: "success - holidays are left out of the salary segment working days",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{
+						Status:      "locked",
+						PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+						WorkingDays: 22,
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{
+						UserID:     12,
+						BaseSalary: decimal.NewFromInt(2200),
+						SalaryChanges: []entity.UserSalary{
+							{UserID: 12, Amount: decimal.NewFromInt(4400), EffectiveFrom: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+						},
+					}}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{
+						{
+							UserID:       12,
+							Date:         time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+							CheckInTime:  time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC),
+							CheckOutTime: time.Date(2024, 1, 10, 17, 0, 0, 0, time.UTC),
+						},
+					}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)).
+					Return([]entity.Holiday{{Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Name: "New Year"}}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					// the segments add up to the 22 working days of the period, New Year's Day is not one of them
+					if len(payslips) != 1 || len(payslips[0].SalarySegments) != 2 {
+						return false
+					}
+					segments := payslips[0].SalarySegments
+					return segments[0].WorkingDays == 9 &&
+						segments[0].AttendancePay.Equal(decimal.NewFromInt(100)) &&
+						segments[1].WorkingDays == 13
+				}), mock.Anything, mock.Anything).Return(entity.PayrollRun{ID: runID}, nil)
+			},
+		},
+		{
+			name: "success - recurring allowances are added as earning lines",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{
+						Status:      "locked",
+						PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+						WorkingDays: 23,
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{UserID: 12, BaseSalary: decimal.NewFromInt(2300)}}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{
+						{
+							UserID:       12,
+							Date:         time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+							CheckInTime:  time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC),
+							CheckOutTime: time.Date(2024, 1, 10, 17, 0, 0, 0, time.UTC),
+						},
+						{
+							UserID:       12,
+							Date:         time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+							CheckInTime:  time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+							CheckOutTime: time.Date(2024, 1, 15, 17, 0, 0, 0, time.UTC),
+						},
+					}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), (*int64)(nil)).
+					Return([]entity.EmployeePayComponent{
+						{
+							UserID:        12,
+							Method:        entity.CalculationFixed,
+							Amount:        decimal.NewFromInt(230),
+							EffectiveFrom: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+							PayComponent:  entity.PayComponent{Code: "TRANSPORT", Name: "Transport allowance"},
+						},
+						{
+							UserID:        12,
+							Method:        entity.CalculationFixed,
+							Amount:        decimal.NewFromInt(460),
+							EffectiveFrom: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+							PayComponent:  entity.PayComponent{Code: "HOUSING", Name: "Housing allowance"},
+						},
+						{
+							UserID:        12,
+							Method:        entity.CalculationPerAttendanceDay,
+							Amount:        decimal.NewFromInt(25),
+							EffectiveFrom: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+							PayComponent:  entity.PayComponent{Code: "MEAL", Name: "Meal allowance"},
+						},
+					}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					// housing covers 13 of the 23 weekdays, the meal allowance only counts the attendance from the 15th
+					if len(payslips) != 1 || len(payslips[0].Earnings) != 3 {
+						return false
+					}
+					earnings := payslips[0].Earnings
+					return earnings[0].Code == "TRANSPORT" && earnings[0].Days == 23 && earnings[0].Amount.Equal(decimal.NewFromInt(230)) &&
+						earnings[1].Code == "HOUSING" && earnings[1].Days == 13 && earnings[1].Amount.Equal(decimal.NewFromInt(260)) &&
+						earnings[2].Code == "MEAL" && earnings[2].Days == 1 && earnings[2].Amount.Equal(decimal.NewFromInt(25)) &&
+						payslips[0].AllowanceTotal.Equal(decimal.NewFromInt(515)) &&
+						payslips[0].TotalTakeHome.Equal(decimal.NewFromInt(715))
+				}), mock.Anything, mock.Anything).Return(entity.PayrollRun{ID: runID}, nil)
+			},
+		},
+		{
+			name: "success - deductions are recovered in priority order down to the net pay floor",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{
+						ID:          1,
+						Status:      "locked",
+						PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+						WorkingDays: 23,
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{UserID: 12, BaseSalary: decimal.NewFromInt(4600)}}, nil)
+				attendances := []entity.EmployeeAttendance{}
+				for day := 8; day <= 12; day++ {
+					attendances = append(attendances, entity.EmployeeAttendance{
+						UserID:       12,
+						Date:         time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC),
+						CheckInTime:  time.Date(2024, 1, day, 9, 0, 0, 0, time.UTC),
+						CheckOutTime: time.Date(2024, 1, day, 17, 0, 0, 0, time.UTC),
+					})
+				}
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return(attendances, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), (*int64)(nil)).
+					Return([]entity.EmployeeDeduction{
+						{
+							ID:            1,
+							UserID:        12,
+							Type:          entity.DeductionRecurring,
+							Priority:      2,
+							Amount:        decimal.NewFromInt(150),
+							EffectiveFrom: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+							Status:        entity.DeductionStatusActive,
+						},
+						{
+							ID:            2,
+							UserID:        12,
+							Type:          entity.DeductionOneOff,
+							Priority:      2,
+							Amount:        decimal.NewFromInt(120),
+							Balance:       decimal.NewFromInt(120),
+							EffectiveFrom: time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC),
+							Status:        entity.DeductionStatusActive,
+						},
+						{
+							ID:            3,
+							UserID:        12,
+							Type:          entity.DeductionLoan,
+							Priority:      1,
+							Amount:        decimal.NewFromInt(300),
+							Principal:     decimal.NewFromInt(1200),
+							Balance:       decimal.NewFromInt(500),
+							CarriedOver:   decimal.NewFromInt(100),
+							EffectiveFrom: time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+							Status:        entity.DeductionStatusActive,
+						},
+					}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					// 1000 gross with a floor of 400 leaves 600: the loan installment and its arrears, the recurring amount, then 50 of the one off
+					if len(payslips) != 1 || len(payslips[0].Deductions) != 3 {
+						return false
+					}
+					lines := payslips[0].Deductions
+					return lines[0].DeductionID == 3 && lines[0].Amount.Equal(decimal.NewFromInt(400)) &&
+						lines[1].DeductionID == 1 && lines[1].Amount.Equal(decimal.NewFromInt(150)) &&
+						lines[2].DeductionID == 2 && lines[2].Amount.Equal(decimal.NewFromInt(50)) && lines[2].Unrecovered.Equal(decimal.NewFromInt(70)) &&
+						payslips[0].GrossPay.Equal(decimal.NewFromInt(1000)) &&
+						payslips[0].DeductionTotal.Equal(decimal.NewFromInt(600)) &&
+						payslips[0].TotalTakeHome.Equal(decimal.NewFromInt(400)) &&
+						payslips[0].LineTotal(entity.PayslipLineDeduction).Equal(decimal.NewFromInt(600)) &&
+						*payslips[0].Lines[len(payslips[0].Lines)-1].SourceID == 2 &&
+						payslips[0].Lines[len(payslips[0].Lines)-1].SourceType == "employee_deductions"
+				}), mock.MatchedBy(func(deductions []entity.EmployeeDeduction) bool {
+					return len(deductions) == 3 &&
+						deductions[0].Balance.Equal(decimal.NewFromInt(100)) && deductions[0].CarriedOver.IsZero() &&
+						deductions[0].Status == entity.DeductionStatusActive &&
+						deductions[1].CarriedOver.IsZero() &&
+						deductions[2].Balance.Equal(decimal.NewFromInt(70)) &&
+						deductions[2].Status == entity.DeductionStatusActive &&
+						deductions[2].UpdatedBy == "admin"
+				}), mock.Anything).Return(entity.PayrollRun{ID: runID}, nil)
+			},
+			payrollConfig: usecase.PayrollConfig{NetPayFloor: decimal.NewFromInt(400)},
+		},
+		{
+			name: "success - BPJS contributions and PPh 21 at the TER rate are withheld",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{
+						ID:          1,
+						Status:      "locked",
+						PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+						WorkingDays: 5,
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{UserID: 12, BaseSalary: decimal.NewFromInt(10000000)}}, nil)
+				attendances := []entity.EmployeeAttendance{}
+				for day := 8; day <= 12; day++ {
+					attendances = append(attendances, entity.EmployeeAttendance{
+						UserID:       12,
+						Date:         time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC),
+						CheckInTime:  time.Date(2024, 1, day, 9, 0, 0, 0, time.UTC),
+						CheckOutTime: time.Date(2024, 1, day, 17, 0, 0, 0, time.UTC),
+					})
+				}
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return(attendances, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).
+					Return(statutoryRates, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					// the employer part of BPJS Kesehatan, JKK and JKM (454,000) is taxed at 2.25%, the category A rate for 10,454,000
+					if len(payslips) != 1 || len(payslips[0].Contributions) != 5 {
+						return false
+					}
+					payslip := payslips[0]
+					return payslip.Contributions[0].Code == "BPJS_KES" && payslip.Contributions[0].EmployeeAmount.Equal(decimal.NewFromInt(100000)) &&
+						payslip.Contributions[2].Code == "JP" && payslip.Contributions[2].EmployerAmount.Equal(decimal.NewFromInt(200000)) &&
+						payslip.EmployeeContributionTotal.Equal(decimal.NewFromInt(400000)) &&
+						payslip.EmployerContributionTotal.Equal(decimal.NewFromInt(1024000)) &&
+						payslip.TaxStatus == entity.DefaultTaxStatus &&
+						payslip.TaxableIncome.Equal(decimal.NewFromInt(10454000)) &&
+						payslip.IncomeTaxMethod == entity.IncomeTaxMethodTER &&
+						payslip.IncomeTaxRate.Equal(decimal.RequireFromString("0.0225")) &&
+						payslip.IncomeTax.Equal(decimal.NewFromInt(235215)) &&
+						payslip.GrossPay.Equal(decimal.NewFromInt(10000000)) &&
+						payslip.TotalTakeHome.Equal(decimal.NewFromInt(9364785)) &&
+						payslip.LineTotal(entity.PayslipLineEarning).Equal(payslip.GrossPay) &&
+						payslip.LineTotal(entity.PayslipLineDeduction).Equal(decimal.NewFromInt(635215)) &&
+						payslip.LineTotal(entity.PayslipLineEmployerCost).Equal(payslip.EmployerContributionTotal) &&
+						payslip.Lines[len(payslip.Lines)-1].Code == entity.PayslipLineCodeIncomeTax &&
+						payslip.Lines[len(payslip.Lines)-1].Sequence == len(payslip.Lines)
+				}), mock.Anything, mock.Anything).Return(entity.PayrollRun{ID: runID}, nil)
+			},
+		},
+		{
+			name: "success - December reconciles PPh 21 against the year to date",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{
+						ID:          12,
+						Status:      "locked",
+						PeriodStart: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+						WorkingDays: 5,
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{UserID: 12, BaseSalary: decimal.NewFromInt(10000000), TaxStatus: "K/1"}}, nil)
+				attendances := []entity.EmployeeAttendance{}
+				for day := 2; day <= 6; day++ {
+					attendances = append(attendances, entity.EmployeeAttendance{
+						UserID:       12,
+						Date:         time.Date(2024, 12, day, 0, 0, 0, 0, time.UTC),
+						CheckInTime:  time.Date(2024, 12, day, 9, 0, 0, 0, time.UTC),
+						CheckOutTime: time.Date(2024, 12, day, 17, 0, 0, 0, time.UTC),
+					})
+				}
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return(attendances, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				rates := statutoryRates
+				rates.Contributions = statutoryRates.Contributions[1:2]
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)).
+					Return(rates, nil)
+				earlierPayslips := []entity.PayrollPayslip{}
+				for month := 1; month <= 11; month++ {
+					earlierPayslips = append(earlierPayslips, entity.PayrollPayslip{
+						UserID:        12,
+						TaxableIncome: decimal.NewFromInt(10000000),
+						IncomeTax:     decimal.NewFromInt(200000),
+						Contributions: []entity.PayslipContribution{{Code: "JHT", EmployeeAmount: decimal.NewFromInt(200000), TaxDeductible: true}},
+					})
+				}
+				payrollRepository.On("GetPayslipsByTimeRange", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC), (*int64)(nil)).
+					Return(earlierPayslips, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					// 120,000,000 less 6,000,000 occupational cost, 2,400,000 JHT and the 63,000,000 PTKP of K/1 is taxed 2,430,000 for the year
+					if len(payslips) != 1 {
+						return false
+					}
+					payslip := payslips[0]
+					return payslip.TaxStatus == "K/1" &&
+						payslip.IncomeTaxMethod == entity.IncomeTaxMethodAnnual &&
+						payslip.IncomeTax.Equal(decimal.NewFromInt(230000)) &&
+						payslip.TotalTakeHome.Equal(decimal.NewFromInt(9570000))
+				}), mock.Anything, mock.Anything).Return(entity.PayrollRun{ID: runID}, nil)
+			},
+		},
+		{
+			name: "success - a period ending in January withholds TER, it is the first of the next tax year",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{
+						ID:          13,
+						Status:      "locked",
+						PeriodStart: time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC),
+						WorkingDays: 5,
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{UserID: 12, BaseSalary: decimal.NewFromInt(10000000), TaxStatus: "K/1"}}, nil)
+				attendances := []entity.EmployeeAttendance{}
+				for day := 16; day <= 20; day++ {
+					attendances = append(attendances, entity.EmployeeAttendance{
+						UserID:       12,
+						Date:         time.Date(2024, 12, day, 0, 0, 0, 0, time.UTC),
+						CheckInTime:  time.Date(2024, 12, day, 9, 0, 0, 0, time.UTC),
+						CheckOutTime: time.Date(2024, 12, day, 17, 0, 0, 0, time.UTC),
+					})
+				}
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return(attendances, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				rates := statutoryRates
+				rates.Contributions = statutoryRates.Contributions[1:2]
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC)).
+					Return(rates, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					return len(payslips) == 1 && payslips[0].IncomeTaxMethod == entity.IncomeTaxMethodTER
+				}), mock.Anything, mock.Anything).Return(entity.PayrollRun{ID: runID}, nil)
+			},
+		},
+		{
+			name: "success - the period ending in December reconciles the periods paid since January, including the one that started in December",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{
+						ID:          24,
+						Status:      "locked",
+						PeriodStart: time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2025, 12, 9, 0, 0, 0, 0, time.UTC),
+						WorkingDays: 5,
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{UserID: 12, BaseSalary: decimal.NewFromInt(10000000), TaxStatus: "K/1"}}, nil)
+				attendances := []entity.EmployeeAttendance{}
+				for day := 17; day <= 21; day++ {
+					attendances = append(attendances, entity.EmployeeAttendance{
+						UserID:       12,
+						Date:         time.Date(2025, 11, day, 0, 0, 0, 0, time.UTC),
+						CheckInTime:  time.Date(2025, 11, day, 9, 0, 0, 0, time.UTC),
+						CheckOutTime: time.Date(2025, 11, day, 17, 0, 0, 0, time.UTC),
+					})
+				}
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return(attendances, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				rates := statutoryRates
+				rates.Contributions = statutoryRates.Contributions[1:2]
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)).
+					Return(rates, nil)
+				// the 11 periods ending January 9 to November 9, the first of them started on December 10, 2024
+				earlierPayslips := []entity.PayrollPayslip{}
+				for month := 1; month <= 11; month++ {
+					earlierPayslips = append(earlierPayslips, entity.PayrollPayslip{
+						UserID:        12,
+						TaxableIncome: decimal.NewFromInt(10000000),
+						IncomeTax:     decimal.NewFromInt(200000),
+						Contributions: []entity.PayslipContribution{{Code: "JHT", EmployeeAmount: decimal.NewFromInt(200000), TaxDeductible: true}},
+					})
+				}
+				payrollRepository.On("GetPayslipsByTimeRange", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 11, 9, 0, 0, 0, 0, time.UTC), (*int64)(nil)).
+					Return(earlierPayslips, nil)
+				payrollRepository.On("CreatePayrollRun", mock.Anything, mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					if len(payslips) != 1 {
+						return false
+					}
+					payslip := payslips[0]
+					return payslip.IncomeTaxMethod == entity.IncomeTaxMethodAnnual &&
+						payslip.IncomeTax.Equal(decimal.NewFromInt(230000)) &&
+						payslip.TotalTakeHome.Equal(decimal.NewFromInt(9570000))
+				}), mock.Anything, mock.Anything).Return(entity.PayrollRun{ID: runID}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employeeRepository := mocks.NewEmployeeRepository(t)
 			payrollRepository := mocks.NewPayrollRepository(t)
 			auditLogRepository := mocks.NewAuditLogRepository(t)
+			employeeProfileRepository := mocks.NewEmployeeProfileRepository(t)
 
 			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository)
 
-			usecase := usecase.NewPayrollUseCase(payrollRepository, employeeRepository, auditLogRepository)
-			err := usecase.GeneratePayslipsByPeriodID(entity.UserContext{}, 0)
+			usecase := usecase.NewPayrollUseCase(tt.payrollConfig, payrollRepository, employeeRepository, employeeProfileRepository, mocks.NewUserRepository(t), auditLogRepository)
+			res, err := usecase.GeneratePayslipsByPeriodID(entity.UserContext{Username: "admin", RequestID: "req-1"}, 0)
 			if tt.wantErr != nil {
 				assert.Equal(t, tt.wantErr.Error(), err.Error())
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, runID, res.ID)
 			}
 		})
 	}
 }
+
+func Test_PayrollUseCase_RerunPayroll(t *testing.T) {
+	request := entity.RerunPayrollRequest{Reason: "Overtime of the last week was approved late"}
+	approvedPeriod := entity.PayrollPeriod{
+		ID:          5,
+		Status:      entity.PayrollStatusApproved,
+		PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		WorkingDays: 23,
+	}
+	approvedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	previousRun := entity.PayrollRun{ID: 7, PayrollPeriodID: 5, Version: 1, ApprovedAt: &approvedAt}
+
+	tests := []struct {
+		name     string
+		request  entity.RerunPayrollRequest
+		mockFunc func(
+			employeeRepository *mocks.EmployeeRepository,
+			payrollRepository *mocks.PayrollRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+	}{
+		{
+			name:    "error - reason is required",
+			request: entity.RerunPayrollRequest{},
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("a reason is required to rerun a payroll"),
+		},
+		{
+			name:    "error - period is not calculated yet",
+			request: request,
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).
+					Return(entity.PayrollPeriod{ID: 5, Status: entity.PayrollStatusLocked}, nil)
+			},
+			wantErr: errors.New("only calculated or approved payroll periods can be rerun, payroll period 5 is locked"),
+		},
+		{
+			name:    "error - period is paid",
+			request: request,
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).
+					Return(entity.PayrollPeriod{ID: 5, Status: entity.PayrollStatusPaid}, nil)
+			},
+			wantErr: errors.New("only calculated or approved payroll periods can be rerun, payroll period 5 is paid"),
+		},
+		{
+			name:    "error - a later period is closed",
+			request: request,
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).Return(approvedPeriod, nil)
+				payrollRepository.On("GetLatestClosedPeriod").
+					Return(entity.PayrollPeriod{
+						ID:          6,
+						Status:      entity.PayrollStatusLocked,
+						PeriodStart: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+						PeriodEnd:   time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+					}, nil)
+			},
+			wantErr: errors.New("only the latest closed payroll period can be rerun, payroll period 6 (2024-02-01 to 2024-02-29) was closed after it"),
+		},
+		{
+			name:    "error - period has no payroll run",
+			request: request,
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).Return(approvedPeriod, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(approvedPeriod, nil)
+				payrollRepository.On("GetCurrentPayrollRun", int64(5)).Return(entity.PayrollRun{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("payroll period 5 has no payroll run to rerun"),
+		},
+		{
+			name:    "error - another rerun superseded the run",
+			request: request,
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).Return(approvedPeriod, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(approvedPeriod, nil)
+				payrollRepository.On("GetCurrentPayrollRun", int64(5)).Return(previousRun, nil)
+				payrollRepository.On("GetPayslipsByRunID", int64(7)).Return([]entity.PayrollPayslip{}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("RerunPayroll", int64(7), entity.PayrollStatusApproved, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(entity.PayrollRun{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("payroll period 5 was changed by another request, its payroll run 7 is no longer current"),
+		},
+		{
+			name:    "error - a deduction was cancelled while the payroll was calculated",
+			request: request,
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).Return(approvedPeriod, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(approvedPeriod, nil)
+				payrollRepository.On("GetCurrentPayrollRun", int64(5)).Return(previousRun, nil)
+				payrollRepository.On("GetPayslipsByRunID", int64(7)).Return([]entity.PayrollPayslip{}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("RerunPayroll", int64(7), entity.PayrollStatusApproved, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(entity.PayrollRun{}, entity.ErrDeductionChanged)
+			},
+			wantErr: entity.ErrDeductionChanged,
+		},
+		{
+			name:    "success - deductions of the previous run are restored and recovered again",
+			request: request,
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(5)).Return(approvedPeriod, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(approvedPeriod, nil)
+				payrollRepository.On("GetCurrentPayrollRun", int64(5)).Return(previousRun, nil)
+				payrollRepository.On("GetPayslipsByRunID", int64(7)).
+					Return([]entity.PayrollPayslip{
+						{
+							ID:     31,
+							UserID: 12,
+							Deductions: []entity.PayslipDeduction{
+								{DeductionID: 9, Type: entity.DeductionLoan, Due: decimal.NewFromInt(300), Amount: decimal.NewFromInt(300)},
+								{DeductionID: 10, Type: entity.DeductionOneOff, Due: decimal.NewFromInt(200), Amount: decimal.NewFromInt(200)},
+							},
+						},
+					}, nil)
+				employeeRepository.On("GetDeductionsByIDs", []int64{9, 10}).
+					Return([]entity.EmployeeDeduction{
+						{ID: 9, UserID: 12, Type: entity.DeductionLoan, Priority: 1, Amount: decimal.NewFromInt(300), Balance: decimal.NewFromInt(700), Status: entity.DeductionStatusActive},
+						{ID: 10, UserID: 12, Type: entity.DeductionOneOff, Priority: 2, Balance: decimal.Zero, Status: entity.DeductionStatusSettled},
+					}, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{{UserID: 12, BaseSalary: decimal.NewFromInt(4600)}}, nil)
+				attendances := []entity.EmployeeAttendance{}
+				for day := 8; day <= 12; day++ {
+					attendances = append(attendances, entity.EmployeeAttendance{
+						UserID:       12,
+						Date:         time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC),
+						CheckInTime:  time.Date(2024, 1, day, 9, 0, 0, 0, time.UTC),
+						CheckOutTime: time.Date(2024, 1, day, 17, 0, 0, 0, time.UTC),
+					})
+				}
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return(attendances, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				// the stored loan balance already has the previous run's installment taken off
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{
+						{ID: 9, UserID: 12, Type: entity.DeductionLoan, Priority: 1, Amount: decimal.NewFromInt(300), Balance: decimal.NewFromInt(700), Status: entity.DeductionStatusActive},
+					}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				payrollRepository.On("RerunPayroll", int64(7), entity.PayrollStatusApproved, mock.MatchedBy(func(run entity.PayrollRun) bool {
+					return run.PayrollPeriodID == 5 && run.Reason == request.Reason && run.PayslipCount == 1 &&
+						run.TotalTakeHome.Equal(decimal.NewFromInt(500))
+				}), mock.MatchedBy(func(payslips []entity.PayrollPayslip) bool {
+					return len(payslips) == 1 && len(payslips[0].Deductions) == 2 &&
+						payslips[0].DeductionTotal.Equal(decimal.NewFromInt(500))
+				}), mock.MatchedBy(func(deductions []entity.EmployeeDeduction) bool {
+					return len(deductions) == 2 &&
+						deductions[0].ID == 9 && deductions[0].Balance.Equal(decimal.NewFromInt(700)) &&
+						deductions[1].ID == 10 && deductions[1].Balance.IsZero() &&
+						deductions[1].Status == entity.DeductionStatusSettled &&
+						deductions[1].UpdatedBy == "finance"
+				}), mock.MatchedBy(func(auditLog entity.AuditLog) bool {
+					return auditLog.Action == "rerun" && auditLog.TableName == "payroll_runs"
+				})).Return(entity.PayrollRun{ID: 8, PayrollPeriodID: 5, Version: 2}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employeeRepository := mocks.NewEmployeeRepository(t)
+			payrollRepository := mocks.NewPayrollRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+			employeeProfileRepository := mocks.NewEmployeeProfileRepository(t)
+
+			tt.mockFunc(employeeRepository, payrollRepository, auditLogRepository)
+
+			usecase := usecase.NewPayrollUseCase(usecase.PayrollConfig{}, payrollRepository, employeeRepository, employeeProfileRepository, mocks.NewUserRepository(t), auditLogRepository)
+			res, err := usecase.RerunPayroll(entity.UserContext{Username: "finance"}, 5, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 2, res.Version)
+			}
+		})
+	}
+}
+
+func Test_PayrollUseCase_DiffPayrollRuns(t *testing.T) {
+	approvedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	firstRun := entity.PayrollRun{ID: 7, PayrollPeriodID: 5, Version: 1, ApprovedAt: &approvedAt}
+	secondRun := entity.PayrollRun{ID: 8, PayrollPeriodID: 5, Version: 2}
+	reviewer := entity.UserContext{Role: entity.RoleHR, Permissions: []entity.Permission{entity.PermissionPayrollApprove}}
+
+	tests := []struct {
+		name        string
+		userContext entity.UserContext
+		fromVersion int
+		toVersion   int
+		mockFunc    func(payrollRepository *mocks.PayrollRepository)
+		wantErr     error
+		wantRes     []entity.PayslipDiff
+	}{
+		{
+			name:        "error - the current run is the first version",
+			userContext: reviewer,
+			mockFunc: func(payrollRepository *mocks.PayrollRepository) {
+				payrollRepository.On("GetCurrentPayrollRun", int64(5)).Return(firstRun, nil)
+			},
+			wantErr: errors.New("payroll run version 1 is the first of payroll period 5, there is no version to compare it with"),
+		},
+		{
+			name:        "error - version not found",
+			userContext: reviewer,
+			fromVersion: 1,
+			toVersion:   3,
+			mockFunc: func(payrollRepository *mocks.PayrollRepository) {
+				payrollRepository.On("GetPayrollRunByVersion", int64(5), 3).Return(entity.PayrollRun{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("payroll run version 3 of payroll period 5 not found"),
+		},
+		{
+			name:        "error - unapproved runs are hidden from auditors",
+			userContext: entity.UserContext{Role: entity.RoleAuditor, Permissions: []entity.Permission{entity.PermissionPayslipReadAll}},
+			mockFunc: func(payrollRepository *mocks.PayrollRepository) {
+				payrollRepository.On("GetCurrentPayrollRun", int64(5)).Return(secondRun, nil)
+				payrollRepository.On("GetPayrollRunByVersion", int64(5), 1).Return(firstRun, nil)
+			},
+			wantErr: errors.New("payroll run version 2 is not approved yet"),
+		},
+		{
+			name:        "success - the current run is compared with the version before it",
+			userContext: reviewer,
+			mockFunc: func(payrollRepository *mocks.PayrollRepository) {
+				payrollRepository.On("GetCurrentPayrollRun", int64(5)).Return(secondRun, nil)
+				payrollRepository.On("GetPayrollRunByVersion", int64(5), 1).Return(firstRun, nil)
+				payrollRepository.On("GetPayslipsByRunID", int64(7)).
+					Return([]entity.PayrollPayslip{
+						{UserID: 11, OvertimePay: decimal.NewFromInt(100), GrossPay: decimal.NewFromInt(1100), TotalTakeHome: decimal.NewFromInt(1100)},
+						{UserID: 12, GrossPay: decimal.NewFromInt(900), TotalTakeHome: decimal.NewFromInt(900)},
+						{UserID: 13, GrossPay: decimal.NewFromInt(500), TotalTakeHome: decimal.NewFromInt(500)},
+					}, nil)
+				payrollRepository.On("GetPayslipsByRunID", int64(8)).
+					Return([]entity.PayrollPayslip{
+						{UserID: 11, OvertimePay: decimal.NewFromInt(250), GrossPay: decimal.NewFromInt(1250), TotalTakeHome: decimal.NewFromInt(1250)},
+						{UserID: 12, GrossPay: decimal.NewFromInt(900), TotalTakeHome: decimal.NewFromInt(900)},
+						{UserID: 14, GrossPay: decimal.NewFromInt(300), TotalTakeHome: decimal.NewFromInt(300)},
+					}, nil)
+			},
+			wantRes: []entity.PayslipDiff{
+				{
+					UserID: 11,
+					Change: entity.PayslipDiffChanged,
+					Amounts: []entity.PayslipAmountChange{
+						{Amount: "overtime_pay", From: decimal.NewFromInt(100), To: decimal.NewFromInt(250), Difference: decimal.NewFromInt(150)},
+						{Amount: "gross_pay", From: decimal.NewFromInt(1100), To: decimal.NewFromInt(1250), Difference: decimal.NewFromInt(150)},
+						{Amount: "total_take_home", From: decimal.NewFromInt(1100), To: decimal.NewFromInt(1250), Difference: decimal.NewFromInt(150)},
+					},
+				},
+				{UserID: 13, Change: entity.PayslipDiffRemoved},
+				{UserID: 14, Change: entity.PayslipDiffAdded},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payrollRepository := mocks.NewPayrollRepository(t)
+
+			tt.mockFunc(payrollRepository)
+
+			usecase := usecase.NewPayrollUseCase(usecase.PayrollConfig{}, payrollRepository, mocks.NewEmployeeRepository(t), mocks.NewEmployeeProfileRepository(t), mocks.NewUserRepository(t), mocks.NewAuditLogRepository(t))
+			res, err := usecase.DiffPayrollRuns(tt.userContext, 5, tt.fromVersion, tt.toVersion)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, len(tt.wantRes), len(res.Payslips))
+			for i, want := range tt.wantRes {
+				assert.Equal(t, want.UserID, res.Payslips[i].UserID)
+				assert.Equal(t, want.Change, res.Payslips[i].Change)
+				if want.Amounts != nil {
+					assert.Equal(t, want.Amounts, res.Payslips[i].Amounts)
+				}
+			}
+		})
+	}
+}
+
+func Test_PayrollUseCase_PreviewPayroll(t *testing.T) {
+	openPeriod := entity.PayrollPeriod{
+		ID:          1,
+		Status:      entity.PayrollStatusOpen,
+		PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		WorkingDays: 23,
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func(
+			employeeRepository *mocks.EmployeeRepository,
+			payrollRepository *mocks.PayrollRepository,
+			employeeProfileRepository *mocks.EmployeeProfileRepository,
+		)
+		wantErr error
+		wantRes func(t *testing.T, preview entity.PayrollPreview)
+	}{
+		{
+			name: "error - period not found",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(1)).Return(entity.PayrollPeriod{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("payroll period not found"),
+		},
+		{
+			name: "error - period is already calculated",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(1)).
+					Return(entity.PayrollPeriod{ID: 1, Status: entity.PayrollStatusCalculated}, nil)
+			},
+			wantErr: errors.New("payroll period 1 is already calculated, read its payslips instead"),
+		},
+		{
+			name: "error - GetProfilesEmployedBetween",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(1)).Return(openPeriod, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{}, nil)
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeAttendance{}, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				employeeProfileRepository.On("GetProfilesEmployedBetween", openPeriod.PeriodStart, openPeriod.PeriodEnd).
+					Return(nil, gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name: "success - an open period is calculated with totals and warnings",
+			mockFunc: func(
+				employeeRepository *mocks.EmployeeRepository,
+				payrollRepository *mocks.PayrollRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", int64(1)).Return(openPeriod, nil)
+				employeeRepository.On("GetEmployeeBaseSalaryByPeriod", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeBaseSalary{
+						{UserID: 12, BaseSalary: decimal.NewFromInt(4600)},
+						{UserID: 13, BaseSalary: decimal.NewFromInt(2300)},
+					}, nil)
+				attendances := []entity.EmployeeAttendance{}
+				for day := 8; day <= 12; day++ {
+					attendances = append(attendances, entity.EmployeeAttendance{
+						UserID:       12,
+						Date:         time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC),
+						CheckInTime:  time.Date(2024, 1, day, 9, 0, 0, 0, time.UTC),
+						CheckOutTime: time.Date(2024, 1, day, 17, 0, 0, 0, time.UTC),
+					})
+				}
+				employeeRepository.On("GetAllAttendanceByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return(attendances, nil)
+				employeeRepository.On("GetAllOvertimeByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeOvertime{
+						{UserID: 12, Date: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), Durations: 3},
+						{UserID: 12, Date: time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC), Durations: 3},
+					}, nil)
+				employeeRepository.On("GetAllReimbursementByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeeReimbursement{}, nil)
+				employeeRepository.On("GetAllPayComponentsByTimeRange", mock.Anything, mock.Anything, mock.Anything).
+					Return([]entity.EmployeePayComponent{}, nil)
+				employeeRepository.On("GetAllHolidaysByTimeRange", mock.Anything, mock.Anything).
+					Return([]entity.Holiday{}, nil)
+				employeeRepository.On("GetAllActiveDeductions", mock.Anything, mock.Anything).
+					Return([]entity.EmployeeDeduction{}, nil)
+				payrollRepository.On("GetPolicyEffectiveOn", mock.Anything).
+					Return(defaultPayrollPolicy, nil)
+				payrollRepository.On("GetStatutoryRatesEffectiveOn", mock.Anything).
+					Return(entity.StatutoryRates{}, nil)
+				employeeProfileRepository.On("GetProfilesEmployedBetween", openPeriod.PeriodStart, openPeriod.PeriodEnd).
+					Return([]entity.EmployeeProfile{
+						{UserID: 12, FullName: "Employee Twelve"},
+						{UserID: 14, FullName: "Employee Fourteen"},
+					}, nil)
+			},
+			wantRes: func(t *testing.T, preview entity.PayrollPreview) {
+				assert.Equal(t, 2, preview.Totals.Headcount)
+				assert.Equal(t, "Employee Twelve", preview.Payslips[0].Employee.FullName)
+				assert.Nil(t, preview.Payslips[1].Employee)
+				assert.True(t, preview.Totals.TotalTakeHome.Equal(preview.Payslips[0].TotalTakeHome.Add(preview.Payslips[1].TotalTakeHome)))
+				assert.Equal(t, []entity.PayrollPreviewWarning{
+					{UserID: 14, Code: entity.PayrollWarningMissingSalary, Message: "employed during the period but has no salary, no payslip is calculated"},
+					{UserID: 12, Code: entity.PayrollWarningUnusualOvertime, Message: "has 6 overtime hours in the period, more than 5"},
+					{UserID: 13, Code: entity.PayrollWarningNoAttendance, Message: "has no attendance in the period"},
+				}, preview.Warnings)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employeeRepository := mocks.NewEmployeeRepository(t)
+			payrollRepository := mocks.NewPayrollRepository(t)
+			employeeProfileRepository := mocks.NewEmployeeProfileRepository(t)
+
+			tt.mockFunc(employeeRepository, payrollRepository, employeeProfileRepository)
+
+			usecase := usecase.NewPayrollUseCase(usecase.PayrollConfig{OvertimeWarningHours: 5}, payrollRepository, employeeRepository, employeeProfileRepository, mocks.NewUserRepository(t), mocks.NewAuditLogRepository(t))
+			res, err := usecase.PreviewPayroll(entity.UserContext{Username: "finance"}, 1)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			tt.wantRes(t, res)
+		})
+	}
+}
+
+func Test_PayrollUseCase_GetPayrollSummary(t *testing.T) {
+	approvedAt := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	payslips := []entity.PayrollPayslip{
+		{
+			UserID:             1,
+			BaseSalary:         decimal.NewFromInt(5000000),
+			AttendancePay:      decimal.NewFromInt(4500000),
+			OvertimePay:        decimal.NewFromInt(250000),
+			ReimbursementTotal: decimal.NewFromInt(100000),
+			TotalTakeHome:      decimal.NewFromInt(4850000),
+		},
+		{
+			UserID:             2,
+			BaseSalary:         decimal.NewFromInt(8000000),
+			AttendancePay:      decimal.NewFromInt(8000000),
+			OvertimePay:        decimal.Zero,
+			ReimbursementTotal: decimal.Zero,
+			TotalTakeHome:      decimal.NewFromInt(8000000),
+		},
+		{
+			UserID:             3,
+			BaseSalary:         decimal.NewFromInt(6000000),
+			AttendancePay:      decimal.NewFromInt(5800001),
+			OvertimePay:        decimal.NewFromInt(300000),
+			ReimbursementTotal: decimal.NewFromInt(50000),
+			TotalTakeHome:      decimal.NewFromInt(6150001),
+		},
+	}
+	header := []string{
+		"group", "headcount",
+		"base_salary_total", "base_salary_average", "base_salary_min", "base_salary_max",
+		"attendance_pay_total", "attendance_pay_average", "attendance_pay_min", "attendance_pay_max",
+		"overtime_pay_total", "overtime_pay_average", "overtime_pay_min", "overtime_pay_max",
+		"reimbursement_total_total", "reimbursement_total_average", "reimbursement_total_min", "reimbursement_total_max",
+		"total_take_home_total", "total_take_home_average", "total_take_home_min", "total_take_home_max",
+	}
+	total := []string{
+		"total", "3",
+		"19000000", "6333333.33", "5000000", "8000000",
+		"18300001", "6100000.33", "4500000", "8000000",
+		"550000", "183333.33", "0", "300000",
+		"150000", "50000", "0", "100000",
+		"19000001", "6333333.67", "4850000", "8000000",
+	}
+
+	tests := []struct {
+		name     string
+		groupBy  string
+		mockFunc func(
+			payrollRepository *mocks.PayrollRepository,
+			employeeProfileRepository *mocks.EmployeeProfileRepository,
+			userRepository *mocks.UserRepository,
+		)
+		wantErr error
+		wantRes [][]string
+	}{
+		{
+			name:    "error - unknown group",
+			groupBy: "job_title",
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				userRepository *mocks.UserRepository,
+			) {
+			},
+			wantErr: errors.New("payroll summary can only be grouped by department or role"),
+		},
+		{
+			name: "error - GetPeriodByID",
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				userRepository *mocks.UserRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{}, gorm.ErrSubQueryRequired)
+			},
+			wantErr: gorm.ErrSubQueryRequired,
+		},
+		{
+			name: "error - period is open",
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				userRepository *mocks.UserRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "open"}, nil)
+			},
+			wantErr: errors.New("the payslips of the payroll period are not calculated yet"),
+		},
+		{
+			name:    "error - GetUsersByIDs",
+			groupBy: "role",
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				userRepository *mocks.UserRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "approved"}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{ID: 3, Version: 1, ApprovedAt: &approvedAt}, nil)
+				payrollRepository.On("GetPayslipsByRunID", int64(3)).
+					Return(payslips, nil)
+				userRepository.On("GetUsersByIDs", []int64{1, 2, 3}).
+					Return([]entity.User{}, gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name: "success - all employees",
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				userRepository *mocks.UserRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "approved"}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{ID: 3, Version: 1, ApprovedAt: &approvedAt}, nil)
+				payrollRepository.On("GetPayslipsByRunID", int64(3)).
+					Return(payslips, nil)
+			},
+			wantRes: [][]string{header, total},
+		},
+		{
+			name:    "success - grouped by department, averages keep two decimals",
+			groupBy: "department",
+			mockFunc: func(
+				payrollRepository *mocks.PayrollRepository,
+				employeeProfileRepository *mocks.EmployeeProfileRepository,
+				userRepository *mocks.UserRepository,
+			) {
+				payrollRepository.On("GetPeriodByID", mock.Anything).
+					Return(entity.PayrollPeriod{Status: "approved"}, nil)
+				payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+					Return(entity.PayrollRun{ID: 3, Version: 1, ApprovedAt: &approvedAt}, nil)
+				payrollRepository.On("GetPayslipsByRunID", int64(3)).
+					Return(payslips, nil)
+				employeeProfileRepository.On("GetProfilesByUserIDs", []int64{1, 2, 3}).
+					Return([]entity.EmployeeProfile{
+						{UserID: 1, Department: "Engineering"},
+						{UserID: 2, Department: "Engineering"},
+						{UserID: 3},
+					}, nil)
+			},
+			wantRes: [][]string{
+				header,
+				{
+					"Engineering", "2",
+					"13000000", "6500000", "5000000", "8000000",
+					"12500000", "6250000", "4500000", "8000000",
+					"250000", "125000", "0", "250000",
+					"100000", "50000", "0", "100000",
+					"12850000", "6425000", "4850000", "8000000",
+				},
+				{
+					"unassigned", "1",
+					"6000000", "6000000", "6000000", "6000000",
+					"5800001", "5800001", "5800001", "5800001",
+					"300000", "300000", "300000", "300000",
+					"50000", "50000", "50000", "50000",
+					"6150001", "6150001", "6150001", "6150001",
+				},
+				total,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payrollRepository := mocks.NewPayrollRepository(t)
+			employeeProfileRepository := mocks.NewEmployeeProfileRepository(t)
+			userRepository := mocks.NewUserRepository(t)
+
+			tt.mockFunc(payrollRepository, employeeProfileRepository, userRepository)
+
+			payrollConfig := usecase.PayrollConfig{Rounding: money.RoundingPolicy{Scale: 0, Mode: money.RoundHalfUp}}
+			usecase := usecase.NewPayrollUseCase(payrollConfig, payrollRepository, mocks.NewEmployeeRepository(t), employeeProfileRepository, userRepository, mocks.NewAuditLogRepository(t))
+			res, err := usecase.GetPayrollSummary(entity.UserContext{}, 0, tt.groupBy)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.Equal(t, tt.wantRes, res.CSVRecords())
+				assert.Equal(t, tt.groupBy, res.GroupBy)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_PayrollUseCase_ExportPayrollSummaryCSV(t *testing.T) {
+	approvedAt := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	payrollRepository := mocks.NewPayrollRepository(t)
+	userRepository := mocks.NewUserRepository(t)
+	payrollRepository.On("GetPeriodByID", mock.Anything).
+		Return(entity.PayrollPeriod{Status: "paid"}, nil)
+	payrollRepository.On("GetLatestApprovedPayrollRun", mock.Anything).
+		Return(entity.PayrollRun{ID: 3, Version: 1, ApprovedAt: &approvedAt}, nil)
+	payrollRepository.On("GetPayslipsByRunID", int64(3)).
+		Return([]entity.PayrollPayslip{
+			{UserID: 1, BaseSalary: decimal.NewFromInt(5000000), AttendancePay: decimal.NewFromInt(4500000), OvertimePay: decimal.NewFromInt(250000), ReimbursementTotal: decimal.NewFromInt(100000), TotalTakeHome: decimal.NewFromInt(4850000)},
+			{UserID: 2, BaseSalary: decimal.NewFromInt(8000000), AttendancePay: decimal.NewFromInt(8000000), OvertimePay: decimal.Zero, ReimbursementTotal: decimal.Zero, TotalTakeHome: decimal.NewFromInt(8000000)},
+		}, nil)
+	userRepository.On("GetUsersByIDs", []int64{1, 2}).
+		Return([]entity.User{{ID: 1, Role: "@SUM(1+1)"}}, nil)
+
+	usecase := usecase.NewPayrollUseCase(usecase.PayrollConfig{}, payrollRepository, mocks.NewEmployeeRepository(t), mocks.NewEmployeeProfileRepository(t), userRepository, mocks.NewAuditLogRepository(t))
+	// a group name a spreadsheet would run as a formula is quoted
+	res, err := usecase.ExportPayrollSummaryCSV(entity.UserContext{}, 0, "role")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "group,headcount,"+
+		"base_salary_total,base_salary_average,base_salary_min,base_salary_max,"+
+		"attendance_pay_total,attendance_pay_average,attendance_pay_min,attendance_pay_max,"+
+		"overtime_pay_total,overtime_pay_average,overtime_pay_min,overtime_pay_max,"+
+		"reimbursement_total_total,reimbursement_total_average,reimbursement_total_min,reimbursement_total_max,"+
+		"total_take_home_total,total_take_home_average,total_take_home_min,total_take_home_max\n"+
+		"'@SUM(1+1),1,5000000,5000000,5000000,5000000,4500000,4500000,4500000,4500000,250000,250000,250000,250000,100000,100000,100000,100000,4850000,4850000,4850000,4850000\n"+
+		"unassigned,1,8000000,8000000,8000000,8000000,8000000,8000000,8000000,8000000,0,0,0,0,0,0,0,0,8000000,8000000,8000000,8000000\n"+
+		"total,2,13000000,6500000,5000000,8000000,12500000,6250000,4500000,8000000,250000,125000,0,250000,100000,50000,0,100000,12850000,6425000,4850000,8000000\n",
+		string(res))
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/eafajri/hr-service.git/internal/cache"
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"go.uber.org/zap"
+)
+
+//go:generate mockery --name PermissionUseCase --output ./mocks
+type PermissionUseCase interface {
+	GetPermissionsByRole(role entity.UserRole) ([]entity.Permission, error)
+	ListRolePermissions() (map[entity.UserRole][]entity.Permission, error)
+
+	GrantPermission(userContext entity.UserContext, role entity.UserRole, permission entity.Permission) error
+	RevokePermission(userContext entity.UserContext, role entity.UserRole, permission entity.Permission) error
+}
+
+type PermissionUseCaseImpl struct {
+	rolePermissionRepository RolePermissionRepository
+	auditLogRepository       AuditLogRepository
+	memoryCache              cache.MemoryCache
+}
+
+func NewPermissionUseCase(
+	rolePermissionRepository RolePermissionRepository,
+	auditLogRepository AuditLogRepository,
+	memoryCache cache.MemoryCache,
+) *PermissionUseCaseImpl {
+	return &PermissionUseCaseImpl{
+		rolePermissionRepository: rolePermissionRepository,
+		auditLogRepository:       auditLogRepository,
+		memoryCache:              memoryCache,
+	}
+}
+
+/*
+Permissions are resolved on every authenticated request, so they are served from memory
+and only reloaded after an assignment for that role changes.
+*/
+func (p *PermissionUseCaseImpl) GetPermissionsByRole(role entity.UserRole) ([]entity.Permission, error) {
+	permissions, err := p.memoryCache.GetOrLoad(rolePermissionCacheKey(role), func() (interface{}, error) {
+		return p.rolePermissionRepository.GetPermissionsByRole(role)
+	})
+	if err != nil {
+		log.Println(
+			"error when GetPermissionsByRole",
+			zap.String("method", "PermissionUseCaseImpl.GetPermissionsByRole"),
+			zap.String("role", string(role)),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return permissions.([]entity.Permission), nil
+}
+
+func (p *PermissionUseCaseImpl) ListRolePermissions() (map[entity.UserRole][]entity.Permission, error) {
+	rolePermissions, err := p.rolePermissionRepository.GetAllRolePermissions()
+	if err != nil {
+		log.Println(
+			"error when GetAllRolePermissions",
+			zap.String("method", "PermissionUseCaseImpl.ListRolePermissions"),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	result := make(map[entity.UserRole][]entity.Permission, len(entity.AllRoles))
+	for _, role := range entity.AllRoles {
+		result[role] = []entity.Permission{}
+	}
+	result[entity.RoleAdmin] = entity.AllPermissions
+
+	for _, rolePermission := range rolePermissions {
+		if rolePermission.Role == entity.RoleAdmin {
+			continue
+		}
+		result[rolePermission.Role] = append(result[rolePermission.Role], rolePermission.Permission)
+	}
+
+	return result, nil
+}
+
+func (p *PermissionUseCaseImpl) GrantPermission(userContext entity.UserContext, role entity.UserRole, permission entity.Permission) error {
+	if err := p.validateAssignment(role, permission); err != nil {
+		return err
+	}
+
+	rolePermission := entity.RolePermission{
+		Role:       role,
+		Permission: permission,
+		CreatedAt:  time.Now(),
+		CreatedBy:  userContext.Username,
+	}
+
+	err := p.rolePermissionRepository.CreateRolePermission(rolePermission)
+	if err != nil {
+		log.Println(
+			"error when CreateRolePermission",
+			zap.String("method", "PermissionUseCaseImpl.GrantPermission"),
+			zap.Any("user_contex", userContext),
+			zap.Any("role_permission", rolePermission),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	p.memoryCache.Delete(rolePermissionCacheKey(role))
+
+	p.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "grant",
+		Target:    "permission",
+		TableName: "role_permissions",
+		CreatedBy: userContext.Username,
+	}, rolePermission)
+
+	return nil
+}
+
+func (p *PermissionUseCaseImpl) RevokePermission(userContext entity.UserContext, role entity.UserRole, permission entity.Permission) error {
+	if err := p.validateAssignment(role, permission); err != nil {
+		return err
+	}
+
+	err := p.rolePermissionRepository.DeleteRolePermission(role, permission)
+	if err != nil {
+		log.Println(
+			"error when DeleteRolePermission",
+			zap.String("method", "PermissionUseCaseImpl.RevokePermission"),
+			zap.Any("user_contex", userContext),
+			zap.String("role", string(role)),
+			zap.String("permission", string(permission)),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	p.memoryCache.Delete(rolePermissionCacheKey(role))
+
+	p.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "revoke",
+		Target:    "permission",
+		TableName: "role_permissions",
+		CreatedBy: userContext.Username,
+	}, entity.RolePermission{Role: role, Permission: permission})
+
+	return nil
+}
+
+func (p *PermissionUseCaseImpl) validateAssignment(role entity.UserRole, permission entity.Permission) error {
+	if !role.IsValid() {
+		return errors.New("invalid role")
+	}
+
+	if role == entity.RoleAdmin {
+		return errors.New("admin permissions cannot be changed")
+	}
+
+	if !permission.IsValid() {
+		return errors.New("invalid permission")
+	}
+
+	return nil
+}
+
+func rolePermissionCacheKey(role entity.UserRole) string {
+	return "permission:role:" + string(role)
+}
@@ -0,0 +1,161 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eafajri/hr-service.git/internal/cache"
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func Test_PermissionUseCase_GetPermissionsByRole(t *testing.T) {
+	rolePermissionRepository := mocks.NewRolePermissionRepository(t)
+	auditLogRepository := mocks.NewAuditLogRepository(t)
+
+	rolePermissionRepository.On("GetPermissionsByRole", entity.RoleFinance).
+		Return([]entity.Permission{entity.PermissionPayrollGenerate}, nil).Once()
+	rolePermissionRepository.On("GetPermissionsByRole", entity.RoleHR).
+		Return(nil, gorm.ErrInvalidDB).Once()
+
+	permissionUc := usecase.NewPermissionUseCase(rolePermissionRepository, auditLogRepository, cache.NewMemoryCache(cache.Config{}))
+
+	// second call must be served from the cache
+	for i := 0; i < 2; i++ {
+		permissions, err := permissionUc.GetPermissionsByRole(entity.RoleFinance)
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.Permission{entity.PermissionPayrollGenerate}, permissions)
+	}
+
+	_, err := permissionUc.GetPermissionsByRole(entity.RoleHR)
+	assert.Equal(t, gorm.ErrInvalidDB, err)
+}
+
+func Test_PermissionUseCase_GrantPermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       entity.UserRole
+		permission entity.Permission
+		mockFunc   func(
+			rolePermissionRepository *mocks.RolePermissionRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+	}{
+		{
+			name:       "error - invalid role",
+			role:       "owner",
+			permission: entity.PermissionPayrollGenerate,
+			mockFunc: func(
+				rolePermissionRepository *mocks.RolePermissionRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("invalid role"),
+		},
+		{
+			name:       "error - admin role",
+			role:       entity.RoleAdmin,
+			permission: entity.PermissionPayrollGenerate,
+			mockFunc: func(
+				rolePermissionRepository *mocks.RolePermissionRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("admin permissions cannot be changed"),
+		},
+		{
+			name:       "error - invalid permission",
+			role:       entity.RoleFinance,
+			permission: "payroll.delete",
+			mockFunc: func(
+				rolePermissionRepository *mocks.RolePermissionRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("invalid permission"),
+		},
+		{
+			name:       "error - CreateRolePermission",
+			role:       entity.RoleFinance,
+			permission: entity.PermissionPayrollGenerate,
+			mockFunc: func(
+				rolePermissionRepository *mocks.RolePermissionRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				rolePermissionRepository.On("CreateRolePermission", mock.Anything).Return(gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name:       "success",
+			role:       entity.RoleFinance,
+			permission: entity.PermissionPayrollGenerate,
+			mockFunc: func(
+				rolePermissionRepository *mocks.RolePermissionRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				rolePermissionRepository.On("CreateRolePermission", mock.MatchedBy(func(rolePermission entity.RolePermission) bool {
+					return rolePermission.Role == entity.RoleFinance && rolePermission.Permission == entity.PermissionPayrollGenerate
+				})).Return(nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolePermissionRepository := mocks.NewRolePermissionRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			tt.mockFunc(rolePermissionRepository, auditLogRepository)
+
+			usecase := usecase.NewPermissionUseCase(rolePermissionRepository, auditLogRepository, cache.NewMemoryCache(cache.Config{}))
+			err := usecase.GrantPermission(entity.UserContext{Username: "admin"}, tt.role, tt.permission)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_PermissionUseCase_RevokePermission_InvalidatesCache(t *testing.T) {
+	rolePermissionRepository := mocks.NewRolePermissionRepository(t)
+	auditLogRepository := mocks.NewAuditLogRepository(t)
+
+	rolePermissionRepository.On("GetPermissionsByRole", entity.RoleFinance).
+		Return([]entity.Permission{entity.PermissionPayrollGenerate}, nil).Once()
+	rolePermissionRepository.On("DeleteRolePermission", entity.RoleFinance, entity.PermissionPayrollGenerate).
+		Return(nil)
+	rolePermissionRepository.On("GetPermissionsByRole", entity.RoleFinance).
+		Return([]entity.Permission{}, nil).Once()
+	auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	permissionUc := usecase.NewPermissionUseCase(rolePermissionRepository, auditLogRepository, cache.NewMemoryCache(cache.Config{}))
+
+	permissions, err := permissionUc.GetPermissionsByRole(entity.RoleFinance)
+	assert.NoError(t, err)
+	assert.Len(t, permissions, 1)
+
+	err = permissionUc.RevokePermission(entity.UserContext{Username: "admin"}, entity.RoleFinance, entity.PermissionPayrollGenerate)
+	assert.NoError(t, err)
+
+	permissions, err = permissionUc.GetPermissionsByRole(entity.RoleFinance)
+	assert.NoError(t, err)
+	assert.Empty(t, permissions)
+}
+
+func Test_UserContext_HasPermission(t *testing.T) {
+	admin := entity.UserContext{Role: entity.RoleAdmin}
+	assert.True(t, admin.HasPermission(entity.PermissionRoleManage))
+
+	finance := entity.UserContext{Role: entity.RoleFinance, Permissions: []entity.Permission{entity.PermissionPayrollGenerate}}
+	assert.True(t, finance.HasPermission(entity.PermissionPayrollGenerate))
+	assert.False(t, finance.HasPermission(entity.PermissionUserManage))
+}
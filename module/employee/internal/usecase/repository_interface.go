@@ -10,6 +10,29 @@ import (
 type UserRepository interface {
 	GetUserByID(userID int64) (entity.User, error)
 	GetUserByUsername(username string) (entity.User, error)
+	GetUsersByIDs(userIDs []int64) ([]entity.User, error)
+	ListUsers(filter entity.UserFilter, pagination entity.Pagination) ([]entity.User, int64, error)
+
+	CreateUser(user entity.User) (entity.User, error)
+	UpdateUser(userID int64, updates map[string]interface{}) error
+}
+
+//go:generate mockery --name UserSessionRepository --output ./mocks
+type UserSessionRepository interface {
+	CreateSession(session entity.UserSession) error
+	GetSessionByID(sessionID string) (entity.UserSession, error)
+	RevokeSession(sessionID string, revokedAt time.Time) error
+	RevokeSessionsByUserID(userID int64, revokedAt time.Time) error
+	GetSessionsRevokedSince(since time.Time) ([]entity.UserSession, error)
+}
+
+//go:generate mockery --name RolePermissionRepository --output ./mocks
+type RolePermissionRepository interface {
+	GetPermissionsByRole(role entity.UserRole) ([]entity.Permission, error)
+	GetAllRolePermissions() ([]entity.RolePermission, error)
+
+	CreateRolePermission(rolePermission entity.RolePermission) error
+	DeleteRolePermission(role entity.UserRole, permission entity.Permission) error
 }
 
 //go:generate mockery --name EmployeeRepository --output ./mocks
@@ -19,26 +42,108 @@ type EmployeeRepository interface {
 	UpsertReimbursement(record entity.EmployeeReimbursement) error
 
 	GetAttendanceByUserAndDate(userID int64, date time.Time) (entity.EmployeeAttendance, error)
+	GetHolidayByDate(date time.Time) (entity.Holiday, error)
+	GetAllHolidaysByTimeRange(startTime time.Time, endTime time.Time) ([]entity.Holiday, error)
 
 	GetAllAttendanceByTimeRange(startTime time.Time, endTime time.Time, userID *int64) ([]entity.EmployeeAttendance, error)
 	GetAllOvertimeByTimeRange(startTime time.Time, endTime time.Time, userID *int64) ([]entity.EmployeeOvertime, error)
 	GetAllReimbursementByTimeRange(startTime time.Time, endTime time.Time, userID *int64) ([]entity.EmployeeReimbursement, error)
+	GetAllPayComponentsByTimeRange(startTime time.Time, endTime time.Time, userID *int64) ([]entity.EmployeePayComponent, error)
+	GetAllActiveDeductions(periodEnd time.Time, userID *int64) ([]entity.EmployeeDeduction, error)
+	GetDeductionsByIDs(deductionIDs []int64) ([]entity.EmployeeDeduction, error)
 
-	GetEmployeeBaseSalaryByPeriodStart(periodStartTime time.Time, userID *int64) ([]entity.EmployeeBaseSalary, error)
+	GetEmployeeBaseSalaryByPeriod(periodStart time.Time, periodEnd time.Time, userID *int64) ([]entity.EmployeeBaseSalary, error)
+}
+
+//go:generate mockery --name EmployeeProfileRepository --output ./mocks
+type EmployeeProfileRepository interface {
+	GetProfileByUserID(userID int64) (entity.EmployeeProfile, error)
+	GetProfileByEmployeeNumber(employeeNumber string) (entity.EmployeeProfile, error)
+	GetProfilesByUserIDs(userIDs []int64) ([]entity.EmployeeProfile, error)
+	GetProfilesEmployedBetween(startTime time.Time, endTime time.Time) ([]entity.EmployeeProfile, error)
+	ListProfiles(filter entity.EmployeeProfileFilter, pagination entity.Pagination) ([]entity.EmployeeProfile, int64, error)
+
+	CreateProfile(profile entity.EmployeeProfile) (entity.EmployeeProfile, error)
+	UpdateProfile(userID int64, updates map[string]interface{}) error
+	DeleteProfile(userID int64) error
+}
+
+//go:generate mockery --name SalaryRepository --output ./mocks
+type SalaryRepository interface {
+	GetSalariesByUserID(userID int64) ([]entity.UserSalary, error)
+
+	CreateSalary(salary entity.UserSalary) (entity.UserSalary, error)
+}
+
+//go:generate mockery --name PayComponentRepository --output ./mocks
+type PayComponentRepository interface {
+	GetComponentByID(componentID int64) (entity.PayComponent, error)
+	GetComponentByCode(code string) (entity.PayComponent, error)
+	ListComponents() ([]entity.PayComponent, error)
+
+	CreateComponent(component entity.PayComponent) (entity.PayComponent, error)
+	UpdateComponent(componentID int64, updates map[string]interface{}) error
+
+	GetAssignmentByID(assignmentID int64) (entity.EmployeePayComponent, error)
+	GetAssignmentsByUserID(userID int64) ([]entity.EmployeePayComponent, error)
+
+	CreateAssignment(assignment entity.EmployeePayComponent) (entity.EmployeePayComponent, error)
+	UpdateAssignment(assignmentID int64, updates map[string]interface{}) error
 }
 
 //go:generate mockery --name PayrollRepository --output ./mocks
 type PayrollRepository interface {
 	GetPeriodByID(periodID int64) (entity.PayrollPeriod, error)
 	GetPeriodByEntityDate(date time.Time) (entity.PayrollPeriod, error)
-	GetPayslip(userID int64, periodID int64) (entity.PayrollPayslip, error)
+	GetLatestClosedPeriod() (entity.PayrollPeriod, error)
+	ListPeriods(status string) ([]entity.PayrollPeriod, error)
+	GetOverlappingPeriods(startTime time.Time, endTime time.Time, excludeID int64) ([]entity.PayrollPeriod, error)
 	GetPayslips(periodID int64) ([]entity.PayrollPayslip, error)
+	GetPayslipByRunID(runID int64, userID int64) (entity.PayrollPayslip, error)
+	GetPayslipsByRunID(runID int64) ([]entity.PayrollPayslip, error)
+	GetPayslipsByTimeRange(startTime time.Time, endTime time.Time, userID *int64) ([]entity.PayrollPayslip, error)
+	GetCurrentPayrollRun(periodID int64) (entity.PayrollRun, error)
+	GetLatestApprovedPayrollRun(periodID int64) (entity.PayrollRun, error)
+	GetPayrollRunByVersion(periodID int64, version int) (entity.PayrollRun, error)
+	ListPayrollRuns(periodID int64) ([]entity.PayrollRun, error)
+
+	CreatePeriods(periods []entity.PayrollPeriod) ([]entity.PayrollPeriod, error)
+	UpdatePeriod(periodID int64, status entity.PayrollPeriodStatus, updates map[string]interface{}) error
+	UpdatePeriodStatus(periodID int64, from entity.PayrollPeriodStatus, to entity.PayrollPeriodStatus, updatedBy string) error
+	ReopenPayrollPeriod(periodID int64, reopenedBy string) ([]int64, []entity.EmployeeDeduction, error)
+	CreatePayrollRun(run entity.PayrollRun, payslips []entity.PayrollPayslip, deductions []entity.EmployeeDeduction, auditLog entity.AuditLog) (entity.PayrollRun, error)
+	RerunPayroll(previousRunID int64, from entity.PayrollPeriodStatus, run entity.PayrollRun, payslips []entity.PayrollPayslip, deductions []entity.EmployeeDeduction, auditLog entity.AuditLog) (entity.PayrollRun, error)
+
+	GetStatutoryRatesEffectiveOn(date time.Time) (entity.StatutoryRates, error)
 
-	ClosePayrollPeriod(periodID int64) error
-	CreatePayslipsByPeriod(payslips []entity.PayrollPayslip) error
+	GetPolicyByID(policyID int64) (entity.PayrollPolicy, error)
+	GetPolicyEffectiveOn(date time.Time) (entity.PayrollPolicy, error)
+	GetLatestPolicy() (entity.PayrollPolicy, error)
+	ListPolicies() ([]entity.PayrollPolicy, error)
+	CreatePolicy(policy entity.PayrollPolicy) (entity.PayrollPolicy, error)
 }
 
 //go:generate mockery --name AuditLogRepository --output ./mocks
 type AuditLogRepository interface {
 	Create(log entity.AuditLog, payload any) error
 }
+
+//go:generate mockery --name DeductionRepository --output ./mocks
+type DeductionRepository interface {
+	GetDeductionByID(deductionID int64) (entity.EmployeeDeduction, error)
+	GetDeductionsByUserID(userID int64) ([]entity.EmployeeDeduction, error)
+
+	CreateDeduction(deduction entity.EmployeeDeduction) (entity.EmployeeDeduction, error)
+	UpdateDeduction(deductionID int64, updates map[string]interface{}) error
+}
+
+//go:generate mockery --name HolidayRepository --output ./mocks
+type HolidayRepository interface {
+	GetHolidayByID(holidayID int64) (entity.Holiday, error)
+	GetHolidaysByTimeRange(startTime time.Time, endTime time.Time) ([]entity.Holiday, error)
+
+	CreateHoliday(holiday entity.Holiday) (entity.Holiday, error)
+	UpdateHoliday(holidayID int64, updates map[string]interface{}) error
+	DeleteHoliday(holidayID int64) error
+	UpsertHolidays(holidays []entity.Holiday) ([]entity.Holiday, error)
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/eafajri/hr-service.git/internal/money"
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name SalaryUseCase --output ./mocks
+type SalaryUseCase interface {
+	GetSalaryHistory(userID int64) ([]entity.UserSalary, error)
+
+	RecordSalaryChange(userContext entity.UserContext, userID int64, request entity.SalaryChangeRequest) (entity.UserSalary, error)
+}
+
+type SalaryUseCaseImpl struct {
+	salaryRepository   SalaryRepository
+	userRepository     UserRepository
+	payrollRepository  PayrollRepository
+	auditLogRepository AuditLogRepository
+}
+
+func NewSalaryUseCase(
+	salaryRepository SalaryRepository,
+	userRepository UserRepository,
+	payrollRepository PayrollRepository,
+	auditLogRepository AuditLogRepository,
+) *SalaryUseCaseImpl {
+	return &SalaryUseCaseImpl{
+		salaryRepository:   salaryRepository,
+		userRepository:     userRepository,
+		payrollRepository:  payrollRepository,
+		auditLogRepository: auditLogRepository,
+	}
+}
+
+/*
+The history is ordered from the most recent effective date, the first entry is the one currently in effect
+unless it is dated in the future.
+*/
+func (s *SalaryUseCaseImpl) GetSalaryHistory(userID int64) ([]entity.UserSalary, error) {
+	if err := s.ensureUserExists("SalaryUseCaseImpl.GetSalaryHistory", userID); err != nil {
+		return nil, err
+	}
+
+	salaries, err := s.salaryRepository.GetSalariesByUserID(userID)
+	if err != nil {
+		log.Println(
+			"error when GetSalariesByUserID",
+			zap.String("method", "SalaryUseCaseImpl.GetSalaryHistory"),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return salaries, nil
+}
+
+/*
+Salary changes are never edited in place, a correction is recorded as a new change with the same effective date
+and the latest recorded one wins.
+A change cannot take effect on or before the end of the latest closed period, since the payslips of that period
+were already finalized with the previous salary.
+*/
+func (s *SalaryUseCaseImpl) RecordSalaryChange(userContext entity.UserContext, userID int64, request entity.SalaryChangeRequest) (entity.UserSalary, error) {
+	if !request.Amount.IsPositive() {
+		return entity.UserSalary{}, errors.New("salary amount must be greater than zero")
+	}
+
+	if !money.FitsScale(request.Amount) {
+		return entity.UserSalary{}, errors.New("salary amount cannot have more than 2 decimal places")
+	}
+
+	effectiveFrom, err := time.Parse("2006-01-02", request.EffectiveFrom)
+	if err != nil {
+		return entity.UserSalary{}, errors.New("invalid effective date format, must be YYYY-MM-DD")
+	}
+
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		return entity.UserSalary{}, errors.New("reason is required")
+	}
+
+	if err := s.ensureUserExists("SalaryUseCaseImpl.RecordSalaryChange", userID); err != nil {
+		return entity.UserSalary{}, err
+	}
+
+	closedPeriod, err := s.payrollRepository.GetLatestClosedPeriod()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println(
+			"error when GetLatestClosedPeriod",
+			zap.String("method", "SalaryUseCaseImpl.RecordSalaryChange"),
+			zap.Any("user_contex", userContext),
+			zap.Error(err),
+		)
+		return entity.UserSalary{}, err
+	}
+	if err == nil && !effectiveFrom.After(closedPeriod.PeriodEnd) {
+		return entity.UserSalary{}, fmt.Errorf(
+			"effective date must be after %s, the end of the latest closed payroll period",
+			closedPeriod.PeriodEnd.Format("2006-01-02"),
+		)
+	}
+
+	salary, err := s.salaryRepository.CreateSalary(entity.UserSalary{
+		UserID:        userID,
+		Amount:        request.Amount,
+		EffectiveFrom: effectiveFrom,
+		Reason:        reason,
+		CreatedBy:     userContext.Username,
+	})
+	if err != nil {
+		log.Println(
+			"error when CreateSalary",
+			zap.String("method", "SalaryUseCaseImpl.RecordSalaryChange"),
+			zap.Any("user_contex", userContext),
+			zap.Int64("user_id", userID),
+			zap.Any("request", request),
+			zap.Error(err),
+		)
+		return entity.UserSalary{}, err
+	}
+
+	s.auditLogRepository.Create(entity.AuditLog{
+		RequestID:     userContext.RequestID,
+		IPAddress:     userContext.IPAddress,
+		Action:        "create",
+		Target:        "salary",
+		TableName:     "user_salaries",
+		CreatedBy:     userContext.Username,
+		SubjectUserID: &userID,
+	}, salary)
+
+	return salary, nil
+}
+
+func (s *SalaryUseCaseImpl) ensureUserExists(method string, userID int64) error {
+	_, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		log.Println(
+			"error when GetUserByID",
+			zap.String("method", method),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
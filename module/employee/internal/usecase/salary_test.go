@@ -0,0 +1,195 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func Test_SalaryUseCase_RecordSalaryChange(t *testing.T) {
+	validRequest := entity.SalaryChangeRequest{
+		Amount:        decimal.NewFromInt(7500000),
+		EffectiveFrom: "2025-07-01",
+		Reason:        " Annual review ",
+	}
+	closedPeriod := entity.PayrollPeriod{
+		ID:          6,
+		PeriodStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		Status:      entity.PayrollStatusLocked,
+	}
+
+	tests := []struct {
+		name     string
+		request  entity.SalaryChangeRequest
+		mockFunc func(
+			salaryRepository *mocks.SalaryRepository,
+			userRepository *mocks.UserRepository,
+			payrollRepository *mocks.PayrollRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+		)
+		wantErr error
+		wantRes entity.UserSalary
+	}{
+		{
+			name:    "error - amount not positive",
+			request: entity.SalaryChangeRequest{EffectiveFrom: "2025-07-01", Reason: "Annual review"},
+			mockFunc: func(
+				salaryRepository *mocks.SalaryRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("salary amount must be greater than zero"),
+		},
+		{
+			name:    "error - more decimal places than stored",
+			request: entity.SalaryChangeRequest{Amount: decimal.RequireFromString("7500000.005"), EffectiveFrom: "2025-07-01", Reason: "Annual review"},
+			mockFunc: func(
+				salaryRepository *mocks.SalaryRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("salary amount cannot have more than 2 decimal places"),
+		},
+		{
+			name:    "error - missing reason",
+			request: entity.SalaryChangeRequest{Amount: decimal.NewFromInt(7500000), EffectiveFrom: "2025-07-01", Reason: " "},
+			mockFunc: func(
+				salaryRepository *mocks.SalaryRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+			},
+			wantErr: errors.New("reason is required"),
+		},
+		{
+			name:    "error - user not found",
+			request: validRequest,
+			mockFunc: func(
+				salaryRepository *mocks.SalaryRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("user not found"),
+		},
+		{
+			name:    "error - back-dated into a closed period",
+			request: entity.SalaryChangeRequest{Amount: decimal.NewFromInt(7500000), EffectiveFrom: "2025-06-30", Reason: "Annual review"},
+			mockFunc: func(
+				salaryRepository *mocks.SalaryRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{ID: 7}, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(closedPeriod, nil)
+			},
+			wantErr: errors.New("effective date must be after 2025-06-30, the end of the latest closed payroll period"),
+		},
+		{
+			name:    "error - CreateSalary",
+			request: validRequest,
+			mockFunc: func(
+				salaryRepository *mocks.SalaryRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{ID: 7}, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(closedPeriod, nil)
+				salaryRepository.On("CreateSalary", mock.Anything).Return(entity.UserSalary{}, gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name:    "success - no closed period yet",
+			request: entity.SalaryChangeRequest{Amount: decimal.NewFromInt(7500000), EffectiveFrom: "2023-01-01", Reason: "Initial salary"},
+			mockFunc: func(
+				salaryRepository *mocks.SalaryRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{ID: 7}, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(entity.PayrollPeriod{}, gorm.ErrRecordNotFound)
+				salaryRepository.On("CreateSalary", mock.Anything).
+					Return(func(salary entity.UserSalary) (entity.UserSalary, error) {
+						return salary, nil
+					})
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantRes: entity.UserSalary{
+				UserID:        7,
+				Amount:        decimal.NewFromInt(7500000),
+				EffectiveFrom: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+				Reason:        "Initial salary",
+				CreatedBy:     "hr",
+			},
+		},
+		{
+			name:    "success",
+			request: validRequest,
+			mockFunc: func(
+				salaryRepository *mocks.SalaryRepository,
+				userRepository *mocks.UserRepository,
+				payrollRepository *mocks.PayrollRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+			) {
+				userRepository.On("GetUserByID", int64(7)).Return(entity.User{ID: 7}, nil)
+				payrollRepository.On("GetLatestClosedPeriod").Return(closedPeriod, nil)
+				salaryRepository.On("CreateSalary", mock.Anything).
+					Return(func(salary entity.UserSalary) (entity.UserSalary, error) {
+						salary.ID = 11
+						return salary, nil
+					})
+				auditLogRepository.On("Create", mock.MatchedBy(func(log entity.AuditLog) bool {
+					return log.TableName == "user_salaries" && log.SubjectUserID != nil && *log.SubjectUserID == 7
+				}), mock.Anything).Return(nil)
+			},
+			wantRes: entity.UserSalary{
+				ID:            11,
+				UserID:        7,
+				Amount:        decimal.NewFromInt(7500000),
+				EffectiveFrom: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+				Reason:        "Annual review",
+				CreatedBy:     "hr",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salaryRepository := mocks.NewSalaryRepository(t)
+			userRepository := mocks.NewUserRepository(t)
+			payrollRepository := mocks.NewPayrollRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+
+			tt.mockFunc(salaryRepository, userRepository, payrollRepository, auditLogRepository)
+
+			usecase := usecase.NewSalaryUseCase(salaryRepository, userRepository, payrollRepository, auditLogRepository)
+			res, err := usecase.RecordSalaryChange(entity.UserContext{Username: "hr"}, 7, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.Equal(t, tt.wantRes, res)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
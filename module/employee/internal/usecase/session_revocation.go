@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+revokedSessions is the list of sessions revoked within the lifetime of an access token, it lets access tokens be
+checked without a query per request. user_sessions stays the source of truth: the list is loaded from it on the
+first check and refreshed every syncInterval, so revocations survive a restart and reach every instance.
+Entries are kept until the access tokens of their session have expired, they are never evicted before that.
+*/
+type revokedSessions struct {
+	mu     sync.RWMutex
+	syncMu sync.Mutex
+
+	userSessionRepository UserSessionRepository
+	accessTokenTTL        time.Duration
+	syncInterval          time.Duration
+
+	lastSync time.Time
+	// session ID to the time its last access token expires
+	sessions map[string]time.Time
+}
+
+func newRevokedSessions(userSessionRepository UserSessionRepository, accessTokenTTL time.Duration, syncInterval time.Duration) *revokedSessions {
+	if syncInterval <= 0 {
+		syncInterval = 5 * time.Second
+	}
+
+	return &revokedSessions{
+		userSessionRepository: userSessionRepository,
+		accessTokenTTL:        accessTokenTTL,
+		syncInterval:          syncInterval,
+		sessions:              make(map[string]time.Time),
+	}
+}
+
+func (r *revokedSessions) add(sessionID string, revokedAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sessions[sessionID] = revokedAt.Add(r.accessTokenTTL)
+}
+
+// contains refreshes a stale list before looking the session up, the error is the failed refresh.
+func (r *revokedSessions) contains(sessionID string, now time.Time) (bool, error) {
+	err := r.sync(now, false)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revokedUntil, ok := r.sessions[sessionID]
+	return ok && now.Before(revokedUntil), err
+}
+
+/*
+sync merges the sessions revoked within an access token lifetime into the list and drops the expired ones.
+Until the first sync every check waits for it, afterwards one request refreshes while the others keep using the
+current list. force refreshes even when the list is not stale, after revocations written by this instance.
+*/
+func (r *revokedSessions) sync(now time.Time, force bool) error {
+	if !force && !r.isStale(now) {
+		return nil
+	}
+
+	if r.isLoaded() && !force {
+		if !r.syncMu.TryLock() {
+			return nil
+		}
+	} else {
+		r.syncMu.Lock()
+	}
+	defer r.syncMu.Unlock()
+
+	if !force && !r.isStale(now) {
+		return nil
+	}
+
+	sessions, err := r.userSessionRepository.GetSessionsRevokedSince(now.Add(-r.accessTokenTTL))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// A failed refresh is retried after the interval as well, so an unavailable database is not queried on every request.
+	r.lastSync = now
+	if err != nil {
+		return err
+	}
+
+	for sessionID, revokedUntil := range r.sessions {
+		if !now.Before(revokedUntil) {
+			delete(r.sessions, sessionID)
+		}
+	}
+
+	for _, session := range sessions {
+		if session.RevokedAt != nil {
+			r.sessions[session.ID] = session.RevokedAt.Add(r.accessTokenTTL)
+		}
+	}
+	return nil
+}
+
+func (r *revokedSessions) isStale(now time.Time) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.lastSync.IsZero() || now.Sub(r.lastSync) >= r.syncInterval
+}
+
+func (r *revokedSessions) isLoaded() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return !r.lastSync.IsZero()
+}
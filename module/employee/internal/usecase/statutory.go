@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"log"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"go.uber.org/zap"
+)
+
+// getStatutoryRatesEffectiveOn loads the contribution and income tax tables in effect on the date, payslips use the first day of the period.
+func getStatutoryRatesEffectiveOn(payrollRepository PayrollRepository, method string, date time.Time) (entity.StatutoryRates, error) {
+	rates, err := payrollRepository.GetStatutoryRatesEffectiveOn(date)
+	if err != nil {
+		log.Println(
+			"error when GetStatutoryRatesEffectiveOn",
+			zap.String("method", method),
+			zap.Time("date", date),
+			zap.Error(err),
+		)
+		return entity.StatutoryRates{}, err
+	}
+	return rates, nil
+}
+
+/*
+getIncomeTaxYearToDate sums the payslips of the earlier periods of the tax year for the annual reconciliation, the
+periods paid since January of the year the period ends in (see PayrollPeriod.TaxYear).
+It is only needed when PPh 21 is configured and the period is the final one of at least one of the employees.
+*/
+func getIncomeTaxYearToDate(payrollRepository PayrollRepository, method string, periodDetails entity.PayrollPeriod, userID *int64) (map[int64]entity.IncomeTaxYearToDate, error) {
+	yearStart := time.Date(periodDetails.TaxYear(), time.January, 1, 0, 0, 0, 0, periodDetails.PeriodEnd.Location())
+
+	payslips, err := payrollRepository.GetPayslipsByTimeRange(yearStart, periodDetails.PeriodStart.AddDate(0, 0, -1), userID)
+	if err != nil {
+		log.Println(
+			"error when GetPayslipsByTimeRange",
+			zap.String("method", method),
+			zap.Int64("period_id", periodDetails.ID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	payslipsMap := make(map[int64][]entity.PayrollPayslip)
+	for _, payslip := range payslips {
+		payslipsMap[payslip.UserID] = append(payslipsMap[payslip.UserID], payslip)
+	}
+
+	yearToDateMap := make(map[int64]entity.IncomeTaxYearToDate, len(payslipsMap))
+	for userID, userPayslips := range payslipsMap {
+		yearToDateMap[userID] = entity.NewIncomeTaxYearToDate(userPayslips)
+	}
+	return yearToDateMap, nil
+}
@@ -1,19 +1,43 @@
 package usecase
 
-import "github.com/eafajri/hr-service.git/module/employee/internal/entity"
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const minPasswordLength = 8
 
 //go:generate mockery --name UserUseCase --output ./mocks
 type UserUseCase interface {
 	GetUserByUsernaname(username string) (entity.User, error)
+	GetUserByID(userID int64) (entity.User, error)
+	ListUsers(filter entity.UserFilter) (entity.UserList, error)
+
+	CreateUser(userContext entity.UserContext, request entity.CreateUserRequest) (entity.User, error)
+	DeactivateUser(userContext entity.UserContext, userID int64) error
+	ResetPassword(userContext entity.UserContext, userID int64, request entity.ResetPasswordRequest) error
+	ChangeRole(userContext entity.UserContext, userID int64, request entity.ChangeRoleRequest) error
 }
 
 type UserUseCaseImpl struct {
-	userRepository UserRepository
+	userRepository     UserRepository
+	auditLogRepository AuditLogRepository
+	permissionUseCase  PermissionUseCase
 }
 
-func NewUserUseCase(userRepository UserRepository) *UserUseCaseImpl {
+func NewUserUseCase(userRepository UserRepository, auditLogRepository AuditLogRepository, permissionUseCase PermissionUseCase) *UserUseCaseImpl {
 	return &UserUseCaseImpl{
-		userRepository: userRepository,
+		userRepository:     userRepository,
+		auditLogRepository: auditLogRepository,
+		permissionUseCase:  permissionUseCase,
 	}
 }
 
@@ -24,3 +48,294 @@ func (u *UserUseCaseImpl) GetUserByUsernaname(username string) (entity.User, err
 	}
 	return user, nil
 }
+
+func (u *UserUseCaseImpl) GetUserByID(userID int64) (entity.User, error) {
+	user, err := u.userRepository.GetUserByID(userID)
+	if err != nil {
+		return entity.User{}, err
+	}
+	return user, nil
+}
+
+func (u *UserUseCaseImpl) ListUsers(filter entity.UserFilter) (entity.UserList, error) {
+	if filter.Role != "" && !filter.Role.IsValid() {
+		return entity.UserList{}, errors.New("invalid role")
+	}
+
+	pagination := entity.NewPagination(filter.Page, filter.Limit)
+
+	users, total, err := u.userRepository.ListUsers(filter, pagination)
+	if err != nil {
+		log.Println(
+			"error when ListUsers",
+			zap.String("method", "UserUseCaseImpl.ListUsers"),
+			zap.Any("filter", filter),
+			zap.Error(err),
+		)
+		return entity.UserList{}, err
+	}
+
+	pagination.Total = total
+	return entity.UserList{
+		Users:      users,
+		Pagination: pagination,
+	}, nil
+}
+
+func (u *UserUseCaseImpl) CreateUser(userContext entity.UserContext, request entity.CreateUserRequest) (entity.User, error) {
+	username := strings.TrimSpace(request.Username)
+	if username == "" {
+		return entity.User{}, errors.New("username is required")
+	}
+
+	if err := u.validateRoleAssignment(userContext, request.Role); err != nil {
+		return entity.User{}, err
+	}
+
+	hashedPassword, err := u.hashPassword(request.Password)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	_, err = u.userRepository.GetUserByUsername(username)
+	if err == nil {
+		return entity.User{}, errors.New("username already exists")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println(
+			"error when GetUserByUsername",
+			zap.String("method", "UserUseCaseImpl.CreateUser"),
+			zap.Any("user_contex", userContext),
+			zap.Error(err),
+		)
+		return entity.User{}, err
+	}
+
+	user, err := u.userRepository.CreateUser(entity.User{
+		Username:  username,
+		Password:  hashedPassword,
+		Role:      request.Role,
+		IsActive:  true,
+		CreatedBy: userContext.Username,
+		UpdatedBy: userContext.Username,
+	})
+	if err != nil {
+		log.Println(
+			"error when CreateUser",
+			zap.String("method", "UserUseCaseImpl.CreateUser"),
+			zap.Any("user_contex", userContext),
+			zap.Error(err),
+		)
+		return entity.User{}, err
+	}
+
+	u.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    "create",
+		Target:    "user",
+		TableName: "users",
+		CreatedBy: userContext.Username,
+	}, user)
+
+	return user, nil
+}
+
+/*
+Deactivated users are kept for history (payslips, audit logs) but can no longer authenticate.
+*/
+func (u *UserUseCaseImpl) DeactivateUser(userContext entity.UserContext, userID int64) error {
+	if userContext.UserID == userID {
+		return errors.New("users cannot deactivate themselves")
+	}
+
+	user, err := u.getUser("UserUseCaseImpl.DeactivateUser", userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == entity.RoleAdmin && userContext.Role != entity.RoleAdmin {
+		return errors.New("only admins can deactivate another admin")
+	}
+
+	if err := u.ensureHoldsRole(userContext, "UserUseCaseImpl.DeactivateUser", user.Role); err != nil {
+		return err
+	}
+
+	if !user.IsActive {
+		return errors.New("the user is already deactivated")
+	}
+
+	return u.updateUser(userContext, user, "deactivate", map[string]interface{}{
+		"is_active": false,
+	})
+}
+
+func (u *UserUseCaseImpl) ResetPassword(userContext entity.UserContext, userID int64, request entity.ResetPasswordRequest) error {
+	hashedPassword, err := u.hashPassword(request.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.getUser("UserUseCaseImpl.ResetPassword", userID)
+	if err != nil {
+		return err
+	}
+
+	// Resetting the password of an admin would let the caller log in as that admin.
+	if user.Role == entity.RoleAdmin && userContext.Role != entity.RoleAdmin {
+		return errors.New("only admins can reset the password of another admin")
+	}
+
+	if err := u.ensureHoldsRole(userContext, "UserUseCaseImpl.ResetPassword", user.Role); err != nil {
+		return err
+	}
+
+	return u.updateUser(userContext, user, "reset_password", map[string]interface{}{
+		"password": hashedPassword,
+	})
+}
+
+func (u *UserUseCaseImpl) ChangeRole(userContext entity.UserContext, userID int64, request entity.ChangeRoleRequest) error {
+	if err := u.validateRoleAssignment(userContext, request.Role); err != nil {
+		return err
+	}
+
+	if userContext.UserID == userID {
+		return errors.New("users cannot change their own role")
+	}
+
+	user, err := u.getUser("UserUseCaseImpl.ChangeRole", userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == request.Role {
+		return nil
+	}
+
+	// Taking the admin role away is as privileged as handing it out.
+	if user.Role == entity.RoleAdmin && userContext.Role != entity.RoleAdmin {
+		return errors.New("only admins can change the role of another admin")
+	}
+
+	if err := u.ensureHoldsRole(userContext, "UserUseCaseImpl.ChangeRole", user.Role); err != nil {
+		return err
+	}
+
+	return u.updateUser(userContext, user, "change_role", map[string]interface{}{
+		"role": request.Role,
+	})
+}
+
+/*
+Users holding user.manage but not the admin role must not be able to create or promote admins, or users of a role
+with permissions they do not hold themselves, otherwise the permission model could be bypassed.
+*/
+func (u *UserUseCaseImpl) validateRoleAssignment(userContext entity.UserContext, role entity.UserRole) error {
+	if !role.IsValid() {
+		return errors.New("invalid role")
+	}
+
+	if role == entity.RoleAdmin && userContext.Role != entity.RoleAdmin {
+		return errors.New("only admins can assign the admin role")
+	}
+
+	return u.ensureHoldsRole(userContext, "UserUseCaseImpl.validateRoleAssignment", role)
+}
+
+/*
+ensureHoldsRole keeps the separation of duties between roles: assigning a role, or resetting the password of,
+deactivating or changing the role of its users, would let the caller act with its permissions, so it is only
+allowed to admins and to callers already holding every permission of the role.
+*/
+func (u *UserUseCaseImpl) ensureHoldsRole(userContext entity.UserContext, method string, role entity.UserRole) error {
+	if userContext.Role == entity.RoleAdmin {
+		return nil
+	}
+
+	permissions, err := u.permissionUseCase.GetPermissionsByRole(role)
+	if err != nil {
+		log.Println(
+			"error when GetPermissionsByRole",
+			zap.String("method", method),
+			zap.Any("user_contex", userContext),
+			zap.String("role", string(role)),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	for _, permission := range permissions {
+		if !userContext.HasPermission(permission) {
+			return fmt.Errorf("the %s role holds the %s permission you do not hold, only admins can assign it or manage its users", role, permission)
+		}
+	}
+	return nil
+}
+
+func (u *UserUseCaseImpl) getUser(method string, userID int64) (entity.User, error) {
+	user, err := u.userRepository.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.User{}, errors.New("user not found")
+		}
+		log.Println(
+			"error when GetUserByID",
+			zap.String("method", method),
+			zap.Int64("user_id", userID),
+			zap.Error(err),
+		)
+		return entity.User{}, err
+	}
+	return user, nil
+}
+
+func (u *UserUseCaseImpl) updateUser(userContext entity.UserContext, user entity.User, action string, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+	updates["updated_by"] = userContext.Username
+
+	err := u.userRepository.UpdateUser(user.ID, updates)
+	if err != nil {
+		log.Println(
+			"error when UpdateUser",
+			zap.String("method", "UserUseCaseImpl.updateUser"),
+			zap.String("action", action),
+			zap.Any("user_contex", userContext),
+			zap.Int64("user_id", user.ID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	// Never write the password hash into the audit trail.
+	payload := map[string]interface{}{"user_id": user.ID}
+	for key, value := range updates {
+		if key != "password" {
+			payload[key] = value
+		}
+	}
+
+	u.auditLogRepository.Create(entity.AuditLog{
+		RequestID: userContext.RequestID,
+		IPAddress: userContext.IPAddress,
+		Action:    action,
+		Target:    "user",
+		TableName: "users",
+		CreatedBy: userContext.Username,
+	}, payload)
+
+	return nil
+}
+
+func (u *UserUseCaseImpl) hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", errors.New("password must be at least 8 characters")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedPassword), nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/eafajri/hr-service.git/module/employee/internal/usecase/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func Test_UserUseCase_GetUserByUsernaname(t *testing.T) {
@@ -44,7 +45,7 @@ func Test_UserUseCase_GetUserByUsernaname(t *testing.T) {
 
 			tt.mockFunc(userRepository)
 
-			usecase := usecase.NewUserUseCase(userRepository)
+			usecase := usecase.NewUserUseCase(userRepository, mocks.NewAuditLogRepository(t), mocks.NewPermissionUseCase(t))
 			res, err := usecase.GetUserByUsernaname("")
 			if tt.wantErr != nil {
 				assert.Equal(t, tt.wantErr.Error(), err.Error())
@@ -55,3 +56,556 @@ func Test_UserUseCase_GetUserByUsernaname(t *testing.T) {
 		})
 	}
 }
+
+// hrPermissions are the permissions the hr role is seeded with.
+var hrPermissions = []entity.Permission{
+	entity.PermissionUserManage,
+	entity.PermissionPayslipReadAll,
+	entity.PermissionSubmitOnBehalf,
+	entity.PermissionEmployeeManage,
+	entity.PermissionSalaryManage,
+	entity.PermissionHolidayManage,
+	entity.PermissionPayrollApprove,
+}
+
+// financePermissions are the permissions the finance role is seeded with.
+var financePermissions = []entity.Permission{
+	entity.PermissionPayrollGenerate,
+	entity.PermissionPayrollPay,
+	entity.PermissionPeriodClose,
+	entity.PermissionPeriodManage,
+	entity.PermissionPayslipReadAll,
+}
+
+func Test_UserUseCase_CreateUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  entity.CreateUserRequest
+		mockFunc func(
+			userRepository *mocks.UserRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+			permissionUseCase *mocks.PermissionUseCase,
+		)
+		wantErr error
+		wantRes entity.User
+	}{
+		{
+			name:    "error - empty username",
+			request: entity.CreateUserRequest{Username: " ", Password: "password", Role: entity.RoleEmployee},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+			},
+			wantErr: errors.New("username is required"),
+		},
+		{
+			name:    "error - invalid role",
+			request: entity.CreateUserRequest{Username: "user5@example.com", Password: "password", Role: "owner"},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+			},
+			wantErr: errors.New("invalid role"),
+		},
+		{
+			name:    "error - password too short",
+			request: entity.CreateUserRequest{Username: "user5@example.com", Password: "short", Role: entity.RoleEmployee},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleEmployee).Return([]entity.Permission{}, nil)
+			},
+			wantErr: errors.New("password must be at least 8 characters"),
+		},
+		{
+			name:    "error - username already exists",
+			request: entity.CreateUserRequest{Username: "user5@example.com", Password: "password", Role: entity.RoleEmployee},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleEmployee).Return([]entity.Permission{}, nil)
+				userRepository.On("GetUserByUsername", "user5@example.com").
+					Return(entity.User{ID: 5}, nil)
+			},
+			wantErr: errors.New("username already exists"),
+		},
+		{
+			name:    "error - hr creates a finance user",
+			request: entity.CreateUserRequest{Username: "user5@example.com", Password: "password", Role: entity.RoleFinance},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleFinance).Return(financePermissions, nil)
+			},
+			wantErr: errors.New("the finance role holds the payroll.generate permission you do not hold, only admins can assign it or manage its users"),
+		},
+		{
+			name:    "success - hr creates an auditor user",
+			request: entity.CreateUserRequest{Username: "user5@example.com", Password: "password", Role: entity.RoleAuditor},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleAuditor).Return([]entity.Permission{entity.PermissionPayslipReadAll}, nil)
+				userRepository.On("GetUserByUsername", "user5@example.com").
+					Return(entity.User{}, gorm.ErrRecordNotFound)
+				userRepository.On("CreateUser", mock.Anything).
+					Return(entity.User{ID: 5, Username: "user5@example.com", Role: entity.RoleAuditor, IsActive: true}, nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantRes: entity.User{ID: 5, Username: "user5@example.com", Role: entity.RoleAuditor, IsActive: true},
+		},
+		{
+			name:    "error - CreateUser",
+			request: entity.CreateUserRequest{Username: "user5@example.com", Password: "password", Role: entity.RoleEmployee},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleEmployee).Return([]entity.Permission{}, nil)
+				userRepository.On("GetUserByUsername", "user5@example.com").
+					Return(entity.User{}, gorm.ErrRecordNotFound)
+				userRepository.On("CreateUser", mock.Anything).
+					Return(entity.User{}, gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name:    "success",
+			request: entity.CreateUserRequest{Username: "user5@example.com", Password: "password", Role: entity.RoleEmployee},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleEmployee).Return([]entity.Permission{}, nil)
+				userRepository.On("GetUserByUsername", "user5@example.com").
+					Return(entity.User{}, gorm.ErrRecordNotFound)
+				userRepository.On("CreateUser", mock.MatchedBy(func(user entity.User) bool {
+					return user.Username == "user5@example.com" && user.Password != "password" && user.IsActive
+				})).Return(entity.User{ID: 5, Username: "user5@example.com", Role: entity.RoleEmployee, IsActive: true}, nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantRes: entity.User{ID: 5, Username: "user5@example.com", Role: entity.RoleEmployee, IsActive: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepository := mocks.NewUserRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+			permissionUseCase := mocks.NewPermissionUseCase(t)
+
+			tt.mockFunc(userRepository, auditLogRepository, permissionUseCase)
+
+			usecase := usecase.NewUserUseCase(userRepository, auditLogRepository, permissionUseCase)
+			res, err := usecase.CreateUser(entity.UserContext{Username: "hr", Role: entity.RoleHR, Permissions: hrPermissions}, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.Equal(t, tt.wantRes, res)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_UserUseCase_DeactivateUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     entity.UserRole
+		userID   int64
+		mockFunc func(
+			userRepository *mocks.UserRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+			permissionUseCase *mocks.PermissionUseCase,
+		)
+		wantErr error
+	}{
+		{
+			name:   "error - deactivate self",
+			userID: 1,
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+			},
+			wantErr: errors.New("users cannot deactivate themselves"),
+		},
+		{
+			name:   "error - user not found",
+			userID: 2,
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: errors.New("user not found"),
+		},
+		{
+			name:   "error - already deactivated",
+			userID: 2,
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleEmployee).Return([]entity.Permission{}, nil)
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{ID: 2, Role: entity.RoleEmployee, IsActive: false}, nil)
+			},
+			wantErr: errors.New("the user is already deactivated"),
+		},
+		{
+			name:   "error - hr deactivates an admin",
+			role:   entity.RoleHR,
+			userID: 2,
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{ID: 2, Role: entity.RoleAdmin, IsActive: true}, nil)
+			},
+			wantErr: errors.New("only admins can deactivate another admin"),
+		},
+		{
+			name:   "error - hr deactivates a finance user",
+			role:   entity.RoleHR,
+			userID: 2,
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{ID: 2, Role: entity.RoleFinance, IsActive: true}, nil)
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleFinance).Return(financePermissions, nil)
+			},
+			wantErr: errors.New("the finance role holds the payroll.generate permission you do not hold, only admins can assign it or manage its users"),
+		},
+		{
+			name:   "success - hr deactivates an auditor user",
+			role:   entity.RoleHR,
+			userID: 2,
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{ID: 2, Role: entity.RoleAuditor, IsActive: true}, nil)
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleAuditor).Return([]entity.Permission{entity.PermissionPayslipReadAll}, nil)
+				userRepository.On("UpdateUser", int64(2), mock.Anything).Return(nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:   "success - admin deactivates an admin",
+			role:   entity.RoleAdmin,
+			userID: 2,
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{ID: 2, Role: entity.RoleAdmin, IsActive: true}, nil)
+				userRepository.On("UpdateUser", int64(2), mock.Anything).Return(nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:   "success",
+			userID: 2,
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleEmployee).Return([]entity.Permission{}, nil)
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{ID: 2, Role: entity.RoleEmployee, IsActive: true}, nil)
+				userRepository.On("UpdateUser", int64(2), mock.MatchedBy(func(updates map[string]interface{}) bool {
+					return updates["is_active"] == false
+				})).Return(nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepository := mocks.NewUserRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+			permissionUseCase := mocks.NewPermissionUseCase(t)
+
+			tt.mockFunc(userRepository, auditLogRepository, permissionUseCase)
+
+			usecase := usecase.NewUserUseCase(userRepository, auditLogRepository, permissionUseCase)
+			err := usecase.DeactivateUser(entity.UserContext{UserID: 1, Username: "admin", Role: tt.role, Permissions: hrPermissions}, tt.userID)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_UserUseCase_ResetPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     entity.UserRole
+		request  entity.ResetPasswordRequest
+		mockFunc func(
+			userRepository *mocks.UserRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+			permissionUseCase *mocks.PermissionUseCase,
+		)
+		wantErr error
+	}{
+		{
+			name:    "error - password too short",
+			request: entity.ResetPasswordRequest{NewPassword: "short"},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+			},
+			wantErr: errors.New("password must be at least 8 characters"),
+		},
+		{
+			name:    "error - hr resets the password of an admin",
+			role:    entity.RoleHR,
+			request: entity.ResetPasswordRequest{NewPassword: "new-password"},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{ID: 2, Role: entity.RoleAdmin, IsActive: true}, nil)
+			},
+			wantErr: errors.New("only admins can reset the password of another admin"),
+		},
+		{
+			name:    "error - hr resets the password of a finance user",
+			role:    entity.RoleHR,
+			request: entity.ResetPasswordRequest{NewPassword: "new-password"},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{ID: 2, Role: entity.RoleFinance, IsActive: true}, nil)
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleFinance).Return(financePermissions, nil)
+			},
+			wantErr: errors.New("the finance role holds the payroll.generate permission you do not hold, only admins can assign it or manage its users"),
+		},
+		{
+			name:    "success - hr resets the password of an auditor user",
+			role:    entity.RoleHR,
+			request: entity.ResetPasswordRequest{NewPassword: "new-password"},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{ID: 2, Role: entity.RoleAuditor, IsActive: true}, nil)
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleAuditor).Return([]entity.Permission{entity.PermissionPayslipReadAll}, nil)
+				userRepository.On("UpdateUser", int64(2), mock.Anything).Return(nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:    "error - UpdateUser",
+			request: entity.ResetPasswordRequest{NewPassword: "new-password"},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleEmployee).Return([]entity.Permission{}, nil)
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{ID: 2, Role: entity.RoleEmployee, IsActive: true}, nil)
+				userRepository.On("UpdateUser", int64(2), mock.Anything).Return(gorm.ErrInvalidDB)
+			},
+			wantErr: gorm.ErrInvalidDB,
+		},
+		{
+			name:    "success",
+			request: entity.ResetPasswordRequest{NewPassword: "new-password"},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleEmployee).Return([]entity.Permission{}, nil)
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{ID: 2, Role: entity.RoleEmployee, IsActive: true}, nil)
+				userRepository.On("UpdateUser", int64(2), mock.Anything).Return(nil)
+				auditLogRepository.On("Create", mock.Anything, mock.MatchedBy(func(payload map[string]interface{}) bool {
+					_, hasPassword := payload["password"]
+					return !hasPassword
+				})).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepository := mocks.NewUserRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+			permissionUseCase := mocks.NewPermissionUseCase(t)
+
+			tt.mockFunc(userRepository, auditLogRepository, permissionUseCase)
+
+			usecase := usecase.NewUserUseCase(userRepository, auditLogRepository, permissionUseCase)
+			err := usecase.ResetPassword(entity.UserContext{UserID: 1, Username: "admin", Role: tt.role, Permissions: hrPermissions}, 2, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_UserUseCase_ChangeRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int64
+		request  entity.ChangeRoleRequest
+		mockFunc func(
+			userRepository *mocks.UserRepository,
+			auditLogRepository *mocks.AuditLogRepository,
+			permissionUseCase *mocks.PermissionUseCase,
+		)
+		wantErr error
+	}{
+		{
+			name:    "error - invalid role",
+			userID:  2,
+			request: entity.ChangeRoleRequest{Role: "owner"},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+			},
+			wantErr: errors.New("invalid role"),
+		},
+		{
+			name:    "error - change own role",
+			userID:  1,
+			request: entity.ChangeRoleRequest{Role: entity.RoleEmployee},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleEmployee).Return([]entity.Permission{}, nil)
+			},
+			wantErr: errors.New("users cannot change their own role"),
+		},
+		{
+			name:    "error - non admin assigns admin role",
+			userID:  2,
+			request: entity.ChangeRoleRequest{Role: entity.RoleAdmin},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+			},
+			wantErr: errors.New("only admins can assign the admin role"),
+		},
+		{
+			name:    "error - non admin demotes an admin",
+			userID:  2,
+			request: entity.ChangeRoleRequest{Role: entity.RoleEmployee},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleEmployee).Return([]entity.Permission{}, nil)
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{ID: 2, Role: entity.RoleAdmin}, nil)
+			},
+			wantErr: errors.New("only admins can change the role of another admin"),
+		},
+		{
+			name:    "error - hr promotes a user to finance",
+			userID:  2,
+			request: entity.ChangeRoleRequest{Role: entity.RoleFinance},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleFinance).Return(financePermissions, nil)
+			},
+			wantErr: errors.New("the finance role holds the payroll.generate permission you do not hold, only admins can assign it or manage its users"),
+		},
+		{
+			name:    "error - hr demotes a finance user",
+			userID:  2,
+			request: entity.ChangeRoleRequest{Role: entity.RoleEmployee},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleEmployee).Return([]entity.Permission{}, nil)
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{ID: 2, Role: entity.RoleFinance}, nil)
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleFinance).Return(financePermissions, nil)
+			},
+			wantErr: errors.New("the finance role holds the payroll.generate permission you do not hold, only admins can assign it or manage its users"),
+		},
+		{
+			name:    "success - hr promotes a user to auditor",
+			userID:  2,
+			request: entity.ChangeRoleRequest{Role: entity.RoleAuditor},
+			mockFunc: func(
+				userRepository *mocks.UserRepository,
+				auditLogRepository *mocks.AuditLogRepository,
+				permissionUseCase *mocks.PermissionUseCase,
+			) {
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleAuditor).Return([]entity.Permission{entity.PermissionPayslipReadAll}, nil)
+				userRepository.On("GetUserByID", int64(2)).Return(entity.User{ID: 2, Role: entity.RoleEmployee}, nil)
+				permissionUseCase.On("GetPermissionsByRole", entity.RoleEmployee).Return([]entity.Permission{}, nil)
+				userRepository.On("UpdateUser", int64(2), mock.MatchedBy(func(updates map[string]interface{}) bool {
+					return updates["role"] == entity.RoleAuditor
+				})).Return(nil)
+				auditLogRepository.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepository := mocks.NewUserRepository(t)
+			auditLogRepository := mocks.NewAuditLogRepository(t)
+			permissionUseCase := mocks.NewPermissionUseCase(t)
+
+			tt.mockFunc(userRepository, auditLogRepository, permissionUseCase)
+
+			usecase := usecase.NewUserUseCase(userRepository, auditLogRepository, permissionUseCase)
+			err := usecase.ChangeRole(entity.UserContext{UserID: 1, Username: "hr", Role: entity.RoleHR, Permissions: hrPermissions}, tt.userID, tt.request)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
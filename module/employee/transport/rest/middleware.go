@@ -2,6 +2,7 @@ package transport
 
 import (
 	"encoding/base64"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -9,10 +10,9 @@ import (
 	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	"golang.org/x/crypto/bcrypt"
 )
 
-func BasicAuthMiddleware(userUc usecase.UserUseCase) echo.MiddlewareFunc {
+func BasicAuthMiddleware(authUc usecase.AuthUseCase) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			authHeader := c.Request().Header.Get("Authorization")
@@ -29,21 +29,25 @@ func BasicAuthMiddleware(userUc usecase.UserUseCase) echo.MiddlewareFunc {
 			if len(creds) != 2 {
 				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid basic auth format")
 			}
-			username := creds[0]
-			password := creds[1]
 
-			user, err := userUc.GetUserByUsernaname(username)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid username or password")
-			}
+			requestID := getRequestID(c)
 
-			if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid username or password")
+			requestContext := entity.UserContext{
+				RequestID: requestID,
+				IPAddress: c.RealIP(),
 			}
 
-			requestID := c.Request().Header.Get("X-Request-ID")
-			if requestID == "" {
-				requestID = uuid.New().String()
+			user, err := authUc.Authenticate(requestContext, entity.LoginRequest{Username: creds[0], Password: creds[1]})
+			if err != nil {
+				switch {
+				case errors.Is(err, usecase.ErrTooManyAttempts):
+					return echo.NewHTTPError(http.StatusTooManyRequests, err.Error())
+				case errors.Is(err, usecase.ErrUserDeactivated):
+					return echo.NewHTTPError(http.StatusUnauthorized, "User account is deactivated")
+				case errors.Is(err, usecase.ErrInvalidCredentials):
+					return echo.NewHTTPError(http.StatusUnauthorized, "Invalid username or password")
+				}
+				return echo.NewHTTPError(http.StatusInternalServerError, "Unable to authenticate")
 			}
 
 			userContext := entity.UserContext{
@@ -61,7 +65,59 @@ func BasicAuthMiddleware(userUc usecase.UserUseCase) echo.MiddlewareFunc {
 	}
 }
 
-func AdminPrevilageMiddleware(userUc usecase.UserUseCase) echo.MiddlewareFunc {
+// TokenAuthMiddleware authenticates requests carrying a Bearer access token and
+// hands any other scheme over to the given fallback middleware.
+func TokenAuthMiddleware(authUc usecase.AuthUseCase, fallback echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		fallbackHandler := fallback(next)
+
+		return func(c echo.Context) error {
+			authHeader := c.Request().Header.Get("Authorization")
+			if !strings.HasPrefix(authHeader, "Bearer ") {
+				return fallbackHandler(c)
+			}
+
+			userContext, err := authUc.ValidateAccessToken(authHeader[len("Bearer "):])
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+			}
+
+			requestID := getRequestID(c)
+
+			userContext.IPAddress = c.RealIP()
+			userContext.RequestID = requestID
+
+			c.Set("user_context", userContext)
+
+			return next(c)
+		}
+	}
+}
+
+// PermissionMiddleware resolves the permissions granted to the authenticated user's role
+// and stores them on the user context for RequirePermission and the usecases.
+func PermissionMiddleware(permissionUc usecase.PermissionUseCase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userContext, ok := c.Get("user_context").(entity.UserContext)
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+			}
+
+			permissions, err := permissionUc.GetPermissionsByRole(userContext.Role)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Unable to resolve permissions")
+			}
+
+			userContext.Permissions = permissions
+			c.Set("user_context", userContext)
+
+			return next(c)
+		}
+	}
+}
+
+func RequirePermission(permission entity.Permission) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			user, ok := c.Get("user_context").(entity.UserContext)
@@ -69,11 +125,19 @@ func AdminPrevilageMiddleware(userUc usecase.UserUseCase) echo.MiddlewareFunc {
 				return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
 			}
 
-			if user.Role != "admin" {
-				return echo.NewHTTPError(http.StatusForbidden, "Access denied: admin privileges required")
+			if !user.HasPermission(permission) {
+				return echo.NewHTTPError(http.StatusForbidden, "Access denied: "+string(permission)+" permission required")
 			}
 
 			return next(c)
 		}
 	}
 }
+
+func getRequestID(c echo.Context) string {
+	requestID := c.Request().Header.Get("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return requestID
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/eafajri/hr-service.git/config"
 	moduleConfig "github.com/eafajri/hr-service.git/module/employee/config"
 	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
 	"github.com/eafajri/hr-service.git/module/employee/internal/repository"
@@ -12,45 +13,155 @@ import (
 )
 
 type Rest struct {
-	userUc     usecase.UserUseCase
-	employeeUc usecase.EmployeeUseCase
-	payrollUc  usecase.PayrollUseCase
+	authUc         usecase.AuthUseCase
+	userUc         usecase.UserUseCase
+	permissionUc   usecase.PermissionUseCase
+	employeeUc     usecase.EmployeeUseCase
+	profileUc      usecase.EmployeeProfileUseCase
+	salaryUc       usecase.SalaryUseCase
+	payrollUc      usecase.PayrollUseCase
+	policyUc       usecase.PayrollPolicyUseCase
+	periodUc       usecase.PayrollPeriodUseCase
+	payComponentUc usecase.PayComponentUseCase
+	deductionUc    usecase.DeductionUseCase
+	holidayUc      usecase.HolidayUseCase
 }
 
 func StartRest(echoInstance *echo.Echo) {
 
+	conf := config.GetConfig()
 	moduleDependencies := moduleConfig.NewModuleDependencies()
 
 	var (
-		userRepository     = repository.NewUserRepository(&moduleDependencies.Database)
-		employeeRepository = repository.NewEmployeeRepository(&moduleDependencies.Database)
-		payrollRepository  = repository.NewPayrollRepository(&moduleDependencies.Database)
-		auditLogRepository = repository.NewAuditLogRepository(&moduleDependencies.Database)
+		userRepository           = repository.NewUserRepository(&moduleDependencies.Database)
+		userSessionRepository    = repository.NewUserSessionRepository(&moduleDependencies.Database)
+		rolePermissionRepository = repository.NewRolePermissionRepository(&moduleDependencies.Database)
+		employeeRepository       = repository.NewEmployeeRepository(&moduleDependencies.Database)
+		profileRepository        = repository.NewEmployeeProfileRepository(&moduleDependencies.Database)
+		salaryRepository         = repository.NewSalaryRepository(&moduleDependencies.Database)
+		payComponentRepository   = repository.NewPayComponentRepository(&moduleDependencies.Database)
+		deductionRepository      = repository.NewDeductionRepository(&moduleDependencies.Database)
+		holidayRepository        = repository.NewHolidayRepository(&moduleDependencies.Database)
+		payrollRepository        = repository.NewCachedPayrollRepository(repository.NewPayrollRepository(&moduleDependencies.Database), moduleDependencies.MemoryCache)
+		auditLogRepository       = repository.NewAuditLogRepository(&moduleDependencies.Database)
 	)
 
+	authConfig := usecase.AuthConfig{
+		Secret:                 []byte(conf.JWTSecret),
+		AccessTokenTTL:         conf.AccessTokenTTL,
+		RefreshTokenTTL:        conf.RefreshTokenTTL,
+		RevocationSyncInterval: conf.RevocationSyncInterval,
+		LoginThrottle: usecase.LoginThrottleConfig{
+			MaxFailedAttempts: conf.LoginMaxFailedAttempts,
+			LockoutDuration:   conf.LoginLockoutDuration,
+		},
+	}
+
+	payrollConfig := usecase.PayrollConfig{
+		Rounding:             conf.PayrollRounding,
+		NetPayFloor:          conf.PayrollNetPayFloor,
+		OvertimeWarningHours: conf.PayrollPreviewOvertimeWarningHours,
+	}
+
+	permissionUc := usecase.NewPermissionUseCase(rolePermissionRepository, auditLogRepository, moduleDependencies.MemoryCache)
+
 	restHandler := &Rest{
-		userUc:     usecase.NewUserUseCase(userRepository),
-		employeeUc: usecase.NewEmployeeUseCase(employeeRepository, payrollRepository, auditLogRepository),
-		payrollUc:  usecase.NewPayrollUseCase(payrollRepository, employeeRepository, auditLogRepository),
+		authUc:         usecase.NewAuthUseCase(authConfig, userRepository, userSessionRepository, auditLogRepository),
+		userUc:         usecase.NewUserUseCase(userRepository, auditLogRepository, permissionUc),
+		permissionUc:   permissionUc,
+		employeeUc:     usecase.NewEmployeeUseCase(payrollConfig, employeeRepository, profileRepository, payrollRepository, auditLogRepository),
+		profileUc:      usecase.NewEmployeeProfileUseCase(profileRepository, userRepository, auditLogRepository),
+		salaryUc:       usecase.NewSalaryUseCase(salaryRepository, userRepository, payrollRepository, auditLogRepository),
+		payrollUc:      usecase.NewPayrollUseCase(payrollConfig, payrollRepository, employeeRepository, profileRepository, userRepository, auditLogRepository),
+		policyUc:       usecase.NewPayrollPolicyUseCase(payrollRepository, auditLogRepository),
+		periodUc:       usecase.NewPayrollPeriodUseCase(payrollRepository, holidayRepository, auditLogRepository),
+		payComponentUc: usecase.NewPayComponentUseCase(payComponentRepository, userRepository, payrollRepository, auditLogRepository),
+		deductionUc:    usecase.NewDeductionUseCase(deductionRepository, userRepository, payrollRepository, auditLogRepository),
+		holidayUc:      usecase.NewHolidayUseCase(holidayRepository, payrollRepository, auditLogRepository),
 	}
 
 	publicApi := echoInstance.Group("/public")
 	publicApi.GET("/check", restHandler.CheckHealth)
+	publicApi.POST("/auth/login", restHandler.Login)
+	publicApi.POST("/auth/refresh", restHandler.RefreshToken)
+
+	authMiddleware := TokenAuthMiddleware(restHandler.authUc, BasicAuthMiddleware(restHandler.authUc))
+
+	permissionMiddleware := PermissionMiddleware(restHandler.permissionUc)
+
+	authApi := echoInstance.Group("/private/auth")
+	authApi.Use(authMiddleware)
+	authApi.POST("/logout", restHandler.Logout)
 
 	employeeApi := echoInstance.Group("/private/employee")
-	employeeApi.Use(BasicAuthMiddleware(restHandler.userUc))
+	employeeApi.Use(authMiddleware)
+	employeeApi.Use(permissionMiddleware)
 	employeeApi.POST("/attendance/submit", restHandler.SubmitAttendance)
 	employeeApi.POST("/overtime/submit", restHandler.SubmitOvertime)
 	employeeApi.POST("/reimbursement/submit", restHandler.SubmitReimbursement)
 	employeeApi.GET("/payslips/:period_id", restHandler.GetPayslipBreakdown)
 
 	adminApi := echoInstance.Group("/private/admin")
-	adminApi.Use(BasicAuthMiddleware(restHandler.userUc))
-	adminApi.Use(AdminPrevilageMiddleware(restHandler.userUc))
-	adminApi.POST("/payroll/period/close/:period_id", restHandler.ClosePayrollPeriod)
-	adminApi.POST("/payroll/generate/:period_id", restHandler.GeneratePayroll)
-	adminApi.GET("/payslips/:period_id", restHandler.GetPayslips)
-	adminApi.GET("/payslips/:period_id/:user_id", restHandler.GetPayslip)
+	adminApi.Use(authMiddleware)
+	adminApi.Use(permissionMiddleware)
+	adminApi.POST("/payroll/period/close/:period_id", restHandler.ClosePayrollPeriod, RequirePermission(entity.PermissionPeriodClose))
+	adminApi.POST("/payroll/period/reopen/:period_id", restHandler.ReopenPayrollPeriod, RequirePermission(entity.PermissionPeriodReopen))
+	adminApi.POST("/payroll/generate/:period_id", restHandler.GeneratePayroll, RequirePermission(entity.PermissionPayrollGenerate))
+	adminApi.GET("/payroll/preview/:period_id", restHandler.PreviewPayroll, RequirePermission(entity.PermissionPayrollGenerate))
+	adminApi.POST("/payroll/rerun/:period_id", restHandler.RerunPayroll, RequirePermission(entity.PermissionPayrollGenerate))
+	adminApi.GET("/payroll/runs/:period_id", restHandler.ListPayrollRuns, RequirePermission(entity.PermissionPayslipReadAll))
+	adminApi.GET("/payroll/runs/:period_id/diff", restHandler.DiffPayrollRuns, RequirePermission(entity.PermissionPayslipReadAll))
+	adminApi.POST("/payroll/period/approve/:period_id", restHandler.ApprovePayrollPeriod, RequirePermission(entity.PermissionPayrollApprove))
+	adminApi.POST("/payroll/period/pay/:period_id", restHandler.MarkPayrollPeriodPaid, RequirePermission(entity.PermissionPayrollPay))
+	adminApi.POST("/payroll/period/archive/:period_id", restHandler.ArchivePayrollPeriod, RequirePermission(entity.PermissionPeriodManage))
+	adminApi.POST("/payroll/periods", restHandler.CreatePayrollPeriod, RequirePermission(entity.PermissionPeriodManage))
+	adminApi.POST("/payroll/periods/generate", restHandler.GeneratePayrollPeriods, RequirePermission(entity.PermissionPeriodManage))
+	adminApi.GET("/payroll/periods", restHandler.ListPayrollPeriods, RequirePermission(entity.PermissionPeriodManage))
+	adminApi.PUT("/payroll/periods/:period_id", restHandler.UpdatePayrollPeriod, RequirePermission(entity.PermissionPeriodManage))
+	adminApi.POST("/payroll/policies", restHandler.CreatePayrollPolicy, RequirePermission(entity.PermissionPolicyManage))
+	adminApi.GET("/payroll/policies", restHandler.ListPayrollPolicies, RequirePermission(entity.PermissionPolicyManage))
+	adminApi.GET("/payroll/policies/:policy_id", restHandler.GetPayrollPolicy, RequirePermission(entity.PermissionPolicyManage))
+	adminApi.GET("/payroll/statutory-rates", restHandler.GetStatutoryRates, RequirePermission(entity.PermissionPolicyManage))
+	adminApi.GET("/payslips/:period_id", restHandler.GetPayslips, RequirePermission(entity.PermissionPayslipReadAll))
+	adminApi.GET("/payslips/:period_id/:user_id", restHandler.GetPayslip, RequirePermission(entity.PermissionPayslipReadAll))
+	adminApi.GET("/payroll/summary/:period_id", restHandler.GetPayrollSummary, RequirePermission(entity.PermissionPayslipReadAll))
+
+	adminApi.POST("/users", restHandler.CreateUser, RequirePermission(entity.PermissionUserManage))
+	adminApi.GET("/users", restHandler.ListUsers, RequirePermission(entity.PermissionUserManage))
+	adminApi.GET("/users/:user_id", restHandler.GetUser, RequirePermission(entity.PermissionUserManage))
+	adminApi.POST("/users/:user_id/deactivate", restHandler.DeactivateUser, RequirePermission(entity.PermissionUserManage))
+	adminApi.POST("/users/:user_id/password/reset", restHandler.ResetUserPassword, RequirePermission(entity.PermissionUserManage))
+	adminApi.PUT("/users/:user_id/role", restHandler.ChangeUserRole, RequirePermission(entity.PermissionUserManage))
+	adminApi.POST("/users/:user_id/sessions/revoke", restHandler.RevokeUserSessions, RequirePermission(entity.PermissionUserManage))
+	adminApi.POST("/users/:user_id/unlock", restHandler.UnlockUser, RequirePermission(entity.PermissionUserManage))
+
+	adminApi.POST("/employees", restHandler.CreateEmployeeProfile, RequirePermission(entity.PermissionEmployeeManage))
+	adminApi.GET("/employees", restHandler.ListEmployeeProfiles, RequirePermission(entity.PermissionEmployeeManage))
+	adminApi.GET("/employees/:user_id", restHandler.GetEmployeeProfile, RequirePermission(entity.PermissionEmployeeManage))
+	adminApi.PUT("/employees/:user_id", restHandler.UpdateEmployeeProfile, RequirePermission(entity.PermissionEmployeeManage))
+	adminApi.DELETE("/employees/:user_id", restHandler.DeleteEmployeeProfile, RequirePermission(entity.PermissionEmployeeManage))
+	adminApi.POST("/employees/:user_id/salaries", restHandler.RecordSalaryChange, RequirePermission(entity.PermissionSalaryManage))
+	adminApi.GET("/employees/:user_id/salaries", restHandler.GetSalaryHistory, RequirePermission(entity.PermissionSalaryManage))
+	adminApi.POST("/employees/:user_id/pay-components", restHandler.AssignPayComponent, RequirePermission(entity.PermissionSalaryManage))
+	adminApi.GET("/employees/:user_id/pay-components", restHandler.GetPayComponentAssignments, RequirePermission(entity.PermissionSalaryManage))
+	adminApi.POST("/employees/:user_id/pay-components/:assignment_id/end", restHandler.EndPayComponentAssignment, RequirePermission(entity.PermissionSalaryManage))
+	adminApi.POST("/employees/:user_id/deductions", restHandler.CreateDeduction, RequirePermission(entity.PermissionSalaryManage))
+	adminApi.GET("/employees/:user_id/deductions", restHandler.GetDeductions, RequirePermission(entity.PermissionSalaryManage))
+	adminApi.POST("/employees/:user_id/deductions/:deduction_id/cancel", restHandler.CancelDeduction, RequirePermission(entity.PermissionSalaryManage))
+
+	adminApi.POST("/pay-components", restHandler.CreatePayComponent, RequirePermission(entity.PermissionSalaryManage))
+	adminApi.GET("/pay-components", restHandler.ListPayComponents, RequirePermission(entity.PermissionSalaryManage))
+	adminApi.PUT("/pay-components/:component_id", restHandler.UpdatePayComponent, RequirePermission(entity.PermissionSalaryManage))
+
+	adminApi.GET("/holidays", restHandler.ListHolidays, RequirePermission(entity.PermissionHolidayManage))
+	adminApi.POST("/holidays", restHandler.CreateHoliday, RequirePermission(entity.PermissionHolidayManage))
+	adminApi.POST("/holidays/import", restHandler.ImportHolidays, RequirePermission(entity.PermissionHolidayManage))
+	adminApi.PUT("/holidays/:holiday_id", restHandler.UpdateHoliday, RequirePermission(entity.PermissionHolidayManage))
+	adminApi.DELETE("/holidays/:holiday_id", restHandler.DeleteHoliday, RequirePermission(entity.PermissionHolidayManage))
+
+	adminApi.GET("/roles/permissions", restHandler.ListRolePermissions, RequirePermission(entity.PermissionRoleManage))
+	adminApi.POST("/roles/:role/permissions", restHandler.GrantPermission, RequirePermission(entity.PermissionRoleManage))
+	adminApi.DELETE("/roles/:role/permissions/:permission", restHandler.RevokePermission, RequirePermission(entity.PermissionRoleManage))
 }
 
 func (h *Rest) CheckHealth(c echo.Context) error {
@@ -66,6 +66,11 @@ func (r *Rest) SubmitReimbursement(c echo.Context) error {
 }
 
 func (r *Rest) GetPayslip(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
 	payrollPeriodID, err := strconv.Atoi(c.Param("period_id"))
 	if err != nil {
 		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
@@ -76,7 +81,7 @@ func (r *Rest) GetPayslip(c echo.Context) error {
 		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
 	}
 
-	response, err := r.payrollUc.GetPayslip(int64(userID), int64(payrollPeriodID))
+	response, err := r.payrollUc.GetPayslip(userDetail, int64(userID), int64(payrollPeriodID))
 	if err != nil {
 		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
 	}
@@ -85,13 +90,18 @@ func (r *Rest) GetPayslip(c echo.Context) error {
 }
 
 func (r *Rest) GetPayslips(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
 	idParam := c.Param("period_id")
 	periodID, err := strconv.Atoi(idParam)
 	if err != nil {
 		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
 	}
 
-	response, err := r.payrollUc.GetPayslips(int64(periodID))
+	response, err := r.payrollUc.GetPayslips(userDetail, int64(periodID))
 	if err != nil {
 		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
 	}
@@ -131,12 +141,12 @@ func (r *Rest) GeneratePayroll(c echo.Context) error {
 		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
 	}
 
-	err = r.payrollUc.GeneratePayslipsByPeriodID(userDetail, int64(periodID))
+	response, err := r.payrollUc.GeneratePayslipsByPeriodID(userDetail, int64(periodID))
 	if err != nil {
 		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
 	}
 
-	return r.standardizeResponse(c, http.StatusOK, "Success", nil)
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
 }
 
 func (r *Rest) ClosePayrollPeriod(c echo.Context) error {
@@ -158,3 +168,84 @@ func (r *Rest) ClosePayrollPeriod(c echo.Context) error {
 
 	return r.standardizeResponse(c, http.StatusOK, "Success", nil)
 }
+
+func (r *Rest) ReopenPayrollPeriod(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	var request entity.ReopenPayrollPeriodRequest
+	if err := c.Bind(&request); err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+
+	err = r.payrollUc.ReopenPayrollPeriod(userDetail, int64(periodID), request)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Payroll period reopened successfully", nil)
+}
+
+func (r *Rest) ApprovePayrollPeriod(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	err = r.payrollUc.ApprovePayrollPeriod(userDetail, int64(periodID))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Payroll period approved successfully", nil)
+}
+
+func (r *Rest) MarkPayrollPeriodPaid(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	err = r.payrollUc.MarkPayrollPeriodPaid(userDetail, int64(periodID))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Payroll period marked as paid successfully", nil)
+}
+
+func (r *Rest) ArchivePayrollPeriod(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	err = r.payrollUc.ArchivePayrollPeriod(userDetail, int64(periodID))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Payroll period archived successfully", nil)
+}
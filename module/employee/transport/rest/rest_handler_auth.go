@@ -0,0 +1,110 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+func (r *Rest) Login(c echo.Context) error {
+	var request entity.LoginRequest
+	if err := c.Bind(&request); err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+
+	requestContext := entity.UserContext{
+		RequestID: getRequestID(c),
+		IPAddress: c.RealIP(),
+	}
+
+	response, err := r.authUc.Login(requestContext, request)
+	if err != nil {
+		if errors.Is(err, usecase.ErrTooManyAttempts) {
+			return r.standardizeResponse(c, http.StatusTooManyRequests, err.Error(), nil)
+		}
+		if errors.Is(err, usecase.ErrInvalidCredentials) || errors.Is(err, usecase.ErrUserDeactivated) {
+			return r.standardizeResponse(c, http.StatusUnauthorized, err.Error(), nil)
+		}
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
+}
+
+func (r *Rest) RefreshToken(c echo.Context) error {
+	var request entity.RefreshTokenRequest
+	if err := c.Bind(&request); err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+
+	requestContext := entity.UserContext{
+		RequestID: getRequestID(c),
+		IPAddress: c.RealIP(),
+	}
+
+	response, err := r.authUc.RefreshToken(requestContext, request)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) || errors.Is(err, usecase.ErrSessionRevoked) {
+			return r.standardizeResponse(c, http.StatusUnauthorized, err.Error(), nil)
+		}
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
+}
+
+func (r *Rest) Logout(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	err := r.authUc.Logout(userDetail)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Logged out successfully", nil)
+}
+
+func (r *Rest) RevokeUserSessions(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	err = r.authUc.RevokeUserSessions(userDetail, int64(userID))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Sessions revoked successfully", nil)
+}
+
+func (r *Rest) UnlockUser(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	err = r.authUc.UnlockUser(userDetail, int64(userID))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "User unlocked successfully", nil)
+}
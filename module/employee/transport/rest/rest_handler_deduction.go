@@ -0,0 +1,71 @@
+package transport
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+func (r *Rest) CreateDeduction(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	var request entity.DeductionRequest
+	if err := c.Bind(&request); err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+
+	response, err := r.deductionUc.CreateDeduction(userDetail, int64(userID), request)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Deduction created successfully", response)
+}
+
+func (r *Rest) GetDeductions(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	response, err := r.deductionUc.GetDeductions(int64(userID))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
+}
+
+func (r *Rest) CancelDeduction(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	deductionID, err := strconv.Atoi(c.Param("deduction_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	response, err := r.deductionUc.CancelDeduction(userDetail, int64(userID), int64(deductionID))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Deduction cancelled successfully", response)
+}
@@ -0,0 +1,99 @@
+package transport
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+func (r *Rest) CreateEmployeeProfile(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	var request entity.EmployeeProfileRequest
+	if err := c.Bind(&request); err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+
+	response, err := r.profileUc.CreateProfile(userDetail, request)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Employee profile created successfully", response)
+}
+
+func (r *Rest) ListEmployeeProfiles(c echo.Context) error {
+	var filter entity.EmployeeProfileFilter
+	if err := c.Bind(&filter); err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+
+	response, err := r.profileUc.ListProfiles(filter)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
+}
+
+func (r *Rest) GetEmployeeProfile(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	response, err := r.profileUc.GetProfile(int64(userID))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
+}
+
+func (r *Rest) UpdateEmployeeProfile(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	var request entity.EmployeeProfileRequest
+	if err := c.Bind(&request); err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+
+	response, err := r.profileUc.UpdateProfile(userDetail, int64(userID), request)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Employee profile updated successfully", response)
+}
+
+func (r *Rest) DeleteEmployeeProfile(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	err = r.profileUc.DeleteProfile(userDetail, int64(userID))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Employee profile deleted successfully", nil)
+}
@@ -0,0 +1,142 @@
+package transport
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// maxHolidayImportSize bounds the body of a holiday import, a yearly list is a few kilobytes.
+const maxHolidayImportSize = 1 << 20
+
+func (r *Rest) ListHolidays(c echo.Context) error {
+	year, err := holidayYear(c)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid year format", nil)
+	}
+
+	response, err := r.holidayUc.ListHolidays(year)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
+}
+
+func (r *Rest) CreateHoliday(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	var request entity.HolidayRequest
+	if err := c.Bind(&request); err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+
+	response, err := r.holidayUc.CreateHoliday(userDetail, request)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Holiday created successfully", response)
+}
+
+/*
+ImportHolidays takes the CSV or iCal file as the request body. The format is the format query parameter, then
+the Content-Type (text/csv or text/calendar), and is otherwise sniffed from the content.
+*/
+func (r *Rest) ImportHolidays(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	year, err := holidayYear(c)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid year format", nil)
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+		switch mediaType {
+		case "text/csv":
+			format = usecase.HolidayImportCSV
+		case "text/calendar":
+			format = usecase.HolidayImportICal
+		}
+	}
+
+	content, err := io.ReadAll(io.LimitReader(c.Request().Body, maxHolidayImportSize+1))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+	if len(content) > maxHolidayImportSize {
+		return r.standardizeResponse(c, http.StatusRequestEntityTooLarge, "The file is too large", nil)
+	}
+
+	response, err := r.holidayUc.ImportHolidays(userDetail, year, format, content)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Holidays imported successfully", response)
+}
+
+func (r *Rest) UpdateHoliday(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	holidayID, err := strconv.Atoi(c.Param("holiday_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	var request entity.HolidayRequest
+	if err := c.Bind(&request); err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+
+	response, err := r.holidayUc.UpdateHoliday(userDetail, int64(holidayID), request)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Holiday updated successfully", response)
+}
+
+func (r *Rest) DeleteHoliday(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	holidayID, err := strconv.Atoi(c.Param("holiday_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	err = r.holidayUc.DeleteHoliday(userDetail, int64(holidayID))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Holiday deleted successfully", nil)
+}
+
+// holidayYear is the year query parameter, the current year when it is missing.
+func holidayYear(c echo.Context) (int, error) {
+	if c.QueryParam("year") == "" {
+		return time.Now().Year(), nil
+	}
+	return strconv.Atoi(c.QueryParam("year"))
+}
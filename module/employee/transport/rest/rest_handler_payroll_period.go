@@ -0,0 +1,80 @@
+package transport
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+func (r *Rest) CreatePayrollPeriod(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	var request entity.PayrollPeriodRequest
+	if err := c.Bind(&request); err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+
+	response, err := r.periodUc.CreatePeriod(userDetail, request)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Payroll period created successfully", response)
+}
+
+func (r *Rest) GeneratePayrollPeriods(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	var request entity.GeneratePayrollPeriodsRequest
+	if err := c.Bind(&request); err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+
+	response, err := r.periodUc.GeneratePeriods(userDetail, request)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Payroll periods generated successfully", response)
+}
+
+func (r *Rest) ListPayrollPeriods(c echo.Context) error {
+	response, err := r.periodUc.ListPeriods(c.QueryParam("status"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
+}
+
+func (r *Rest) UpdatePayrollPeriod(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	var request entity.PayrollPeriodRequest
+	if err := c.Bind(&request); err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+
+	response, err := r.periodUc.UpdatePeriod(userDetail, int64(periodID), request)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Payroll period updated successfully", response)
+}
@@ -0,0 +1,60 @@
+package transport
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+func (r *Rest) CreatePayrollPolicy(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	var request entity.PayrollPolicyRequest
+	if err := c.Bind(&request); err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+
+	response, err := r.policyUc.CreatePolicy(userDetail, request)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Payroll policy created successfully", response)
+}
+
+func (r *Rest) ListPayrollPolicies(c echo.Context) error {
+	response, err := r.policyUc.ListPolicies()
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
+}
+
+func (r *Rest) GetPayrollPolicy(c echo.Context) error {
+	policyID, err := strconv.Atoi(c.Param("policy_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	response, err := r.policyUc.GetPolicy(int64(policyID))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
+}
+
+func (r *Rest) GetStatutoryRates(c echo.Context) error {
+	response, err := r.policyUc.GetStatutoryRates(c.QueryParam("date"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
+}
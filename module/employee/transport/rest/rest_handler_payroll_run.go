@@ -0,0 +1,109 @@
+package transport
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+func (r *Rest) RerunPayroll(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	var request entity.RerunPayrollRequest
+	if err := c.Bind(&request); err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid request format", nil)
+	}
+
+	response, err := r.payrollUc.RerunPayroll(userDetail, int64(periodID), request)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Payroll rerun successfully", response)
+}
+
+func (r *Rest) ListPayrollRuns(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	response, err := r.payrollUc.ListPayrollRuns(userDetail, int64(periodID))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
+}
+
+// DiffPayrollRuns compares the from and to versions, the current run with the version before it when they are missing.
+func (r *Rest) DiffPayrollRuns(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	fromVersion, err := runVersion(c, "from")
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid version format", nil)
+	}
+
+	toVersion, err := runVersion(c, "to")
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid version format", nil)
+	}
+
+	response, err := r.payrollUc.DiffPayrollRuns(userDetail, int64(periodID), fromVersion, toVersion)
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
+}
+
+// runVersion is a version query parameter, 0 when it is missing.
+func runVersion(c echo.Context, name string) (int, error) {
+	if c.QueryParam(name) == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(c.QueryParam(name))
+}
+
+func (r *Rest) PreviewPayroll(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	response, err := r.payrollUc.PreviewPayroll(userDetail, int64(periodID))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
+}
@@ -0,0 +1,49 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/eafajri/hr-service.git/module/employee/internal/entity"
+	"github.com/eafajri/hr-service.git/module/employee/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// GetPayrollSummary groups the report by the group_by query, format=csv downloads it instead of the JSON response.
+func (r *Rest) GetPayrollSummary(c echo.Context) error {
+	userDetail, ok := c.Get("user_context").(entity.UserContext)
+	if !ok {
+		return r.standardizeResponse(c, http.StatusUnauthorized, "User ID not found in context", nil)
+	}
+
+	periodID, err := strconv.Atoi(c.Param("period_id"))
+	if err != nil {
+		return r.standardizeResponse(c, http.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	groupBy := c.QueryParam("group_by")
+	if c.QueryParam("format") == "csv" {
+		content, err := r.payrollUc.ExportPayrollSummaryCSV(userDetail, int64(periodID), groupBy)
+		if err != nil {
+			if errors.Is(err, usecase.ErrInvalidPayrollSummaryGroup) {
+				return r.standardizeResponse(c, http.StatusBadRequest, err.Error(), nil)
+			}
+			return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=payroll-summary-%d.csv", periodID))
+		return c.Blob(http.StatusOK, "text/csv", content)
+	}
+
+	response, err := r.payrollUc.GetPayrollSummary(userDetail, int64(periodID), groupBy)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidPayrollSummaryGroup) {
+			return r.standardizeResponse(c, http.StatusBadRequest, err.Error(), nil)
+		}
+		return r.standardizeResponse(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+
+	return r.standardizeResponse(c, http.StatusOK, "Success", response)
+}